@@ -0,0 +1,208 @@
+package fwdptest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gabstv/freeport"
+	"github.com/usnistgov/ndn-dpdk/app/fwdp"
+	"github.com/usnistgov/ndn-dpdk/container/strategycode"
+	"github.com/usnistgov/ndn-dpdk/iface"
+	"github.com/usnistgov/ndn-dpdk/iface/intface"
+	"github.com/usnistgov/ndn-dpdk/iface/socketface"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"github.com/usnistgov/ndn-dpdk/ndn/mgmt/nfdmgmt"
+	"github.com/usnistgov/ndn-dpdk/ndn/sockettransport"
+	"go4.org/must"
+)
+
+func TestNfdMgmt(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := NewFixture(t)
+	defer fixture.Close()
+
+	sc, e := strategycode.LoadFile("multicast", "")
+	require.NoError(e)
+	operatorPvt, operatorPub, e := keychain.NewECDSAKeyPair(ndn.ParseName("/operator"))
+	require.NoError(e)
+	operatorCert, e := keychain.MakeCert(operatorPub, operatorPvt, keychain.MakeCertOptions{})
+	require.NoError(e)
+	anchor, e := keychain.MarshalCert(operatorCert)
+	require.NoError(e)
+	sockPath := filepath.Join(t.TempDir(), "nfd.sock")
+	m, e := fwdp.NewNfdMgmt(fixture.DataPlane, sc, fwdp.NfdMgmtConfig{
+		Unix:    socketface.UnixListenerConfig{Listen: sockPath, Mode: 0o600},
+		Anchors: [][]byte{anchor},
+	})
+	require.NoError(e)
+	defer must.Close(m)
+
+	fi, e := os.Stat(sockPath)
+	require.NoError(e)
+	assert.EqualValues(0o600, fi.Mode().Perm())
+
+	tr, e := sockettransport.Dial(socketface.NetworkUnix, "", sockPath)
+	require.NoError(e)
+	l3face, e := l3.NewFace(tr, l3.FaceConfig{})
+	require.NoError(e)
+	fw := l3.NewForwarder()
+	fwFace, e := fw.AddFace(l3face)
+	require.NoError(e)
+	defer fwFace.Close()
+	fwFace.AddRoute(fwdp.NfdMgmtPrefix)
+
+	client, e := nfdmgmt.New()
+	require.NoError(e)
+	client.ConsumerOpts.Fw = fw
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// commands must be signed by a trusted key
+	cr, e := client.Invoke(ctx, "rib/register", nfdmgmt.ControlParameters{Name: ndn.ParseName("/U")})
+	require.NoError(e)
+	assert.EqualValues(nfdmgmt.StatusForbidden, cr.StatusCode)
+	assert.Nil(fixture.Fib.Find(ndn.ParseName("/U")))
+	client.Signer = operatorPvt
+
+	statusCode := func(e error) uint {
+		var statusErr nfdmgmt.StatusError
+		if assert.ErrorAs(e, &statusErr) {
+			return statusErr.Code
+		}
+		return 0
+	}
+	nexthops := func(name string) []iface.ID {
+		if entry := fixture.Fib.Find(ndn.ParseName(name)); entry != nil {
+			return entry.Nexthops
+		}
+		return nil
+	}
+	register := func(name string, faceID, cost uint64, expiration *uint64) (body nfdmgmt.ControlParameters) {
+		cr, e := client.Invoke(ctx, "rib/register", nfdmgmt.ControlParameters{
+			Name:             ndn.ParseName(name),
+			FaceID:           faceID,
+			Cost:             &cost,
+			ExpirationPeriod: expiration,
+		})
+		require.NoError(e)
+		require.NoError(cr.Err())
+		require.NotNil(cr.Body)
+		return *cr.Body
+	}
+
+	// faces/create
+	_, e = client.CreateFace(ctx, nfdmgmt.ControlParameters{URI: "ether://[02:00:00:00:00:01]"})
+	assert.EqualValues(nfdmgmt.StatusNotAcceptable, statusCode(e))
+
+	portLocal, _ := freeport.UDP()
+	remoteURI := "udp4://127.0.0.1:6363"
+	localURI := fmt.Sprintf("udp4://127.0.0.1:%d", portLocal)
+	body, e := client.CreateFace(ctx, nfdmgmt.ControlParameters{URI: remoteURI, LocalURI: localURI})
+	require.NoError(e)
+	udpID := body.FaceID
+	assert.NotZero(udpID)
+	assert.Equal(remoteURI, body.URI)
+	assert.Equal(localURI, body.LocalURI)
+
+	body, e = client.CreateFace(ctx, nfdmgmt.ControlParameters{URI: remoteURI})
+	assert.EqualValues(nfdmgmt.StatusConflict, statusCode(e))
+	assert.Equal(udpID, body.FaceID)
+
+	// rib/register and rib/unregister
+	body = register("/R", udpID, 20, nil)
+	assert.Equal(udpID, body.FaceID)
+	body = register("/R", 0, 10, nil)
+	localID := body.FaceID
+	assert.NotEqual(udpID, localID)
+	if assert.NotNil(body.Origin) {
+		assert.EqualValues(nfdmgmt.RouteOriginApp, *body.Origin)
+	}
+	assert.Equal([]iface.ID{iface.ID(localID), iface.ID(udpID)}, nexthops("/R"))
+
+	register("/R", 0, 30, nil) // replace route
+	assert.Equal([]iface.ID{iface.ID(udpID), iface.ID(localID)}, nexthops("/R"))
+
+	expiration := uint64(300)
+	body = register("/E", 0, 0, &expiration)
+	if assert.NotNil(body.ExpirationPeriod) {
+		assert.EqualValues(300, *body.ExpirationPeriod)
+	}
+	assert.Len(nexthops("/E"), 1)
+
+	ribList, e := client.ListRib(ctx)
+	require.NoError(e)
+	require.Len(ribList, 2)
+	assert.Equal("/8=E", ribList[0].Name.String())
+	if assert.Len(ribList[0].Routes, 1) && assert.NotNil(ribList[0].Routes[0].ExpirationPeriod) {
+		assert.LessOrEqual(*ribList[0].Routes[0].ExpirationPeriod, uint64(300))
+	}
+	assert.Equal("/8=R", ribList[1].Name.String())
+	if assert.Len(ribList[1].Routes, 2) {
+		assert.Nil(ribList[1].Routes[0].ExpirationPeriod)
+	}
+
+	assert.Eventually(func() bool { return fixture.Fib.Find(ndn.ParseName("/E")) == nil }, 2*time.Second, 50*time.Millisecond)
+
+	_, e = client.Invoke(ctx, "rib/unregister", nfdmgmt.ControlParameters{Name: ndn.ParseName("/R")})
+	require.NoError(e)
+	assert.Equal([]iface.ID{iface.ID(udpID)}, nexthops("/R"))
+
+	// datasets
+	faceList, e := client.ListFaces(ctx)
+	require.NoError(e)
+	var hasLocal, hasUDP bool
+	for _, fs := range faceList {
+		switch fs.FaceID {
+		case localID:
+			hasLocal = true
+			assert.Equal(fmt.Sprintf("fd://%d", localID), fs.URI)
+			assert.Equal("unix://"+sockPath, fs.LocalURI)
+			assert.EqualValues(nfdmgmt.FaceScopeLocal, fs.FaceScope)
+		case udpID:
+			hasUDP = true
+			assert.Equal(remoteURI, fs.URI)
+			assert.EqualValues(nfdmgmt.FaceScopeNonLocal, fs.FaceScope)
+		}
+	}
+	assert.True(hasLocal)
+	assert.True(hasUDP)
+
+	fibList, e := client.ListFib(ctx)
+	require.NoError(e)
+	var hasR bool
+	for _, fe := range fibList {
+		if fe.Name.String() == "/8=R" && assert.Len(fe.NextHops, 1) {
+			hasR = true
+			assert.Equal(udpID, fe.NextHops[0].FaceID)
+			assert.EqualValues(20, fe.NextHops[0].Cost)
+		}
+	}
+	assert.True(hasR)
+
+	_, e = client.CsInfo(ctx)
+	assert.NoError(e)
+
+	cr, e = client.Invoke(ctx, "faces/unknown", nfdmgmt.ControlParameters{})
+	require.NoError(e)
+	assert.EqualValues(nfdmgmt.StatusNotSupport, cr.StatusCode)
+
+	// faces/destroy deletes routes
+	require.NoError(client.DestroyFace(ctx, udpID))
+	assert.Eventually(func() bool { return fixture.Fib.Find(ndn.ParseName("/R")) == nil }, 2*time.Second, 50*time.Millisecond)
+
+	// non-local face cannot reach NfdMgmt
+	face := intface.MustNew()
+	defer must.Close(face.D)
+	face.Tx <- ndn.MakeInterest("/localhost/nfd/faces/list", ndn.CanBePrefixFlag)
+	select {
+	case pkt := <-face.Rx:
+		assert.Fail("unexpected packet", pkt)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
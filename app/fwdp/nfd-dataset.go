@@ -0,0 +1,193 @@
+package fwdp
+
+import (
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/iface"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+	"go.uber.org/zap"
+)
+
+const (
+	// nfdDatasetSegmentLen is the maximum payload length in each status dataset segment.
+	nfdDatasetSegmentLen = 4000
+
+	// nfdFreshness is the FreshnessPeriod of status dataset and notification packets.
+	nfdFreshness = time.Second
+
+	// nfdFaceEventsCapacity is the number of recent notifications kept in faces/events stream.
+	nfdFaceEventsCapacity = 64
+)
+
+// nfdDatasetVersion is a generated version of a status dataset.
+type nfdDatasetVersion struct {
+	Version  ndn.NameComponent
+	Segments [][]byte
+}
+
+func makeNfdDatasetVersion(payload []byte) (dv nfdDatasetVersion) {
+	dv.Version = ndn.VersionConvention.MakeTime(time.Now())
+	for len(payload) > nfdDatasetSegmentLen {
+		dv.Segments = append(dv.Segments, payload[:nfdDatasetSegmentLen])
+		payload = payload[nfdDatasetSegmentLen:]
+	}
+	dv.Segments = append(dv.Segments, payload)
+	return dv
+}
+
+// serveDataset responds to a status dataset request.
+//
+// An Interest for the dataset prefix, which must have CanBePrefix, generates a new version
+// and returns its first segment. Subsequent segments are served from the latest version only.
+func (m *NfdMgmt) serveDataset(r nfdRequest, key string, h nfdDatasetHandler) {
+	m.datasetMutex.Lock()
+	defer m.datasetMutex.Unlock()
+
+	var dv nfdDatasetVersion
+	var seg uint64
+	switch rest := r.Rest(); len(rest) {
+	case 0:
+		if !r.CanBePrefix {
+			return
+		}
+		payload, e := tlv.EncodeFrom(h(m)...)
+		if e != nil {
+			nfdLogger.Warn("dataset encode error", zap.String("dataset", key), zap.Error(e))
+			return
+		}
+		dv = makeNfdDatasetVersion(payload)
+		m.datasets[key] = dv
+	case 2:
+		var ok bool
+		dv, ok = m.datasets[key]
+		if !ok || !dv.Version.Equal(rest[0]) {
+			return
+		}
+		if seg, ok = ndn.SegmentConvention.Parse(rest[1]); !ok || seg >= uint64(len(dv.Segments)) {
+			return
+		}
+	default:
+		return
+	}
+
+	name := r.Name[:len(NfdMgmtPrefix)+2].Append(dv.Version, ndn.SegmentConvention.Make(seg))
+	lastSeg := ndn.SegmentConvention.Make(uint64(len(dv.Segments) - 1))
+	r.Respond(ndn.MakeData(r.Interest, name, nfdFreshness, ndn.FinalBlock(lastSeg), dv.Segments[seg]))
+}
+
+// nfdFaceEvents contains faces/events notification stream state.
+type nfdFaceEvents struct {
+	closed  bool
+	nextSeq uint64
+	recent  []nfdFaceEvent
+	pending []nfdPendingRequest
+}
+
+// nfdFaceEvent is a notification in faces/events stream.
+type nfdFaceEvent struct {
+	Seq  uint64
+	Wire []byte
+}
+
+// nfdPendingRequest is a faces/events request waiting for a future notification.
+type nfdPendingRequest struct {
+	nfdRequest
+	Expire time.Time
+}
+
+func (m *NfdMgmt) makeFaceEventData(r nfdRequest, ev nfdFaceEvent) ndn.Data {
+	name := r.Name[:len(NfdMgmtPrefix)+2].Append(ndn.SequenceNumConvention.Make(ev.Seq))
+	return ndn.MakeData(r.Interest, name, nfdFreshness, ev.Wire)
+}
+
+// serveFaceEvent responds to a faces/events request.
+//
+// An Interest for the stream prefix, which must have CanBePrefix, retrieves the latest notification.
+// An Interest for a specific sequence number retrieves that notification if it is still kept,
+// or waits for it if it has not been published.
+func (m *NfdMgmt) serveFaceEvent(r nfdRequest) {
+	m.eventMutex.Lock()
+	defer m.eventMutex.Unlock()
+	now := time.Now()
+	m.events.expirePending(now)
+
+	var seq uint64
+	switch rest := r.Rest(); len(rest) {
+	case 0:
+		if !r.CanBePrefix {
+			return
+		}
+		if n := len(m.events.recent); n > 0 {
+			r.Respond(m.makeFaceEventData(r, m.events.recent[n-1]))
+			return
+		}
+		seq = m.events.nextSeq
+	case 1:
+		var ok bool
+		if seq, ok = ndn.SequenceNumConvention.Parse(rest[0]); !ok {
+			return
+		}
+		for _, ev := range m.events.recent {
+			if ev.Seq == seq {
+				r.Respond(m.makeFaceEventData(r, ev))
+				return
+			}
+		}
+	default:
+		return
+	}
+
+	if seq >= m.events.nextSeq {
+		m.events.pending = append(m.events.pending, nfdPendingRequest{
+			nfdRequest: r,
+			Expire:     now.Add(r.ApplyDefaultLifetime()),
+		})
+	}
+}
+
+func (evs *nfdFaceEvents) expirePending(now time.Time) {
+	pending := evs.pending[:0]
+	for _, p := range evs.pending {
+		if p.Expire.After(now) {
+			pending = append(pending, p)
+		}
+	}
+	evs.pending = pending
+}
+
+// publishFaceEvent appends a notification to faces/events stream.
+func (m *NfdMgmt) publishFaceEvent(kind uint64, id iface.ID) {
+	n, ok := m.makeFaceEvent(kind, id)
+	if !ok {
+		return
+	}
+	wire, e := tlv.EncodeFrom(n)
+	if e != nil {
+		return
+	}
+
+	m.eventMutex.Lock()
+	defer m.eventMutex.Unlock()
+	if m.events.closed {
+		return
+	}
+	m.events.expirePending(time.Now())
+
+	ev := nfdFaceEvent{Seq: m.events.nextSeq, Wire: wire}
+	m.events.nextSeq++
+	m.events.recent = append(m.events.recent, ev)
+	if len(m.events.recent) > nfdFaceEventsCapacity {
+		m.events.recent = m.events.recent[len(m.events.recent)-nfdFaceEventsCapacity:]
+	}
+
+	pending := m.events.pending[:0]
+	for _, p := range m.events.pending {
+		if data := m.makeFaceEventData(p.nfdRequest, ev); data.CanSatisfy(p.Interest) {
+			p.Respond(data)
+		} else {
+			pending = append(pending, p)
+		}
+	}
+	m.events.pending = pending
+}
@@ -0,0 +1,206 @@
+package fwdp
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/usnistgov/ndn-dpdk/iface"
+	"github.com/usnistgov/ndn-dpdk/iface/socketface"
+	"github.com/usnistgov/ndn-dpdk/ndn/mgmt/nfdmgmt"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// nfdFaceInfo contains NFD face properties derived from an iface.Face.
+type nfdFaceInfo struct {
+	FaceID    uint64
+	URI       string
+	LocalURI  string
+	FaceScope uint64
+}
+
+func makeNfdFaceInfo(face iface.Face) (info nfdFaceInfo) {
+	info.FaceID = uint64(face.ID())
+	switch loc := face.Locator().(type) {
+	case socketface.Locator:
+		switch loc.Network {
+		case socketface.NetworkUnix:
+			info.URI = fmt.Sprintf("fd://%d", face.ID())
+			info.LocalURI = "unix://" + loc.Local
+			info.FaceScope = nfdmgmt.FaceScopeLocal
+		case socketface.NetworkUDP, socketface.NetworkTCP:
+			info.URI = makeNfdSocketURI(loc.Network, loc.Remote)
+			info.LocalURI = makeNfdSocketURI(loc.Network, loc.Local)
+		default:
+			if strings.Contains(loc.Remote, "://") {
+				info.URI = loc.Remote
+			} else {
+				info.URI = loc.Network + "://" + loc.Remote
+			}
+			info.LocalURI = loc.Network + "://" + loc.Local
+		}
+	default:
+		info.URI = fmt.Sprintf("%s://%d", face.Locator().Scheme(), face.ID())
+		info.LocalURI = info.URI
+	}
+	return
+}
+
+// makeNfdSocketURI constructs NFD FaceUri of UDP or TCP socket address.
+func makeNfdSocketURI(network, addr string) string {
+	host, _, e := net.SplitHostPort(addr)
+	if ip := net.ParseIP(host); e == nil && ip != nil && ip.To4() == nil {
+		return network + "6://" + addr
+	}
+	return network + "4://" + addr
+}
+
+// parseNfdFaceURI converts NFD FaceUri to socketface.Locator.
+func parseNfdFaceURI(uri, localURI string) (loc socketface.Locator, ok bool) {
+	u, e := url.Parse(uri)
+	if e != nil {
+		return loc, false
+	}
+
+	switch u.Scheme {
+	case "udp4", "udp6":
+		loc.Network, loc.Remote = socketface.NetworkUDP, u.Host
+	case "tcp4", "tcp6":
+		loc.Network, loc.Remote = socketface.NetworkTCP, u.Host
+	case socketface.NetworkWebSocket, socketface.NetworkWebSocketSecure:
+		loc.Network, loc.Remote = u.Scheme, uri
+		return loc, true
+	default:
+		return loc, false
+	}
+
+	if localURI != "" {
+		lu, e := url.Parse(localURI)
+		if e != nil || lu.Scheme != u.Scheme {
+			return loc, false
+		}
+		loc.Local = lu.Host
+	}
+	return loc, loc.Validate() == nil
+}
+
+func (info nfdFaceInfo) ControlParameters(flags uint64) *nfdmgmt.ControlParameters {
+	persistency := uint64(nfdmgmt.FacePersistencyPersistent)
+	return &nfdmgmt.ControlParameters{
+		FaceID:          info.FaceID,
+		URI:             info.URI,
+		LocalURI:        info.LocalURI,
+		FacePersistency: &persistency,
+		Flags:           &flags,
+	}
+}
+
+// findNfdFace finds an existing socket face with same network and remote address.
+func findNfdFace(loc socketface.Locator) iface.Face {
+	for _, face := range iface.List() {
+		if existing, ok := face.Locator().(socketface.Locator); ok &&
+			existing.Network == loc.Network && existing.Remote == loc.Remote {
+			return face
+		}
+	}
+	return nil
+}
+
+func (m *NfdMgmt) faceCreate(r nfdRequest, cp nfdmgmt.ControlParameters) nfdmgmt.ControlResponse {
+	loc, ok := parseNfdFaceURI(cp.URI, cp.LocalURI)
+	if !ok {
+		return nfdResponse(nfdmgmt.StatusNotAcceptable, "unsupported FaceUri", nil)
+	}
+	if cp.FacePersistency != nil && *cp.FacePersistency == nfdmgmt.FacePersistencyOnDemand {
+		return nfdResponse(nfdmgmt.StatusNotAcceptable, "on-demand persistency is not supported", nil)
+	}
+
+	if face := findNfdFace(loc); face != nil {
+		return nfdResponse(nfdmgmt.StatusConflict, "face exists", makeNfdFaceInfo(face).ControlParameters(0))
+	}
+
+	cfg := m.faceCfg
+	cfg.MTU = int(cp.MTU)
+	flags := uint64(0)
+	if cp.Flags != nil && cp.Mask != nil && *cp.Mask&nfdmgmt.FaceFlagLpReliability != 0 {
+		cfg.Reliability.Enabled = *cp.Flags&nfdmgmt.FaceFlagLpReliability != 0
+		if cfg.Reliability.Enabled {
+			flags |= nfdmgmt.FaceFlagLpReliability
+		}
+	}
+	loc.Config = &cfg
+
+	face, e := loc.CreateFace()
+	if e != nil {
+		return nfdResponse(nfdmgmt.StatusInternal, e.Error(), nil)
+	}
+	body := makeNfdFaceInfo(face).ControlParameters(flags)
+	body.MTU = cp.MTU
+	return nfdResponse(nfdmgmt.StatusOK, "OK", body)
+}
+
+func (m *NfdMgmt) faceUpdate(r nfdRequest, cp nfdmgmt.ControlParameters) nfdmgmt.ControlResponse {
+	id := iface.ID(cp.FaceID)
+	if cp.FaceID == 0 {
+		id = r.FaceID
+	}
+	face := iface.Get(id)
+	if face == nil {
+		return nfdResponse(nfdmgmt.StatusNotFound, "face not found", nil)
+	}
+
+	body := makeNfdFaceInfo(face).ControlParameters(0)
+	if cp.FacePersistency != nil && *cp.FacePersistency != *body.FacePersistency ||
+		cp.Mask != nil && *cp.Mask != 0 || cp.MTU != 0 {
+		return nfdResponse(nfdmgmt.StatusConflict, "face properties cannot be changed", body)
+	}
+	return nfdResponse(nfdmgmt.StatusOK, "OK", body)
+}
+
+func (m *NfdMgmt) faceDestroy(r nfdRequest, cp nfdmgmt.ControlParameters) nfdmgmt.ControlResponse {
+	if face := iface.Get(iface.ID(cp.FaceID)); face != nil {
+		face.Close()
+	}
+	return nfdResponse(nfdmgmt.StatusOK, "OK", &nfdmgmt.ControlParameters{FaceID: cp.FaceID})
+}
+
+func (m *NfdMgmt) faceList() (list []tlv.Fielder) {
+	for _, face := range iface.List() {
+		info, cnt := makeNfdFaceInfo(face), face.Counters()
+		list = append(list, nfdmgmt.FaceStatus{
+			FaceID:          info.FaceID,
+			URI:             info.URI,
+			LocalURI:        info.LocalURI,
+			FaceScope:       info.FaceScope,
+			FacePersistency: nfdmgmt.FacePersistencyPersistent,
+			LinkType:        nfdmgmt.LinkTypePointToPoint,
+			NInInterests:    cnt.RxInterests,
+			NInData:         cnt.RxData,
+			NInNacks:        cnt.RxNacks,
+			NOutInterests:   cnt.TxInterests,
+			NOutData:        cnt.TxData,
+			NOutNacks:       cnt.TxNacks,
+			NInBytes:        cnt.RxOctets,
+			NOutBytes:       cnt.TxOctets,
+		})
+	}
+	return list
+}
+
+func (m *NfdMgmt) makeFaceEvent(kind uint64, id iface.ID) (n nfdmgmt.FaceEventNotification, ok bool) {
+	face := iface.Get(id)
+	if face == nil {
+		return n, false
+	}
+	info := makeNfdFaceInfo(face)
+	return nfdmgmt.FaceEventNotification{
+		Kind:            kind,
+		FaceID:          info.FaceID,
+		URI:             info.URI,
+		LocalURI:        info.LocalURI,
+		FaceScope:       info.FaceScope,
+		FacePersistency: nfdmgmt.FacePersistencyPersistent,
+		LinkType:        nfdmgmt.LinkTypePointToPoint,
+	}, true
+}
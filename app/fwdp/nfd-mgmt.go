@@ -0,0 +1,455 @@
+package fwdp
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+	"github.com/usnistgov/ndn-dpdk/container/fib/fibdef"
+	"github.com/usnistgov/ndn-dpdk/container/strategycode"
+	"github.com/usnistgov/ndn-dpdk/core/logging"
+	"github.com/usnistgov/ndn-dpdk/iface"
+	"github.com/usnistgov/ndn-dpdk/iface/intface"
+	"github.com/usnistgov/ndn-dpdk/iface/socketface"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"github.com/usnistgov/ndn-dpdk/ndn/mgmt/nfdmgmt"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+var nfdLogger = logging.New("fwdp.nfdmgmt")
+
+// NfdMgmtPrefix is the name prefix of NFD management protocol.
+var NfdMgmtPrefix = ndn.ParseName("/localhost/nfd")
+
+var nfdMgmtPrefixValue, _ = NfdMgmtPrefix.MarshalBinary()
+
+const (
+	// nfdMaxHandlers is the maximum number of concurrently processed management requests.
+	// Further requests are dropped, and the application would retransmit them.
+	nfdMaxHandlers = 64
+
+	// nfdOriginsCapacity is the number of recent requests whose incoming face is remembered.
+	nfdOriginsCapacity = 1024
+
+	// nfdDefaultTrustSchema permits any signer, so that access is restricted by trust anchors only.
+	nfdDefaultTrustSchema = "<*> <= <*>"
+)
+
+// nfdCommandPolicy is the signed Interest policy of control commands, same as NFD.
+var nfdCommandPolicy = ndn.SignedInterestPolicy{Nonce: true, Time: true}
+
+// NfdMgmtConfig contains NFD management responder configuration.
+type NfdMgmtConfig struct {
+	// Unix configures the Unix socket listener for local applications.
+	// NFD management requests are accepted only from faces created by this listener.
+	Unix socketface.UnixListenerConfig `json:"unix,omitempty"`
+
+	// Anchors are trust anchor certificates in TLV format.
+	// In JSON, each certificate is base64 encoded, such as the output of 'ndnsec cert-dump' command.
+	// A control command is accepted only if its signer is certified by a trust anchor, either directly or through
+	// intermediate certificates retrieved via the forwarder.
+	// If empty, every control command is rejected, but status datasets are still served.
+	Anchors [][]byte `json:"anchors,omitempty"`
+
+	// TrustSchema contains trust schema rules in keychain.ParseTrustSchema syntax,
+	// which restrict the signers of control commands and intermediate certificates.
+	// Default permits any signer.
+	TrustSchema string `json:"trustSchema,omitempty"`
+}
+
+func (cfg NfdMgmtConfig) makeValidatorOptions() (opts keychain.ValidatorOptions, e error) {
+	schema := cfg.TrustSchema
+	if schema == "" {
+		schema = nfdDefaultTrustSchema
+	}
+	if opts.Schema, e = keychain.ParseTrustSchema(schema); e != nil {
+		return opts, fmt.Errorf("trustSchema: %w", e)
+	}
+
+	for i, wire := range cfg.Anchors {
+		cert, e := keychain.UnmarshalCert(wire)
+		if e != nil {
+			return opts, fmt.Errorf("anchors[%d]: %w", i, e)
+		}
+		opts.Anchors = append(opts.Anchors, cert)
+	}
+	return opts, nil
+}
+
+// NfdMgmt responds to NFD management protocol commands and dataset requests.
+//
+// Local applications designed for NFD connect to the Unix socket listener, and then send
+// Interests under /localhost/nfd prefix. The forwarder routes these Interests to an internal face,
+// on which NfdMgmt processes them. Supported modules are faces, fib, rib, strategy-choice, and cs.
+//
+// Control commands must be signed Interests with SigNonce and SigTime, verified against NfdMgmtConfig.Anchors.
+// The incoming face of each request is learned from the Unix socket listener, which allows a command to refer to
+// the requesting face with FaceId=0. Requests that did not arrive on a face of this listener are dropped.
+type NfdMgmt struct {
+	dp              *DataPlane
+	defaultStrategy *strategycode.Strategy
+	faceCfg         socketface.Config
+	ln              *socketface.UnixListener
+	face            *intface.IntFace
+	fwFace          l3.FwFace
+	lface           *endpoint.LFace
+	verifier        ndn.Verifier
+	handlers        chan struct{}
+	closing         chan struct{}
+	rxDone          chan struct{}
+	cancelEvents    []func()
+
+	originMutex sync.Mutex
+	origins     *simplelru.LRU // Interest name string => iface.ID
+
+	ribMutex   sync.Mutex
+	closed     bool
+	rib        map[string]*nfdRibEntry
+	strategies map[string]nfdStrategyChoice
+
+	datasetMutex sync.Mutex
+	datasets     map[string]nfdDatasetVersion
+
+	eventMutex sync.Mutex
+	events     nfdFaceEvents
+}
+
+// NewNfdMgmt creates an NFD management responder on a data plane.
+// defaultStrategy is the strategy of the root namespace.
+func NewNfdMgmt(dp *DataPlane, defaultStrategy *strategycode.Strategy, cfg NfdMgmtConfig) (m *NfdMgmt, e error) {
+	validatorOpts, e := cfg.makeValidatorOptions()
+	if e != nil {
+		return nil, e
+	}
+
+	m = &NfdMgmt{
+		dp:              dp,
+		defaultStrategy: defaultStrategy,
+		faceCfg:         cfg.Unix.Face,
+		rib:             map[string]*nfdRibEntry{},
+		strategies:      map[string]nfdStrategyChoice{},
+		datasets:        map[string]nfdDatasetVersion{},
+		handlers:        make(chan struct{}, nfdMaxHandlers),
+		closing:         make(chan struct{}),
+		rxDone:          make(chan struct{}),
+	}
+	m.origins, _ = simplelru.NewLRU(nfdOriginsCapacity, nil)
+	defer func(m *NfdMgmt) {
+		if e != nil {
+			m.Close()
+		}
+	}(m)
+
+	// internal face: DPDK forwarder <=> m.face <=> NDNgo forwarder <=> m.lface
+	if m.face, e = intface.New(socketface.Config{}); e != nil {
+		return nil, e
+	}
+	fw := l3.NewForwarder()
+	if m.fwFace, e = fw.AddFace(m.face.A); e != nil {
+		return nil, e
+	}
+	m.fwFace.AddRoute(ndn.Name{}) // retrieve certificates via DPDK forwarder
+	if m.lface, e = endpoint.NewLFace(fw); e != nil {
+		return nil, e
+	}
+	m.lface.FwFace.AddRoute(NfdMgmtPrefix)
+	go m.rxLoop()
+
+	validatorOpts.Consumer.Fw = fw
+	m.verifier = nfdCommandPolicy.Verifier(keychain.NewValidator(validatorOpts))
+
+	if e = dp.Fib().Insert(fibdef.Entry{
+		Name:     NfdMgmtPrefix,
+		Nexthops: []iface.ID{m.face.ID},
+		Strategy: defaultStrategy.ID(),
+	}); e != nil {
+		return nil, e
+	}
+
+	lnCfg := cfg.Unix
+	lnCfg.RxObserver = m.observeRx
+	if m.ln, e = socketface.ListenUnix(lnCfg); e != nil {
+		return nil, e
+	}
+
+	m.cancelEvents = append(m.cancelEvents,
+		iface.OnFaceNew(func(id iface.ID) { m.publishFaceEvent(nfdmgmt.FaceEventCreated, id) }),
+		iface.OnFaceUp(func(id iface.ID) { m.publishFaceEvent(nfdmgmt.FaceEventUp, id) }),
+		iface.OnFaceDown(func(id iface.ID) { m.publishFaceEvent(nfdmgmt.FaceEventDown, id) }),
+		iface.OnFaceClosing(func(id iface.ID) {
+			m.publishFaceEvent(nfdmgmt.FaceEventDestroyed, id)
+			go m.removeFaceRoutes(id)
+		}),
+	)
+	return m, nil
+}
+
+// Close stops the NFD management responder.
+// FIB entries created via rib/register commands are retained.
+func (m *NfdMgmt) Close() error {
+	errs := []error{}
+	if m.ln != nil {
+		errs = append(errs, m.ln.Close())
+		m.ln = nil
+	}
+	for _, cancel := range m.cancelEvents {
+		cancel()
+	}
+	m.cancelEvents = nil
+
+	m.ribMutex.Lock()
+	m.closed = true
+	m.ribMutex.Unlock()
+	if m.dp.Fib().Find(NfdMgmtPrefix) != nil {
+		errs = append(errs, m.dp.Fib().Erase(NfdMgmtPrefix))
+	}
+
+	if m.lface != nil {
+		close(m.closing)
+		<-m.rxDone
+		for i := 0; i < cap(m.handlers); i++ { // wait for running handlers
+			m.handlers <- struct{}{}
+		}
+		m.eventMutex.Lock()
+		m.events.closed, m.events.pending = true, nil
+		m.eventMutex.Unlock()
+		errs = append(errs, m.lface.Close())
+		m.lface = nil
+	}
+	if m.fwFace != nil {
+		errs = append(errs, m.fwFace.Close())
+		m.fwFace = nil
+	}
+	if m.face != nil {
+		errs = append(errs, m.face.D.Close())
+		m.face = nil
+	}
+	return multierr.Combine(errs...)
+}
+
+// observeRx implements socketface.RxObserver.
+// It remembers the incoming face of /localhost/nfd Interests received on the Unix socket listener.
+func (m *NfdMgmt) observeRx(id iface.ID, wire []byte) {
+	if !isNfdMgmtInterest(wire) {
+		return
+	}
+	var pkt ndn.Packet
+	if e := tlv.Decode(wire, &pkt); e != nil || pkt.Interest == nil {
+		return
+	}
+
+	m.originMutex.Lock()
+	defer m.originMutex.Unlock()
+	m.origins.Add(pkt.Interest.Name.String(), id)
+}
+
+// originOf returns the incoming face of a request.
+func (m *NfdMgmt) originOf(name ndn.Name) (id iface.ID, ok bool) {
+	m.originMutex.Lock()
+	defer m.originMutex.Unlock()
+	value, ok := m.origins.Get(name.String())
+	if !ok {
+		return 0, false
+	}
+	return value.(iface.ID), true
+}
+
+// rxLoop receives requests forwarded to the internal face.
+func (m *NfdMgmt) rxLoop() {
+	defer close(m.rxDone)
+	for {
+		var l3pkt ndn.L3Packet
+		select {
+		case <-m.closing:
+			return
+		case l3pkt = <-m.lface.Rx():
+		}
+
+		pkt := l3pkt.ToPacket()
+		if pkt.Interest == nil || !NfdMgmtPrefix.IsPrefixOf(pkt.Interest.Name) {
+			continue
+		}
+		id, ok := m.originOf(pkt.Interest.Name)
+		if !ok { // drop /localhost packets from non-local faces
+			continue
+		}
+
+		r := nfdRequest{
+			Interest: *pkt.Interest,
+			FaceID:   id,
+			lp:       pkt.Lp,
+			reply:    m.reply,
+		}
+		select {
+		case m.handlers <- struct{}{}:
+			go func() {
+				defer func() { <-m.handlers }()
+				m.handle(r)
+			}()
+		default: // too many pending requests
+		}
+	}
+}
+
+// reply sends a packet toward the DPDK forwarder.
+func (m *NfdMgmt) reply(pkt *ndn.Packet) {
+	select {
+	case m.lface.Tx() <- pkt:
+	case <-m.closing:
+	}
+}
+
+// isNfdMgmtInterest quickly determines whether a packet is an Interest under NfdMgmtPrefix.
+func isNfdMgmtInterest(wire []byte) bool {
+	d := tlv.DecodingBuffer(wire)
+	de, e := d.Element()
+	if e != nil {
+		return false
+	}
+
+	if de.Type == an.TtLpPacket {
+		d = tlv.DecodingBuffer(de.Value)
+		found := false
+		for _, field := range d.Elements() {
+			if field.Type == an.TtLpPayload {
+				de.Value, found = field.Value, true
+				d = tlv.DecodingBuffer(field.Value)
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+		if de, e = d.Element(); e != nil {
+			return false
+		}
+	}
+
+	if de.Type != an.TtInterest {
+		return false
+	}
+	d = tlv.DecodingBuffer(de.Value)
+	if de, e = d.Element(); e != nil || de.Type != an.TtName {
+		return false
+	}
+	return len(de.Value) > len(nfdMgmtPrefixValue) && bytes.HasPrefix(de.Value, nfdMgmtPrefixValue)
+}
+
+// nfdRequest represents an incoming Interest under NfdMgmtPrefix.
+type nfdRequest struct {
+	ndn.Interest
+	FaceID iface.ID
+
+	lp    ndn.LpL3
+	reply func(pkt *ndn.Packet)
+}
+
+// Module returns the management module name.
+func (r nfdRequest) Module() string {
+	return string(r.Name.Get(len(NfdMgmtPrefix)).Value)
+}
+
+// Verb returns the command verb or dataset name.
+func (r nfdRequest) Verb() string {
+	return string(r.Name.Get(len(NfdMgmtPrefix) + 1).Value)
+}
+
+// Rest returns name components after the verb.
+func (r nfdRequest) Rest() ndn.Name {
+	if len(r.Name) <= len(NfdMgmtPrefix)+2 {
+		return nil
+	}
+	return r.Name[len(NfdMgmtPrefix)+2:]
+}
+
+// Respond sends a Data packet.
+func (r nfdRequest) Respond(data ndn.Data) {
+	if e := ndn.DigestSigning.Sign(&data); e != nil {
+		return
+	}
+	r.reply(&ndn.Packet{Lp: r.lp, Data: &data})
+}
+
+// nfdCommandHandler handles a control command.
+type nfdCommandHandler func(m *NfdMgmt, r nfdRequest, cp nfdmgmt.ControlParameters) nfdmgmt.ControlResponse
+
+var nfdCommands = map[string]nfdCommandHandler{
+	"faces/create":          (*NfdMgmt).faceCreate,
+	"faces/update":          (*NfdMgmt).faceUpdate,
+	"faces/destroy":         (*NfdMgmt).faceDestroy,
+	"rib/register":          (*NfdMgmt).ribRegister,
+	"rib/unregister":        (*NfdMgmt).ribUnregister,
+	"strategy-choice/set":   (*NfdMgmt).strategySet,
+	"strategy-choice/unset": (*NfdMgmt).strategyUnset,
+}
+
+// nfdDatasetHandler generates a status dataset.
+type nfdDatasetHandler func(m *NfdMgmt) []tlv.Fielder
+
+var nfdDatasets = map[string]nfdDatasetHandler{
+	"faces/list":           (*NfdMgmt).faceList,
+	"fib/list":             (*NfdMgmt).fibList,
+	"rib/list":             (*NfdMgmt).ribList,
+	"strategy-choice/list": (*NfdMgmt).strategyList,
+	"cs/info":              (*NfdMgmt).csInfo,
+}
+
+func (m *NfdMgmt) handle(r nfdRequest) {
+	if len(r.Name) < len(NfdMgmtPrefix)+2 {
+		return
+	}
+	key := r.Module() + "/" + r.Verb()
+
+	if h := nfdCommands[key]; h != nil {
+		rest := r.Rest()
+		if len(rest) == 0 {
+			return
+		}
+		var cp nfdmgmt.ControlParameters
+		var cr nfdmgmt.ControlResponse
+		if e := m.verifier.Verify(r.Interest); e != nil {
+			nfdLogger.Info("command rejected", zap.String("command", key), r.FaceID.ZapField("face"), zap.Error(e))
+			cr = nfdResponse(nfdmgmt.StatusForbidden, "command Interest verification failed", nil)
+		} else if e := tlv.Decode(rest[0].Value, &cp); e != nil {
+			cr = nfdResponse(nfdmgmt.StatusBadParams, "malformed ControlParameters", nil)
+		} else {
+			cr = h(m, r, cp)
+		}
+		m.respondControl(r, cr)
+		return
+	}
+
+	if h := nfdDatasets[key]; h != nil {
+		m.serveDataset(r, key, h)
+		return
+	}
+
+	if key == "faces/events" {
+		m.serveFaceEvent(r)
+		return
+	}
+
+	if len(r.Rest()) > 0 {
+		m.respondControl(r, nfdResponse(nfdmgmt.StatusNotSupport, "unknown command", nil))
+	}
+}
+
+func (m *NfdMgmt) respondControl(r nfdRequest, cr nfdmgmt.ControlResponse) {
+	wire, e := tlv.EncodeFrom(cr)
+	if e != nil {
+		nfdLogger.Warn("encode error", zap.Error(e))
+		return
+	}
+	r.Respond(ndn.MakeData(r.Interest, wire))
+}
+
+func nfdResponse(code uint, text string, body *nfdmgmt.ControlParameters) nfdmgmt.ControlResponse {
+	return nfdmgmt.ControlResponse{StatusCode: code, StatusText: text, Body: body}
+}
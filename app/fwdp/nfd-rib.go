@@ -0,0 +1,406 @@
+package fwdp
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/container/cs/cscnt"
+	"github.com/usnistgov/ndn-dpdk/container/fib/fibdef"
+	"github.com/usnistgov/ndn-dpdk/container/strategycode"
+	"github.com/usnistgov/ndn-dpdk/iface"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/mgmt/nfdmgmt"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+	"go.uber.org/zap"
+)
+
+// NfdStrategyPrefix is the name prefix of strategy names in NFD management protocol.
+// The component after this prefix is the strategy name known to strategycode package.
+var NfdStrategyPrefix = NfdMgmtPrefix.Append(ndn.ParseNameComponent("strategy"))
+
+var nfdStrategyShortName = regexp.MustCompile(`^[0-9A-Za-z_-]+$`)
+
+// nfdRoute is a route in nfdRibEntry.
+type nfdRoute struct {
+	FaceID  iface.ID
+	Origin  uint64
+	Cost    uint64
+	Flags   uint64
+	Expires time.Time // zero means never
+}
+
+// ExpirationPeriod returns remaining lifetime in milliseconds, or nil if the route does not expire.
+func (r nfdRoute) ExpirationPeriod(now time.Time) *uint64 {
+	if r.Expires.IsZero() {
+		return nil
+	}
+	ms := uint64(0)
+	if d := r.Expires.Sub(now); d > 0 {
+		ms = uint64(d / time.Millisecond)
+	}
+	return &ms
+}
+
+// nfdRibEntry is a RIB entry.
+// It is mapped to a FIB entry whose nexthops are sorted by ascending cost.
+type nfdRibEntry struct {
+	Name   ndn.Name
+	Routes []nfdRoute
+}
+
+// put inserts or replaces a route with same FaceID and Origin.
+// If a route is replaced, returns the old route.
+func (entry *nfdRibEntry) put(route nfdRoute) (old nfdRoute, replaced bool) {
+	for i, r := range entry.Routes {
+		if r.FaceID == route.FaceID && r.Origin == route.Origin {
+			entry.Routes[i] = route
+			return r, true
+		}
+	}
+	entry.Routes = append(entry.Routes, route)
+	return nfdRoute{}, false
+}
+
+// remove deletes routes matching a predicate.
+// Returns true if any route was deleted.
+func (entry *nfdRibEntry) remove(pred func(r nfdRoute) bool) bool {
+	routes := entry.Routes[:0]
+	for _, r := range entry.Routes {
+		if !pred(r) {
+			routes = append(routes, r)
+		}
+	}
+	removed := len(routes) != len(entry.Routes)
+	entry.Routes = routes
+	return removed
+}
+
+// Nexthops returns FIB nexthops ordered by ascending cost.
+func (entry *nfdRibEntry) Nexthops() (nexthops []iface.ID) {
+	routes := append([]nfdRoute{}, entry.Routes...)
+	sort.SliceStable(routes, func(i, j int) bool { return routes[i].Cost < routes[j].Cost })
+	seen := map[iface.ID]bool{}
+	for _, r := range routes {
+		if seen[r.FaceID] || len(nexthops) >= fibdef.MaxNexthops {
+			continue
+		}
+		seen[r.FaceID] = true
+		nexthops = append(nexthops, r.FaceID)
+	}
+	return nexthops
+}
+
+// Cost returns the lowest cost toward a nexthop.
+func (entry *nfdRibEntry) Cost(nh iface.ID) (cost uint64, ok bool) {
+	for _, r := range entry.Routes {
+		if r.FaceID == nh && (!ok || r.Cost < cost) {
+			cost, ok = r.Cost, true
+		}
+	}
+	return
+}
+
+// nfdStrategyChoice is a strategy choice entry.
+type nfdStrategyChoice struct {
+	Name     ndn.Name
+	Strategy *strategycode.Strategy
+}
+
+func makeNfdStrategyName(sc *strategycode.Strategy) ndn.Name {
+	return NfdStrategyPrefix.Append(ndn.ParseNameComponent(sc.Name()))
+}
+
+// findNfdStrategy finds or loads a strategy by NFD strategy name.
+func findNfdStrategy(name ndn.Name) (*strategycode.Strategy, error) {
+	if len(name) <= len(NfdStrategyPrefix) || !NfdStrategyPrefix.IsPrefixOf(name) {
+		return nil, errors.New("bad strategy name")
+	}
+	shortName := string(name[len(NfdStrategyPrefix)].Value)
+	if !nfdStrategyShortName.MatchString(shortName) {
+		return nil, errors.New("bad strategy name")
+	}
+
+	if sc := strategycode.Find(shortName); sc != nil {
+		return sc, nil
+	}
+	return strategycode.LoadFile(shortName, "")
+}
+
+// strategyFor determines the strategy of a name by longest prefix match.
+// Caller must hold ribMutex.
+func (m *NfdMgmt) strategyFor(name ndn.Name) *strategycode.Strategy {
+	for i := len(name); i > 0; i-- {
+		if sc, ok := m.strategies[name[:i].String()]; ok {
+			return sc.Strategy
+		}
+	}
+	return m.defaultStrategy
+}
+
+// applyRib updates FIB entry to reflect a RIB entry.
+// Caller must hold ribMutex.
+func (m *NfdMgmt) applyRib(entry *nfdRibEntry) error {
+	fib := m.dp.Fib()
+	if len(entry.Routes) == 0 {
+		delete(m.rib, entry.Name.String())
+		if fib.Find(entry.Name) == nil {
+			return nil
+		}
+		return fib.Erase(entry.Name)
+	}
+
+	var fe fibdef.Entry
+	fe.Name = entry.Name
+	fe.Nexthops = entry.Nexthops()
+	fe.Strategy = m.strategyFor(entry.Name).ID()
+	return fib.Insert(fe)
+}
+
+// expireRoutes deletes expired routes in a RIB entry.
+func (m *NfdMgmt) expireRoutes(name ndn.Name) {
+	m.ribMutex.Lock()
+	defer m.ribMutex.Unlock()
+	entry := m.rib[name.String()]
+	if m.closed || entry == nil {
+		return
+	}
+	now := time.Now()
+	if entry.remove(func(r nfdRoute) bool { return !r.Expires.IsZero() && !r.Expires.After(now) }) {
+		if e := m.applyRib(entry); e != nil {
+			nfdLogger.Warn("FIB update error", zap.Stringer("name", entry.Name), zap.Error(e))
+		}
+	}
+}
+
+// removeFaceRoutes deletes routes toward a face that is closing.
+func (m *NfdMgmt) removeFaceRoutes(id iface.ID) {
+	m.ribMutex.Lock()
+	defer m.ribMutex.Unlock()
+	for _, entry := range m.rib {
+		if entry.remove(func(r nfdRoute) bool { return r.FaceID == id }) {
+			if e := m.applyRib(entry); e != nil {
+				nfdLogger.Warn("FIB update error", id.ZapField("face"), zap.Stringer("name", entry.Name), zap.Error(e))
+			}
+		}
+	}
+}
+
+func (m *NfdMgmt) ribRegister(r nfdRequest, cp nfdmgmt.ControlParameters) nfdmgmt.ControlResponse {
+	route := nfdRoute{
+		FaceID: iface.ID(cp.FaceID),
+		Origin: nfdmgmt.RouteOriginApp,
+		Flags:  nfdmgmt.RouteFlagChildInherit,
+	}
+	if cp.FaceID == 0 {
+		route.FaceID = r.FaceID
+	}
+	if cp.Origin != nil {
+		route.Origin = *cp.Origin
+	}
+	if cp.Cost != nil {
+		route.Cost = *cp.Cost
+	}
+	if cp.Flags != nil {
+		route.Flags = *cp.Flags
+	}
+	var lifetime time.Duration
+	if cp.ExpirationPeriod != nil {
+		lifetime = time.Duration(*cp.ExpirationPeriod) * time.Millisecond
+		route.Expires = time.Now().Add(lifetime)
+	}
+	if iface.Get(route.FaceID) == nil {
+		return nfdResponse(nfdmgmt.StatusGone, "face not found", nil)
+	}
+
+	m.ribMutex.Lock()
+	defer m.ribMutex.Unlock()
+	key := cp.Name.String()
+	entry := m.rib[key]
+	if entry == nil {
+		entry = &nfdRibEntry{Name: cp.Name}
+		m.rib[key] = entry
+	}
+	old, replaced := entry.put(route)
+	if e := m.applyRib(entry); e != nil {
+		if replaced {
+			entry.put(old)
+		} else {
+			entry.remove(func(r nfdRoute) bool { return r.FaceID == route.FaceID && r.Origin == route.Origin })
+		}
+		m.applyRib(entry)
+		return nfdResponse(nfdmgmt.StatusInternal, e.Error(), nil)
+	}
+	if cp.ExpirationPeriod != nil {
+		time.AfterFunc(lifetime, func() { m.expireRoutes(cp.Name) })
+	}
+
+	return nfdResponse(nfdmgmt.StatusOK, "OK", &nfdmgmt.ControlParameters{
+		Name:             cp.Name,
+		FaceID:           uint64(route.FaceID),
+		Origin:           &route.Origin,
+		Cost:             &route.Cost,
+		Flags:            &route.Flags,
+		ExpirationPeriod: cp.ExpirationPeriod,
+	})
+}
+
+func (m *NfdMgmt) ribUnregister(r nfdRequest, cp nfdmgmt.ControlParameters) nfdmgmt.ControlResponse {
+	id, origin := iface.ID(cp.FaceID), uint64(nfdmgmt.RouteOriginApp)
+	if cp.FaceID == 0 {
+		id = r.FaceID
+	}
+	if cp.Origin != nil {
+		origin = *cp.Origin
+	}
+
+	m.ribMutex.Lock()
+	defer m.ribMutex.Unlock()
+	if entry := m.rib[cp.Name.String()]; entry != nil &&
+		entry.remove(func(r nfdRoute) bool { return r.FaceID == id && r.Origin == origin }) {
+		if e := m.applyRib(entry); e != nil {
+			return nfdResponse(nfdmgmt.StatusInternal, e.Error(), nil)
+		}
+	}
+
+	return nfdResponse(nfdmgmt.StatusOK, "OK", &nfdmgmt.ControlParameters{
+		Name:   cp.Name,
+		FaceID: uint64(id),
+		Origin: &origin,
+	})
+}
+
+func (m *NfdMgmt) ribList() (list []tlv.Fielder) {
+	m.ribMutex.Lock()
+	defer m.ribMutex.Unlock()
+	entries := make([]*nfdRibEntry, 0, len(m.rib))
+	for _, entry := range m.rib {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name.Compare(entries[j].Name) < 0 })
+
+	now := time.Now()
+	for _, entry := range entries {
+		re := nfdmgmt.RibEntry{Name: entry.Name}
+		for _, r := range entry.Routes {
+			re.Routes = append(re.Routes, nfdmgmt.Route{
+				FaceID:           uint64(r.FaceID),
+				Origin:           r.Origin,
+				Cost:             r.Cost,
+				Flags:            r.Flags,
+				ExpirationPeriod: r.ExpirationPeriod(now),
+			})
+		}
+		list = append(list, re)
+	}
+	return list
+}
+
+func (m *NfdMgmt) fibList() (list []tlv.Fielder) {
+	m.ribMutex.Lock()
+	defer m.ribMutex.Unlock()
+	for _, fe := range m.dp.Fib().List() {
+		entry := nfdmgmt.FibEntry{Name: fe.Name}
+		re := m.rib[fe.Name.String()]
+		for _, nh := range fe.Nexthops {
+			nhr := nfdmgmt.NextHopRecord{FaceID: uint64(nh)}
+			if re != nil {
+				nhr.Cost, _ = re.Cost(nh)
+			}
+			entry.NextHops = append(entry.NextHops, nhr)
+		}
+		list = append(list, entry)
+	}
+	return list
+}
+
+// updateFibStrategies updates strategy of FIB entries under a prefix after strategy choice changes.
+// Caller must hold ribMutex.
+func (m *NfdMgmt) updateFibStrategies(prefix ndn.Name) {
+	fib := m.dp.Fib()
+	for _, fe := range fib.List() {
+		if !prefix.IsPrefixOf(fe.Name) {
+			continue
+		}
+		if id := m.strategyFor(fe.Name).ID(); id != fe.Strategy {
+			fe.Strategy = id
+			if e := fib.Insert(fe.Entry); e != nil {
+				nfdLogger.Warn("FIB update error", zap.Stringer("name", fe.Name), zap.Error(e))
+			}
+		}
+	}
+}
+
+func (m *NfdMgmt) strategySet(r nfdRequest, cp nfdmgmt.ControlParameters) nfdmgmt.ControlResponse {
+	if cp.Strategy == nil {
+		return nfdResponse(nfdmgmt.StatusBadParams, "Strategy is missing", nil)
+	}
+	sc, e := findNfdStrategy(cp.Strategy.Name)
+	if e != nil {
+		return nfdResponse(nfdmgmt.StatusNotFound, "strategy not found", nil)
+	}
+
+	m.ribMutex.Lock()
+	defer m.ribMutex.Unlock()
+	if len(cp.Name) == 0 {
+		m.defaultStrategy = sc
+	} else {
+		m.strategies[cp.Name.String()] = nfdStrategyChoice{Name: cp.Name, Strategy: sc}
+	}
+	m.updateFibStrategies(cp.Name)
+
+	return nfdResponse(nfdmgmt.StatusOK, "OK", &nfdmgmt.ControlParameters{
+		Name:     cp.Name,
+		Strategy: &nfdmgmt.Strategy{Name: makeNfdStrategyName(sc)},
+	})
+}
+
+func (m *NfdMgmt) strategyUnset(r nfdRequest, cp nfdmgmt.ControlParameters) nfdmgmt.ControlResponse {
+	if len(cp.Name) == 0 {
+		return nfdResponse(nfdmgmt.StatusBadParams, "cannot unset root strategy", nil)
+	}
+
+	m.ribMutex.Lock()
+	defer m.ribMutex.Unlock()
+	delete(m.strategies, cp.Name.String())
+	m.updateFibStrategies(cp.Name)
+
+	return nfdResponse(nfdmgmt.StatusOK, "OK", &nfdmgmt.ControlParameters{Name: cp.Name})
+}
+
+func (m *NfdMgmt) strategyList() (list []tlv.Fielder) {
+	m.ribMutex.Lock()
+	defer m.ribMutex.Unlock()
+	list = append(list, nfdmgmt.StrategyChoice{
+		Name:     ndn.Name{},
+		Strategy: nfdmgmt.Strategy{Name: makeNfdStrategyName(m.defaultStrategy)},
+	})
+
+	choices := make([]nfdStrategyChoice, 0, len(m.strategies))
+	for _, sc := range m.strategies {
+		choices = append(choices, sc)
+	}
+	sort.Slice(choices, func(i, j int) bool { return choices[i].Name.Compare(choices[j].Name) < 0 })
+	for _, sc := range choices {
+		list = append(list, nfdmgmt.StrategyChoice{
+			Name:     sc.Name,
+			Strategy: nfdmgmt.Strategy{Name: makeNfdStrategyName(sc.Strategy)},
+		})
+	}
+	return list
+}
+
+func (m *NfdMgmt) csInfo() []tlv.Fielder {
+	info := nfdmgmt.CsInfo{
+		Flags: nfdmgmt.CsFlagEnableAdmit | nfdmgmt.CsFlagEnableServe,
+	}
+	for _, fwd := range m.dp.Fwds() {
+		cnt := cscnt.ReadCounters(fwd.Pit(), fwd.Cs())
+		info.Capacity += uint64(cnt.DirectCapacity)
+		info.NCsEntries += uint64(cnt.DirectEntries)
+		info.NHits += cnt.NHits
+		info.NMisses += cnt.NMisses
+	}
+	return []tlv.Fielder{info}
+}
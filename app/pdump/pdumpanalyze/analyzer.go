@@ -0,0 +1,284 @@
+package pdumpanalyze
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+)
+
+// Options contains Analyzer options.
+type Options struct {
+	// PrefixLen is the number of name components in the prefix that statistics are aggregated by.
+	// Default is 1.
+	PrefixLen int
+
+	// TopN is the number of most frequently requested names to report.
+	// Default is 10.
+	TopN int
+}
+
+func (opts *Options) applyDefaults() {
+	if opts.PrefixLen <= 0 {
+		opts.PrefixLen = 1
+	}
+	if opts.TopN <= 0 {
+		opts.TopN = 10
+	}
+}
+
+// pendingKey identifies a pending Interest by PIT token.
+type pendingKey struct {
+	intf  string
+	dir   Direction
+	token string
+}
+
+// pendingNameKey identifies a pending Interest by name.
+type pendingNameKey struct {
+	intf string
+	dir  Direction
+	name string
+}
+
+// pendingInterest is an Interest that has not been answered.
+// Retransmissions of the same name on the same interface and direction are folded into one entry.
+type pendingInterest struct {
+	interest ndn.Interest
+	nameKey  pendingNameKey
+	tokens   []string
+	txTime   time.Time
+	expiry   time.Time // InterestLifetime expiry of the latest transmission
+	nRetx    int
+	prefix   *prefixCounters
+	name     *NameStats
+}
+
+type prefixCounters struct {
+	PrefixStats
+	rtts []time.Duration
+}
+
+// Analyzer matches Interests with Data and Nacks, and collects statistics.
+//
+// An Interest on an interface is matched with a Data or Nack on the same interface in the opposite direction.
+// Matching uses the NDNLPv2 PIT token if present, and falls back to the Interest name otherwise.
+// An Interest with the same name as a pending Interest on the same interface and direction, sent within the
+// InterestLifetime of the previous transmission, is counted as a retransmission; after the InterestLifetime,
+// the previous Interest is counted as a timeout. RTT is measured only on Interests that are not retransmitted.
+type Analyzer struct {
+	opts       Options
+	byToken    map[pendingKey]*pendingInterest
+	byName     map[pendingNameKey]*pendingInterest
+	prefixes   map[string]*prefixCounters
+	names      map[string]*NameStats
+	nUnmatched UnmatchedCounters
+}
+
+// Add processes a packet.
+func (a *Analyzer) Add(rec Record) {
+	switch {
+	case rec.Interest != nil:
+		a.addInterest(rec, *rec.Interest)
+	case rec.Data != nil:
+		if p := a.matchData(rec, *rec.Data); p != nil {
+			p.prefix.NData++
+			p.name.NData++
+			if p.nRetx == 0 {
+				p.prefix.rtts = append(p.prefix.rtts, rec.Time.Sub(p.txTime))
+			}
+		} else {
+			a.nUnmatched.NData++
+		}
+	case rec.Nack != nil:
+		if p := a.matchNack(rec, *rec.Nack); p != nil {
+			p.prefix.NNacks++
+			p.name.NNacks++
+		} else {
+			a.nUnmatched.NNacks++
+		}
+	}
+}
+
+func (a *Analyzer) addInterest(rec Record, interest ndn.Interest) {
+	nameV, _ := interest.Name.MarshalBinary()
+	nameKey := pendingNameKey{rec.Intf, rec.Dir, string(nameV)}
+	token := string(rec.Lp.PitToken)
+
+	p := a.byName[nameKey]
+	if p != nil && !rec.Time.Before(p.expiry) {
+		a.remove(p)
+		p.prefix.NTimeouts++
+		p.name.NTimeouts++
+		p = nil
+	}
+
+	if p == nil {
+		p = &pendingInterest{
+			interest: interest,
+			nameKey:  nameKey,
+			txTime:   rec.Time,
+			prefix:   a.prefixCounters(interest.Name),
+			name:     a.nameStats(interest.Name, nameKey.name),
+		}
+		a.byName[nameKey] = p
+	} else {
+		p.nRetx++
+		p.prefix.NRetx++
+		p.name.NRetx++
+	}
+	p.expiry = rec.Time.Add(interest.ApplyDefaultLifetime())
+	p.prefix.NInterests++
+	p.name.NInterests++
+
+	if token != "" {
+		p.tokens = append(p.tokens, token)
+		a.byToken[pendingKey{rec.Intf, rec.Dir, token}] = p
+	}
+}
+
+func (a *Analyzer) prefixCounters(name ndn.Name) *prefixCounters {
+	if len(name) > a.opts.PrefixLen {
+		name = name.GetPrefix(a.opts.PrefixLen)
+	}
+	nameV, _ := name.MarshalBinary()
+	pc := a.prefixes[string(nameV)]
+	if pc == nil {
+		pc = &prefixCounters{PrefixStats: PrefixStats{Prefix: name}}
+		a.prefixes[string(nameV)] = pc
+	}
+	return pc
+}
+
+func (a *Analyzer) nameStats(name ndn.Name, nameV string) *NameStats {
+	ns := a.names[nameV]
+	if ns == nil {
+		ns = &NameStats{Name: name}
+		a.names[nameV] = ns
+	}
+	return ns
+}
+
+// find finds a pending Interest by PIT token or name.
+// match determines whether a candidate is a match.
+func (a *Analyzer) find(rec Record, names []ndn.Name, match func(p *pendingInterest) bool) *pendingInterest {
+	dir := rec.Dir.Reverse()
+	if token := string(rec.Lp.PitToken); token != "" {
+		if p := a.byToken[pendingKey{rec.Intf, dir, token}]; p != nil && match(p) {
+			return p
+		}
+	}
+	for _, name := range names {
+		nameV, _ := name.MarshalBinary()
+		if p := a.byName[pendingNameKey{rec.Intf, dir, string(nameV)}]; p != nil && match(p) {
+			return p
+		}
+	}
+	return nil
+}
+
+// remove deletes a pending Interest.
+func (a *Analyzer) remove(p *pendingInterest) {
+	delete(a.byName, p.nameKey)
+	for _, token := range p.tokens {
+		key := pendingKey{p.nameKey.intf, p.nameKey.dir, token}
+		if a.byToken[key] == p {
+			delete(a.byToken, key)
+		}
+	}
+}
+
+func (a *Analyzer) matchData(rec Record, data ndn.Data) (p *pendingInterest) {
+	// Data name and its prefixes, from longest to shortest, where a prefix may match a CanBePrefix Interest.
+	names := []ndn.Name{data.FullName()}
+	for i := len(data.Name); i > 0; i-- {
+		names = append(names, data.Name.GetPrefix(i))
+	}
+
+	if p = a.find(rec, names, func(p *pendingInterest) bool {
+		interest := p.interest
+		interest.MustBeFresh = false
+		return data.CanSatisfy(interest)
+	}); p != nil {
+		a.remove(p)
+	}
+	return p
+}
+
+func (a *Analyzer) matchNack(rec Record, nack ndn.Nack) (p *pendingInterest) {
+	if p = a.find(rec, []ndn.Name{nack.Interest.Name}, func(p *pendingInterest) bool {
+		return p.interest.Name.Equal(nack.Interest.Name)
+	}); p != nil {
+		a.remove(p)
+	}
+	return p
+}
+
+// Result returns statistics.
+// Interests that are still pending are counted as timeouts, in addition to those that have expired.
+func (a *Analyzer) Result() (res Result) {
+	prefixTimeouts, nameTimeouts := map[*prefixCounters]int{}, map[*NameStats]int{}
+	for _, p := range a.byName {
+		prefixTimeouts[p.prefix]++
+		nameTimeouts[p.name]++
+	}
+
+	res.Unmatched = a.nUnmatched
+	for _, pc := range a.prefixes {
+		ps := pc.PrefixStats
+		ps.NTimeouts += prefixTimeouts[pc]
+		ps.computeSatisfaction()
+		ps.Rtt = makeRttStats(pc.rtts)
+		res.Prefixes = append(res.Prefixes, ps)
+	}
+	sort.Slice(res.Prefixes, func(i, j int) bool { return res.Prefixes[i].Prefix.Compare(res.Prefixes[j].Prefix) < 0 })
+
+	for _, ns := range a.names {
+		s := *ns
+		s.NTimeouts += nameTimeouts[ns]
+		res.TopNames = append(res.TopNames, s)
+	}
+	sort.Slice(res.TopNames, func(i, j int) bool {
+		if ni, nj := res.TopNames[i].NInterests, res.TopNames[j].NInterests; ni != nj {
+			return ni > nj
+		}
+		return res.TopNames[i].Name.Compare(res.TopNames[j].Name) < 0
+	})
+	if len(res.TopNames) > a.opts.TopN {
+		res.TopNames = res.TopNames[:a.opts.TopN]
+	}
+	return res
+}
+
+// New creates an Analyzer.
+func New(opts Options) *Analyzer {
+	opts.applyDefaults()
+	return &Analyzer{
+		opts:     opts,
+		byToken:  map[pendingKey]*pendingInterest{},
+		byName:   map[pendingNameKey]*pendingInterest{},
+		prefixes: map[string]*prefixCounters{},
+		names:    map[string]*NameStats{},
+	}
+}
+
+// Analyze reads a packet dump and returns statistics.
+func Analyze(r *Reader, opts Options) (res Result, e error) {
+	a := New(opts)
+	for {
+		rec, e := r.Read()
+		if errors.Is(e, io.EOF) {
+			break
+		}
+		if e != nil {
+			return res, e
+		}
+		a.Add(rec)
+	}
+
+	res = a.Result()
+	res.Reader = r.Counters
+	return res, nil
+}
@@ -0,0 +1,179 @@
+package pdumpanalyze_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+	"github.com/usnistgov/ndn-dpdk/app/pdump/pdumpanalyze"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/ndntestenv"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+type captureWriter struct {
+	t0   time.Time
+	w    *pcapgo.NgWriter
+	port int
+}
+
+func (cw *captureWriter) write(intf int, ms int, frame []byte) {
+	cw.w.WritePacket(gopacket.CaptureInfo{
+		Timestamp:      cw.t0.Add(time.Duration(ms) * time.Millisecond),
+		CaptureLength:  len(frame),
+		Length:         len(frame),
+		InterfaceIndex: intf,
+	}, frame)
+}
+
+// Face writes a packet captured on face1.
+func (cw *captureWriter) Face(ms int, dir pdumpanalyze.Direction, pkt *ndn.Packet, token string) {
+	if token != "" {
+		pkt.Lp.PitToken = []byte(token)
+	}
+	wire, _ := tlv.EncodeFrom(pkt)
+
+	frame := make([]byte, 16, 16+len(wire))
+	if dir == pdumpanalyze.DirOutgoing {
+		binary.BigEndian.PutUint16(frame[0:], uint16(layers.LinuxSLLPacketTypeOutgoing))
+	}
+	binary.BigEndian.PutUint16(frame[14:], an.EtherTypeNDN)
+	cw.write(0, ms, append(frame, wire...))
+}
+
+// Port writes a packet captured on port0.
+func (cw *captureWriter) Port(ms int, pkt *ndn.Packet) {
+	wire, _ := tlv.EncodeFrom(pkt)
+	frame := make([]byte, 14, 14+len(wire))
+	copy(frame[0:], []byte{0x01, 0x00, 0x5E, 0x00, 0x17, 0xAA, 0x02, 0, 0, 0, 0, 0x01})
+	binary.BigEndian.PutUint16(frame[12:], an.EtherTypeNDN)
+	cw.write(cw.port, ms, append(frame, wire...))
+}
+
+func TestAnalyze(t *testing.T) {
+	assert, require := makeAR(t)
+
+	var b bytes.Buffer
+	w, e := pcapgo.NewNgWriterInterface(&b, pcapgo.NgInterface{
+		Name:       "face1",
+		LinkType:   layers.LinkTypeLinuxSLL,
+		SnapLength: 262144,
+	}, pcapgo.DefaultNgWriterOptions)
+	require.NoError(e)
+	cw := &captureWriter{t0: time.Unix(1600000000, 0), w: w}
+	cw.port, e = w.AddInterface(pcapgo.NgInterface{
+		Name:       "port0",
+		LinkType:   layers.LinkTypeEthernet,
+		SnapLength: 262144,
+	})
+	require.NoError(e)
+
+	rx, tx := pdumpanalyze.DirIncoming, pdumpanalyze.DirOutgoing
+	interestA1 := ndn.MakeInterest("/A/1")
+	cw.Face(0, tx, interestA1.ToPacket(), "t1")
+	cw.Face(5, rx, ndn.MakeInterest("/A/1").ToPacket(), "t1") // same token in reverse direction is not a match
+	cw.Face(10, rx, ndn.MakeData(interestA1).ToPacket(), "t1")
+
+	interestA2 := ndn.MakeInterest("/A/2")
+	cw.Face(0, tx, interestA2.ToPacket(), "")
+	cw.Face(100, tx, interestA2.ToPacket(), "")
+	cw.Face(120, rx, ndn.MakeData(interestA2).ToPacket(), "")
+
+	interestA3 := ndn.MakeInterest("/A/3", ndn.CanBePrefixFlag)
+	cw.Face(200, tx, interestA3.ToPacket(), "t3")
+	fragmenter := ndn.NewLpFragmenter(600)
+	data3 := ndn.MakeData("/A/3/v", make([]byte, 1500)).ToPacket()
+	data3.Lp.PitToken = []byte("t3")
+	frags, e := fragmenter.Fragment(data3)
+	require.NoError(e)
+	require.Greater(len(frags), 1)
+	for _, frag := range frags {
+		cw.Face(230, rx, frag, "")
+	}
+
+	interestB1 := ndn.MakeInterest("/B/1")
+	cw.Face(300, tx, interestB1.ToPacket(), "t4")
+	cw.Face(310, rx, ndn.MakeNack(interestB1, an.NackNoRoute).ToPacket(), "t4")
+	cw.Face(320, tx, ndn.MakeInterest("/B/2").ToPacket(), "t5")
+
+	cw.Face(400, rx, ndn.MakeData("/C/1").ToPacket(), "t6")
+	cw.Port(410, ndn.MakeInterest("/C/2").ToPacket())
+
+	interestD1 := ndn.MakeInterest("/D/1", 100*time.Millisecond)
+	cw.Face(500, tx, interestD1.ToPacket(), "")
+	cw.Face(600, tx, interestD1.ToPacket(), "") // previous Interest expired, not a retransmission
+	cw.Face(650, tx, interestD1.ToPacket(), "")
+	cw.Face(660, rx, ndn.MakeData(interestD1).ToPacket(), "")
+	require.NoError(w.Flush())
+
+	r, e := pdumpanalyze.NewReader(&b)
+	require.NoError(e)
+	res, e := pdumpanalyze.Analyze(r, pdumpanalyze.Options{TopN: 3})
+	require.NoError(e)
+
+	assert.Equal(len(frags)+16, res.Reader.NFrames)
+	assert.Equal(len(frags), res.Reader.NFragments)
+	assert.Equal(17, res.Reader.NPackets)
+	assert.Equal(1, res.Unmatched.NData)
+	assert.Equal(0, res.Unmatched.NNacks)
+
+	require.Len(res.Prefixes, 4)
+	psA, psB, psC, psD := res.Prefixes[0], res.Prefixes[1], res.Prefixes[2], res.Prefixes[3]
+	ndntestenv.NameEqual(assert, "/A", psA.Prefix)
+	assert.Equal(5, psA.NInterests) // including RX /A/1 that is never answered
+	assert.Equal(1, psA.NRetx)
+	assert.Equal(3, psA.NData)
+	assert.Equal(1, psA.NTimeouts)
+	assert.InDelta(0.75, psA.SatisfactionRatio, 0.001)
+	assert.Equal(2, psA.Rtt.Count)
+	assert.Equal(10*time.Millisecond, psA.Rtt.Min)
+	assert.Equal(30*time.Millisecond, psA.Rtt.Max)
+	assert.Equal(20*time.Millisecond, psA.Rtt.Mean)
+	assert.Equal(10*time.Millisecond, psA.Rtt.P50)
+	assert.Equal(30*time.Millisecond, psA.Rtt.P99)
+
+	ndntestenv.NameEqual(assert, "/B", psB.Prefix)
+	assert.Equal(2, psB.NInterests)
+	assert.Equal(1, psB.NNacks)
+	assert.Equal(1, psB.NTimeouts)
+	assert.InDelta(0.0, psB.SatisfactionRatio, 0.001)
+	assert.Equal(0, psB.Rtt.Count)
+
+	ndntestenv.NameEqual(assert, "/C", psC.Prefix)
+	assert.Equal(1, psC.NInterests)
+	assert.Equal(1, psC.NTimeouts)
+
+	ndntestenv.NameEqual(assert, "/D", psD.Prefix)
+	assert.Equal(3, psD.NInterests)
+	assert.Equal(1, psD.NRetx)
+	assert.Equal(1, psD.NData)
+	assert.Equal(1, psD.NTimeouts)
+	assert.InDelta(0.5, psD.SatisfactionRatio, 0.001)
+	assert.Equal(0, psD.Rtt.Count)
+
+	require.Len(res.TopNames, 3)
+	ndntestenv.NameEqual(assert, "/D/1", res.TopNames[0].Name)
+	assert.Equal(3, res.TopNames[0].NInterests)
+	assert.Equal(1, res.TopNames[0].NTimeouts)
+	ndntestenv.NameEqual(assert, "/A/1", res.TopNames[1].Name)
+	assert.Equal(2, res.TopNames[1].NInterests)
+	assert.Equal(0, res.TopNames[1].NRetx)
+	ndntestenv.NameEqual(assert, "/A/2", res.TopNames[2].Name)
+	assert.Equal(1, res.TopNames[2].NRetx)
+	assert.Equal(1, res.TopNames[2].NData)
+
+	var csvPrefixes, csvNames strings.Builder
+	require.NoError(res.WritePrefixesCSV(&csvPrefixes))
+	require.NoError(res.WriteNamesCSV(&csvNames))
+	lines := strings.Split(strings.TrimSpace(csvPrefixes.String()), "\n")
+	require.Len(lines, 5)
+	assert.True(strings.HasPrefix(lines[0], "prefix,nInterests,"))
+	assert.Equal("/8=A,5,1,3,0,1,0.75,2,10000000,30000000,20000000,14142135,10000000,30000000,30000000,30000000", lines[1])
+	assert.Len(strings.Split(strings.TrimSpace(csvNames.String()), "\n"), 4)
+}
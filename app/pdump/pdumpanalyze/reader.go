@@ -0,0 +1,235 @@
+// Package pdumpanalyze analyzes packet dumps written by pdump.Writer.
+package pdumpanalyze
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// Direction indicates traffic direction.
+type Direction string
+
+// Direction values, same as pdump.Direction.
+const (
+	DirIncoming Direction = "RX"
+	DirOutgoing Direction = "TX"
+)
+
+// Reverse returns the opposite direction.
+func (dir Direction) Reverse() Direction {
+	if dir == DirIncoming {
+		return DirOutgoing
+	}
+	return DirIncoming
+}
+
+// Record is an NDN packet in a packet dump.
+type Record struct {
+	// Time is the capture timestamp.
+	// For a reassembled packet, this is the timestamp of the last fragment.
+	Time time.Time
+
+	// Intf is the pcapng interface name, such as "face1" for a face or "port0" for an Ethernet port.
+	Intf string
+
+	// Dir is the traffic direction.
+	Dir Direction
+
+	// Packet is the L3 packet, after NDNLPv2 reassembly.
+	*ndn.Packet
+}
+
+// ReaderCounters contains Reader counters.
+type ReaderCounters struct {
+	NFrames           int `json:"nFrames"`           // pcapng packets
+	NSkipped          int `json:"nSkipped"`          // frames without NDN payload
+	NDecodeErrors     int `json:"nDecodeErrors"`     // frames with malformed NDN payload
+	NFragments        int `json:"nFragments"`        // NDNLPv2 fragments
+	NReassemblyErrors int `json:"nReassemblyErrors"` // fragments rejected by reassembler
+	NPackets          int `json:"nPackets"`          // L3 packets
+}
+
+// ReassemblerCapacity is the number of partial packets kept in the reassembler of each interface and direction.
+const ReassemblerCapacity = 256
+
+type intfDir struct {
+	intf int
+	dir  Direction
+}
+
+// Reader reads NDN packets from a pcapng file written by pdump.Writer.
+//
+// Face captures are in Linux cooked-mode capture (SLL) link type, where SLL packet type indicates direction.
+// Ethernet port captures are in Ethernet link type, and are considered incoming; NDN packets may appear
+// directly over Ethernet, with or without VLAN tag, or over UDP.
+type Reader struct {
+	ng     *pcapgo.NgReader
+	reass  map[intfDir]*ndn.LpReassembler
+	closer io.Closer
+
+	sll     layers.LinuxSLL
+	eth     layers.Ethernet
+	dot1q   layers.Dot1Q
+	ip4     layers.IPv4
+	ip6     layers.IPv6
+	udp     layers.UDP
+	sllP    *gopacket.DecodingLayerParser
+	ethP    *gopacket.DecodingLayerParser
+	decoded []gopacket.LayerType
+
+	Counters ReaderCounters
+}
+
+// Read returns the next NDN packet.
+// Returns io.EOF when the end of file is reached.
+func (r *Reader) Read() (rec Record, e error) {
+	for {
+		frame, ci, e := r.ng.ReadPacketData()
+		if e != nil {
+			return rec, e
+		}
+		r.Counters.NFrames++
+
+		intf, e := r.ng.Interface(ci.InterfaceIndex)
+		if e != nil {
+			return rec, e
+		}
+		dir, wire := r.extract(intf.LinkType, frame)
+		if wire == nil {
+			r.Counters.NSkipped++
+			continue
+		}
+
+		var pkt ndn.Packet
+		if e := tlv.Decode(wire, &pkt); e != nil {
+			r.Counters.NDecodeErrors++
+			continue
+		}
+
+		full := &pkt
+		if pkt.Fragment != nil {
+			r.Counters.NFragments++
+			key := intfDir{ci.InterfaceIndex, dir}
+			reass := r.reass[key]
+			if reass == nil {
+				reass = ndn.NewLpReassembler(ReassemblerCapacity)
+				r.reass[key] = reass
+			}
+			if full, e = reass.Accept(&pkt); e != nil {
+				r.Counters.NReassemblyErrors++
+				continue
+			}
+			if full == nil {
+				continue
+			}
+		}
+
+		if full.Interest == nil && full.Data == nil && full.Nack == nil {
+			r.Counters.NSkipped++
+			continue
+		}
+		r.Counters.NPackets++
+		return Record{
+			Time:   ci.Timestamp,
+			Intf:   intf.Name,
+			Dir:    dir,
+			Packet: full,
+		}, nil
+	}
+}
+
+// extract extracts NDN packet from a frame.
+// Returns nil wire if the frame does not contain NDN packet.
+func (r *Reader) extract(linkType layers.LinkType, frame []byte) (dir Direction, wire []byte) {
+	dir = DirIncoming
+	var parser *gopacket.DecodingLayerParser
+	switch linkType {
+	case layers.LinkTypeLinuxSLL:
+		parser = r.sllP
+	case layers.LinkTypeEthernet:
+		parser = r.ethP
+	default:
+		return dir, nil
+	}
+
+	if e := parser.DecodeLayers(frame, &r.decoded); e != nil || len(r.decoded) == 0 {
+		return dir, nil
+	}
+	switch last := r.decoded[len(r.decoded)-1]; last {
+	case layers.LayerTypeLinuxSLL:
+		if r.sll.PacketType == layers.LinuxSLLPacketTypeOutgoing {
+			dir = DirOutgoing
+		}
+		return dir, r.sll.Payload
+	case layers.LayerTypeEthernet:
+		if r.eth.EthernetType == an.EtherTypeNDN {
+			return dir, r.eth.Payload
+		}
+	case layers.LayerTypeDot1Q:
+		if r.dot1q.Type == an.EtherTypeNDN {
+			return dir, r.dot1q.Payload
+		}
+	case layers.LayerTypeUDP:
+		if r.udp.SrcPort == an.UDPPortNDN || r.udp.DstPort == an.UDPPortNDN {
+			return dir, r.udp.Payload
+		}
+	}
+	return dir, nil
+}
+
+// Close closes the underlying file, if Reader was created by Open.
+func (r *Reader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+// NewReader creates a Reader.
+func NewReader(input io.Reader) (r *Reader, e error) {
+	opts := pcapgo.DefaultNgReaderOptions
+	opts.WantMixedLinkType = true
+	opts.SkipUnknownVersion = true
+
+	r = &Reader{
+		reass: map[intfDir]*ndn.LpReassembler{},
+	}
+	if r.ng, e = pcapgo.NewNgReader(input, opts); e != nil {
+		if errors.Is(e, io.EOF) {
+			e = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("pcapng: %w", e)
+	}
+
+	r.sllP = gopacket.NewDecodingLayerParser(layers.LayerTypeLinuxSLL, &r.sll)
+	r.ethP = gopacket.NewDecodingLayerParser(layers.LayerTypeEthernet, &r.eth, &r.dot1q, &r.ip4, &r.ip6, &r.udp)
+	r.sllP.IgnoreUnsupported = true
+	r.ethP.IgnoreUnsupported = true
+	return r, nil
+}
+
+// Open opens a pcapng file.
+func Open(filename string) (r *Reader, e error) {
+	file, e := os.Open(filename)
+	if e != nil {
+		return nil, e
+	}
+
+	if r, e = NewReader(bufio.NewReader(file)); e != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: %w", filename, e)
+	}
+	r.closer = file
+	return r, nil
+}
@@ -0,0 +1,146 @@
+package pdumpanalyze
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+)
+
+// RttStats describes an RTT distribution.
+type RttStats struct {
+	Count int           `json:"count"`
+	Min   time.Duration `json:"min"`
+	Max   time.Duration `json:"max"`
+	Mean  time.Duration `json:"mean"`
+	Stdev time.Duration `json:"stdev"`
+	P50   time.Duration `json:"p50"`
+	P90   time.Duration `json:"p90"`
+	P95   time.Duration `json:"p95"`
+	P99   time.Duration `json:"p99"`
+}
+
+func makeRttStats(samples []time.Duration) (s RttStats) {
+	s.Count = len(samples)
+	if s.Count == 0 {
+		return s
+	}
+
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	percentile := func(p float64) time.Duration { // nearest-rank method
+		rank := int(math.Ceil(p * float64(s.Count)))
+		return sorted[rank-1]
+	}
+	s.Min, s.Max = sorted[0], sorted[s.Count-1]
+	s.P50, s.P90, s.P95, s.P99 = percentile(0.50), percentile(0.90), percentile(0.95), percentile(0.99)
+
+	var sum, sumSq float64
+	for _, rtt := range sorted {
+		sum += float64(rtt)
+		sumSq += float64(rtt) * float64(rtt)
+	}
+	mean := sum / float64(s.Count)
+	s.Mean = time.Duration(mean)
+	if s.Count > 1 {
+		variance := (sumSq - sum*mean) / float64(s.Count-1)
+		s.Stdev = time.Duration(math.Sqrt(math.Max(0, variance)))
+	}
+	return s
+}
+
+// PrefixStats contains statistics of Interests under a name prefix.
+//
+// Each Interest, together with its retransmissions, has one outcome: Data, Nack, or timeout.
+type PrefixStats struct {
+	Prefix     ndn.Name `json:"prefix"`
+	NInterests int      `json:"nInterests"` // Interest packets, including retransmissions
+	NRetx      int      `json:"nRetx"`      // retransmitted Interest packets
+	NData      int      `json:"nData"`      // Interests satisfied by Data
+	NNacks     int      `json:"nNacks"`     // Interests answered by Nack
+	NTimeouts  int      `json:"nTimeouts"`  // Interests not answered within InterestLifetime or until end of capture
+
+	// SatisfactionRatio is NData divided by the total number of outcomes.
+	SatisfactionRatio float64 `json:"satisfactionRatio"`
+
+	// Rtt is the distribution of RTT, measured on Interests that are satisfied without retransmission.
+	Rtt RttStats `json:"rtt"`
+}
+
+func (ps *PrefixStats) computeSatisfaction() {
+	if total := ps.NData + ps.NNacks + ps.NTimeouts; total > 0 {
+		ps.SatisfactionRatio = float64(ps.NData) / float64(total)
+	}
+}
+
+// NameStats contains statistics of Interests with a specific name.
+type NameStats struct {
+	Name       ndn.Name `json:"name"`
+	NInterests int      `json:"nInterests"`
+	NRetx      int      `json:"nRetx"`
+	NData      int      `json:"nData"`
+	NNacks     int      `json:"nNacks"`
+	NTimeouts  int      `json:"nTimeouts"`
+}
+
+// UnmatchedCounters counts Data and Nacks that do not match any pending Interest.
+type UnmatchedCounters struct {
+	NData  int `json:"nData"`
+	NNacks int `json:"nNacks"`
+}
+
+// Result contains analysis result.
+type Result struct {
+	Reader    ReaderCounters    `json:"reader"`
+	Unmatched UnmatchedCounters `json:"unmatched"`
+	Prefixes  []PrefixStats     `json:"prefixes"`
+	TopNames  []NameStats       `json:"topNames"`
+}
+
+// WritePrefixesCSV writes per-prefix statistics as CSV.
+// Durations are in nanoseconds.
+func (res Result) WritePrefixesCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"prefix", "nInterests", "nRetx", "nData", "nNacks", "nTimeouts", "satisfactionRatio",
+		"rttCount", "rttMin", "rttMax", "rttMean", "rttStdev", "rttP50", "rttP90", "rttP95", "rttP99",
+	})
+	for _, ps := range res.Prefixes {
+		cw.Write([]string{
+			ps.Prefix.String(),
+			strconv.Itoa(ps.NInterests), strconv.Itoa(ps.NRetx),
+			strconv.Itoa(ps.NData), strconv.Itoa(ps.NNacks), strconv.Itoa(ps.NTimeouts),
+			strconv.FormatFloat(ps.SatisfactionRatio, 'f', -1, 64),
+			strconv.Itoa(ps.Rtt.Count),
+			formatDuration(ps.Rtt.Min), formatDuration(ps.Rtt.Max),
+			formatDuration(ps.Rtt.Mean), formatDuration(ps.Rtt.Stdev),
+			formatDuration(ps.Rtt.P50), formatDuration(ps.Rtt.P90),
+			formatDuration(ps.Rtt.P95), formatDuration(ps.Rtt.P99),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteNamesCSV writes top names as CSV.
+func (res Result) WriteNamesCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"name", "nInterests", "nRetx", "nData", "nNacks", "nTimeouts"})
+	for _, ns := range res.TopNames {
+		cw.Write([]string{
+			ns.Name.String(),
+			strconv.Itoa(ns.NInterests), strconv.Itoa(ns.NRetx),
+			strconv.Itoa(ns.NData), strconv.Itoa(ns.NNacks), strconv.Itoa(ns.NTimeouts),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatDuration(d time.Duration) string {
+	return strconv.FormatInt(d.Nanoseconds(), 10)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 	"time"
@@ -36,6 +37,16 @@ func init() {
 				Destination: &serveOptions.ChunkSize,
 				Value:       4096,
 			},
+			&cli.BoolFlag{
+				Name:        "versioned",
+				Usage:       "publish versioned object with RDR metadata",
+				Destination: &serveOptions.Versioned,
+			},
+			&cli.BoolFlag{
+				Name:        "manifest",
+				Usage:       "publish FLIC manifest of segment digests",
+				Destination: &serveOptions.Manifest,
+			},
 		},
 		Before: openUplink,
 		Action: func(c *cli.Context) error {
@@ -61,8 +72,14 @@ func init() {
 	})
 }
 
+var congestionControls = map[string]func() segmented.CongestionControl{
+	"aimd":  segmented.NewAIMD,
+	"cubic": segmented.NewCubic,
+	"bbr":   segmented.NewBBR,
+}
+
 func init() {
-	var name string
+	var name, cc, output string
 	var fetchOptions segmented.FetchOptions
 	defineCommand(&cli.Command{
 		Name:  "get",
@@ -86,9 +103,34 @@ func init() {
 				Destination: &fetchOptions.MaxCwnd,
 				Value:       24,
 			},
+			&cli.StringFlag{
+				Name:        "cc",
+				Usage:       "congestion control `algorithm`: aimd, cubic, bbr",
+				Destination: &cc,
+				Value:       "cubic",
+			},
+			&cli.BoolFlag{
+				Name:        "discover",
+				Usage:       "discover latest version via RDR",
+				Destination: &fetchOptions.DiscoverVersion,
+			},
+			&cli.BoolFlag{
+				Name:        "manifest",
+				Usage:       "verify segments against FLIC manifest",
+				Destination: &fetchOptions.Manifest,
+			},
+			&cli.StringFlag{
+				Name:        "output",
+				Usage:       "output `file`, resumable after interruption (default is stdout)",
+				Destination: &output,
+			},
 		},
 		Before: openUplink,
 		Action: func(c *cli.Context) error {
+			if fetchOptions.CongestionControl = congestionControls[cc]; fetchOptions.CongestionControl == nil {
+				return fmt.Errorf("unknown congestion control algorithm %s", cc)
+			}
+
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
 			onInterrupt(cancel)
@@ -113,9 +155,25 @@ func init() {
 			}()
 
 			t0 := time.Now()
-			e := fetcher.Pipe(ctx, os.Stdout)
+			var e error
+			if output == "" {
+				e = fetcher.Pipe(ctx, os.Stdout)
+			} else {
+				checkpoint, flag := output+".checkpoint", os.O_RDWR|os.O_CREATE
+				if _, e := os.Stat(checkpoint); os.IsNotExist(e) {
+					flag |= os.O_TRUNC // not resuming, discard existing content
+				}
+				var f *os.File
+				if f, e = os.OpenFile(output, flag, 0o644); e != nil {
+					return e
+				}
+				defer f.Close()
+				e = fetcher.WriteAt(ctx, f, checkpoint)
+			}
 			if e == nil {
-				log.Printf("finished %d segments in %v", fetcher.Count(), time.Since(t0).Truncate(time.Millisecond))
+				stats := fetcher.Stats()
+				log.Printf("finished %d segments in %v, %d retransmissions, %d congestion marks",
+					fetcher.Count(), time.Since(t0).Truncate(time.Millisecond), stats.NRetx, stats.NCongMarks)
 			}
 			return e
 		},
@@ -0,0 +1,125 @@
+// Command ndndpdk-pdump-analyze analyzes packet dumps written by NDN-DPDK packet dumper.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"github.com/usnistgov/ndn-dpdk/app/pdump/pdumpanalyze"
+	"github.com/usnistgov/ndn-dpdk/mk/version"
+)
+
+var (
+	opts   pdumpanalyze.Options
+	format string
+	table  string
+)
+
+// analyzeFiles analyzes one or more pcapng files.
+// Interests and Data are matched within each file, and statistics are combined.
+func analyzeFiles(filenames []string) (res pdumpanalyze.Result, e error) {
+	a := pdumpanalyze.New(opts)
+	var cnt pdumpanalyze.ReaderCounters
+	for _, filename := range filenames {
+		r, e := pdumpanalyze.Open(filename)
+		if e != nil {
+			return res, e
+		}
+
+		for {
+			rec, e := r.Read()
+			if errors.Is(e, io.EOF) {
+				break
+			}
+			if e != nil {
+				r.Close()
+				return res, fmt.Errorf("%s: %w", filename, e)
+			}
+			if len(filenames) > 1 {
+				rec.Intf = filename + ":" + rec.Intf
+			}
+			a.Add(rec)
+		}
+		r.Close()
+
+		cnt.NFrames += r.Counters.NFrames
+		cnt.NSkipped += r.Counters.NSkipped
+		cnt.NDecodeErrors += r.Counters.NDecodeErrors
+		cnt.NFragments += r.Counters.NFragments
+		cnt.NReassemblyErrors += r.Counters.NReassemblyErrors
+		cnt.NPackets += r.Counters.NPackets
+	}
+
+	res = a.Result()
+	res.Reader = cnt
+	return res, nil
+}
+
+var app = &cli.App{
+	Version:   version.Get().String(),
+	Usage:     "Analyze NDN-DPDK packet dumps.",
+	ArgsUsage: "FILE.pcapng...",
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:        "prefix-len",
+			Usage:       "aggregate statistics by name prefix of `N` components",
+			Value:       1,
+			Destination: &opts.PrefixLen,
+		},
+		&cli.IntFlag{
+			Name:        "top",
+			Usage:       "report `N` most frequently requested names",
+			Value:       10,
+			Destination: &opts.TopN,
+		},
+		&cli.StringFlag{
+			Name:        "format",
+			Usage:       "output `format`: json, csv",
+			Value:       "json",
+			Destination: &format,
+		},
+		&cli.StringFlag{
+			Name:        "table",
+			Usage:       "CSV `table`: prefixes, names",
+			Value:       "prefixes",
+			Destination: &table,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.NArg() == 0 {
+			return errors.New("no input file")
+		}
+		res, e := analyzeFiles(c.Args().Slice())
+		if e != nil {
+			return e
+		}
+
+		switch format {
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(res)
+		case "csv":
+			switch table {
+			case "prefixes":
+				return res.WritePrefixesCSV(os.Stdout)
+			case "names":
+				return res.WriteNamesCSV(os.Stdout)
+			}
+			return fmt.Errorf("unknown table %s", table)
+		}
+		return fmt.Errorf("unknown format %s", format)
+	},
+}
+
+func main() {
+	e := app.Run(os.Args)
+	if e != nil {
+		log.Fatal(e)
+	}
+}
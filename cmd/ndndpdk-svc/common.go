@@ -1,6 +1,7 @@
 package main
 
 import (
+	"io"
 	"sync"
 	"time"
 
@@ -71,7 +72,30 @@ func initXDPProgram() {
 	ethnetif.XDPProgram = path
 }
 
-var shutdownOnce sync.Once
+var (
+	shutdownOnce    sync.Once
+	shutdownMutex   sync.Mutex
+	shutdownClosers []io.Closer
+)
+
+// closeOnShutdown registers a service started during activation, to be closed during shutdown.
+func closeOnShutdown(c io.Closer) {
+	shutdownMutex.Lock()
+	defer shutdownMutex.Unlock()
+	shutdownClosers = append(shutdownClosers, c)
+}
+
+// closeServices closes registered services in reverse order.
+func closeServices() {
+	shutdownMutex.Lock()
+	defer shutdownMutex.Unlock()
+	for i := len(shutdownClosers) - 1; i >= 0; i-- {
+		if e := shutdownClosers[i].Close(); e != nil {
+			logger.Warn("service close error", zap.Error(e))
+		}
+	}
+	shutdownClosers = nil
+}
 
 func delayedShutdown(then func()) {
 	// Shutdown is slightly delayed to allow enough time to send back the GraphQL result.
@@ -80,6 +104,7 @@ func delayedShutdown(then func()) {
 
 	go func() {
 		shutdownOnce.Do(func() {
+			closeServices()
 			iface.CloseAll()
 		})
 		time.Sleep(100 * time.Millisecond)
@@ -6,6 +6,7 @@ import (
 	"github.com/usnistgov/ndn-dpdk/container/ndt"
 	"github.com/usnistgov/ndn-dpdk/container/strategycode"
 	"github.com/usnistgov/ndn-dpdk/iface"
+	"github.com/usnistgov/ndn-dpdk/iface/socketface"
 )
 
 const defaultStrategyName = "multicast"
@@ -13,6 +14,15 @@ const defaultStrategyName = "multicast"
 type fwArgs struct {
 	CommonArgs
 	fwdp.Config
+
+	// WebSocket enables a WebSocket listener that creates a socket face for each accepted connection.
+	WebSocket *socketface.WebSocketListenerConfig `json:"webSocket,omitempty"`
+
+	// WebTransport enables a WebTransport listener that creates a socket face for each accepted session.
+	WebTransport *socketface.WebTransportListenerConfig `json:"webTransport,omitempty"`
+
+	// NfdMgmt enables NFD management responder and Unix socket listener for local applications.
+	NfdMgmt *fwdp.NfdMgmtConfig `json:"nfdMgmt,omitempty"`
 }
 
 func (a fwArgs) Activate() error {
@@ -36,5 +46,28 @@ func (a fwArgs) Activate() error {
 		return e
 	}
 
+	if a.WebSocket != nil {
+		ln, e := socketface.ListenWebSocket(*a.WebSocket)
+		if e != nil {
+			return e
+		}
+		closeOnShutdown(ln)
+	}
+	if a.WebTransport != nil {
+		ln, e := socketface.ListenWebTransport(*a.WebTransport)
+		if e != nil {
+			return e
+		}
+		closeOnShutdown(ln)
+	}
+
+	if a.NfdMgmt != nil {
+		m, e := fwdp.NewNfdMgmt(dp, fib.GqlDefaultStrategy, *a.NfdMgmt)
+		if e != nil {
+			return e
+		}
+		closeOnShutdown(m)
+	}
+
 	return nil
 }
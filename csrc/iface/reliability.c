@@ -0,0 +1,305 @@
+#include "reliability.h"
+#include "../core/logger.h"
+#include "../ndni/tlv-decoder.h"
+#include "../ndni/tlv-encoder.h"
+
+#include <rte_random.h>
+
+N_LOG_INIT(LpReliability);
+
+void
+LpReliability_Init(LpReliability* rel)
+{
+  NDNDPDK_ASSERT(rte_is_power_of_2(rel->windowMask + 1));
+  rel->nextTxSeq = RTE_MAX(rte_rand() >> 1, UINT64_C(1));
+  rel->oldestTxSeq = rel->nextTxSeq;
+}
+
+void
+LpReliability_Close(LpReliability* rel)
+{
+  for (uint32_t i = 0; i <= rel->windowMask; ++i) {
+    LpRelEntry* entry = &rel->window[i];
+    if (entry->frame != NULL) {
+      rte_pktmbuf_free(entry->frame);
+      entry->frame = NULL;
+    }
+  }
+}
+
+bool
+LpReliability_Rx(LpReliability* rel, struct rte_mbuf* frame)
+{
+  TlvDecoder d;
+  TlvDecoder_Init(&d, frame);
+  uint32_t length0, type0 = TlvDecoder_ReadTL(&d, &length0);
+  if (type0 != TtLpPacket) {
+    return true;
+  }
+  d.length = length0;
+
+  uint64_t acks[MaxBurstSize];
+  uint16_t nAcks = 0;
+  bool hasPayload = false;
+  TlvDecoder_EachTL (&d, type, length) {
+    switch (type) {
+      case TtLpPayload:
+        hasPayload = true;
+        goto FINISH;
+      case TtTxSequence: {
+        uint64_t txSeq;
+        if (likely(length == sizeof(txSeq) && TlvDecoder_ReadNniTo(&d, length, &txSeq))) {
+          rte_ring_enqueue_elem(rel->toAck, &txSeq, sizeof(txSeq));
+        } else {
+          TlvDecoder_Skip(&d, length);
+        }
+        break;
+      }
+      case TtAck: {
+        if (likely(length == sizeof(acks[0]) && TlvDecoder_ReadNniTo(&d, length, &acks[nAcks]))) {
+          if (++nAcks == RTE_DIM(acks)) {
+            rte_ring_enqueue_burst_elem(rel->acked, acks, sizeof(acks[0]), nAcks, NULL);
+            nAcks = 0;
+          }
+        } else {
+          TlvDecoder_Skip(&d, length);
+        }
+        break;
+      }
+      default:
+        TlvDecoder_Skip(&d, length);
+        break;
+    }
+  }
+
+FINISH:
+  if (nAcks > 0) {
+    rte_ring_enqueue_burst_elem(rel->acked, acks, sizeof(acks[0]), nAcks, NULL);
+  }
+  return hasPayload;
+}
+
+/** @brief Strip LpPacket TLV-TYPE and TLV-LENGTH from a frame prepared by TxProc. */
+__attribute__((nonnull)) static __rte_always_inline void
+LpReliability_StripTL(struct rte_mbuf* frame)
+{
+  TlvDecoder d;
+  TlvDecoder_Init(&d, frame);
+  uint32_t length, type = TlvDecoder_ReadTL(&d, &length);
+  NDNDPDK_ASSERT(type == TtLpPacket);
+  RTE_SET_USED(type);
+  rte_pktmbuf_adj(frame, frame->pkt_len - length);
+}
+
+/**
+ * @brief Determine length of NDNLPv2 header fields.
+ * @param frame LpPacket TLV-VALUE.
+ * @return offset of LpPayload element, or length of @p frame if LpPayload is absent.
+ */
+__attribute__((nonnull)) static uint32_t
+LpReliability_HeaderLength(struct rte_mbuf* frame)
+{
+  TlvDecoder d;
+  TlvDecoder_Init(&d, frame);
+  while (d.length > 0) {
+    uint32_t offset = frame->pkt_len - d.length;
+    uint32_t length, type = TlvDecoder_ReadTL(&d, &length);
+    if (type == TtLpPayload || type == 0) {
+      return offset;
+    }
+    TlvDecoder_Skip(&d, length);
+  }
+  return frame->pkt_len;
+}
+
+/**
+ * @brief Insert reliability fields and prepend LpPacket TLV-TYPE and TLV-LENGTH.
+ * @param frame LpPacket TLV-VALUE, must have at least @c LpReliabilityHeadroom headroom;
+ *              existing header fields must be in the first segment.
+ * @param txSeq TxSequence, or 0 to omit.
+ *
+ * NDNLPv2 header fields must appear in increasing TLV-TYPE order. Ack and TxSequence have greater
+ * TLV-TYPE numbers than other header fields, so that they are inserted immediately before LpPayload.
+ */
+__attribute__((nonnull)) static void
+LpReliability_Prepend(LpReliability* rel, struct rte_mbuf* frame, uint64_t txSeq)
+{
+  typedef struct Uint64F
+  {
+    unaligned_uint32_t tl;
+    unaligned_uint64_t v;
+  } __rte_packed Uint64F;
+
+  uint64_t acks[LpMaxAcks];
+  uint16_t nAcks =
+    rte_ring_dequeue_burst_elem(rel->toAck, acks, sizeof(acks[0]), RTE_DIM(acks), NULL);
+  uint16_t nFields = nAcks + (txSeq != 0);
+  if (likely(nFields > 0)) {
+    uint32_t hdrLen = LpReliability_HeaderLength(frame);
+    NDNDPDK_ASSERT(hdrLen <= frame->data_len);
+    uint8_t* room = (uint8_t*)rte_pktmbuf_prepend(frame, nFields * sizeof(Uint64F));
+    memmove(room, RTE_PTR_ADD(room, nFields * sizeof(Uint64F)), hdrLen);
+
+    Uint64F* f = RTE_PTR_ADD(room, hdrLen);
+    for (uint16_t i = 0; i < nAcks; ++i, ++f) {
+      f->tl = TlvEncoder_ConstTL3(TtAck, sizeof(f->v));
+      f->v = rte_cpu_to_be_64(acks[i]);
+    }
+    if (likely(txSeq != 0)) {
+      f->tl = TlvEncoder_ConstTL3(TtTxSequence, sizeof(f->v));
+      f->v = rte_cpu_to_be_64(txSeq);
+    }
+  }
+
+  TlvEncoder_PrependTL(frame, TtLpPacket, frame->pkt_len);
+}
+
+/** @brief Insert a frame into the window, and return its TxSequence. */
+__attribute__((nonnull)) static uint64_t
+LpReliability_Insert(LpReliability* rel, struct rte_mbuf* saved, uint8_t nRetx, TscTime now)
+{
+  uint64_t txSeq = rel->nextTxSeq++;
+  LpRelEntry* entry = &rel->window[txSeq & rel->windowMask];
+  if (unlikely(entry->frame != NULL)) { // window is full, give up the oldest frame
+    N_LOGD("window-full lost=%016" PRIx64, entry->txSeq);
+    ++rel->nLost;
+    rte_pktmbuf_free(entry->frame);
+  }
+  *entry = (LpRelEntry){
+    .frame = saved,
+    .txSeq = txSeq,
+    .sendTime = now,
+    .nRetx = nRetx,
+  };
+  return txSeq;
+}
+
+/**
+ * @brief Make a copy of a saved frame, with enough headroom for reliability fields.
+ *
+ * Header fields are copied into a new mbuf, so that inserting reliability fields does not modify
+ * the data buffer of @p saved .
+ */
+__attribute__((nonnull)) static struct rte_mbuf*
+LpReliability_Copy(LpReliability* rel, struct rte_mbuf* saved)
+{
+  if (rel->linearize) {
+    struct rte_mbuf* frame = rte_pktmbuf_alloc(rel->mp.packet);
+    if (unlikely(frame == NULL)) {
+      return NULL;
+    }
+    frame->data_off = RTE_PKTMBUF_HEADROOM + LpHeaderHeadroom + LpReliabilityHeadroom;
+    Mbuf_CopyTo(saved, rte_pktmbuf_append(frame, saved->pkt_len));
+    return frame;
+  }
+
+  uint32_t hdrLen = LpReliability_HeaderLength(saved);
+  NDNDPDK_ASSERT(hdrLen <= saved->data_len && hdrLen <= LpHeaderHeadroom);
+  struct rte_mbuf* frame = rte_pktmbuf_alloc(rel->mp.header);
+  if (unlikely(frame == NULL)) {
+    return NULL;
+  }
+  frame->data_off = RTE_PKTMBUF_HEADROOM + LpHeaderHeadroom + LpReliabilityHeadroom - hdrLen;
+  rte_memcpy(rte_pktmbuf_append(frame, hdrLen), rte_pktmbuf_mtod(saved, const uint8_t*), hdrLen);
+
+  struct rte_mbuf* payload = rte_pktmbuf_clone(saved, rel->mp.indirect);
+  if (likely(payload != NULL)) {
+    rte_pktmbuf_adj(payload, hdrLen);
+  }
+  if (unlikely(payload == NULL || !Mbuf_Chain(frame, frame, payload))) {
+    rte_pktmbuf_free(payload);
+    rte_pktmbuf_free(frame);
+    return NULL;
+  }
+  return frame;
+}
+
+struct rte_mbuf*
+LpReliability_Tx(LpReliability* rel, struct rte_mbuf* frame, TscTime now)
+{
+  LpReliability_StripTL(frame);
+
+  struct rte_mbuf* output = LpReliability_Copy(rel, frame);
+  if (unlikely(output == NULL)) {
+    ++rel->nAllocFails;
+    TlvEncoder_PrependTL(frame, TtLpPacket, frame->pkt_len);
+    return frame;
+  }
+  Mbuf_SetTimestamp(output, Mbuf_GetTimestamp(frame));
+  Packet_SetType(Packet_FromMbuf(output), Packet_GetType(Packet_FromMbuf(frame)));
+
+  uint64_t txSeq = LpReliability_Insert(rel, frame, 0, now);
+  LpReliability_Prepend(rel, output, txSeq);
+  return output;
+}
+
+uint16_t
+LpReliability_Poll(LpReliability* rel, struct rte_mbuf** frames, uint16_t maxFrames, TscTime now)
+{
+  uint64_t acks[MaxBurstSize];
+  uint16_t nAcks;
+  while ((nAcks = rte_ring_dequeue_burst_elem(rel->acked, acks, sizeof(acks[0]), RTE_DIM(acks),
+                                              NULL)) > 0) {
+    for (uint16_t i = 0; i < nAcks; ++i) {
+      LpRelEntry* entry = &rel->window[acks[i] & rel->windowMask];
+      if (entry->frame != NULL && entry->txSeq == acks[i]) {
+        ++rel->nAcked;
+        rte_pktmbuf_free(entry->frame);
+        entry->frame = NULL;
+      }
+    }
+  }
+
+  uint16_t nFrames = 0;
+  for (; rel->oldestTxSeq < rel->nextTxSeq && nFrames < maxFrames; ++rel->oldestTxSeq) {
+    LpRelEntry* entry = &rel->window[rel->oldestTxSeq & rel->windowMask];
+    if (entry->frame == NULL || entry->txSeq != rel->oldestTxSeq) {
+      continue;
+    }
+    if (now - entry->sendTime < rel->rto) {
+      // frames are sent in TxSequence order, so that subsequent frames have not timed out either
+      break;
+    }
+
+    struct rte_mbuf* saved = entry->frame;
+    uint8_t nRetx = entry->nRetx;
+    if (nRetx >= rel->maxRetx) {
+      N_LOGD("retx-limit lost=%016" PRIx64, entry->txSeq);
+      ++rel->nLost;
+      rte_pktmbuf_free(saved);
+      entry->frame = NULL;
+      continue;
+    }
+
+    struct rte_mbuf* frame = LpReliability_Copy(rel, saved);
+    if (unlikely(frame == NULL)) {
+      ++rel->nAllocFails;
+      break;
+    }
+    entry->frame = NULL;
+
+    uint64_t txSeq = LpReliability_Insert(rel, saved, nRetx + 1, now);
+    N_LOGV("retx old=%016" PRIx64 " new=%016" PRIx64, rel->oldestTxSeq, txSeq);
+    LpReliability_Prepend(rel, frame, txSeq);
+    Mbuf_SetTimestamp(frame, now);
+    Packet_SetType(Packet_FromMbuf(frame), PktFragment);
+    ++rel->nRetx;
+    frames[nFrames++] = frame;
+  }
+
+  while (nFrames < maxFrames && !rte_ring_empty(rel->toAck)) {
+    struct rte_mbuf* frame = rte_pktmbuf_alloc(rel->mp.header);
+    if (unlikely(frame == NULL)) {
+      ++rel->nAllocFails;
+      break;
+    }
+    frame->data_off = RTE_PKTMBUF_HEADROOM + LpHeaderHeadroom + LpReliabilityHeadroom;
+    LpReliability_Prepend(rel, frame, 0);
+    Mbuf_SetTimestamp(frame, now);
+    Packet_SetType(Packet_FromMbuf(frame), PktFragment);
+    ++rel->nIdle;
+    frames[nFrames++] = frame;
+  }
+
+  return nFrames;
+}
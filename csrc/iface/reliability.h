@@ -0,0 +1,98 @@
+#ifndef NDNDPDK_IFACE_RELIABILITY_H
+#define NDNDPDK_IFACE_RELIABILITY_H
+
+/** @file */
+
+#include "common.h"
+
+/**
+ * @brief NDNLPv2 link reliability unacknowledged frame.
+ *
+ * Transmissions and retransmissions share the data buffer of @c frame , so that it must not be
+ * modified while saved in the window. Reliability fields are inserted into a separate header mbuf.
+ */
+typedef struct LpRelEntry
+{
+  struct rte_mbuf* frame; ///< LpPacket TLV-VALUE without reliability fields; NULL if unused
+  uint64_t txSeq;
+  TscTime sendTime;
+  uint8_t nRetx;
+} LpRelEntry;
+
+/**
+ * @brief NDNLPv2 link reliability.
+ *
+ * RX threads extract TxSequence and Ack fields from incoming frames, and pass them to the output
+ * thread via rings. The output thread assigns TxSequence to outgoing frames, piggybacks Acks, and
+ * retransmits frames that are not acknowledged within the retransmission timeout. Acks that cannot
+ * be piggybacked in the same burst are sent in IDLE frames.
+ */
+typedef struct LpReliability
+{
+  struct rte_ring* toAck; ///< TxSequence of received frames
+  struct rte_ring* acked; ///< Acks in received frames
+  PacketMempools mp;
+  bool linearize;
+
+  TscDuration rto;
+  uint8_t maxRetx;
+  uint32_t windowMask;
+  uint64_t nextTxSeq;   ///< next TxSequence
+  uint64_t oldestTxSeq; ///< oldest TxSequence that may be unacknowledged
+
+  uint64_t nAcked;      ///< frames acknowledged
+  uint64_t nRetx;       ///< frames retransmitted
+  uint64_t nLost;       ///< frames given up after maxRetx or window overflow
+  uint64_t nAllocFails; ///< allocation failures
+  uint64_t nIdle;       ///< IDLE frames sent
+
+  LpRelEntry window[0];
+} LpReliability;
+
+/**
+ * @brief Initialize LpReliability.
+ * @param rel zero LpReliability struct with @c windowMask+1 window entries.
+ * @pre Go code has assigned rings, mempools, and configuration fields.
+ */
+__attribute__((nonnull)) void
+LpReliability_Init(LpReliability* rel);
+
+/** @brief Release all frames except @p rel struct and rings. */
+__attribute__((nonnull)) void
+LpReliability_Close(LpReliability* rel);
+
+/**
+ * @brief Process reliability fields on an incoming frame.
+ * @param frame incoming L2 frame, starting from NDNLP header; it is not modified.
+ * @return whether the frame should continue RX processing.
+ * @retval false @p frame is an IDLE frame.
+ *
+ * This function is thread-safe.
+ */
+__attribute__((nonnull)) bool
+LpReliability_Rx(LpReliability* rel, struct rte_mbuf* frame);
+
+/**
+ * @brief Assign TxSequence and piggyback Acks on an outgoing frame.
+ * @param frame outgoing L2 frame, prepared by TxProc; LpReliability takes ownership.
+ * @return L2 frame to be transmitted.
+ *
+ * @p frame is saved for retransmission without modification. Reliability fields are inserted
+ * into a copy of its header, so that @p frame does not need @c LpReliabilityHeadroom headroom.
+ *
+ * This function must be called on the output thread.
+ */
+__attribute__((nonnull, returns_nonnull)) struct rte_mbuf*
+LpReliability_Tx(LpReliability* rel, struct rte_mbuf* frame, TscTime now);
+
+/**
+ * @brief Process received Acks and generate retransmissions and IDLE frames.
+ * @param[out] frames L2 frames to be transmitted.
+ * @return number of L2 frames to be transmitted.
+ *
+ * This function must be called on the output thread.
+ */
+__attribute__((nonnull)) uint16_t
+LpReliability_Poll(LpReliability* rel, struct rte_mbuf** frames, uint16_t maxFrames, TscTime now);
+
+#endif // NDNDPDK_IFACE_RELIABILITY_H
@@ -12,6 +12,11 @@ RxProc_Input(RxProc* rx, int thread, struct rte_mbuf* frame)
   RxProcThread* rxt = &rx->threads[thread];
   rxt->nFrames[0] += frame->pkt_len;
 
+  if (rx->rel != NULL && !LpReliability_Rx(rx->rel, frame)) {
+    rte_pktmbuf_free(frame); // IDLE frame
+    return NULL;
+  }
+
   Packet* npkt = Packet_FromMbuf(frame);
   if (unlikely(!Packet_Parse(npkt))) {
     ++rxt->nDecodeErr;
@@ -5,6 +5,7 @@
 
 #include "../pdump/source.h"
 #include "reassembler.h"
+#include "reliability.h"
 
 /** @brief RxProc per-thread information. */
 typedef struct RxProcThread
@@ -19,6 +20,7 @@ typedef struct RxProc
 {
   RxProcThread threads[MaxRxProcThreads];
   PdumpSourceRef pdump;
+  LpReliability* rel; ///< NDNLPv2 link reliability, NULL if disabled
 } RxProc;
 
 /**
@@ -5,7 +5,7 @@
 
 N_LOG_INIT(TxProc);
 
-static_assert((int)MinMTU > (int)LpHeaderHeadroom, "");
+static_assert((int)MinMTU > (int)LpHeaderHeadroom + (int)LpReliabilityHeadroom, "");
 
 __attribute__((nonnull)) static __rte_always_inline uint16_t
 TxProc_One(const char* logVerb, Packet* npkt, struct rte_mbuf* frames[LpMaxFragments])
@@ -4,7 +4,7 @@
 /** @file */
 
 #include "../pdump/source.h"
-#include "common.h"
+#include "reliability.h"
 
 /**
  * @brief Transmit a burst of L2 frames.
@@ -25,6 +25,7 @@ typedef struct TxProc
 {
   Face_L2TxBurst l2Burst;
   PdumpSourceRef pdump;
+  LpReliability* rel; ///< NDNLPv2 link reliability, NULL if disabled
 
   PacketMempools mp; ///< mempools for fragmentation
   TxProc_OutputFunc_ outputFunc[2];
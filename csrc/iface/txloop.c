@@ -62,7 +62,13 @@ TxLoop_Transfer(Face* face)
       }
     }
 
-    nFrames += TxProc_Output(tx, npkt, &frames[nFrames], face->txAlign);
+    uint16_t nOutput = TxProc_Output(tx, npkt, &frames[nFrames], face->txAlign);
+    if (tx->rel != NULL) {
+      for (uint16_t j = nFrames; j < nFrames + nOutput; ++j) {
+        frames[j] = LpReliability_Tx(tx->rel, frames[j], now);
+      }
+    }
+    nFrames += nOutput;
     if (unlikely(nFrames >= MaxBurstSize)) {
       TxLoop_TxFrames(face, frames, nFrames);
       nFrames = 0;
@@ -74,6 +80,14 @@ TxLoop_Transfer(Face* face)
   }
   Hrlog_Post(hrl, nHrls);
 
+  if (tx->rel != NULL) {
+    nFrames = LpReliability_Poll(tx->rel, frames, MaxBurstSize, now);
+    if (nFrames > 0) {
+      TxLoop_TxFrames(face, frames, nFrames);
+    }
+    count += nFrames;
+  }
+
   return count;
 }
 
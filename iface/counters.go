@@ -64,11 +64,17 @@ type TxCounters struct {
 	TxFragBad   uint64 `json:"txFragBad" gqldesc:"TX fragmentation failures."`
 	TxAllocErrs uint64 `json:"txAllocErrs" gqldesc:"TX allocation errors."`
 	TxDropped   uint64 `json:"txDropped" gqldesc:"TX dropped L2 frames due to full queue."`
+
+	TxRelAcked uint64 `json:"txRelAcked" gqldesc:"TX frames acknowledged by NDNLPv2 link reliability."`
+	TxRelRetx  uint64 `json:"txRelRetx" gqldesc:"TX frames retransmitted by NDNLPv2 link reliability."`
+	TxRelLost  uint64 `json:"txRelLost" gqldesc:"TX frames considered lost by NDNLPv2 link reliability."`
+	TxRelIdle  uint64 `json:"txRelIdle" gqldesc:"TX IDLE frames carrying NDNLPv2 Acks."`
 }
 
 func (cnt TxCounters) String() string {
-	return fmt.Sprintf("%dfrm %db %dI %dD %dN frag=(%dgood %dbad) alloc=%derr %ddropped",
-		cnt.TxFrames, cnt.TxOctets, cnt.TxInterests, cnt.TxData, cnt.TxNacks, cnt.TxFragGood, cnt.TxFragBad, cnt.TxAllocErrs, cnt.TxDropped)
+	return fmt.Sprintf("%dfrm %db %dI %dD %dN frag=(%dgood %dbad) alloc=%derr %ddropped rel=(%dacked %dretx %dlost %didle)",
+		cnt.TxFrames, cnt.TxOctets, cnt.TxInterests, cnt.TxData, cnt.TxNacks, cnt.TxFragGood, cnt.TxFragBad, cnt.TxAllocErrs, cnt.TxDropped,
+		cnt.TxRelAcked, cnt.TxRelRetx, cnt.TxRelLost, cnt.TxRelIdle)
 }
 
 // Since computes the difference between cnt and prev.
@@ -91,6 +97,14 @@ func (cnt *TxCounters) readFrom(c *C.TxProc) {
 	cnt.TxFragBad = uint64(c.nL3OverLength + c.nAllocFails)
 	cnt.TxAllocErrs = uint64(c.nAllocFails)
 	cnt.TxDropped = uint64(c.nDroppedFrames)
+
+	if rel := c.rel; rel != nil {
+		cnt.TxAllocErrs += uint64(rel.nAllocFails)
+		cnt.TxRelAcked = uint64(rel.nAcked)
+		cnt.TxRelRetx = uint64(rel.nRetx)
+		cnt.TxRelLost = uint64(rel.nLost)
+		cnt.TxRelIdle = uint64(rel.nIdle)
+	}
 }
 
 // Counters contains face counters.
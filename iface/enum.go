@@ -37,6 +37,15 @@ const (
 	// MaxMTU is the maximum value of Maximum Transmission Unit (MTU).
 	MaxMTU = 65000
 
+	// MinReliabilityWindow is the minimum number of unacknowledged frames in NDNLPv2 link reliability.
+	MinReliabilityWindow = 64
+
+	// MaxReliabilityWindow is the maximum number of unacknowledged frames in NDNLPv2 link reliability.
+	MaxReliabilityWindow = 65536
+
+	// DefaultReliabilityWindow is the default number of unacknowledged frames in NDNLPv2 link reliability.
+	DefaultReliabilityWindow = 1024
+
 	_ = "enumgen"
 )
 
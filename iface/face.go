@@ -8,11 +8,13 @@ import "C"
 import (
 	"fmt"
 	"io"
+	stdmath "math"
 	"unsafe"
 
 	"github.com/pkg/math"
 	"github.com/usnistgov/ndn-dpdk/core/cptr"
 	"github.com/usnistgov/ndn-dpdk/core/logging"
+	"github.com/usnistgov/ndn-dpdk/core/nnduration"
 	"github.com/usnistgov/ndn-dpdk/dpdk/eal"
 	"github.com/usnistgov/ndn-dpdk/dpdk/ringbuffer"
 	"github.com/usnistgov/ndn-dpdk/ndni"
@@ -71,9 +73,47 @@ type Config struct {
 	// If this is less than MinMTU or greater than the maximum, the face will fail to initialize.
 	MTU int `json:"mtu,omitempty"`
 
+	// Reliability contains NDNLPv2 link reliability options.
+	Reliability ReliabilityConfig `json:"reliability,omitempty"`
+
 	maxMTU int
 }
 
+// ReliabilityConfig contains NDNLPv2 link reliability options.
+//
+// When enabled, each outgoing frame carries a TxSequence field, and is retransmitted if it is not
+// acknowledged within the retransmission timeout. Both ends of the link should enable this feature.
+type ReliabilityConfig struct {
+	// Enabled enables NDNLPv2 link reliability.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// WindowSize is the maximum number of unacknowledged frames.
+	// If the window is full, the oldest unacknowledged frame is considered lost.
+	//
+	// If this value is zero, it defaults to DefaultReliabilityWindow.
+	// Otherwise, it is adjusted up to the next power of 2, and clamped between MinReliabilityWindow and MaxReliabilityWindow.
+	WindowSize int `json:"windowSize,omitempty"`
+
+	// RTO is the retransmission timeout.
+	// Default is 100ms.
+	RTO nnduration.Milliseconds `json:"rto,omitempty"`
+
+	// MaxRetx is the maximum number of retransmissions of a frame.
+	// Default is 3. Maximum is 255.
+	MaxRetx int `json:"maxRetx,omitempty"`
+}
+
+func (c *ReliabilityConfig) applyDefaults() {
+	c.WindowSize = ringbuffer.AlignCapacity(c.WindowSize, MinReliabilityWindow, DefaultReliabilityWindow, MaxReliabilityWindow)
+	if c.RTO == 0 {
+		c.RTO = 100
+	}
+	if c.MaxRetx <= 0 {
+		c.MaxRetx = 3
+	}
+	c.MaxRetx = math.MinInt(c.MaxRetx, stdmath.MaxUint8)
+}
+
 // ApplyDefaults applies defaults.
 func (c *Config) ApplyDefaults() {
 	if c.ReassemblerCapacity == 0 {
@@ -82,6 +122,10 @@ func (c *Config) ApplyDefaults() {
 	c.ReassemblerCapacity = math.MinInt(math.MaxInt(MinReassemblerCapacity, c.ReassemblerCapacity), MaxReassemblerCapacity)
 
 	c.OutputQueueSize = ringbuffer.AlignCapacity(c.OutputQueueSize, MinOutputQueueSize, DefaultOutputQueueSize)
+
+	if c.Reliability.Enabled {
+		c.Reliability.applyDefaults()
+	}
 }
 
 // WithMaxMTU returns a copy of Config with consideration of device MTU.
@@ -201,9 +245,13 @@ func newFace(p NewParams) (Face, error) {
 	}
 	logEntry = logEntry.With(LocatorZapField("locator", f.Locator()))
 
+	fragmentPayloadSize := p.MTU - ndni.LpHeaderHeadroom
+	if p.Reliability.Enabled {
+		fragmentPayloadSize -= ndni.LpReliabilityHeadroom
+	}
 	c.txAlign = C.PacketTxAlign{
 		linearize:           C.bool(initResult.TxLinearize),
-		fragmentPayloadSize: C.uint16_t(p.MTU - ndni.LpHeaderHeadroom),
+		fragmentPayloadSize: C.uint16_t(fragmentPayloadSize),
 	}
 	c.impl.tx.l2Burst = (C.Face_L2TxBurst)(initResult.L2TxBurst)
 	(*ndni.Mempools)(unsafe.Pointer(&c.impl.tx.mp)).Assign(p.Socket)
@@ -229,6 +277,13 @@ func newFace(p NewParams) (Face, error) {
 		}
 	}
 
+	if p.Reliability.Enabled {
+		if e := f.initReliability(p.Reliability, p.Socket, c.txAlign); e != nil {
+			logEntry.Warn("LpReliability error", zap.Error(e))
+			return f.clear(), e
+		}
+	}
+
 	C.TxProc_Init(&c.impl.tx, c.txAlign)
 
 	if e := p.Start(); e != nil {
@@ -293,6 +348,29 @@ func (f *face) close() error {
 	return nil
 }
 
+func (f *face) initReliability(cfg ReliabilityConfig, socket eal.NumaSocket, align C.PacketTxAlign) error {
+	c := f.ptr()
+	rel := (*C.LpReliability)(eal.ZmallocAligned("LpReliability",
+		C.sizeof_LpReliability+uintptr(cfg.WindowSize)*C.sizeof_LpRelEntry, 1, socket))
+	c.impl.tx.rel, c.impl.rx.rel = rel, rel
+
+	for _, ring := range []**C.struct_rte_ring{&rel.toAck, &rel.acked} {
+		r, e := ringbuffer.New(cfg.WindowSize, socket, ringbuffer.ProducerMulti, ringbuffer.ConsumerSingle)
+		if e != nil {
+			return e
+		}
+		*ring = (*C.struct_rte_ring)(r.Ptr())
+	}
+
+	(*ndni.Mempools)(unsafe.Pointer(&rel.mp)).Assign(socket)
+	rel.linearize = align.linearize
+	rel.rto = C.TscDuration(eal.ToTscDuration(cfg.RTO.Duration()))
+	rel.maxRetx = C.uint8_t(cfg.MaxRetx)
+	rel.windowMask = C.uint32_t(cfg.WindowSize - 1)
+	C.LpReliability_Init(rel)
+	return nil
+}
+
 func (f *face) clear() Face {
 	id, c := f.id, f.ptr()
 	c.state = StateRemoved
@@ -300,6 +378,15 @@ func (f *face) clear() Face {
 		for i := 0; i < MaxRxProcThreads; i++ {
 			C.Reassembler_Close(&c.impl.rx.threads[i].reass)
 		}
+		if rel := c.impl.tx.rel; rel != nil {
+			C.LpReliability_Close(rel)
+			for _, ring := range []*C.struct_rte_ring{rel.toAck, rel.acked} {
+				if ring != nil {
+					must.Close(ringbuffer.FromPtr(unsafe.Pointer(ring)))
+				}
+			}
+			eal.Free(rel)
+		}
 		eal.Free(c.impl)
 	}
 	if c.outputQueue != nil {
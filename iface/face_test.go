@@ -1,11 +1,17 @@
 package iface_test
 
 import (
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/usnistgov/ndn-dpdk/iface"
 	"github.com/usnistgov/ndn-dpdk/iface/intface"
+	"github.com/usnistgov/ndn-dpdk/iface/socketface"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/sockettransport"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
 	"github.com/usnistgov/ndn-dpdk/ndni"
 	"github.com/usnistgov/ndn-dpdk/ndni/ndnitestenv"
 	"go4.org/must"
@@ -38,6 +44,81 @@ func TestTxBurst(t *testing.T) {
 	assert.NotNil(collect.Get(0).Data)
 }
 
+func TestReliability(t *testing.T) {
+	assert, require := makeAR(t)
+
+	trA, trD, e := sockettransport.Pipe(sockettransport.Config{})
+	require.NoError(e)
+	var cfg socketface.Config
+	cfg.Reliability = iface.ReliabilityConfig{Enabled: true, RTO: 100, MaxRetx: 2}
+	face, e := socketface.Wrap(trD, cfg)
+	require.NoError(e)
+	defer must.Close(face)
+	id := face.ID()
+
+	recv := func() (pkt ndn.Packet) {
+		select {
+		case wire := <-trA.Rx():
+			d := tlv.DecodingBuffer(wire)
+			de, e := d.Element()
+			require.NoError(e)
+			require.EqualValues(an.TtLpPacket, de.Type)
+			var types []uint32
+			value := tlv.DecodingBuffer(de.Value)
+			for _, field := range value.Elements() {
+				types = append(types, field.Type)
+			}
+			if n := len(types); n > 0 && types[n-1] == an.TtLpPayload {
+				types = types[:n-1]
+			}
+			assert.True(sort.SliceIsSorted(types, func(i, j int) bool { return types[i] < types[j] }), "%x", types)
+			require.NoError(tlv.Decode(wire, &pkt))
+		case <-time.After(time.Second):
+			require.FailNow("frame not received")
+		}
+		return
+	}
+
+	iface.TxBurst(id, []*ndni.Packet{ndnitestenv.MakeInterest("/A", ndnitestenv.SetPitToken([]byte{0xA0, 0xA1}))})
+	frame1 := recv()
+	require.NotNil(frame1.Interest)
+	assert.Equal("/8=A", frame1.Interest.Name.String())
+	assert.Equal([]byte{0xA0, 0xA1}, frame1.Lp.PitToken)
+	assert.NotZero(frame1.Reliability.TxSequence)
+
+	frame2 := recv() // retransmission
+	require.NotNil(frame2.Interest)
+	assert.Equal("/8=A", frame2.Interest.Name.String())
+	assert.Equal([]byte{0xA0, 0xA1}, frame2.Lp.PitToken)
+	assert.NotZero(frame2.Reliability.TxSequence)
+	assert.NotEqual(frame1.Reliability.TxSequence, frame2.Reliability.TxSequence)
+
+	wire, e := tlv.EncodeFrom(&ndn.Packet{
+		Interest:    &ndn.Interest{Name: ndn.ParseName("/B")},
+		Reliability: ndn.LpReliability{TxSequence: 0xB0, Acks: []uint64{frame2.Reliability.TxSequence}},
+	})
+	require.NoError(e)
+	trA.Tx() <- wire
+
+	idle := recv()
+	assert.Nil(idle.Interest)
+	assert.Zero(idle.Reliability.TxSequence)
+	assert.Equal([]uint64{0xB0}, idle.Reliability.Acks)
+
+	iface.TxBurst(id, []*ndni.Packet{ndnitestenv.MakeData("/C")})
+	for i := 0; i <= 2; i++ {
+		frame := recv()
+		require.NotNil(frame.Data)
+		assert.Equal("/8=C", frame.Data.Name.String())
+	}
+
+	assert.Eventually(func() bool { return face.Counters().TxRelLost == 1 }, time.Second, 10*time.Millisecond)
+	cnt := face.Counters()
+	assert.EqualValues(1, cnt.TxRelAcked)
+	assert.EqualValues(3, cnt.TxRelRetx)
+	assert.EqualValues(1, cnt.TxRelIdle)
+}
+
 func TestEvents(t *testing.T) {
 	assert, _ := makeAR(t)
 
@@ -33,6 +33,7 @@ type IntFace struct {
 }
 
 // New creates an IntFace.
+// If cfg enables NDNLPv2 link reliability, it is enabled on both sides.
 func New(cfg socketface.Config) (*IntFace, error) {
 	var f IntFace
 
@@ -41,7 +42,9 @@ func New(cfg socketface.Config) (*IntFace, error) {
 		return nil, e
 	}
 
-	if f.A, e = l3.NewFace(trA, l3.FaceConfig{}); e != nil {
+	var cfgA l3.FaceConfig
+	cfgA.Reliability.Enabled = cfg.Reliability.Enabled
+	if f.A, e = l3.NewFace(trA, cfgA); e != nil {
 		return nil, e
 	}
 	if f.D, e = socketface.Wrap(trD, cfg); e != nil {
@@ -1,4 +1,4 @@
-// Package socketface implements UDP/TCP socket faces using Go net.Conn type.
+// Package socketface implements UDP/TCP/WebSocket/WebTransport socket faces using Go net.Conn type.
 package socketface
 
 /*
@@ -7,6 +7,7 @@ extern uint16_t go_SocketFace_TxBurst(Face* faceC, struct rte_mbuf** pkts, uint1
 */
 import "C"
 import (
+	"crypto/tls"
 	"unsafe"
 
 	"github.com/usnistgov/ndn-dpdk/core/nnduration"
@@ -33,6 +34,22 @@ type Config struct {
 	TxQueueSize          int                     `json:"txQueueSize,omitempty"`
 	RedialBackoffInitial nnduration.Milliseconds `json:"redialBackoffInitial,omitempty"`
 	RedialBackoffMaximum nnduration.Milliseconds `json:"redialBackoffMaximum,omitempty"`
+
+	// InsecureSkipVerify disables server certificate verification with "wss" and "h3" schemes.
+	// This should only be used for testing with self-signed certificates.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+func (cfg Config) transportConfig() (tc sockettransport.Config) {
+	tc.RxBufferLength = ndni.PacketMempool.Config().Dataroom
+	tc.RxQueueSize = cfg.RxQueueSize
+	tc.TxQueueSize = cfg.TxQueueSize
+	tc.RedialBackoffInitial = cfg.RedialBackoffInitial.Duration()
+	tc.RedialBackoffMaximum = cfg.RedialBackoffMaximum.Duration()
+	if cfg.InsecureSkipVerify {
+		tc.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return tc
 }
 
 // New creates a socket face.
@@ -46,12 +63,7 @@ func New(loc Locator) (iface.Face, error) {
 		cfg = *loc.Config
 	}
 
-	var dialer sockettransport.Dialer
-	dialer.RxBufferLength = ndni.PacketMempool.Config().Dataroom
-	dialer.RxQueueSize = cfg.RxQueueSize
-	dialer.TxQueueSize = cfg.TxQueueSize
-	dialer.RedialBackoffInitial = cfg.RedialBackoffInitial.Duration()
-	dialer.RedialBackoffMaximum = cfg.RedialBackoffMaximum.Duration()
+	dialer := sockettransport.Dialer{Config: cfg.transportConfig()}
 	transport, e := dialer.Dial(loc.Network, loc.Local, loc.Remote)
 	if e != nil {
 		return nil, e
@@ -62,9 +74,17 @@ func New(loc Locator) (iface.Face, error) {
 
 // Wrap wraps a sockettransport.Transport to a socket face.
 func Wrap(transport sockettransport.Transport, cfg Config) (iface.Face, error) {
+	return wrap(transport, cfg, nil)
+}
+
+func wrap(transport sockettransport.Transport, cfg Config, observer RxObserver) (iface.Face, error) {
 	face := &socketFace{
-		transport: transport,
-		rxMempool: ndni.PacketMempool.Get(eal.NumaSocket{}),
+		transport:  transport,
+		rxMempool:  ndni.PacketMempool.Get(eal.NumaSocket{}),
+		rxObserver: observer,
+	}
+	if mtu := transport.MTU(); mtu > 0 {
+		cfg.Config = cfg.Config.WithMaxMTU(mtu)
 	}
 	return iface.New(iface.NewParams{
 		Config: cfg.Config,
@@ -119,8 +139,9 @@ func Wrap(transport sockettransport.Transport, cfg Config) (iface.Face, error) {
 // socketFace is a face using socket as transport.
 type socketFace struct {
 	iface.Face
-	transport sockettransport.Transport
-	rxMempool *pktmbuf.Pool
+	transport  sockettransport.Transport
+	rxMempool  *pktmbuf.Pool
+	rxObserver RxObserver
 }
 
 func (face *socketFace) ptr() *C.Face {
@@ -129,6 +150,10 @@ func (face *socketFace) ptr() *C.Face {
 
 func (face *socketFace) rxLoop() {
 	for wire := range face.transport.Rx() {
+		if face.rxObserver != nil {
+			face.rxObserver(face.ID(), wire)
+		}
+
 		vec, e := face.rxMempool.Alloc(1)
 		if e != nil { // ignore alloc error
 			continue
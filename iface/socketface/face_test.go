@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"os"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -148,3 +150,104 @@ func TestUnix(t *testing.T) {
 		return face
 	})
 }
+
+func checkListener(t *testing.T, locA socketface.Locator) {
+	assert, require := makeAR(t)
+	fixture := ifacetestenv.NewFixture(t)
+
+	newFaces := make(chan iface.ID, 2)
+	defer iface.OnFaceNew(func(id iface.ID) {
+		newFaces <- id
+	})()
+
+	ifacetestenv.CheckLocatorMarshal(t, locA)
+	faceA, e := socketface.New(locA)
+	require.NoError(e)
+	loc := faceA.Locator().(socketface.Locator)
+	assert.Equal(locA.Scheme(), loc.Scheme())
+	assert.Equal(locA.Remote, loc.Remote)
+
+	var faceB iface.Face
+	for faceB == nil {
+		select {
+		case id := <-newFaces:
+			if id != faceA.ID() {
+				faceB = iface.Get(id)
+			}
+		case <-time.After(time.Second):
+			require.FailNow("face not accepted")
+		}
+	}
+	assert.Equal(locA.Scheme(), faceB.Locator().Scheme())
+
+	fixture.RunTest(faceA, faceB)
+	fixture.CheckCounters()
+
+	closedB := make(chan struct{})
+	defer iface.OnFaceClosed(func(id iface.ID) {
+		if id == faceB.ID() {
+			close(closedB)
+		}
+	})()
+	must.Close(faceA)
+	select {
+	case <-closedB:
+	case <-time.After(5 * time.Second):
+		assert.Fail("accepted face not closed after client disconnects")
+	}
+}
+
+func TestUnixListener(t *testing.T) {
+	assert, require := makeAR(t)
+
+	var observed sync.Map // iface.ID => true
+	addr, del := testenv.TempName("unix.sock")
+	defer del()
+	listener, e := socketface.ListenUnix(socketface.UnixListenerConfig{
+		Listen: addr,
+		Mode:   0o600,
+		RxObserver: func(id iface.ID, wire []byte) {
+			observed.Store(id, true)
+		},
+	})
+	require.NoError(e)
+	defer listener.Close()
+
+	fi, e := os.Stat(addr)
+	require.NoError(e)
+	assert.EqualValues(0o600, fi.Mode().Perm())
+
+	checkListener(t, mustParseLocator(fmt.Sprintf(`{ "scheme": "unix", "remote": "%s" }`, addr)))
+
+	nObserved := 0
+	observed.Range(func(key, value interface{}) bool {
+		nObserved++
+		return true
+	})
+	assert.Equal(1, nObserved) // accepted face only, not the dialing face
+}
+
+func TestWebSocket(t *testing.T) {
+	_, require := makeAR(t)
+
+	listener, e := socketface.ListenWebSocket(socketface.WebSocketListenerConfig{Listen: "127.0.0.1:0"})
+	require.NoError(e)
+	defer listener.Close()
+
+	checkListener(t, mustParseLocator(fmt.Sprintf(`{ "scheme": "ws", "remote": "ws://%s/" }`, listener.Addr())))
+}
+
+func TestWebTransport(t *testing.T) {
+	assert, require := makeAR(t)
+
+	listener, e := socketface.ListenWebTransport(socketface.WebTransportListenerConfig{Listen: "127.0.0.1:0"})
+	require.NoError(e)
+	defer listener.Close()
+
+	loc := mustParseLocator(fmt.Sprintf(`{ "scheme": "h3", "remote": "https://%s/ndn" }`, listener.Addr()))
+	_, e = socketface.New(loc)
+	assert.Error(e) // self-signed certificate
+
+	loc.Config = &socketface.Config{InsecureSkipVerify: true}
+	checkListener(t, loc)
+}
@@ -0,0 +1,282 @@
+package socketface
+
+import (
+	"crypto/tls"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/core/logging"
+	"github.com/usnistgov/ndn-dpdk/iface"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"github.com/usnistgov/ndn-dpdk/ndn/sockettransport"
+	"go.uber.org/zap"
+)
+
+var logger = logging.New("socketface")
+
+// Default listen addresses.
+const (
+	// DefaultUnixListen is the default Unix socket listen path, same as NFD.
+	DefaultUnixListen = "/run/nfd/nfd.sock"
+
+	// DefaultWebSocketListen is the default WebSocket listen address, same as NFD.
+	DefaultWebSocketListen = ":9696"
+
+	// DefaultWebTransportListen is the default WebTransport listen address.
+	DefaultWebTransportListen = ":443"
+)
+
+// RxObserver examines a packet received on a face accepted by a listener, before it enters the forwarder.
+// id is the receiving face. wire must not be modified or retained.
+//
+// This is invoked on the RX goroutine of each face, and thus should return quickly.
+type RxObserver func(id iface.ID, wire []byte)
+
+// acceptFace creates a socket face on an accepted transport.
+// The face is closed when the transport goes down, because an accepted transport cannot be redialed.
+func acceptFace(transport sockettransport.Transport, cfg Config, kind string, observer RxObserver) {
+	remote := transport.Conn().RemoteAddr().String()
+	face, e := wrap(transport, cfg, observer)
+	if e != nil {
+		logger.Warn("face creation error", zap.String("listener", kind), zap.String("remote", remote), zap.Error(e))
+		close(transport.Tx())
+		return
+	}
+	logger.Info("face accepted", zap.String("listener", kind), face.ID().ZapField("face"), zap.String("remote", remote))
+
+	var closeOnce sync.Once
+	transport.OnStateChange(func(st l3.TransportState) {
+		if st == l3.TransportDown {
+			closeOnce.Do(func() { go face.Close() })
+		}
+	})
+}
+
+// UnixListenerConfig contains Unix stream socket listener configuration.
+type UnixListenerConfig struct {
+	// Listen is the socket path to listen on.
+	// Default is DefaultUnixListen.
+	// If the file exists, it is deleted before listening.
+	Listen string `json:"listen,omitempty"`
+
+	// Mode is the permission bits of the socket file, which determine which local users can connect.
+	// Default is 0o666, same as NFD, which allows every local user to connect.
+	Mode os.FileMode `json:"mode,omitempty"`
+
+	// Face contains configuration of accepted faces.
+	Face Config `json:"face,omitempty"`
+
+	// RxObserver, if not nil, is invoked on every packet received on accepted faces.
+	RxObserver RxObserver `json:"-"`
+}
+
+// UnixListener accepts Unix stream socket connections, and creates a socket face for each connection.
+//
+// This allows local applications designed for NFD to connect to the forwarder.
+// An accepted face is closed when the application disconnects.
+type UnixListener struct {
+	ln net.Listener
+}
+
+// ListenUnix starts a Unix stream socket listener.
+func ListenUnix(cfg UnixListenerConfig) (*UnixListener, error) {
+	if cfg.Listen == "" {
+		cfg.Listen = DefaultUnixListen
+	}
+	if cfg.Mode == 0 {
+		cfg.Mode = 0o666
+	}
+
+	if e := os.MkdirAll(filepath.Dir(cfg.Listen), 0o755); e != nil {
+		return nil, e
+	}
+	os.Remove(cfg.Listen) // ignore error
+	ln, e := net.Listen(NetworkUnix, cfg.Listen)
+	if e != nil {
+		return nil, e
+	}
+	if e := os.Chmod(cfg.Listen, cfg.Mode.Perm()); e != nil {
+		ln.Close()
+		return nil, e
+	}
+
+	l := &UnixListener{ln: ln}
+	go l.acceptLoop(cfg.Face, cfg.RxObserver)
+	logger.Info("Unix listener started", zap.String("listen", cfg.Listen))
+	return l, nil
+}
+
+func (l *UnixListener) acceptLoop(cfg Config, observer RxObserver) {
+	tc := cfg.transportConfig()
+	for {
+		conn, e := l.ln.Accept()
+		if e != nil {
+			return
+		}
+
+		tr, e := sockettransport.New(conn, tc)
+		if e != nil {
+			conn.Close()
+			continue
+		}
+		acceptFace(tr, cfg, NetworkUnix, observer)
+	}
+}
+
+// Addr returns the listening address.
+func (l *UnixListener) Addr() net.Addr {
+	return l.ln.Addr()
+}
+
+// Close stops accepting connections.
+// Faces that have been accepted are not closed.
+func (l *UnixListener) Close() error {
+	return l.ln.Close()
+}
+
+// WebSocketListenerConfig contains WebSocket listener configuration.
+type WebSocketListenerConfig struct {
+	// Listen is the TCP address to listen on.
+	// Default is DefaultWebSocketListen.
+	Listen string `json:"listen,omitempty"`
+
+	// Face contains configuration of accepted faces.
+	Face Config `json:"face,omitempty"`
+}
+
+// WebSocketListener accepts WebSocket connections, and creates a socket face for each connection.
+//
+// Each WebSocket binary message carries one NDN packet, same as NFD WebSocket face.
+// An accepted face is closed when the client disconnects.
+type WebSocketListener struct {
+	ln     net.Listener
+	server *http.Server
+}
+
+// ListenWebSocket starts a WebSocket listener.
+func ListenWebSocket(cfg WebSocketListenerConfig) (*WebSocketListener, error) {
+	if cfg.Listen == "" {
+		cfg.Listen = DefaultWebSocketListen
+	}
+
+	ln, e := net.Listen("tcp", cfg.Listen)
+	if e != nil {
+		return nil, e
+	}
+
+	l := &WebSocketListener{
+		ln: ln,
+		server: &http.Server{
+			Handler: sockettransport.WebSocketHandler{
+				Config: cfg.Face.transportConfig(),
+				Accept: func(tr sockettransport.Transport) { acceptFace(tr, cfg.Face, NetworkWebSocket, nil) },
+			},
+		},
+	}
+	go l.server.Serve(ln)
+	logger.Info("WebSocket listener started", zap.Stringer("listen", ln.Addr()))
+	return l, nil
+}
+
+// Addr returns the listening address.
+func (l *WebSocketListener) Addr() net.Addr {
+	return l.ln.Addr()
+}
+
+// Close stops accepting connections.
+// Faces that have been accepted are not closed.
+func (l *WebSocketListener) Close() error {
+	return l.server.Close()
+}
+
+// WebTransportListenerConfig contains WebTransport listener configuration.
+type WebTransportListenerConfig struct {
+	// Listen is the UDP address to listen on.
+	// Default is DefaultWebTransportListen.
+	Listen string `json:"listen,omitempty"`
+
+	// CertFile and KeyFile are filenames of TLS certificate and private key in PEM format.
+	// If omitted, a self-signed certificate is generated, and its SHA-256 hash is logged.
+	// The self-signed certificate is regenerated every WebTransportCertRenewal, before it expires.
+	CertFile string `json:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty"`
+
+	// Face contains configuration of accepted faces.
+	Face Config `json:"face,omitempty"`
+}
+
+// WebTransportCertRenewal is the interval of regenerating a self-signed WebTransport certificate.
+// It is half of the validity period of sockettransport.GenerateWebTransportCertificate.
+const WebTransportCertRenewal = 7 * 24 * time.Hour
+
+// selfSignedCert provides a self-signed WebTransport certificate, regenerated periodically.
+type selfSignedCert struct {
+	mutex   sync.Mutex
+	cert    tls.Certificate
+	renewAt time.Time
+}
+
+func (c *selfSignedCert) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if now := time.Now(); !now.Before(c.renewAt) {
+		cert, hash, e := sockettransport.GenerateWebTransportCertificate()
+		if e != nil {
+			logger.Warn("WebTransport self-signed certificate error", zap.Error(e))
+			if c.cert.Certificate == nil {
+				return nil, e
+			}
+			return &c.cert, nil
+		}
+		c.cert, c.renewAt = cert, now.Add(WebTransportCertRenewal)
+		logger.Info("WebTransport self-signed certificate generated", zap.String("sha256", hex.EncodeToString(hash[:])))
+	}
+	return &c.cert, nil
+}
+
+// WebTransportListener accepts HTTP/3 WebTransport sessions, and creates a socket face for each session.
+//
+// Each QUIC datagram carries one NDN packet or NDNLPv2 fragment.
+// An accepted face is closed when the client disconnects.
+type WebTransportListener struct {
+	*sockettransport.WebTransportListener
+}
+
+// ListenWebTransport starts a WebTransport listener.
+func ListenWebTransport(cfg WebTransportListenerConfig) (*WebTransportListener, error) {
+	if cfg.Listen == "" {
+		cfg.Listen = DefaultWebTransportListen
+	}
+
+	var tlsConfig tls.Config
+	if cfg.CertFile == "" && cfg.KeyFile == "" {
+		ssc := &selfSignedCert{}
+		if _, e := ssc.GetCertificate(nil); e != nil {
+			return nil, e
+		}
+		tlsConfig.GetCertificate = ssc.GetCertificate
+	} else {
+		cert, e := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if e != nil {
+			return nil, e
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	l, e := sockettransport.ListenWebTransport(cfg.Listen, sockettransport.WebTransportListenerConfig{
+		Config:    cfg.Face.transportConfig(),
+		TLSConfig: &tlsConfig,
+		Accept:    func(tr sockettransport.Transport) { acceptFace(tr, cfg.Face, NetworkWebTransport, nil) },
+	})
+	if e != nil {
+		return nil, e
+	}
+	logger.Info("WebTransport listener started", zap.Stringer("listen", l.Addr()))
+	return &WebTransportListener{l}, nil
+}
@@ -1,8 +1,10 @@
 package socketface
 
 import (
+	"errors"
 	"fmt"
 	"net"
+	"net/url"
 
 	"github.com/usnistgov/ndn-dpdk/iface"
 )
@@ -12,6 +14,10 @@ const (
 	NetworkUnix = "unix"
 	NetworkUDP  = "udp"
 	NetworkTCP  = "tcp"
+
+	NetworkWebSocket       = "ws"
+	NetworkWebSocketSecure = "wss"
+	NetworkWebTransport    = "h3"
 )
 
 // Locator describes network and addresses of a socket.
@@ -65,6 +71,30 @@ func (loc Locator) Validate() error {
 			}
 		}
 		return nil
+	case NetworkWebSocket, NetworkWebSocketSecure:
+		u, e := url.Parse(loc.Remote)
+		if e != nil {
+			return fmt.Errorf("remote %w", e)
+		}
+		if u.Scheme != loc.Network {
+			return fmt.Errorf("remote URL scheme must be %s", loc.Network)
+		}
+		if loc.Local != "" {
+			return fmt.Errorf("local is not accepted with %s scheme", loc.Network)
+		}
+		return nil
+	case NetworkWebTransport:
+		u, e := url.Parse(loc.Remote)
+		if e != nil {
+			return fmt.Errorf("remote %w", e)
+		}
+		if u.Scheme != "https" {
+			return errors.New("remote URL scheme must be https")
+		}
+		if loc.Local != "" {
+			return fmt.Errorf("local is not accepted with %s scheme", loc.Network)
+		}
+		return nil
 	}
 	return fmt.Errorf("unknown scheme %s", loc.Network)
 }
@@ -75,5 +105,5 @@ func (loc Locator) CreateFace() (iface.Face, error) {
 }
 
 func init() {
-	iface.RegisterLocatorType(Locator{}, NetworkUnix, NetworkUDP, NetworkTCP)
+	iface.RegisterLocatorType(Locator{}, NetworkUnix, NetworkUDP, NetworkTCP, NetworkWebSocket, NetworkWebSocketSecure, NetworkWebTransport)
 }
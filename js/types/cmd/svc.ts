@@ -1,7 +1,7 @@
 import type { EalConfig, LCoreAllocConfig, PktmbufPoolTemplateUpdates } from "../dpdk";
-import type { FwdpConfig } from "../fwdp";
+import type { FwdpConfig, FwdpNfdMgmtConfig } from "../fwdp";
 import type { HrlogWriterConfig } from "../hrlog";
-import type { FaceLocator } from "../iface";
+import type { FaceLocator, WebSocketListenerConfig, WebTransportListenerConfig } from "../iface";
 import type { FileServerConfig } from "../tg/mod";
 
 export interface ActivateArgsCommon<Roles extends string = never> {
@@ -18,6 +18,15 @@ export interface ActivateArgsCommon<Roles extends string = never> {
  */
 export interface ActivateFwArgs extends ActivateArgsCommon<"RX" | "TX" | "CRYPTO" | "FWD">, FwdpConfig {
   mempool?: PktmbufPoolTemplateUpdates<"DIRECT" | "INDIRECT" | "HEADER">;
+
+  /** WebSocket listener that creates a socket face for each accepted connection. */
+  webSocket?: WebSocketListenerConfig;
+
+  /** WebTransport listener that creates a socket face for each accepted session. */
+  webTransport?: WebTransportListenerConfig;
+
+  /** NFD management responder that allows local applications designed for NFD to connect. */
+  nfdMgmt?: FwdpNfdMgmtConfig;
 }
 
 /**
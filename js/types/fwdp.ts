@@ -1,5 +1,6 @@
 import type { Uint } from "./core";
 import type { FibConfig } from "./fib";
+import type { UnixListenerConfig } from "./iface";
 import type { NdtConfig } from "./ndt";
 import type { PcctConfig } from "./pcct";
 import type { SuppressConfig } from "./pit";
@@ -25,3 +26,23 @@ export interface FwdpCryptoConfig {
   inputCapacity?: Uint;
   opPoolCapacity?: Uint;
 }
+
+/**
+ * NFD management responder configuration.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/app/fwdp#NfdMgmtConfig>
+ */
+export interface FwdpNfdMgmtConfig {
+  unix?: UnixListenerConfig;
+
+  /**
+   * Trust anchor certificates that authorize control commands.
+   * Each is a base64 encoded certificate, such as the output of `ndnsec cert-dump` command.
+   */
+  anchors?: string[];
+
+  /**
+   * Trust schema rules that restrict signers of control commands.
+   * @default "<*> <= <*>"
+   */
+  trustSchema?: string;
+}
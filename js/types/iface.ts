@@ -37,6 +37,34 @@ export interface FaceConfig {
    * @maximum 65000
    */
   mtu?: Uint;
+
+  reliability?: FaceReliabilityConfig;
+}
+
+/**
+ * NDNLPv2 link reliability configuration.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/iface#ReliabilityConfig>
+ */
+export interface FaceReliabilityConfig {
+  /** @default false */
+  enabled?: boolean;
+
+  /**
+   * @minimum 64
+   * @maximum 65536
+   * @default 1024
+   */
+  windowSize?: Uint;
+
+  /** @default 100 */
+  rto?: NNMilliseconds;
+
+  /**
+   * @minimum 1
+   * @maximum 255
+   * @default 3
+   */
+  maxRetx?: Uint;
 }
 
 /**
@@ -185,6 +213,9 @@ export interface SocketFaceConfig extends FaceConfig {
   txQueueSize?: Uint;
   redialBackoffInitial?: NNMilliseconds;
   redialBackoffMaximum?: NNMilliseconds;
+
+  /** Disable server certificate verification with "wss" and "h3" schemes. */
+  insecureSkipVerify?: boolean;
 }
 
 /**
@@ -192,13 +223,68 @@ export interface SocketFaceConfig extends FaceConfig {
  * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/iface/socketface#Locator>
  */
 export interface SocketFaceLocator {
-  scheme: "udp" | "tcp" | "unix";
+  scheme: "udp" | "tcp" | "unix" | "ws" | "wss" | "h3";
   local?: string;
   remote: string;
 
   config?: SocketFaceConfig;
 }
 
+/**
+ * Unix stream socket listener configuration.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/iface/socketface#UnixListenerConfig>
+ */
+export interface UnixListenerConfig {
+  /**
+   * Socket path to listen on.
+   * @default "/run/nfd/nfd.sock"
+   */
+  listen?: string;
+
+  /**
+   * Permission bits of the socket file, written in decimal (438 is 0o666).
+   * @default 438
+   */
+  mode?: Uint;
+
+  face?: SocketFaceConfig;
+}
+
+/**
+ * WebSocket listener configuration.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/iface/socketface#WebSocketListenerConfig>
+ */
+export interface WebSocketListenerConfig {
+  /**
+   * TCP address to listen on.
+   * @default ":9696"
+   */
+  listen?: string;
+
+  face?: SocketFaceConfig;
+}
+
+/**
+ * WebTransport listener configuration.
+ * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/iface/socketface#WebTransportListenerConfig>
+ */
+export interface WebTransportListenerConfig {
+  /**
+   * UDP address to listen on.
+   * @default ":443"
+   */
+  listen?: string;
+
+  /**
+   * TLS certificate and private key filenames in PEM format.
+   * If omitted, a self-signed certificate is generated, and regenerated every 7 days.
+   */
+  certFile?: string;
+  keyFile?: string;
+
+  face?: SocketFaceConfig;
+}
+
 /**
  * Face counters.
  * @see <https://pkg.go.dev/github.com/usnistgov/ndn-dpdk/iface#Counters>
@@ -230,4 +316,9 @@ export interface FaceTxCounters {
   txFragBad: Counter;
   txAllocErrs: Counter;
   txDropped: Counter;
+
+  txRelAcked: Counter;
+  txRelRetx: Counter;
+  txRelLost: Counter;
+  txRelIdle: Counter;
 }
@@ -11,6 +11,7 @@ install -m0755 build/bin/ndndpdk-ctrl "$DESTBIN/"
 install -m0755 build/bin/ndndpdk-godemo "$DESTBIN/"
 install -m0755 build/bin/ndndpdk-hrlog2histogram "$DESTBIN/"
 install -m0755 build/bin/ndndpdk-jrproxy "$DESTBIN/"
+install -m0755 build/bin/ndndpdk-pdump-analyze "$DESTBIN/"
 install -m0755 build/bin/ndndpdk-svc "$DESTBIN/"
 
 install -d -m0755 "$DESTSHARE"
@@ -13,6 +13,8 @@ const (
 	TtNack           = 0x0320
 	TtNackReason     = 0x0321
 	TtCongestionMark = 0x0340
+	TtAck            = 0x0344
+	TtTxSequence     = 0x0348
 
 	TtName                            = 0x07
 	TtGenericNameComponent            = 0x08
@@ -56,5 +58,8 @@ const (
 	TtNotBefore      = 0x00FE
 	TtNotAfter       = 0x00FF
 
+	TtSafeBag         = 0x80
+	TtEncryptedKeyBag = 0x81
+
 	_ = "enumgen"
 )
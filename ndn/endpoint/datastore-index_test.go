@@ -0,0 +1,28 @@
+package endpoint
+
+import (
+	"testing"
+
+	"github.com/usnistgov/ndn-dpdk/core/testenv"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+)
+
+func TestMemoryDataStoreIndex(t *testing.T) {
+	assert, _ := testenv.MakeAR(t)
+
+	s := NewMemoryDataStore(MemoryDataStoreOptions{})
+	s.Put(ndn.MakeData("/A/2"))
+	s.names = append([]ndn.Name{ndn.ParseName("/A/1")}, s.names...) // indexed but missing from the LRU
+
+	data, ok := s.Get(ndn.MakeInterest("/A", ndn.CanBePrefixFlag))
+	if assert.True(ok) {
+		assert.Equal("/8=A/8=2", data.Name.String())
+	}
+	assert.Len(s.names, 1)
+
+	s.names = append(s.names, ndn.ParseName("/A/3"))
+	s.Delete(ndn.ParseName("/A/2"))
+	_, ok = s.Get(ndn.MakeInterest("/A", ndn.CanBePrefixFlag))
+	assert.False(ok)
+	assert.Len(s.names, 0)
+}
@@ -0,0 +1,185 @@
+package endpoint
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+)
+
+// DataStore is a Data packet store used by a producer.
+type DataStore interface {
+	// Get finds a Data packet that can satisfy the Interest.
+	Get(interest ndn.Interest) (data ndn.Data, ok bool)
+
+	// Put inserts a Data packet.
+	// It replaces any existing Data packet of the same name.
+	Put(data ndn.Data)
+}
+
+// MemoryDataStoreOptions contains arguments to NewMemoryDataStore function.
+type MemoryDataStoreOptions struct {
+	// Capacity is the maximum number of Data packets.
+	// When exceeded, the least recently used Data packet is evicted.
+	// Default is 4096.
+	Capacity int
+
+	// StaleLifetime is how long a Data packet is kept after its FreshnessPeriod elapses.
+	// During this time, it can satisfy Interests without MustBeFresh.
+	// Default is zero, which means a Data packet is kept until it is evicted or deleted.
+	StaleLifetime time.Duration
+}
+
+func (opts *MemoryDataStoreOptions) applyDefaults() {
+	if opts.Capacity <= 0 {
+		opts.Capacity = 4096
+	}
+}
+
+type memoryDataStoreEntry struct {
+	data     ndn.Data
+	staleAt  time.Time
+	expireAt time.Time // zero means never
+}
+
+func (entry *memoryDataStoreEntry) canSatisfy(interest ndn.Interest, now time.Time) bool {
+	if interest.MustBeFresh && !now.Before(entry.staleAt) {
+		return false
+	}
+	return entry.data.CanSatisfy(interest)
+}
+
+// MemoryDataStore is an in-memory DataStore with LRU eviction.
+type MemoryDataStore struct {
+	opts  MemoryDataStoreOptions
+	mutex sync.Mutex
+	lru   *simplelru.LRU // name string => *memoryDataStoreEntry
+	names []ndn.Name     // sorted in canonical order, for prefix match
+}
+
+var _ DataStore = (*MemoryDataStore)(nil)
+
+// NewMemoryDataStore creates a MemoryDataStore.
+func NewMemoryDataStore(opts MemoryDataStoreOptions) *MemoryDataStore {
+	opts.applyDefaults()
+	s := &MemoryDataStore{
+		opts: opts,
+	}
+	s.lru, _ = simplelru.NewLRU(opts.Capacity, s.evict)
+	return s
+}
+
+// search returns the position of the first name not less than name.
+// Caller must hold the mutex.
+func (s *MemoryDataStore) search(name ndn.Name) int {
+	return sort.Search(len(s.names), func(i int) bool { return s.names[i].Compare(name) >= 0 })
+}
+
+// evict removes an entry from the name index, when it is removed from the LRU.
+// Caller must hold the mutex.
+func (s *MemoryDataStore) evict(key, value interface{}) {
+	name := value.(*memoryDataStoreEntry).data.Name
+	if i := s.search(name); i < len(s.names) && s.names[i].Equal(name) {
+		s.names = append(s.names[:i], s.names[i+1:]...)
+	}
+}
+
+// Len returns number of stored Data packets, including expired packets not yet evicted.
+func (s *MemoryDataStore) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.lru.Len()
+}
+
+// Put implements DataStore interface.
+func (s *MemoryDataStore) Put(data ndn.Data) {
+	now := time.Now()
+	entry := &memoryDataStoreEntry{
+		data:    data,
+		staleAt: now.Add(data.Freshness),
+	}
+	if s.opts.StaleLifetime > 0 {
+		entry.expireAt = entry.staleAt.Add(s.opts.StaleLifetime)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	key := data.Name.String()
+	if s.lru.Contains(key) { // replace existing entry, name is already indexed
+		s.lru.Add(key, entry)
+		return
+	}
+	s.lru.Add(key, entry)
+	i := s.search(data.Name)
+	s.names = append(s.names, nil)
+	copy(s.names[i+1:], s.names[i:])
+	s.names[i] = data.Name
+}
+
+// Delete removes a Data packet by exact name.
+func (s *MemoryDataStore) Delete(name ndn.Name) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.lru.Remove(name.String())
+}
+
+// Get implements DataStore interface.
+// If multiple Data packets match a CanBePrefix Interest, the first in canonical order is returned.
+func (s *MemoryDataStore) Get(interest ndn.Interest) (data ndn.Data, ok bool) {
+	if len(interest.Name) == 0 {
+		return data, false
+	}
+	now := time.Now()
+
+	name := interest.Name
+	if name[len(name)-1].Type == an.TtImplicitSha256DigestComponent {
+		name = name.GetPrefix(-1)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !interest.CanBePrefix {
+		key := name.String()
+		if entry := s.lookup(key, now); entry != nil && entry.canSatisfy(interest, now) {
+			s.lru.Get(key)
+			return entry.data, true
+		}
+		return data, false
+	}
+
+	for i := s.search(name); i < len(s.names) && name.IsPrefixOf(s.names[i]); {
+		key, nNames := s.names[i].String(), len(s.names)
+		entry := s.lookup(key, now)
+		if entry == nil {
+			if len(s.names) == nNames { // name is missing from the LRU, so that evict did not remove it
+				s.names = append(s.names[:i], s.names[i+1:]...)
+			}
+			continue
+		}
+		if entry.canSatisfy(interest, now) {
+			s.lru.Get(key)
+			return entry.data, true
+		}
+		i++
+	}
+	return data, false
+}
+
+// lookup retrieves an entry without updating recentness, and evicts the entry if it has expired.
+// Caller must hold the mutex.
+func (s *MemoryDataStore) lookup(key interface{}, now time.Time) *memoryDataStoreEntry {
+	value, ok := s.lru.Peek(key)
+	if !ok {
+		return nil
+	}
+	entry := value.(*memoryDataStoreEntry)
+	if !entry.expireAt.IsZero() && now.After(entry.expireAt) {
+		s.lru.Remove(key)
+		return nil
+	}
+	return entry
+}
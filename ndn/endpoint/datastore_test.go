@@ -0,0 +1,124 @@
+package endpoint_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+)
+
+func TestMemoryDataStore(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	store := endpoint.NewMemoryDataStore(endpoint.MemoryDataStoreOptions{
+		Capacity:      3,
+		StaleLifetime: 200 * time.Millisecond,
+	})
+	dataA := ndn.MakeData("/A/1", 100*time.Millisecond)
+	dataB := ndn.MakeData("/B/1")
+	store.Put(dataA)
+	store.Put(dataB)
+	assert.Equal(2, store.Len())
+
+	getName := func(interest ndn.Interest) string {
+		if data, ok := store.Get(interest); ok {
+			return data.Name.String()
+		}
+		return ""
+	}
+
+	assert.Equal("/8=A/8=1", getName(ndn.MakeInterest("/A/1")))
+	assert.Equal("", getName(ndn.MakeInterest("/A")))
+	assert.Equal("/8=A/8=1", getName(ndn.MakeInterest("/A", ndn.CanBePrefixFlag)))
+	assert.Equal("/8=A/8=1", getName(ndn.MakeInterest("/A/1", ndn.MustBeFreshFlag)))
+	assert.Equal("/8=A/8=1", getName(ndn.MakeInterest(dataA.FullName())))
+	assert.Equal("", getName(ndn.MakeInterest(dataA.Name.Append(dataB.FullName().Get(-1)))))
+	assert.Equal("/8=B/8=1", getName(ndn.MakeInterest("/B/1")))
+	assert.Equal("", getName(ndn.MakeInterest("/B/1", ndn.MustBeFreshFlag)))
+	assert.Equal("", getName(ndn.MakeInterest("/C", ndn.CanBePrefixFlag)))
+
+	time.Sleep(150 * time.Millisecond) // dataA becomes stale
+	assert.Equal("", getName(ndn.MakeInterest("/A/1", ndn.MustBeFreshFlag)))
+	assert.Equal("/8=A/8=1", getName(ndn.MakeInterest("/A/1")))
+
+	store.Put(ndn.MakeData("/C/1"))
+	store.Get(ndn.MakeInterest("/A/1")) // dataA becomes most recently used
+	store.Put(ndn.MakeData("/C/2"))     // dataB is evicted
+	assert.Equal(3, store.Len())
+	assert.Equal("", getName(ndn.MakeInterest("/B/1")))
+	assert.Equal("", getName(ndn.MakeInterest("/B", ndn.CanBePrefixFlag)))
+	assert.Equal("/8=C/8=1", getName(ndn.MakeInterest("/C", ndn.CanBePrefixFlag))) // canonical order
+	assert.Equal("/8=C/8=2", getName(ndn.MakeInterest("/C/2", ndn.CanBePrefixFlag)))
+
+	store.Delete(ndn.ParseName("/C/1"))
+	assert.Equal("/8=C/8=2", getName(ndn.MakeInterest("/C", ndn.CanBePrefixFlag)))
+
+	time.Sleep(200 * time.Millisecond) // dataA expires
+	assert.Equal("", getName(ndn.MakeInterest("/A/1")))
+
+	store = endpoint.NewMemoryDataStore(endpoint.MemoryDataStoreOptions{})
+	store.Put(ndn.MakeData("/D/1", 10*time.Millisecond))
+	time.Sleep(50 * time.Millisecond) // stale Data is kept without StaleLifetime
+	assert.Equal("", getName(ndn.MakeInterest("/D/1", ndn.MustBeFreshFlag)))
+	assert.Equal("/8=D/8=1", getName(ndn.MakeInterest("/D/1")))
+}
+
+func TestProducerDataStore(t *testing.T) {
+	defer l3.DeleteDefaultForwarder()
+	assert, require := makeAR(t)
+
+	store := endpoint.NewMemoryDataStore(endpoint.MemoryDataStoreOptions{})
+	store.Put(ndn.MakeData("/P/1", time.Second))
+
+	p, e := endpoint.Produce(context.Background(), endpoint.ProducerOptions{
+		Prefix:     ndn.ParseName("/P"),
+		DataStore:  store,
+		DataSigner: ndn.DigestSigning,
+	})
+	require.NoError(e)
+	defer p.Close()
+
+	for i := 0; i < 2; i++ {
+		data, e := endpoint.Consume(context.Background(), ndn.MakeInterest("/P", ndn.CanBePrefixFlag, ndn.MustBeFreshFlag),
+			endpoint.ConsumerOptions{})
+		if assert.NoError(e) {
+			nameEqual(assert, "/P/1", data)
+			assert.Nil(data.SigInfo) // stored Data is served unchanged
+		}
+	}
+	assert.Equal(1, store.Len())
+
+	_, e = endpoint.Consume(context.Background(), ndn.MakeInterest("/P/2", 100*time.Millisecond),
+		endpoint.ConsumerOptions{})
+	assert.EqualError(e, endpoint.ErrExpire.Error())
+}
+
+func TestProducerDataStoreHandler(t *testing.T) {
+	defer l3.DeleteDefaultForwarder()
+	assert, require := makeAR(t)
+
+	var invokeCount int
+	store := endpoint.NewMemoryDataStore(endpoint.MemoryDataStoreOptions{})
+	p, e := endpoint.Produce(context.Background(), endpoint.ProducerOptions{
+		Prefix: ndn.ParseName("/P"),
+		Handler: func(ctx context.Context, interest ndn.Interest) (ndn.Data, error) {
+			invokeCount++
+			return ndn.MakeData(interest), nil
+		},
+		DataStore: store,
+	})
+	require.NoError(e)
+	defer p.Close()
+
+	for i := 0; i < 3; i++ {
+		data, e := endpoint.Consume(context.Background(), ndn.MakeInterest("/P/1"), endpoint.ConsumerOptions{})
+		if assert.NoError(e) {
+			nameEqual(assert, "/P/1", data)
+		}
+	}
+	assert.Equal(1, invokeCount)
+	assert.Equal(1, store.Len())
+}
@@ -46,20 +46,26 @@ type ProducerOptions struct {
 
 	// Handler is a function to handle Interests under the prefix.
 	// This may be invoked concurrently.
+	// This may be omitted if DataStore is specified, in which case only stored Data are served.
 	Handler ProducerHandler
 
+	// DataStore answers Interests before Handler is invoked, and retains Data returned by Handler.
+	// Stored Data are served unchanged; Data inserted by the application should be signed beforehand.
+	// Default is invoking Handler for every Interest.
+	DataStore DataStore
+
 	// Fw specifies the L3 Forwarder.
 	// Default is the default Forwarder.
 	Fw l3.Forwarder
 
-	// DataSigner automatically signs Data packets unless already signed.
+	// DataSigner automatically signs Data packets returned by Handler unless already signed.
 	// Default is keeping the Null signature.
 	DataSigner ndn.Signer
 }
 
 // Produce starts a producer.
 func Produce(ctx context.Context, opts ProducerOptions) (Producer, error) {
-	if opts.Handler == nil {
+	if opts.Handler == nil && opts.DataStore == nil {
 		return nil, ErrNoHandler
 	}
 
@@ -132,9 +138,20 @@ func (p *producer) handleInterest(ctx context.Context, wg *sync.WaitGroup, pkt *
 		return
 	}
 
-	ctx1, cancel1 := context.WithTimeout(ctx, interest.ApplyDefaultLifetime())
-	defer cancel1()
-	data, e := p.Handler(ctx1, *interest)
+	var data ndn.Data
+	var e error
+	stored := false
+	if p.DataStore != nil {
+		data, stored = p.DataStore.Get(*interest)
+	}
+	if !stored {
+		if p.Handler == nil {
+			return
+		}
+		ctx1, cancel1 := context.WithTimeout(ctx, interest.ApplyDefaultLifetime())
+		defer cancel1()
+		data, e = p.Handler(ctx1, *interest)
+	}
 
 	var reply *ndn.Packet
 	if e != nil {
@@ -143,9 +160,14 @@ func (p *producer) handleInterest(ctx context.Context, wg *sync.WaitGroup, pkt *
 			reply = nack.ToPacket()
 		}
 	} else if data.CanSatisfy(*interest) {
-		if (data.SigInfo == nil || data.SigInfo.Type == an.SigNull) && p.DataSigner != nil {
-			if e := p.DataSigner.Sign(&data); e != nil {
-				return
+		if !stored {
+			if (data.SigInfo == nil || data.SigInfo.Type == an.SigNull) && p.DataSigner != nil {
+				if e := p.DataSigner.Sign(&data); e != nil {
+					return
+				}
+			}
+			if p.DataStore != nil {
+				p.DataStore.Put(data)
 			}
 		}
 		reply = &ndn.Packet{
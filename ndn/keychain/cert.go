@@ -2,6 +2,7 @@ package keychain
 
 import (
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding"
@@ -92,21 +93,29 @@ func CertFromData(data ndn.Data) (cert *Certificate, e error) {
 		return nil, e
 	}
 
-	key, e := x509.ParsePKIXPublicKey(data.Content)
+	if cert.key, e = ParsePublicKey(keyName, data.Content); e != nil {
+		return nil, e
+	}
+	return cert, nil
+}
+
+// ParsePublicKey parses a public key in SubjectPublicKeyInfo format.
+func ParsePublicKey(keyName ndn.Name, spki []byte) (pub PublicKey, e error) {
+	key, e := x509.ParsePKIXPublicKey(spki)
 	if e != nil {
 		return nil, ErrX509PublicKey
 	}
 	switch key := key.(type) {
 	case *rsa.PublicKey:
-		cert.key, _ = NewRSAPublicKey(keyName, key)
+		pub, e = NewRSAPublicKey(keyName, key)
 	case *ecdsa.PublicKey:
-		cert.key, _ = NewECDSAPublicKey(keyName, key)
-	}
-	if cert.key == nil {
+		pub, e = NewECDSAPublicKey(keyName, key)
+	case ed25519.PublicKey:
+		pub, e = NewEd25519PublicKey(keyName, key)
+	default:
 		return nil, ErrX509PublicKey
 	}
-
-	return cert, nil
+	return pub, e
 }
 
 // MakeCertOptions contains arguments to MakeCert function.
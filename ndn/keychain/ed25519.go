@@ -0,0 +1,54 @@
+package keychain
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+)
+
+// ErrEd25519KeySize indicates an Ed25519 key has wrong length.
+var ErrEd25519KeySize = errors.New("bad Ed25519 key size")
+
+// NewEd25519PrivateKey creates a private key for SigEd25519 signature type.
+func NewEd25519PrivateKey(keyName ndn.Name, key ed25519.PrivateKey) (PrivateKey, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, ErrEd25519KeySize
+	}
+	return newPrivateKey(an.SigEd25519, keyName, key, func(input []byte) (sig []byte, e error) {
+		return ed25519.Sign(key, input), nil
+	})
+}
+
+// NewEd25519PublicKey creates a public key for SigEd25519 signature type.
+func NewEd25519PublicKey(keyName ndn.Name, key ed25519.PublicKey) (PublicKey, error) {
+	if len(key) != ed25519.PublicKeySize {
+		return nil, ErrEd25519KeySize
+	}
+	return newPublicKey(an.SigEd25519, keyName, key, func(input, sig []byte) error {
+		if ok := ed25519.Verify(key, input, sig); !ok {
+			return ndn.ErrSigValue
+		}
+		return nil
+	})
+}
+
+// NewEd25519KeyPair creates a key pair for SigEd25519 signature type.
+func NewEd25519KeyPair(name ndn.Name) (PrivateKey, PublicKey, error) {
+	keyName := ToKeyName(name)
+	pubKey, pvtKey, e := ed25519.GenerateKey(rand.Reader)
+	if e != nil {
+		return nil, nil, e
+	}
+	pvt, e := NewEd25519PrivateKey(keyName, pvtKey)
+	if e != nil {
+		return nil, nil, e
+	}
+	pub, e := NewEd25519PublicKey(keyName, pubKey)
+	if e != nil {
+		return nil, nil, e
+	}
+	return pvt, pub, e
+}
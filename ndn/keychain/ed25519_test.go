@@ -0,0 +1,82 @@
+package keychain_test
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+func TestEd25519Signing(t *testing.T) {
+	assert, require := makeAR(t)
+	_, privA, e := ed25519.GenerateKey(nil)
+	require.NoError(e)
+
+	subjectName := ndn.ParseName("/K")
+	_, e = keychain.NewEd25519PrivateKey(subjectName, privA)
+	assert.Error(e)
+	_, e = keychain.NewEd25519PublicKey(subjectName, privA.Public().(ed25519.PublicKey))
+	assert.Error(e)
+
+	keyNameA := keychain.ToKeyName(subjectName)
+	_, e = keychain.NewEd25519PrivateKey(keyNameA, privA[:16])
+	assert.ErrorIs(e, keychain.ErrEd25519KeySize)
+	pvtA, e := keychain.NewEd25519PrivateKey(keyNameA, privA)
+	require.NoError(e)
+	pubA, e := keychain.NewEd25519PublicKey(keyNameA, privA.Public().(ed25519.PublicKey))
+	require.NoError(e)
+	nameEqual(assert, keyNameA, pvtA)
+	nameEqual(assert, keyNameA, pubA)
+
+	pvtB, pubB, e := keychain.NewEd25519KeyPair(subjectName)
+	require.NoError(e)
+	nameEqual(assert, pvtB, pubB)
+
+	checkKeyCertPair(t, an.SigEd25519, pvtA, pvtB, pubA, pubB)
+}
+
+func TestEd25519Vector(t *testing.T) {
+	assert, require := makeAR(t)
+
+	// key from RFC 8032 section 7.1 TEST 1; signature computed by OpenSSL over the signed portion.
+	// Data has no MetaInfo, and KeyLocator contains the key name.
+	seed, _ := hex.DecodeString("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	spki, _ := hex.DecodeString("302a300506032b6570032100d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a")
+	wire, _ := hex.DecodeString("0662" +
+		"0703080141" + // Name
+		"150568656c6c6f" + // Content
+		"16121b01051c0d070b08014b08034b455908016b" + // SigInfo
+		"1740babf39355a1821491dbc8e45da84d133359559f5025796e3bcae78510b717b7c" +
+		"9933d600b004e95c6214433fb510313dc11b65f9799032d5437d61900ede3603") // SigValue
+	keyName := ndn.ParseName("/K/KEY/k")
+
+	var pkt ndn.Packet
+	require.NoError(tlv.Decode(wire, &pkt))
+	require.NotNil(pkt.Data)
+	assert.EqualValues(an.SigEd25519, pkt.Data.SigInfo.Type)
+
+	pvt, e := keychain.NewEd25519PrivateKey(keyName, ed25519.NewKeyFromSeed(seed))
+	require.NoError(e)
+	pubK, e := keychain.NewEd25519PublicKey(keyName, ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey))
+	require.NoError(e)
+	spkiK, e := pubK.SPKI()
+	require.NoError(e)
+	assert.Equal(spki, spkiK)
+	assert.NoError(pubK.Verify(*pkt.Data))
+
+	data := ndn.MakeData("/A", []byte("hello"))
+	require.NoError(pvt.Sign(&data))
+	assert.Equal(pkt.Data.SigValue, data.SigValue)
+	wireK, e := tlv.EncodeFrom(data)
+	require.NoError(e)
+	assert.Equal(wire, wireK)
+	assert.NoError(pubK.Verify(data))
+
+	wire[len(wire)-1] ^= 0x01
+	require.NoError(tlv.Decode(wire, &pkt))
+	assert.Error(pubK.Verify(*pkt.Data))
+}
@@ -0,0 +1,446 @@
+package keychain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// ErrNotFound indicates an identity, key, or certificate does not exist in the FileStore.
+var ErrNotFound = errors.New("not found in keychain")
+
+const (
+	fileStoreKeysDir  = "keys"
+	fileStoreCertsDir = "certs"
+	fileStorePibFile  = "pib.json"
+	fileStoreKeyExt   = ".key"
+	fileStoreCertExt  = ".cert"
+)
+
+type fileStorePib struct {
+	DefaultIdentity ndn.Name            `json:"defaultIdentity,omitempty"`
+	DefaultKeys     map[string]ndn.Name `json:"defaultKeys,omitempty"` // identity URI => key name
+}
+
+// FileStore is a persistent keychain stored in a directory.
+// It combines the functionality of ndn-cxx PIB and TPM.
+//
+// The directory contains:
+//   - keys/*.key: private keys in MarshalKey format.
+//   - certs/*.cert: certificates in MarshalCert format.
+//   - pib.json: default identity and default key of each identity.
+type FileStore struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// OpenFileStore opens a FileStore, creating the directory if it does not exist.
+func OpenFileStore(dir string) (*FileStore, error) {
+	for _, subdir := range []string{fileStoreKeysDir, fileStoreCertsDir} {
+		if e := os.MkdirAll(filepath.Join(dir, subdir), 0700); e != nil {
+			return nil, e
+		}
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) filename(subdir string, name ndn.Name, ext string) string {
+	wire, _ := tlv.EncodeFrom(name)
+	h := sha256.Sum256(wire)
+	return filepath.Join(s.dir, subdir, hex.EncodeToString(h[:])+ext)
+}
+
+func (s *FileStore) writeFile(filename string, content []byte) error {
+	tmp := filename + ".tmp"
+	if e := os.WriteFile(tmp, content, 0600); e != nil {
+		return e
+	}
+	return os.Rename(tmp, filename)
+}
+
+func (s *FileStore) readFile(filename string, name ndn.Name) ([]byte, error) {
+	content, e := os.ReadFile(filename)
+	if errors.Is(e, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return content, e
+}
+
+// listFiles invokes f on the content of every file in a subdirectory.
+func (s *FileStore) listFiles(subdir, ext string, f func(content []byte) error) error {
+	entries, e := os.ReadDir(filepath.Join(s.dir, subdir))
+	if e != nil {
+		return e
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ext) {
+			continue
+		}
+		content, e := os.ReadFile(filepath.Join(s.dir, subdir, entry.Name()))
+		if e != nil {
+			return e
+		}
+		if e = f(content); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func (s *FileStore) loadPib() (pib fileStorePib, e error) {
+	content, e := os.ReadFile(filepath.Join(s.dir, fileStorePibFile))
+	switch {
+	case errors.Is(e, fs.ErrNotExist):
+		return pib, nil
+	case e != nil:
+		return pib, e
+	}
+	e = json.Unmarshal(content, &pib)
+	return pib, e
+}
+
+func (s *FileStore) savePib(pib fileStorePib) error {
+	content, e := json.MarshalIndent(pib, "", "  ")
+	if e != nil {
+		return e
+	}
+	return s.writeFile(filepath.Join(s.dir, fileStorePibFile), content)
+}
+
+// InsertKey stores a private key.
+// If a key of the same name exists, it is replaced.
+func (s *FileStore) InsertKey(key PrivateKey) error {
+	wire, e := MarshalKey(key)
+	if e != nil {
+		return e
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.writeFile(s.filename(fileStoreKeysDir, key.Name(), fileStoreKeyExt), wire)
+}
+
+// InsertCert stores a certificate.
+// If a certificate of the same name exists, it is replaced.
+// The certificate may be stored without its private key.
+func (s *FileStore) InsertCert(cert *Certificate) error {
+	wire, e := MarshalCert(cert)
+	if e != nil {
+		return e
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.writeFile(s.filename(fileStoreCertsDir, cert.Name(), fileStoreCertExt), wire)
+}
+
+// Key retrieves a private key.
+func (s *FileStore) Key(keyName ndn.Name) (PrivateKey, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	wire, e := s.readFile(s.filename(fileStoreKeysDir, keyName, fileStoreKeyExt), keyName)
+	if e != nil {
+		return nil, e
+	}
+	return UnmarshalKey(wire)
+}
+
+// Cert retrieves a certificate.
+func (s *FileStore) Cert(certName ndn.Name) (*Certificate, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	wire, e := s.readFile(s.filename(fileStoreCertsDir, certName, fileStoreCertExt), certName)
+	if e != nil {
+		return nil, e
+	}
+	return UnmarshalCert(wire)
+}
+
+func (s *FileStore) listKeys() (names []ndn.Name, e error) {
+	e = s.listFiles(fileStoreKeysDir, fileStoreKeyExt, func(wire []byte) error {
+		d := tlv.DecodingBuffer(wire)
+		de, e := d.Element()
+		if e != nil {
+			return e
+		}
+		var name ndn.Name
+		if e := de.UnmarshalValue(&name); e != nil {
+			return e
+		}
+		names = append(names, name)
+		return nil
+	})
+	sortNames(names)
+	return names, e
+}
+
+func (s *FileStore) listCerts() (names []ndn.Name, e error) {
+	e = s.listFiles(fileStoreCertsDir, fileStoreCertExt, func(wire []byte) error {
+		cert, e := UnmarshalCert(wire)
+		if e != nil {
+			return e
+		}
+		names = append(names, cert.Name())
+		return nil
+	})
+	sortNames(names)
+	return names, e
+}
+
+func sortNames(names []ndn.Name) {
+	sort.Slice(names, func(i, j int) bool { return names[i].Compare(names[j]) < 0 })
+}
+
+// Identities returns a sorted list of identities that have at least one private key.
+func (s *FileStore) Identities() (identities []ndn.Name, e error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	keys, e := s.listKeys()
+	if e != nil {
+		return nil, e
+	}
+	for _, keyName := range keys {
+		id := ToSubjectName(keyName)
+		if n := len(identities); n == 0 || !identities[n-1].Equal(id) {
+			identities = append(identities, id)
+		}
+	}
+	return identities, nil
+}
+
+// Keys returns a sorted list of private key names of an identity.
+func (s *FileStore) Keys(identity ndn.Name) (keys []ndn.Name, e error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.keysOf(identity)
+}
+
+func (s *FileStore) keysOf(identity ndn.Name) (keys []ndn.Name, e error) {
+	all, e := s.listKeys()
+	if e != nil {
+		return nil, e
+	}
+	for _, keyName := range all {
+		if ToSubjectName(keyName).Equal(identity) {
+			keys = append(keys, keyName)
+		}
+	}
+	return keys, nil
+}
+
+// Certs returns a sorted list of certificate names of a key.
+func (s *FileStore) Certs(keyName ndn.Name) (certs []ndn.Name, e error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.certsOf(keyName)
+}
+
+func (s *FileStore) certsOf(keyName ndn.Name) (certs []ndn.Name, e error) {
+	all, e := s.listCerts()
+	if e != nil {
+		return nil, e
+	}
+	for _, certName := range all {
+		if ToKeyName(certName).Equal(keyName) {
+			certs = append(certs, certName)
+		}
+	}
+	return certs, nil
+}
+
+// DeleteKey deletes a private key and its certificates.
+func (s *FileStore) DeleteKey(keyName ndn.Name) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	certs, e := s.certsOf(keyName)
+	if e != nil {
+		return e
+	}
+	for _, certName := range certs {
+		if e := os.Remove(s.filename(fileStoreCertsDir, certName, fileStoreCertExt)); e != nil {
+			return e
+		}
+	}
+
+	if e := os.Remove(s.filename(fileStoreKeysDir, keyName, fileStoreKeyExt)); e != nil {
+		if errors.Is(e, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrNotFound, keyName)
+		}
+		return e
+	}
+	return nil
+}
+
+// DeleteCert deletes a certificate.
+func (s *FileStore) DeleteCert(certName ndn.Name) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if e := os.Remove(s.filename(fileStoreCertsDir, certName, fileStoreCertExt)); e != nil {
+		if errors.Is(e, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrNotFound, certName)
+		}
+		return e
+	}
+	return nil
+}
+
+// DefaultIdentity returns the default identity.
+// If no default identity has been set or it no longer has any key, the first identity is returned.
+func (s *FileStore) DefaultIdentity() (ndn.Name, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	pib, e := s.loadPib()
+	if e != nil {
+		return nil, e
+	}
+
+	keys, e := s.listKeys()
+	if e != nil {
+		return nil, e
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: no identity", ErrNotFound)
+	}
+	for _, keyName := range keys {
+		if ToSubjectName(keyName).Equal(pib.DefaultIdentity) {
+			return pib.DefaultIdentity, nil
+		}
+	}
+	return ToSubjectName(keys[0]), nil
+}
+
+// SetDefaultIdentity changes the default identity.
+// The identity must have at least one private key.
+func (s *FileStore) SetDefaultIdentity(identity ndn.Name) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if keys, e := s.keysOf(identity); e != nil {
+		return e
+	} else if len(keys) == 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, identity)
+	}
+
+	pib, e := s.loadPib()
+	if e != nil {
+		return e
+	}
+	pib.DefaultIdentity = identity
+	return s.savePib(pib)
+}
+
+// DefaultKey returns the default key name of an identity.
+// If no default key has been set or it no longer exists, the first key is returned.
+func (s *FileStore) DefaultKey(identity ndn.Name) (ndn.Name, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	pib, e := s.loadPib()
+	if e != nil {
+		return nil, e
+	}
+
+	keys, e := s.keysOf(identity)
+	if e != nil {
+		return nil, e
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, identity)
+	}
+	if keyName, ok := pib.DefaultKeys[identity.String()]; ok {
+		for _, k := range keys {
+			if k.Equal(keyName) {
+				return keyName, nil
+			}
+		}
+	}
+	return keys[0], nil
+}
+
+// SetDefaultKey changes the default key of its identity.
+func (s *FileStore) SetDefaultKey(keyName ndn.Name) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, e := os.Stat(s.filename(fileStoreKeysDir, keyName, fileStoreKeyExt)); e != nil {
+		if errors.Is(e, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrNotFound, keyName)
+		}
+		return e
+	}
+
+	pib, e := s.loadPib()
+	if e != nil {
+		return e
+	}
+	if pib.DefaultKeys == nil {
+		pib.DefaultKeys = map[string]ndn.Name{}
+	}
+	pib.DefaultKeys[ToSubjectName(keyName).String()] = keyName
+	return s.savePib(pib)
+}
+
+// Signer returns a signer using the default key of an identity.
+// If identity is empty, the default identity is used.
+// If the key has certificates, KeyLocator is set to the last certificate name, which normally has the latest version.
+func (s *FileStore) Signer(identity ndn.Name) (ndn.Signer, error) {
+	if len(identity) == 0 {
+		id, e := s.DefaultIdentity()
+		if e != nil {
+			return nil, e
+		}
+		identity = id
+	}
+
+	keyName, e := s.DefaultKey(identity)
+	if e != nil {
+		return nil, e
+	}
+	key, e := s.Key(keyName)
+	if e != nil {
+		return nil, e
+	}
+	certs, e := s.Certs(keyName)
+	if e != nil {
+		return nil, e
+	}
+	if len(certs) == 0 {
+		return key, nil
+	}
+	return key.WithKeyLocator(certs[len(certs)-1]), nil
+}
+
+// ImportSafeBag decrypts a SafeBag and stores its private key and certificate.
+// It fails if the private key does not match the public key in the certificate.
+func (s *FileStore) ImportSafeBag(sb SafeBag, passphrase []byte) error {
+	key, cert, e := sb.Decrypt(passphrase)
+	if e != nil {
+		return e
+	}
+	if e := s.InsertKey(key); e != nil {
+		return e
+	}
+	return s.InsertCert(cert)
+}
+
+// ExportSafeBag exports a certificate and its private key as SafeBag.
+func (s *FileStore) ExportSafeBag(certName ndn.Name, passphrase []byte) (sb SafeBag, e error) {
+	cert, e := s.Cert(certName)
+	if e != nil {
+		return sb, e
+	}
+	key, e := s.Key(ToKeyName(certName))
+	if e != nil {
+		return sb, e
+	}
+	return MakeSafeBag(key, cert, passphrase)
+}
@@ -0,0 +1,102 @@
+package keychain_test
+
+import (
+	"testing"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+)
+
+func TestFileStore(t *testing.T) {
+	assert, require := makeAR(t)
+	dir := t.TempDir()
+
+	s, e := keychain.OpenFileStore(dir)
+	require.NoError(e)
+	_, e = s.DefaultIdentity()
+	assert.ErrorIs(e, keychain.ErrNotFound)
+
+	pvtA1, pubA1, _ := keychain.NewECDSAKeyPair(ndn.ParseName("/A"))
+	pvtA2, pubA2, _ := keychain.NewRSAKeyPair(ndn.ParseName("/A"))
+	pvtB, pubB, _ := keychain.NewECDSAKeyPair(ndn.ParseName("/B"))
+	certA1, _ := keychain.MakeCert(pubA1, pvtA1, keychain.MakeCertOptions{})
+	certA2, _ := keychain.MakeCert(pubA2, pvtA1, keychain.MakeCertOptions{})
+	certB, _ := keychain.MakeCert(pubB, pvtA1, keychain.MakeCertOptions{})
+	for _, key := range []keychain.PrivateKey{pvtA1, pvtA2, pvtB} {
+		require.NoError(s.InsertKey(key))
+	}
+	for _, cert := range []*keychain.Certificate{certA1, certA2, certB} {
+		require.NoError(s.InsertCert(cert))
+	}
+
+	// reopen to ensure persistence
+	s, e = keychain.OpenFileStore(dir)
+	require.NoError(e)
+
+	ids, e := s.Identities()
+	require.NoError(e)
+	require.Len(ids, 2)
+	nameEqual(assert, "/A", ids[0])
+	nameEqual(assert, "/B", ids[1])
+
+	keys, e := s.Keys(ndn.ParseName("/A"))
+	require.NoError(e)
+	assert.Len(keys, 2)
+	certs, e := s.Certs(pvtB.Name())
+	require.NoError(e)
+	require.Len(certs, 1)
+	nameEqual(assert, certB, certs[0])
+
+	pvt, e := s.Key(pvtA2.Name())
+	require.NoError(e)
+	nameEqual(assert, pvtA2, pvt)
+	cert, e := s.Cert(certA1.Name())
+	require.NoError(e)
+	nameEqual(assert, certA1, cert.Data())
+	_, e = s.Key(ndn.ParseName("/C/KEY/k"))
+	assert.ErrorIs(e, keychain.ErrNotFound)
+
+	id, e := s.DefaultIdentity()
+	require.NoError(e)
+	nameEqual(assert, "/A", id)
+	require.NoError(s.SetDefaultIdentity(ndn.ParseName("/B")))
+	assert.ErrorIs(s.SetDefaultIdentity(ndn.ParseName("/C")), keychain.ErrNotFound)
+	id, e = s.DefaultIdentity()
+	require.NoError(e)
+	nameEqual(assert, "/B", id)
+
+	require.NoError(s.SetDefaultKey(pvtA2.Name()))
+	keyName, e := s.DefaultKey(ndn.ParseName("/A"))
+	require.NoError(e)
+	nameEqual(assert, pvtA2, keyName)
+
+	signer, e := s.Signer(nil)
+	require.NoError(e)
+	data := ndn.MakeData("/B/data")
+	require.NoError(signer.Sign(&data))
+	nameEqual(assert, certB, data.SigInfo.KeyLocator)
+	assert.NoError(pubB.Verify(data))
+
+	sb, e := s.ExportSafeBag(certA2.Name(), []byte("PASSWORD"))
+	require.NoError(e)
+	require.NoError(s.DeleteKey(pvtA2.Name()))
+	certs, e = s.Certs(pvtA2.Name())
+	require.NoError(e)
+	assert.Len(certs, 0)
+	keyName, e = s.DefaultKey(ndn.ParseName("/A"))
+	require.NoError(e)
+	nameEqual(assert, pvtA1, keyName)
+
+	s2, e := keychain.OpenFileStore(t.TempDir())
+	require.NoError(e)
+	require.NoError(s2.ImportSafeBag(sb, []byte("PASSWORD")))
+	signer, e = s2.Signer(ndn.ParseName("/A"))
+	require.NoError(e)
+	data = ndn.MakeData("/A/data")
+	require.NoError(signer.Sign(&data))
+	nameEqual(assert, certA2, data.SigInfo.KeyLocator)
+	assert.NoError(pubA2.Verify(data))
+
+	sb.Certificate = certB.Data() // certificate does not match private key
+	assert.ErrorIs(s2.ImportSafeBag(sb, []byte("PASSWORD")), keychain.ErrKeyMismatch)
+}
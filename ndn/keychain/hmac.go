@@ -0,0 +1,74 @@
+package keychain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+)
+
+// ErrHMACSecret indicates an HMAC secret is empty.
+var ErrHMACSecret = errors.New("empty HMAC secret")
+
+func makeHMAC(secret, input []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write(input)
+	return h.Sum(nil)
+}
+
+// NewHMACPrivateKey creates a private key for SigHmacWithSha256 signature type.
+// keyName appears in KeyLocator. Unlike other key types, it does not need to be a key name,
+// because a shared secret has no certificate.
+// HMAC private keys cannot be serialized by MarshalKey.
+func NewHMACPrivateKey(keyName ndn.Name, secret []byte) (PrivateKey, error) {
+	if len(secret) == 0 {
+		return nil, ErrHMACSecret
+	}
+	secret = append([]byte{}, secret...)
+	return &privateKey{
+		namedSigner: namedSigner{
+			sigType: an.SigHmacWithSha256,
+			klName:  keyName,
+			llSign: func(input []byte) (sig []byte, e error) {
+				return makeHMAC(secret, input), nil
+			},
+		},
+		key: secret,
+	}, nil
+}
+
+type hmacVerifier struct {
+	keyName ndn.Name
+	secret  []byte
+}
+
+func (v hmacVerifier) Verify(packet ndn.Verifiable) error {
+	return packet.VerifyWith(func(name ndn.Name, si ndn.SigInfo) (ndn.LLVerify, error) {
+		if si.Type != an.SigHmacWithSha256 {
+			return nil, ndn.ErrSigType
+		}
+		if !si.KeyLocator.Name.Equal(v.keyName) {
+			return nil, ndn.ErrKeyLocator
+		}
+		return func(input, sig []byte) error {
+			if !hmac.Equal(makeHMAC(v.secret, input), sig) {
+				return ndn.ErrSigValue
+			}
+			return nil
+		}, nil
+	})
+}
+
+// NewHMACVerifier creates a verifier for SigHmacWithSha256 signature type.
+// It accepts packets whose KeyLocator name equals keyName.
+func NewHMACVerifier(keyName ndn.Name, secret []byte) (ndn.Verifier, error) {
+	if len(secret) == 0 {
+		return nil, ErrHMACSecret
+	}
+	return hmacVerifier{
+		keyName: keyName,
+		secret:  append([]byte{}, secret...),
+	}, nil
+}
@@ -0,0 +1,77 @@
+package keychain_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+	"github.com/usnistgov/ndn-dpdk/ndn/ndntestenv"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+func TestHMACSigning(t *testing.T) {
+	assert, require := makeAR(t)
+
+	_, e := keychain.NewHMACPrivateKey(ndn.ParseName("/K"), nil)
+	assert.ErrorIs(e, keychain.ErrHMACSecret)
+	_, e = keychain.NewHMACVerifier(ndn.ParseName("/K"), nil)
+	assert.ErrorIs(e, keychain.ErrHMACSecret)
+
+	pvtA, e := keychain.NewHMACPrivateKey(ndn.ParseName("/K"), []byte("A"))
+	require.NoError(e)
+	pubA, e := keychain.NewHMACVerifier(ndn.ParseName("/K"), []byte("A"))
+	require.NoError(e)
+	pvtB, e := keychain.NewHMACPrivateKey(ndn.ParseName("/K"), []byte("B"))
+	require.NoError(e)
+	pubB, e := keychain.NewHMACVerifier(ndn.ParseName("/K"), []byte("B"))
+	require.NoError(e)
+	pvtC, e := keychain.NewHMACPrivateKey(ndn.ParseName("/C"), []byte("A"))
+	require.NoError(e)
+	nameEqual(assert, "/K", pvtA)
+
+	var c ndntestenv.SignVerifyTester
+	c.PvtA, c.PvtB, c.PubA, c.PubB = pvtA, pvtB, pubA, pubB
+	c.CheckInterest(t)
+	c.CheckInterestParameterized(t)
+	rec := c.CheckData(t)
+
+	dataA := rec.PktA.(*ndn.Data)
+	assert.EqualValues(an.SigHmacWithSha256, dataA.SigInfo.Type)
+	nameEqual(assert, "/K", dataA.SigInfo.KeyLocator)
+
+	dataC := ndn.MakeData("/NAME")
+	require.NoError(pvtC.Sign(&dataC))
+	assert.ErrorIs(pubA.Verify(dataC), ndn.ErrKeyLocator)
+
+	_, e = keychain.MarshalKey(pvtA)
+	assert.Error(e)
+}
+
+func TestHMACVector(t *testing.T) {
+	assert, require := makeAR(t)
+
+	// signature computed by Python hmac module
+	wire, _ := hex.DecodeString("0641" +
+		"0703080141" + // Name
+		"150568656c6c6f" + // Content
+		"16111b01041c0c070a0808686d61632d6b6579" + // SigInfo
+		"1720a9add9e21d6d392c2fd752dc55efcc5a6a076aa6294e78d29345cec6aa6904c0") // SigValue
+	keyName := ndn.ParseName("/hmac-key")
+	secret := []byte("secret-key")
+
+	var pkt ndn.Packet
+	require.NoError(tlv.Decode(wire, &pkt))
+	require.NotNil(pkt.Data)
+
+	verifier, e := keychain.NewHMACVerifier(keyName, secret)
+	require.NoError(e)
+	assert.NoError(verifier.Verify(*pkt.Data))
+
+	signer, e := keychain.NewHMACPrivateKey(keyName, secret)
+	require.NoError(e)
+	data := ndn.MakeData("/A", []byte("hello"))
+	require.NoError(signer.Sign(&data))
+	assert.Equal(pkt.Data.SigValue, data.SigValue)
+}
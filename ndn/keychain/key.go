@@ -35,7 +35,7 @@ func (signer namedSigner) Sign(packet ndn.Signable) error {
 
 type privateKey struct {
 	namedSigner
-	key interface{} // *rsa.PrivateKey or *ecdsa.PrivateKey
+	key interface{} // *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey, or []byte HMAC secret
 }
 
 func (pvt privateKey) Name() ndn.Name {
@@ -76,7 +76,7 @@ type PublicKey interface {
 type publicKey struct {
 	sigType  uint32
 	keyName  ndn.Name
-	key      interface{} // *rsa.PublicKey or *ecdsa.PublicKey
+	key      interface{} // *rsa.PublicKey, *ecdsa.PublicKey, or ed25519.PublicKey
 	llVerify ndn.LLVerify
 }
 
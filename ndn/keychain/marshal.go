@@ -3,6 +3,7 @@ package keychain
 import (
 	"bytes"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"crypto/x509"
 	"errors"
@@ -44,7 +45,10 @@ func UnmarshalKey(wire []byte) (PrivateKey, error) {
 		return nil, e
 	}
 
-	pkcs8 := d.Rest()
+	return parsePKCS8(name, d.Rest())
+}
+
+func parsePKCS8(name ndn.Name, pkcs8 []byte) (PrivateKey, error) {
 	key, e := x509.ParsePKCS8PrivateKey(pkcs8)
 	if e != nil {
 		return nil, e
@@ -55,6 +59,8 @@ func UnmarshalKey(wire []byte) (PrivateKey, error) {
 		return NewRSAPrivateKey(name, key)
 	case *ecdsa.PrivateKey:
 		return NewECDSAPrivateKey(name, key)
+	case ed25519.PrivateKey:
+		return NewEd25519PrivateKey(name, key)
 	}
 	return nil, fmt.Errorf("unknown private key type %T", key)
 }
@@ -7,7 +7,6 @@ import (
 
 	"github.com/usnistgov/ndn-dpdk/ndn"
 	"github.com/usnistgov/ndn-dpdk/ndn/an"
-	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
 )
 
 // Name components for certificate naming.
@@ -82,6 +81,5 @@ func makeRandomKeyID() ndn.NameComponent {
 }
 
 func makeVersionFromCurrentTime() (comp ndn.NameComponent) {
-	now := time.Now().UnixMicro()
-	return ndn.NameComponentFrom(an.TtVersionNameComponent, tlv.NNI(now))
+	return ndn.VersionConvention.MakeTime(time.Now())
 }
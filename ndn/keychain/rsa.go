@@ -4,12 +4,17 @@ import (
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha1"
 	"crypto/sha256"
+	"errors"
 
 	"github.com/usnistgov/ndn-dpdk/ndn"
 	"github.com/usnistgov/ndn-dpdk/ndn/an"
 )
 
+// ErrNotRSA indicates the key is not an RSA key.
+var ErrNotRSA = errors.New("not an RSA key")
+
 // NewRSAPrivateKey creates a private key for SigSha256WithRsa signature type.
 func NewRSAPrivateKey(keyName ndn.Name, key *rsa.PrivateKey) (PrivateKey, error) {
 	return newPrivateKey(an.SigSha256WithRsa, keyName, key, func(input []byte) (sig []byte, e error) {
@@ -43,3 +48,30 @@ func NewRSAKeyPair(name ndn.Name) (PrivateKey, PublicKey, error) {
 	}
 	return pvt, pub, e
 }
+
+// RSAEncrypt encrypts a short message with an RSA public key.
+// It uses RSA-OAEP padding with SHA-1, compatible with ndn-cxx PublicKey::encrypt.
+func RSAEncrypt(pub PublicKey, plaintext []byte) ([]byte, error) {
+	ppub, _ := pub.(*publicKey)
+	if ppub == nil {
+		return nil, ErrNotRSA
+	}
+	key, ok := ppub.key.(*rsa.PublicKey)
+	if !ok {
+		return nil, ErrNotRSA
+	}
+	return rsa.EncryptOAEP(sha1.New(), rand.Reader, key, plaintext, nil)
+}
+
+// RSADecrypt decrypts a message encrypted by RSAEncrypt.
+func RSADecrypt(pvt PrivateKey, ciphertext []byte) ([]byte, error) {
+	ppvt, _ := pvt.(*privateKey)
+	if ppvt == nil {
+		return nil, ErrNotRSA
+	}
+	key, ok := ppvt.key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, ErrNotRSA
+	}
+	return rsa.DecryptOAEP(sha1.New(), rand.Reader, key, ciphertext, nil)
+}
@@ -48,3 +48,37 @@ func TestRSAVerify(t *testing.T) {
 	data := ndntestvector.TestbedShijunxiao20200301()
 	assert.NoError(cert.PublicKey().Verify(data))
 }
+
+func TestRSAEncrypt(t *testing.T) {
+	assert, require := makeAR(t)
+	pvtA, pubA, e := keychain.NewRSAKeyPair(ndn.ParseName("/A"))
+	require.NoError(e)
+	pvtB, _, e := keychain.NewRSAKeyPair(ndn.ParseName("/B"))
+	require.NoError(e)
+	pvtC, pubC, e := keychain.NewECDSAKeyPair(ndn.ParseName("/C"))
+	require.NoError(e)
+
+	plaintext := []byte("secret")
+	ciphertext, e := keychain.RSAEncrypt(pubA, plaintext)
+	require.NoError(e)
+	decrypted, e := keychain.RSADecrypt(pvtA, ciphertext)
+	require.NoError(e)
+	assert.Equal(plaintext, decrypted)
+
+	_, e = keychain.RSADecrypt(pvtB, ciphertext)
+	assert.Error(e)
+	_, e = keychain.RSAEncrypt(pubC, plaintext)
+	assert.ErrorIs(e, keychain.ErrNotRSA)
+	_, e = keychain.RSADecrypt(pvtC, ciphertext)
+	assert.ErrorIs(e, keychain.ErrNotRSA)
+
+	spki, e := pubA.SPKI()
+	require.NoError(e)
+	parsed, e := keychain.ParsePublicKey(pubA.Name(), spki)
+	require.NoError(e)
+	ciphertext, e = keychain.RSAEncrypt(parsed, plaintext)
+	require.NoError(e)
+	decrypted, e = keychain.RSADecrypt(pvtA, ciphertext)
+	require.NoError(e)
+	assert.Equal(plaintext, decrypted)
+}
@@ -0,0 +1,299 @@
+package keychain
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding"
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Error conditions for SafeBag.
+var (
+	ErrSafeBag     = errors.New("bad SafeBag")
+	ErrPassphrase  = errors.New("cannot decrypt private key, wrong passphrase?")
+	ErrKeyMismatch = errors.New("private key does not match certificate")
+)
+
+// SafeBagIterations is the PBKDF2 iteration count when encrypting a SafeBag.
+// This matches the default of OpenSSL PKCS8_encrypt function, as used by ndn-cxx.
+const SafeBagIterations = 2048
+
+// SafeBagMaxIterations is the maximum PBKDF2 iteration count accepted when decrypting a SafeBag.
+// It prevents a crafted SafeBag from consuming excessive CPU time.
+const SafeBagMaxIterations = 10000000
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+type encryptedPrivateKeyInfo struct {
+	Algo          pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// SafeBag represents an exported private key and its certificate, compatible with ndn-cxx 'ndnsec export' command.
+type SafeBag struct {
+	// Certificate is the certificate Data packet.
+	Certificate ndn.Data
+
+	// EncryptedKey is the private key in PKCS#8 EncryptedPrivateKeyInfo format.
+	EncryptedKey []byte
+}
+
+var (
+	_ tlv.Fielder                = SafeBag{}
+	_ encoding.BinaryUnmarshaler = (*SafeBag)(nil)
+)
+
+// Field implements tlv.Fielder interface.
+func (sb SafeBag) Field() tlv.Field {
+	return tlv.TLV(an.TtSafeBag, sb.Certificate.Field(), tlv.TLVBytes(an.TtEncryptedKeyBag, sb.EncryptedKey))
+}
+
+// UnmarshalBinary decodes from TLV-VALUE.
+func (sb *SafeBag) UnmarshalBinary(wire []byte) (e error) {
+	*sb = SafeBag{}
+	hasCert := false
+	d := tlv.DecodingBuffer(wire)
+	for _, de := range d.Elements() {
+		switch de.Type {
+		case an.TtData:
+			if e := de.UnmarshalValue(&sb.Certificate); e != nil {
+				return e
+			}
+			hasCert = true
+		case an.TtEncryptedKeyBag:
+			sb.EncryptedKey = de.Value
+		default:
+			if de.IsCriticalType() {
+				return tlv.ErrCritical
+			}
+		}
+	}
+	if !hasCert || len(sb.EncryptedKey) == 0 {
+		return ErrSafeBag
+	}
+	return d.ErrUnlessEOF()
+}
+
+// ParseSafeBag decodes a SafeBag from TLV or base64 encoding.
+// The base64 encoding is the output format of 'ndnsec export' command.
+func ParseSafeBag(input []byte) (sb SafeBag, e error) {
+	wire := input
+	if len(input) > 0 && input[0] != an.TtSafeBag {
+		wire = make([]byte, base64.StdEncoding.DecodedLen(len(input)))
+		n, e := base64.StdEncoding.Decode(wire, bytes.Join(bytes.Fields(input), nil))
+		if e != nil {
+			return sb, fmt.Errorf("%w: %v", ErrSafeBag, e)
+		}
+		wire = wire[:n]
+	}
+
+	d := tlv.DecodingBuffer(wire)
+	de, e := d.Element()
+	if e != nil {
+		return sb, e
+	}
+	if de.Type != an.TtSafeBag {
+		return sb, ErrSafeBag
+	}
+	if e = sb.UnmarshalBinary(de.Value); e != nil {
+		return sb, e
+	}
+	return sb, d.ErrUnlessEOF()
+}
+
+// MakeSafeBag encrypts a private key with a passphrase and packages it with its certificate.
+// The private key is encrypted with PBES2, using PBKDF2-HMAC-SHA256 key derivation and AES-256-CBC encryption.
+func MakeSafeBag(key PrivateKey, cert *Certificate, passphrase []byte) (sb SafeBag, e error) {
+	pkey, _ := key.(*privateKey)
+	if pkey == nil {
+		return sb, fmt.Errorf("unknown key type %T", key)
+	}
+	if !ToKeyName(cert.Name()).Equal(pkey.Name()) {
+		return sb, ErrCertName
+	}
+	if !keyPairMatch(pkey, cert.PublicKey()) {
+		return sb, ErrKeyMismatch
+	}
+
+	pkcs8, e := x509.MarshalPKCS8PrivateKey(pkey.key)
+	if e != nil {
+		return sb, e
+	}
+	if sb.EncryptedKey, e = encryptPKCS8(pkcs8, passphrase); e != nil {
+		return sb, e
+	}
+	sb.Certificate = cert.Data()
+	return sb, nil
+}
+
+// Decrypt decrypts the private key and parses the certificate.
+func (sb SafeBag) Decrypt(passphrase []byte) (key PrivateKey, cert *Certificate, e error) {
+	if cert, e = CertFromData(sb.Certificate); e != nil {
+		return nil, nil, e
+	}
+
+	pkcs8, e := decryptPKCS8(sb.EncryptedKey, passphrase)
+	if e != nil {
+		return nil, nil, e
+	}
+	if key, e = parsePKCS8(ToKeyName(cert.Name()), pkcs8); e != nil {
+		return nil, nil, e
+	}
+	if !keyPairMatch(key, cert.PublicKey()) {
+		return nil, nil, ErrKeyMismatch
+	}
+	return key, cert, nil
+}
+
+// keyPairMatch determines whether a private key corresponds to a public key.
+func keyPairMatch(pvt PrivateKey, pub PublicKey) bool {
+	ppvt, _ := pvt.(*privateKey)
+	ppub, _ := pub.(*publicKey)
+	if ppvt == nil || ppub == nil {
+		return false
+	}
+	signer, ok := ppvt.key.(crypto.Signer)
+	if !ok {
+		return false
+	}
+	public, ok := signer.Public().(interface{ Equal(x crypto.PublicKey) bool })
+	return ok && public.Equal(ppub.key)
+}
+
+func encryptPKCS8(pkcs8, passphrase []byte) ([]byte, error) {
+	salt := make([]byte, 8)
+	iv := make([]byte, aes.BlockSize)
+	if _, e := rand.Read(salt); e != nil {
+		return nil, e
+	}
+	if _, e := rand.Read(iv); e != nil {
+		return nil, e
+	}
+
+	kdfParams, e := asn1.Marshal(pbkdf2Params{
+		Salt:           salt,
+		IterationCount: SafeBagIterations,
+		PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+	})
+	if e != nil {
+		return nil, e
+	}
+	ivParam, e := asn1.Marshal(iv)
+	if e != nil {
+		return nil, e
+	}
+	params, e := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParam}},
+	})
+	if e != nil {
+		return nil, e
+	}
+
+	block, _ := aes.NewCipher(pbkdf2.Key(passphrase, salt, SafeBagIterations, 32, sha256.New))
+	padLen := aes.BlockSize - len(pkcs8)%aes.BlockSize
+	ciphertext := append(append([]byte{}, pkcs8...), bytes.Repeat([]byte{byte(padLen)}, padLen)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+
+	return asn1.Marshal(encryptedPrivateKeyInfo{
+		Algo:          pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}},
+		EncryptedData: ciphertext,
+	})
+}
+
+func decryptPKCS8(der, passphrase []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if rest, e := asn1.Unmarshal(der, &info); e != nil || len(rest) > 0 || !info.Algo.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("%w: unsupported EncryptedPrivateKeyInfo", ErrSafeBag)
+	}
+	var params pbes2Params
+	if _, e := asn1.Unmarshal(info.Algo.Parameters.FullBytes, &params); e != nil || !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("%w: unsupported PBES2 parameters", ErrSafeBag)
+	}
+	var kdf pbkdf2Params
+	if _, e := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); e != nil {
+		return nil, fmt.Errorf("%w: bad PBKDF2 parameters", ErrSafeBag)
+	}
+	if kdf.IterationCount <= 0 || kdf.IterationCount > SafeBagMaxIterations {
+		return nil, fmt.Errorf("%w: PBKDF2 iteration count %d out of range", ErrSafeBag, kdf.IterationCount)
+	}
+
+	prf := sha1.New
+	switch {
+	case len(kdf.PRF.Algorithm) == 0, kdf.PRF.Algorithm.Equal(oidHMACWithSHA1):
+	case kdf.PRF.Algorithm.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	default:
+		return nil, fmt.Errorf("%w: unsupported PBKDF2 PRF %s", ErrSafeBag, kdf.PRF.Algorithm)
+	}
+
+	var keyLen int
+	switch enc := params.EncryptionScheme.Algorithm; {
+	case enc.Equal(oidAES128CBC):
+		keyLen = 16
+	case enc.Equal(oidAES192CBC):
+		keyLen = 24
+	case enc.Equal(oidAES256CBC):
+		keyLen = 32
+	default:
+		return nil, fmt.Errorf("%w: unsupported cipher %s", ErrSafeBag, enc)
+	}
+	var iv []byte
+	if _, e := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); e != nil || len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("%w: bad IV", ErrSafeBag)
+	}
+
+	ciphertext := info.EncryptedData
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, ErrPassphrase
+	}
+	block, _ := aes.NewCipher(pbkdf2.Key(passphrase, kdf.Salt, kdf.IterationCount, keyLen, prf))
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	padLen := int(plaintext[len(plaintext)-1])
+	if padLen == 0 || padLen > aes.BlockSize {
+		return nil, ErrPassphrase
+	}
+	for _, b := range plaintext[len(plaintext)-padLen:] {
+		if int(b) != padLen {
+			return nil, ErrPassphrase
+		}
+	}
+	return plaintext[:len(plaintext)-padLen], nil
+}
@@ -0,0 +1,96 @@
+package keychain_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/base64"
+	"testing"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+func TestSafeBag(t *testing.T) {
+	assert, require := makeAR(t)
+
+	pvt, pub, e := keychain.NewECDSAKeyPair(ndn.ParseName("/S"))
+	require.NoError(e)
+	cert, e := keychain.MakeCert(pub, pvt, keychain.MakeCertOptions{})
+	require.NoError(e)
+
+	sb, e := keychain.MakeSafeBag(pvt, cert, []byte("PASSWORD"))
+	require.NoError(e)
+	wire, e := tlv.EncodeFrom(sb)
+	require.NoError(e)
+
+	for _, input := range [][]byte{wire, []byte(base64.StdEncoding.EncodeToString(wire) + "\n")} {
+		sb, e = keychain.ParseSafeBag(input)
+		require.NoError(e)
+
+		_, _, e = sb.Decrypt([]byte("WRONG"))
+		assert.Error(e)
+
+		pvt2, cert2, e := sb.Decrypt([]byte("PASSWORD"))
+		require.NoError(e)
+		nameEqual(assert, pvt, pvt2)
+		nameEqual(assert, cert, cert2.Data())
+
+		data := ndn.MakeData("/S/data")
+		require.NoError(pvt2.Sign(&data))
+		assert.NoError(cert.PublicKey().Verify(data))
+	}
+
+	_, e = keychain.ParseSafeBag([]byte{0x80, 0x00})
+	assert.ErrorIs(e, keychain.ErrSafeBag)
+}
+
+func TestSafeBagOpenSSL(t *testing.T) {
+	assert, require := makeAR(t)
+
+	// generated by:
+	//  openssl ecparam -name prime256v1 -genkey -noout -out ec.pem
+	//  openssl ec -in ec.pem -pubout -outform DER | base64
+	//  openssl pkcs8 -topk8 -in ec.pem -v2 aes-256-cbc -v2prf hmacWithSHA256 -passout pass:PASSWORD -outform DER | base64
+	//  openssl pkcs8 -topk8 -in ec.pem -v2 aes-128-cbc -v2prf hmacWithSHA1 -passout pass:PASSWORD -outform DER | base64
+	spki, _ := base64.StdEncoding.DecodeString("" +
+		"MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEOvOSDIpvcn8KBmBsjLcmaewP+N0pNaMkpjMJmEw9" +
+		"wdniM2MHUdnys9wNfJ3oKS/vJWQ/L9lC0ohmZJZvQz6I6g==")
+	encrypted := []string{
+		"MIHsMFcGCSqGSIb3DQEFDTBKMCkGCSqGSIb3DQEFDDAcBAinh4XAR3jkJAICCAAwDAYIKoZIhvcN" +
+			"AgkFADAdBglghkgBZQMEASoEEJY0HC3HPg1eg4MJFEWaJtoEgZDYw86MXo0wwiesVZ6ZSDo/30ya" +
+			"CT2E6NyK6G+NYnovzfeIX8sWKflM22OgHaiHZSNGGz8wbbSndn7qkJwjcQsHdScDpjoIXph+XtfM" +
+			"HZV6MKBdeUKltTcc7+SDsSQFt3pjjAsf9RwAhFaB3lwLWji4GW0kVxE1CbevS5Hcaa1gU2ICSIpu" +
+			"YTvvUNawFMmOXIg=",
+		"MIHeMEkGCSqGSIb3DQEFDTA8MBsGCSqGSIb3DQEFDDAOBAjQKKG6bKAIIgICCAAwHQYJYIZIAWUD" +
+			"BAECBBB51oFWvW8rMWNx7tgxqVhiBIGQ6pLS/bmvVi/oubbtpRnv7+hfG3FGsWedTNaDTRkwH02c" +
+			"2HMJjVURc4CjbgKmJbGaJ/eaJprXKummtypEe/mRfYUdqGAX2aBhIqYNjioNcdkBY93wELtRC9+4" +
+			"2FZ+bYVBp3H7kay+UWkWwaek3lmSbHA2TPcE6lC5k8BjK/Qka7Nah1rcCnWCHMCb15GxGJqq",
+	}
+
+	key, e := x509.ParsePKIXPublicKey(spki)
+	require.NoError(e)
+	pub, e := keychain.NewECDSAPublicKey(keychain.ToKeyName(ndn.ParseName("/O")), key.(*ecdsa.PublicKey))
+	require.NoError(e)
+	issuer, _, _ := keychain.NewECDSAKeyPair(ndn.ParseName("/I"))
+	cert, e := keychain.MakeCert(pub, issuer, keychain.MakeCertOptions{})
+	require.NoError(e)
+
+	for i, input := range encrypted {
+		var sb keychain.SafeBag
+		sb.Certificate = cert.Data()
+		sb.EncryptedKey, _ = base64.StdEncoding.DecodeString(input)
+
+		pvt, _, e := sb.Decrypt([]byte("PASSWORD"))
+		require.NoError(e, i)
+		data := ndn.MakeData("/O/data")
+		require.NoError(pvt.Sign(&data))
+		assert.NoError(pub.Verify(data), i)
+
+		// PBKDF2 iteration count out of range
+		sb.EncryptedKey = bytes.Replace(sb.EncryptedKey, []byte{0x02, 0x02, 0x08, 0x00}, []byte{0x02, 0x02, 0x80, 0x00}, 1)
+		_, _, e = sb.Decrypt([]byte("PASSWORD"))
+		assert.ErrorIs(e, keychain.ErrSafeBag, i)
+	}
+}
@@ -0,0 +1,225 @@
+package keychain
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+)
+
+// Error conditions for trust schema.
+var (
+	ErrNamePattern = errors.New("bad name pattern")
+	ErrTrustSchema = errors.New("no trust schema rule permits this signer")
+)
+
+type patternComponentKind int
+
+const (
+	patternLiteral patternComponentKind = iota
+	patternWildcard
+	patternVariable
+	patternRest
+)
+
+type patternComponent struct {
+	kind patternComponentKind
+	comp ndn.NameComponent
+	tag  string
+}
+
+func (pc patternComponent) String() string {
+	switch pc.kind {
+	case patternWildcard:
+		return "<>"
+	case patternVariable:
+		return "<" + pc.tag + ">"
+	case patternRest:
+		return "<*>"
+	}
+	return pc.comp.String()
+}
+
+// NamePattern is a name pattern in a trust schema rule.
+//
+// It is written in URI format where each component is one of:
+//   - a name component in URI format, which matches the same component.
+//   - "<>", which matches any one component.
+//   - "<tag>", which matches any one component, and binds it to a variable.
+//     Within a rule, every occurrence of the same variable must bind to the same component.
+//   - "<*>", which matches zero or more components.
+type NamePattern []patternComponent
+
+// ParseNamePattern parses URI representation of name pattern.
+func ParseNamePattern(input string) (p NamePattern, e error) {
+	input = strings.TrimPrefix(input, "ndn:")
+	for _, token := range strings.Split(input, "/") {
+		switch {
+		case token == "":
+			continue
+		case token == "<>":
+			p = append(p, patternComponent{kind: patternWildcard})
+		case token == "<*>":
+			p = append(p, patternComponent{kind: patternRest})
+		case strings.HasPrefix(token, "<") && strings.HasSuffix(token, ">"):
+			tag := token[1 : len(token)-1]
+			if strings.ContainsAny(tag, "<>*") {
+				return nil, fmt.Errorf("%w: %s", ErrNamePattern, token)
+			}
+			p = append(p, patternComponent{kind: patternVariable, tag: tag})
+		case strings.ContainsAny(token, "<>"):
+			return nil, fmt.Errorf("%w: %s", ErrNamePattern, token)
+		default:
+			p = append(p, patternComponent{kind: patternLiteral, comp: ndn.ParseNameComponent(token)})
+		}
+	}
+	return p, nil
+}
+
+// MustParseNamePattern parses URI representation of name pattern, and panics on error.
+func MustParseNamePattern(input string) NamePattern {
+	p, e := ParseNamePattern(input)
+	if e != nil {
+		panic(e)
+	}
+	return p
+}
+
+// Match determines whether a name matches this pattern.
+//
+// vars contains variable bindings.
+// Existing bindings must be satisfied; new bindings are added if the name matches.
+// It may be nil if the caller does not need bindings.
+func (p NamePattern) Match(name ndn.Name, vars map[string]ndn.NameComponent) bool {
+	if vars == nil {
+		vars = map[string]ndn.NameComponent{}
+	}
+	return p.match(name, vars, func() bool { return true })
+}
+
+// match performs backtracking match.
+// cont is invoked after a complete match; if it returns false, other bindings are attempted.
+func (p NamePattern) match(name ndn.Name, vars map[string]ndn.NameComponent, cont func() bool) bool {
+	if len(p) == 0 {
+		return len(name) == 0 && cont()
+	}
+
+	pc := p[0]
+	if pc.kind == patternRest {
+		for i := 0; i <= len(name); i++ {
+			if p[1:].match(name[i:], vars, cont) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+	comp := name[0]
+	switch pc.kind {
+	case patternLiteral:
+		if !pc.comp.Equal(comp) {
+			return false
+		}
+	case patternVariable:
+		if bound, ok := vars[pc.tag]; !ok {
+			vars[pc.tag] = comp
+			if p[1:].match(name[1:], vars, cont) {
+				return true
+			}
+			delete(vars, pc.tag)
+			return false
+		} else if !bound.Equal(comp) {
+			return false
+		}
+	}
+	return p[1:].match(name[1:], vars, cont)
+}
+
+func (p NamePattern) String() string {
+	if len(p) == 0 {
+		return "/"
+	}
+	var b strings.Builder
+	for _, pc := range p {
+		b.WriteByte('/')
+		b.WriteString(pc.String())
+	}
+	return b.String()
+}
+
+// TrustRule is a trust schema rule.
+// It permits a packet whose name matches Packet pattern to be signed by a key whose name matches Signer pattern.
+type TrustRule struct {
+	Packet NamePattern
+	Signer NamePattern
+}
+
+// ParseTrustRule parses a trust schema rule.
+// The syntax is "packet-pattern <= signer-pattern", such as:
+//
+//	/lab/sensor/<id>/<*> <= /lab/sensor/<id>/KEY/<>
+func ParseTrustRule(input string) (rule TrustRule, e error) {
+	tokens := strings.Split(input, "<=")
+	if len(tokens) != 2 {
+		return rule, fmt.Errorf("%w: %s", ErrNamePattern, input)
+	}
+	if rule.Packet, e = ParseNamePattern(strings.TrimSpace(tokens[0])); e != nil {
+		return rule, e
+	}
+	if rule.Signer, e = ParseNamePattern(strings.TrimSpace(tokens[1])); e != nil {
+		return rule, e
+	}
+	return rule, nil
+}
+
+// Match determines whether a packet name and a signer key name satisfy this rule.
+func (rule TrustRule) Match(pktName, keyName ndn.Name) bool {
+	vars := map[string]ndn.NameComponent{}
+	return rule.Packet.match(pktName, vars, func() bool {
+		return rule.Signer.match(keyName, vars, func() bool { return true })
+	})
+}
+
+func (rule TrustRule) String() string {
+	return rule.Packet.String() + " <= " + rule.Signer.String()
+}
+
+// TrustSchema is a list of trust schema rules.
+type TrustSchema []TrustRule
+
+// ParseTrustSchema parses a trust schema consisting of one rule per line.
+// Empty lines and lines starting with '#' are ignored.
+func ParseTrustSchema(input string) (schema TrustSchema, e error) {
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule, e := ParseTrustRule(line)
+		if e != nil {
+			return nil, e
+		}
+		schema = append(schema, rule)
+	}
+	return schema, scanner.Err()
+}
+
+// Check determines whether a packet name may be signed by a key.
+// If keyName is a certificate name, it is converted to a key name before matching.
+func (schema TrustSchema) Check(pktName, keyName ndn.Name) bool {
+	if IsCertName(keyName) {
+		keyName = ToKeyName(keyName)
+	}
+	for _, rule := range schema {
+		if rule.Match(pktName, keyName) {
+			return true
+		}
+	}
+	return false
+}
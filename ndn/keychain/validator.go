@@ -0,0 +1,255 @@
+package keychain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+)
+
+// Error conditions for validator.
+var (
+	ErrCertChain    = errors.New("certificate chain exceeds MaxChainLength")
+	ErrCertValidity = errors.New("certificate is outside ValidityPeriod")
+	ErrCertFetch    = errors.New("cannot retrieve certificate")
+	ErrCertLoop     = errors.New("certificate chain contains a loop")
+)
+
+// ValidatorOptions contains arguments to NewValidator function.
+type ValidatorOptions struct {
+	// Schema contains trust schema rules.
+	// Every packet in the certificate chain, including the certificates, must satisfy a rule.
+	Schema TrustSchema
+
+	// Anchors are trusted certificates.
+	// A certificate chain ends when it reaches a trust anchor.
+	Anchors []*Certificate
+
+	// Consumer contains options for retrieving certificates with endpoint.Consume.
+	// Verifier is ignored, because retrieved certificates are validated by this validator.
+	Consumer endpoint.ConsumerOptions
+
+	// NoFetch disables certificate retrieval.
+	// If true, intermediate certificates must be added with AddCert.
+	NoFetch bool
+
+	// MaxChainLength is the maximum number of certificates retrieved for validating one packet,
+	// excluding the trust anchor.
+	// Default is 8.
+	MaxChainLength int
+
+	// CacheCapacity is the maximum number of validated certificates in the cache.
+	// Default is 256.
+	CacheCapacity int
+}
+
+func (opts *ValidatorOptions) applyDefaults() {
+	opts.Consumer.Verifier = nil
+	if opts.MaxChainLength <= 0 {
+		opts.MaxChainLength = 8
+	}
+	if opts.CacheCapacity <= 0 {
+		opts.CacheCapacity = 256
+	}
+}
+
+// Validator is a trust schema based ndn.Verifier.
+//
+// It verifies a packet in these steps:
+//  1. Find a trust schema rule that permits the KeyLocator to sign the packet.
+//  2. Find the certificate named by KeyLocator among trust anchors, the certificate cache, or the network.
+//  3. Validate a retrieved certificate recursively, and ensure every certificate is within its ValidityPeriod.
+//     A cached certificate is revalidated after the earliest NotAfter in its chain.
+//  4. Verify the packet signature with the public key in the certificate.
+type Validator struct {
+	opts    ValidatorOptions
+	cacheMu sync.Mutex
+	cache   *simplelru.LRU // key name string => *validatedCert
+	pending *simplelru.LRU // key name string => *Certificate, not yet validated
+}
+
+var _ ndn.Verifier = (*Validator)(nil)
+
+// NewValidator creates a Validator.
+func NewValidator(opts ValidatorOptions) *Validator {
+	opts.applyDefaults()
+	v := &Validator{
+		opts: opts,
+	}
+	v.cache, _ = simplelru.NewLRU(opts.CacheCapacity, nil)
+	v.pending, _ = simplelru.NewLRU(opts.CacheCapacity, nil)
+	return v
+}
+
+// AddCert inserts an intermediate certificate.
+// It will be validated when it is used in a certificate chain.
+func (v *Validator) AddCert(cert *Certificate) {
+	v.cacheMu.Lock()
+	defer v.cacheMu.Unlock()
+	v.pending.Add(ToKeyName(cert.Name()).String(), cert)
+}
+
+// Verify implements ndn.Verifier interface.
+// Certificate retrieval is not cancelable; use VerifyContext to pass a context.
+func (v *Validator) Verify(packet ndn.Verifiable) error {
+	return v.VerifyContext(context.Background(), packet)
+}
+
+// VerifyContext verifies a packet.
+// ctx bounds certificate retrieval.
+func (v *Validator) VerifyContext(ctx context.Context, packet ndn.Verifiable) error {
+	_, e := v.verify(ctx, packet, nil)
+	return e
+}
+
+// verify verifies a packet.
+// chain contains key names of certificates being validated, from the original packet's signer.
+// It returns the earliest NotAfter among certificates in the signer's chain.
+func (v *Validator) verify(ctx context.Context, packet ndn.Verifiable, chain []string) (notAfter time.Time, e error) {
+	e = packet.VerifyWith(func(name ndn.Name, si ndn.SigInfo) (ndn.LLVerify, error) {
+		klName := si.KeyLocator.Name
+		if !IsKeyName(klName) && !IsCertName(klName) {
+			return nil, ndn.ErrKeyLocator
+		}
+		if !v.opts.Schema.Check(name, klName) {
+			return nil, fmt.Errorf("%w: %s <= %s", ErrTrustSchema, name, klName)
+		}
+
+		cert, certNotAfter, e := v.findCert(ctx, klName, chain)
+		if e != nil {
+			return nil, e
+		}
+		notAfter = certNotAfter
+
+		pub, ok := cert.PublicKey().(*publicKey)
+		if !ok {
+			return nil, fmt.Errorf("unknown key type %T", cert.PublicKey())
+		}
+		if si.Type != pub.sigType {
+			return nil, ndn.ErrSigType
+		}
+		return pub.llVerify, nil
+	})
+	return
+}
+
+func (v *Validator) findCert(ctx context.Context, klName ndn.Name, chain []string) (cert *Certificate, notAfter time.Time, e error) {
+	now := time.Now()
+	matchName := func(cert *Certificate) bool {
+		if IsCertName(klName) {
+			return cert.Name().Equal(klName)
+		}
+		return ToKeyName(cert.Name()).Equal(klName)
+	}
+
+	for _, anchor := range v.opts.Anchors {
+		if matchName(anchor) {
+			if !anchor.Validity().Includes(now) {
+				return nil, time.Time{}, fmt.Errorf("%w: %s", ErrCertValidity, anchor.Name())
+			}
+			return anchor, anchor.Validity().NotAfter, nil
+		}
+	}
+
+	if len(chain) >= v.opts.MaxChainLength {
+		return nil, time.Time{}, ErrCertChain
+	}
+	keyS := ToKeyName(klName).String()
+	for _, k := range chain {
+		if k == keyS {
+			return nil, time.Time{}, fmt.Errorf("%w: %s", ErrCertLoop, klName)
+		}
+	}
+
+	isPending := false
+	v.cacheMu.Lock()
+	if c, ok := v.cache.Get(keyS); ok && matchName(c.(*validatedCert).cert) {
+		cert = c.(*validatedCert).cert
+	} else if c, ok := v.pending.Peek(keyS); ok && matchName(c.(*Certificate)) {
+		cert, isPending = c.(*Certificate), true
+	}
+	v.cacheMu.Unlock()
+
+	if cert != nil {
+		if notAfter, e = v.validateCert(ctx, cert, chain); e == nil {
+			if isPending { // pending certificate stays until it is validated
+				v.cacheMu.Lock()
+				if c, ok := v.pending.Peek(keyS); ok && c.(*Certificate) == cert {
+					v.pending.Remove(keyS)
+				}
+				v.cacheMu.Unlock()
+			}
+			return cert, notAfter, nil
+		}
+	}
+
+	if v.opts.NoFetch {
+		if e == nil {
+			e = fmt.Errorf("%w: %s", ErrCertFetch, klName)
+		}
+		return nil, time.Time{}, e
+	}
+
+	interest := ndn.Interest{
+		Name:        klName,
+		CanBePrefix: IsKeyName(klName),
+	}
+	data, e := endpoint.Consume(ctx, interest, v.opts.Consumer)
+	if e != nil {
+		return nil, time.Time{}, fmt.Errorf("%w: %s %v", ErrCertFetch, klName, e)
+	}
+	if cert, e = CertFromData(*data); e != nil {
+		return nil, time.Time{}, e
+	}
+	if !matchName(cert) {
+		return nil, time.Time{}, fmt.Errorf("%w: %s", ErrCertName, cert.Name())
+	}
+	if notAfter, e = v.validateCert(ctx, cert, chain); e != nil {
+		return nil, time.Time{}, e
+	}
+	return cert, notAfter, nil
+}
+
+// validateCert validates a certificate and its issuer chain.
+// It returns the earliest NotAfter in the chain, until which the certificate stays in the cache.
+func (v *Validator) validateCert(ctx context.Context, cert *Certificate, chain []string) (notAfter time.Time, e error) {
+	now := time.Now()
+	if !cert.Validity().Includes(now) {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrCertValidity, cert.Name())
+	}
+
+	keyS := ToKeyName(cert.Name()).String()
+	v.cacheMu.Lock()
+	c, ok := v.cache.Get(keyS)
+	v.cacheMu.Unlock()
+	if ok {
+		if vc := c.(*validatedCert); vc.cert.Name().Equal(cert.Name()) && !now.Truncate(time.Second).After(vc.notAfter) {
+			return vc.notAfter, nil
+		}
+	}
+
+	issuerNotAfter, e := v.verify(ctx, cert.Data(), append(chain[:len(chain):len(chain)], keyS))
+	if e != nil {
+		return time.Time{}, e
+	}
+	notAfter = cert.Validity().NotAfter
+	if issuerNotAfter.Before(notAfter) {
+		notAfter = issuerNotAfter
+	}
+
+	v.cacheMu.Lock()
+	defer v.cacheMu.Unlock()
+	v.cache.Add(keyS, &validatedCert{cert, notAfter})
+	return notAfter, nil
+}
+
+// validatedCert is a certificate cache entry.
+type validatedCert struct {
+	cert     *Certificate
+	notAfter time.Time // earliest NotAfter in the certificate chain
+}
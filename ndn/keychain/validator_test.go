@@ -0,0 +1,184 @@
+package keychain_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"go4.org/must"
+)
+
+func TestNamePattern(t *testing.T) {
+	assert, require := makeAR(t)
+
+	p, e := keychain.ParseNamePattern("/lab/sensor/<id>/<>/<*>")
+	require.NoError(e)
+	assert.Equal("/8=lab/8=sensor/<id>/<>/<*>", p.String())
+
+	vars := map[string]ndn.NameComponent{}
+	assert.True(p.Match(ndn.ParseName("/lab/sensor/7/temp"), vars))
+	assert.Equal("8=7", vars["id"].String())
+	assert.True(p.Match(ndn.ParseName("/lab/sensor/7/temp/1/2"), vars))
+	assert.False(p.Match(ndn.ParseName("/lab/sensor/8/temp"), vars))
+	assert.False(p.Match(ndn.ParseName("/lab/sensor/7"), nil))
+	assert.False(p.Match(ndn.ParseName("/lab/actuator/7/temp"), nil))
+
+	_, e = keychain.ParseNamePattern("/lab/<<>")
+	assert.Error(e)
+	_, e = keychain.ParseNamePattern("/lab/a<b")
+	assert.Error(e)
+
+	rule, e := keychain.ParseTrustRule("/<*>/<user>/data/<*> <= /<*>/<user>/KEY/<>")
+	require.NoError(e)
+	assert.True(rule.Match(ndn.ParseName("/org/alice/data/1"), ndn.ParseName("/org/alice/KEY/k")))
+	assert.True(rule.Match(ndn.ParseName("/org/alice/data/1"), ndn.ParseName("/other/alice/KEY/k")))
+	assert.False(rule.Match(ndn.ParseName("/org/alice/data/1"), ndn.ParseName("/org/bob/KEY/k")))
+
+	_, e = keychain.ParseTrustRule("/A /B")
+	assert.Error(e)
+}
+
+func TestValidator(t *testing.T) {
+	assert, require := makeAR(t)
+	fw := l3.NewForwarder()
+
+	rootPvt, rootPub, e := keychain.NewECDSAKeyPair(ndn.ParseName("/lab"))
+	require.NoError(e)
+	rootCert, e := keychain.MakeCert(rootPub, rootPvt, keychain.MakeCertOptions{})
+	require.NoError(e)
+
+	makeKey := func(subject string, issuer ndn.Signer, validity keychain.ValidityPeriod) (signer keychain.PrivateKey, cert *keychain.Certificate) {
+		pvt, pub, e := keychain.NewECDSAKeyPair(ndn.ParseName(subject))
+		require.NoError(e)
+		cert, e = keychain.MakeCert(pub, issuer, keychain.MakeCertOptions{Validity: validity})
+		require.NoError(e)
+
+		p, e := endpoint.Produce(context.Background(), endpoint.ProducerOptions{
+			Prefix: keychain.ToKeyName(cert.Name()),
+			Handler: func(ctx context.Context, interest ndn.Interest) (ndn.Data, error) {
+				return cert.Data(), nil
+			},
+			Fw: fw,
+		})
+		require.NoError(e)
+		t.Cleanup(func() { must.Close(p) })
+		return pvt, cert
+	}
+	withKL := func(pvt keychain.PrivateKey, cert *keychain.Certificate) ndn.Signer {
+		return pvt.WithKeyLocator(cert.Name())
+	}
+
+	now := time.Now()
+	expired := keychain.ValidityPeriod{NotBefore: now.Add(-2 * time.Hour), NotAfter: now.Add(-time.Hour)}
+	signer7 := withKL(makeKey("/lab/sensor/7", rootPvt, keychain.ValidityPeriod{}))
+	signer8 := withKL(makeKey("/lab/sensor/8", rootPvt, expired))
+	pvt9, cert9 := makeKey("/lab/sensor/9", rootPvt, keychain.ValidityPeriod{})
+	signer9 := withKL(pvt9, cert9)
+
+	schema, e := keychain.ParseTrustSchema(`
+		# sensor data signed by sensor key
+		/lab/sensor/<id>/<*> <= /lab/sensor/<id>/KEY/<>
+		# sensor certificate signed by lab key or site key
+		/lab/sensor/<id>/KEY/<>/<>/<> <= /lab/<*>/KEY/<>
+		# site certificate signed by lab key
+		/lab/site/KEY/<>/<>/<> <= /lab/KEY/<>
+	`)
+	require.NoError(e)
+	require.Len(schema, 3)
+
+	v := keychain.NewValidator(keychain.ValidatorOptions{
+		Schema:   schema,
+		Anchors:  []*keychain.Certificate{rootCert},
+		Consumer: endpoint.ConsumerOptions{Fw: fw},
+	})
+
+	makeData := func(name string, signer ndn.Signer) *ndn.Data {
+		data := ndn.MakeData(name, []byte{0xC0})
+		require.NoError(signer.Sign(&data))
+		return &data
+	}
+
+	assert.NoError(v.Verify(makeData("/lab/sensor/7/temp/1", signer7)))
+	assert.NoError(v.Verify(makeData("/lab/sensor/7/temp/2", signer7))) // certificate from cache
+	assert.ErrorIs(v.Verify(makeData("/lab/sensor/8/temp/1", signer7)), keychain.ErrTrustSchema)
+	assert.ErrorIs(v.Verify(makeData("/lab/sensor/8/temp/1", signer8)), keychain.ErrCertValidity)
+	assert.ErrorIs(v.Verify(makeData("/lab/sensor/9/temp/1", signer7)), keychain.ErrTrustSchema)
+	assert.ErrorIs(v.Verify(makeData("/lab/sensor/7/temp/1", rootPvt)), keychain.ErrTrustSchema)
+
+	interest := ndn.MakeInterest("/lab/sensor/9/cmd", []byte{0xC0})
+	require.NoError(signer9.Sign(&interest))
+	assert.NoError(v.Verify(interest))
+
+	forged := makeData("/lab/sensor/7/temp/3", signer7)
+	forged.Content = []byte{0xC1}
+	assert.ErrorIs(v.Verify(forged), ndn.ErrSigValue)
+
+	// certificate retrieval honors caller's context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	v1 := keychain.NewValidator(keychain.ValidatorOptions{
+		Schema:   schema,
+		Anchors:  []*keychain.Certificate{rootCert},
+		Consumer: endpoint.ConsumerOptions{Fw: fw},
+	})
+	t0 := time.Now()
+	assert.ErrorIs(v1.VerifyContext(ctx, makeData("/lab/sensor/7/temp/1", signer7)), keychain.ErrCertFetch)
+	assert.Less(time.Since(t0), time.Second)
+
+	// intermediate certificates without retrieval
+	sitePvt, siteCert := makeKey("/lab/site", rootPvt, keychain.ValidityPeriod{})
+	pvt10, cert10 := makeKey("/lab/sensor/10", withKL(sitePvt, siteCert), keychain.ValidityPeriod{})
+	signer10 := withKL(pvt10, cert10)
+	v2 := keychain.NewValidator(keychain.ValidatorOptions{
+		Schema:  schema,
+		Anchors: []*keychain.Certificate{rootCert},
+		NoFetch: true,
+	})
+	assert.ErrorIs(v2.Verify(makeData("/lab/sensor/9/temp/1", signer9)), keychain.ErrCertFetch)
+	v2.AddCert(cert9)
+	assert.NoError(v2.Verify(makeData("/lab/sensor/9/temp/1", signer9)))
+
+	v2.AddCert(cert10)
+	assert.ErrorIs(v2.Verify(makeData("/lab/sensor/10/temp/1", signer10)), keychain.ErrCertFetch) // site cert missing
+	v2.AddCert(siteCert)
+	assert.NoError(v2.Verify(makeData("/lab/sensor/10/temp/1", signer10))) // cert10 kept after failed validation
+
+	// intermediate certificate revalidated after its issuer expires
+	shortPvt, shortCert := makeKey("/lab/site", rootPvt, keychain.ValidityPeriod{NotBefore: now.Add(-time.Hour), NotAfter: now.Add(time.Second)})
+	pvt11, cert11 := makeKey("/lab/sensor/11", withKL(shortPvt, shortCert), keychain.ValidityPeriod{})
+	signer11 := withKL(pvt11, cert11)
+	v3 := keychain.NewValidator(keychain.ValidatorOptions{
+		Schema:  schema,
+		Anchors: []*keychain.Certificate{rootCert},
+		NoFetch: true,
+	})
+	v3.AddCert(shortCert)
+	v3.AddCert(cert11)
+	assert.NoError(v3.Verify(makeData("/lab/sensor/11/temp/1", signer11)))
+	time.Sleep(time.Until(shortCert.Validity().NotAfter.Add(1100 * time.Millisecond)))
+	assert.ErrorIs(v3.Verify(makeData("/lab/sensor/11/temp/2", signer11)), keychain.ErrCertValidity)
+
+	// certificates signing each other
+	permissive, e := keychain.ParseTrustSchema(`/<*> <= /<*>`)
+	require.NoError(e)
+	pvtP, pubP, e := keychain.NewECDSAKeyPair(ndn.ParseName("/P"))
+	require.NoError(e)
+	pvtQ, pubQ, e := keychain.NewECDSAKeyPair(ndn.ParseName("/Q"))
+	require.NoError(e)
+	certP, e := keychain.MakeCert(pubP, pvtQ.WithKeyLocator(pubQ.Name()), keychain.MakeCertOptions{})
+	require.NoError(e)
+	certQ, e := keychain.MakeCert(pubQ, pvtP.WithKeyLocator(pubP.Name()), keychain.MakeCertOptions{})
+	require.NoError(e)
+	v4 := keychain.NewValidator(keychain.ValidatorOptions{
+		Schema:  permissive,
+		Anchors: []*keychain.Certificate{rootCert},
+		NoFetch: true,
+	})
+	v4.AddCert(certP)
+	v4.AddCert(certQ)
+	assert.ErrorIs(v4.Verify(makeData("/P/data", withKL(pvtP, certP))), keychain.ErrCertLoop)
+}
@@ -0,0 +1,91 @@
+package l3
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+)
+
+type csEntry struct {
+	data    *ndn.Data
+	staleAt time.Time
+}
+
+func (entry *csEntry) canSatisfy(interest ndn.Interest, now time.Time) bool {
+	if interest.MustBeFresh && !now.Before(entry.staleAt) {
+		return false
+	}
+	return entry.data.CanSatisfy(interest)
+}
+
+// cs is the content store.
+type cs struct {
+	lru  *simplelru.LRU // name TLV-VALUE => *csEntry
+	keys []string       // sorted name TLV-VALUE, for prefix match
+}
+
+func newCs(capacity int) *cs {
+	if capacity <= 0 {
+		return nil
+	}
+	c := &cs{}
+	c.lru, _ = simplelru.NewLRU(capacity, c.evict)
+	return c
+}
+
+// evict removes a key from the sorted index, when it is removed from the LRU.
+func (c *cs) evict(key, value interface{}) {
+	k := key.(string)
+	if i := sort.SearchStrings(c.keys, k); i < len(c.keys) && c.keys[i] == k {
+		c.keys = append(c.keys[:i], c.keys[i+1:]...)
+	}
+}
+
+// Insert adds a Data packet, replacing any existing Data packet of the same name.
+func (c *cs) Insert(data *ndn.Data, now time.Time) {
+	if c == nil {
+		return
+	}
+	key := pitKey(data.Name)
+	isNew := !c.lru.Contains(key)
+	c.lru.Add(key, &csEntry{
+		data:    data,
+		staleAt: now.Add(data.Freshness),
+	})
+	if isNew {
+		i := sort.SearchStrings(c.keys, key)
+		c.keys = append(c.keys, "")
+		copy(c.keys[i+1:], c.keys[i:])
+		c.keys[i] = key
+	}
+}
+
+// Find returns a Data packet that can satisfy the Interest.
+func (c *cs) Find(interest ndn.Interest, now time.Time) *ndn.Data {
+	if c == nil {
+		return nil
+	}
+
+	if !interest.CanBePrefix {
+		key := pitKey(interest.Name)
+		if v, ok := c.lru.Peek(key); ok && v.(*csEntry).canSatisfy(interest, now) {
+			c.lru.Get(key)
+			return v.(*csEntry).data
+		}
+		return nil
+	}
+
+	// TLV-VALUE of a name under the prefix starts with TLV-VALUE of the prefix
+	prefix := pitKey(interest.Name)
+	for i := sort.SearchStrings(c.keys, prefix); i < len(c.keys) && strings.HasPrefix(c.keys[i], prefix); i++ {
+		v, _ := c.lru.Peek(c.keys[i])
+		if entry := v.(*csEntry); entry.canSatisfy(interest, now) {
+			c.lru.Get(c.keys[i])
+			return entry.data
+		}
+	}
+	return nil
+}
@@ -10,6 +10,8 @@
 package l3
 
 import (
+	"time"
+
 	"github.com/pkg/math"
 	"github.com/usnistgov/ndn-dpdk/ndn"
 	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
@@ -23,6 +25,10 @@ const (
 // FaceConfig contains options for NewFace.
 type FaceConfig struct {
 	ReassemblerCapacity int
+
+	// Reliability contains NDNLPv2 link reliability options.
+	// Both ends of the link should enable this feature.
+	Reliability ReliabilityConfig
 }
 
 func (cfg *FaceConfig) applyDefaults() {
@@ -59,7 +65,14 @@ func NewFace(tr Transport, cfg FaceConfig) (Face, error) {
 		reassembler: ndn.NewLpReassembler(cfg.ReassemblerCapacity),
 	}
 
-	if mtu := tr.MTU(); mtu > 0 {
+	mtu := tr.MTU()
+	if cfg.Reliability.Enabled {
+		f.rel = newLpReliability(cfg.Reliability, mtu)
+		if mtu > 0 {
+			mtu -= reliabilityOverhead
+		}
+	}
+	if mtu > 0 {
 		f.fragmenter = ndn.NewLpFragmenter(mtu)
 	}
 
@@ -75,6 +88,7 @@ type face struct {
 
 	fragmenter  *ndn.LpFragmenter
 	reassembler *ndn.LpReassembler
+	rel         *lpReliability
 }
 
 type faceTr struct {
@@ -101,9 +115,15 @@ func (f *face) rxLoop() {
 			continue
 		}
 
-		if pkt.Fragment == nil {
+		if f.rel != nil {
+			f.rel.Rx(&pkt)
+		}
+
+		switch {
+		case pkt.Fragment == nil && pkt.Interest == nil && pkt.Data == nil && pkt.Nack == nil: // IDLE frame
+		case pkt.Fragment == nil:
 			f.rx <- &pkt
-		} else {
+		default:
 			full, e := f.reassembler.Accept(&pkt)
 			if e == nil && full != nil {
 				f.rx <- full
@@ -115,22 +135,56 @@ func (f *face) rxLoop() {
 
 func (f *face) txLoop() {
 	transportTx := f.faceTr.Tx()
-	for l3packet := range f.tx {
-		pkt := l3packet.ToPacket()
-
-		if f.fragmenter == nil {
-			f.txFrames(transportTx, pkt)
-		} else {
-			frags, e := f.fragmenter.Fragment(pkt)
-			if e == nil {
-				f.txFrames(transportTx, frags...)
+	defer close(transportTx)
+
+	var retxTimer, idleTimer <-chan time.Time
+	var ackNotify <-chan struct{}
+	if f.rel != nil {
+		ticker := time.NewTicker(f.rel.cfg.RTO / 4)
+		defer ticker.Stop()
+		retxTimer, ackNotify = ticker.C, f.rel.ackNotify
+	}
+
+	for {
+		select {
+		case l3packet, ok := <-f.tx:
+			if !ok {
+				return
+			}
+			pkt := l3packet.ToPacket()
+
+			if f.fragmenter == nil {
+				f.txFrames(transportTx, pkt)
+			} else {
+				frags, e := f.fragmenter.Fragment(pkt)
+				if e == nil {
+					f.txFrames(transportTx, frags...)
+				}
 			}
+		case now := <-retxTimer:
+			f.txWire(transportTx, f.rel.Retransmit(now)...)
+		case <-ackNotify:
+			if idleTimer == nil {
+				idleTimer = time.After(reliabilityIdleAckDelay)
+			}
+		case <-idleTimer:
+			idleTimer = nil
+			f.txWire(transportTx, f.rel.Idle()...)
 		}
 	}
-	close(transportTx)
 }
 
 func (f *face) txFrames(transportTx chan<- []byte, frames ...*ndn.Packet) {
+	if f.rel != nil {
+		now := time.Now()
+		for i, frame := range frames {
+			frames[i] = f.rel.Tx(frame, now)
+		}
+	}
+	f.txWire(transportTx, frames...)
+}
+
+func (f *face) txWire(transportTx chan<- []byte, frames ...*ndn.Packet) {
 	for _, frame := range frames {
 		wire, e := tlv.EncodeFrom(frame)
 		if e == nil {
@@ -0,0 +1,81 @@
+package l3_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+)
+
+// newLossyFacePair creates a pair of faces connected by a link that drops every lossInterval-th frame.
+func newLossyFacePair(t *testing.T, cfg l3.FaceConfig, lossInterval int) (faceA, faceB l3.Face) {
+	_, require := makeAR(t)
+	trA, pA := l3.NewTransportBase(l3.TransportBaseConfig{MTU: 1000})
+	trB, pB := l3.NewTransportBase(l3.TransportBaseConfig{MTU: 1000})
+	relay := func(src <-chan []byte, dst chan<- []byte) {
+		n := 0
+		for wire := range src {
+			if n++; n%lossInterval != 0 {
+				dst <- wire
+			}
+		}
+		close(dst)
+	}
+	go relay(pA.Tx, pB.Rx)
+	go relay(pB.Tx, pA.Rx)
+
+	var e error
+	faceA, e = l3.NewFace(trA, cfg)
+	require.NoError(e)
+	faceB, e = l3.NewFace(trB, cfg)
+	require.NoError(e)
+	return
+}
+
+func countReceived(faceA, faceB l3.Face, count int) int {
+	go func() {
+		for i := 0; i < count; i++ {
+			faceA.Tx() <- ndn.MakeData(fmt.Sprintf("/D/%d", i), bytes.Repeat([]byte{0xCC}, 3000))
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	received := map[string]bool{}
+	timeout := time.After(2 * time.Second)
+	for len(received) < count {
+		select {
+		case pkt := <-faceB.Rx():
+			if pkt.Data != nil {
+				received[pkt.Data.Name.String()] = true
+			}
+		case <-timeout:
+			return len(received)
+		}
+	}
+	return len(received)
+}
+
+func TestFaceReliability(t *testing.T) {
+	assert, _ := makeAR(t)
+	const count = 50
+
+	faceA, faceB := newLossyFacePair(t, l3.FaceConfig{}, 7)
+	assert.Less(countReceived(faceA, faceB, count), count)
+	close(faceA.Tx())
+	close(faceB.Tx())
+
+	faceA, faceB = newLossyFacePair(t, l3.FaceConfig{
+		ReassemblerCapacity: count,
+		Reliability: l3.ReliabilityConfig{
+			Enabled: true,
+			RTO:     50 * time.Millisecond,
+			MaxRetx: 8,
+		},
+	}, 7)
+	assert.Equal(count, countReceived(faceA, faceB, count))
+	close(faceA.Tx())
+	close(faceB.Tx())
+}
@@ -3,27 +3,39 @@ package l3
 import (
 	"math/rand"
 	"sync"
+	"time"
 
 	"github.com/jwangsadinata/go-multimap"
 	"github.com/jwangsadinata/go-multimap/setmultimap"
 	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
 )
 
 // Forwarder is a logical forwarding plane.
 // Its main purpose is to demultiplex incoming packets among faces, where a 'face' is defined as a duplex stream of packets.
 //
-// This is a simplified forwarder with several limitations.
-//  - There is no loop prevention: no Nonce list and no decrementing HopLimit.
-//    If multiple uplinks have "/" route, Interests will be forwarded among them and might cause persistent loops.
-//    Thus, it is not recommended to connect to multiple uplinks with overlapping routes.
-//  - There is no pending Interest table. Instead, downstream 'face' ID is inserted as part of the PIT token.
-//    Since PIT token cannot exceed 32 octets, this takes away some space.
-//    Thus, consumers are allowed to use a PIT token up to 28 octets; Interests with longer PIT tokens may be dropped.
+// The forwarder has a pending Interest table (PIT) that aggregates Interests of the same name from multiple downstreams,
+// and returns Data and Nacks to downstreams according to PIT entries.
+// Looping Interests are detected by Nonce, in either the PIT or a dead Nonce list, and are Nacked with reason Duplicate.
+// A small content store (CS) can be enabled with ForwarderConfig.CsCapacity.
+// Each Interest is forwarded among the faces with the longest matching route, according to the forwarding strategy
+// chosen for the Interest name; see SetStrategy.
+//
+// HopLimit is not decremented.
+// Interests sent by the forwarder carry an 8-octet PIT token; consumers may use a PIT token up to 32 octets.
 type Forwarder interface {
 	// AddFace adds a Face to the forwarder.
 	// face.Rx() and face.Tx() should not be used after this operation.
 	AddFace(face Face) (FwFace, error)
 
+	// SetStrategy selects the forwarding strategy for a name prefix.
+	// An Interest uses the strategy of the longest matching prefix.
+	// Default is StrategyMulticast; StrategyBestRoute must be selected explicitly.
+	SetStrategy(prefix ndn.Name, strategy Strategy)
+
+	// UnsetStrategy removes the strategy choice of a name prefix.
+	UnsetStrategy(prefix ndn.Name)
+
 	// AddReadvertiseDestination adds a destination for prefix announcement.
 	//
 	// Limitations of current implementation:
@@ -40,12 +52,43 @@ type Forwarder interface {
 	RemoveReadvertiseDestination(dest ReadvertiseDestination)
 }
 
-// NewForwarder creates a Forwarder.
+// ForwarderConfig contains options for NewForwarderWithConfig.
+type ForwarderConfig struct {
+	// CsCapacity is the maximum number of Data packets in the content store.
+	// Default is zero, which disables the content store.
+	CsCapacity int
+
+	// DeadNonceLifetime is how long a Nonce is remembered after its PIT entry is erased.
+	// Default is 6 seconds.
+	DeadNonceLifetime time.Duration
+}
+
+func (cfg *ForwarderConfig) applyDefaults() {
+	if cfg.DeadNonceLifetime <= 0 {
+		cfg.DeadNonceLifetime = 6 * time.Second
+	}
+}
+
+// cleanupInterval is the interval of erasing expired PIT entries and dead Nonces.
+const cleanupInterval = 100 * time.Millisecond
+
+// NewForwarder creates a Forwarder with default configuration.
 func NewForwarder() Forwarder {
+	return NewForwarderWithConfig(ForwarderConfig{})
+}
+
+// NewForwarderWithConfig creates a Forwarder.
+func NewForwarderWithConfig(cfg ForwarderConfig) Forwarder {
+	cfg.applyDefaults()
 	fw := &forwarder{
+		cfg:           cfg,
 		faces:         map[uint32]*fwFace{},
 		announcements: setmultimap.New(),
 		readvertise:   map[ReadvertiseDestination]bool{},
+		strategy:      strategyChoice{},
+		pit:           newPit(),
+		dnl:           newDeadNonceList(),
+		cs:            newCs(cfg.CsCapacity),
 		cmd:           make(chan func()),
 		rx:            make(chan fwRxPkt),
 	}
@@ -59,9 +102,14 @@ type fwRxPkt struct {
 }
 
 type forwarder struct {
+	cfg           ForwarderConfig
 	faces         map[uint32]*fwFace
 	announcements multimap.MultiMap // multimap[string(prefixV)]*fwFace
 	readvertise   map[ReadvertiseDestination]bool
+	strategy      strategyChoice
+	pit           *pit
+	dnl           *deadNonceList
+	cs            *cs
 	cmd           chan func()
 	rx            chan fwRxPkt
 }
@@ -95,6 +143,20 @@ func (fw *forwarder) AddFace(face Face) (ff FwFace, e error) {
 	return f, nil
 }
 
+func (fw *forwarder) SetStrategy(prefix ndn.Name, strategy Strategy) {
+	prefixV, _ := prefix.MarshalBinary()
+	fw.execute(func() {
+		fw.strategy[string(prefixV)] = strategy
+	})
+}
+
+func (fw *forwarder) UnsetStrategy(prefix ndn.Name) {
+	prefixV, _ := prefix.MarshalBinary()
+	fw.execute(func() {
+		delete(fw.strategy, string(prefixV))
+	})
+}
+
 func (fw *forwarder) AddReadvertiseDestination(dest ReadvertiseDestination) {
 	fw.execute(func() {
 		if fw.readvertise[dest] {
@@ -123,6 +185,8 @@ func (fw *forwarder) execute(fn func()) {
 }
 
 func (fw *forwarder) loop() {
+	cleanup := time.NewTicker(cleanupInterval)
+	defer cleanup.Stop()
 	for {
 		select {
 		case fn := <-fw.cmd:
@@ -130,23 +194,30 @@ func (fw *forwarder) loop() {
 		case pkt := <-fw.rx:
 			switch {
 			case pkt.Interest != nil:
-				fw.forwardInterest(pkt)
-			case pkt.Data != nil, pkt.Nack != nil:
-				fw.forwardDataNack(pkt)
+				fw.processInterest(pkt)
+			case pkt.Data != nil:
+				fw.processData(pkt)
+			case pkt.Nack != nil:
+				fw.processNack(pkt)
 			}
+		case now := <-cleanup.C:
+			fw.pit.EraseExpired(now, func(entry *pitEntry) {
+				fw.dnl.Add(entry, now.Add(fw.cfg.DeadNonceLifetime))
+			})
+			fw.dnl.EraseExpired(now)
 		}
 	}
 }
 
-func (fw *forwarder) forwardInterest(pkt fwRxPkt) {
+// nexthops returns faces with the longest matching route, except the incoming face.
+func (fw *forwarder) nexthops(name ndn.Name, rxFace *fwFace) (nexthops []*fwFace) {
 	lpmLen := 0
-	var nexthops []*fwFace
 	for _, f := range fw.faces {
-		if pkt.rxFace == f {
+		if f == rxFace {
 			continue
 		}
 
-		matchLen := f.lpmRoute(pkt.Interest.Name)
+		matchLen := f.lpmRoute(name)
 		switch {
 		case matchLen > lpmLen:
 			lpmLen = matchLen
@@ -156,17 +227,151 @@ func (fw *forwarder) forwardInterest(pkt fwRxPkt) {
 			nexthops = append(nexthops, f)
 		}
 	}
+	sortFwFaces(nexthops)
+	return nexthops
+}
+
+func (fw *forwarder) processInterest(pkt fwRxPkt) {
+	interest := *pkt.Interest
+	if len(interest.Name) == 0 {
+		return
+	}
+	if interest.Nonce.IsZero() {
+		interest.Nonce = ndn.NewNonce()
+	}
+	now := time.Now()
+	expiry := now.Add(interest.ApplyDefaultLifetime())
+
+	entry := fw.pit.Find(interest)
+	switch {
+	case entry == nil && fw.dnl.Has(pitKey(interest.Name), interest.Nonce, now),
+		entry != nil && entry.hasDuplicateNonce(pkt.rxFace, interest.Nonce):
+		fw.sendNack(pkt.rxFace, pkt.Lp.PitToken, interest, an.NackDuplicate)
+		return
+	case entry == nil:
+		if data := fw.cs.Find(interest, now); data != nil {
+			fw.sendData(pkt.rxFace, pkt.Lp, data)
+			return
+		}
+		entry = fw.pit.Insert(interest, fw.strategy.Find(interest.Name))
+	}
+
+	isPending := entry.isPending(now)
+	isNewDownstream := fw.pit.InsertInRecord(entry, pkt.rxFace, pkt.Lp.PitToken, interest.Nonce, expiry)
+	if isPending && isNewDownstream { // aggregated
+		return
+	}
+
+	nexthops := fw.nexthops(interest.Name, pkt.rxFace)
+	if len(nexthops) == 0 {
+		if !isPending {
+			fw.rejectPending(entry, an.NackNoRoute)
+		}
+		return
+	}
 
-	for _, f := range nexthops {
-		f.tx <- pkt
+	out := &ndn.Packet{
+		Lp:       ndn.LpL3{PitToken: fw.pit.Token(entry), CongMark: pkt.Lp.CongMark},
+		Interest: &interest,
+	}
+	for _, nh := range entry.strategy.selectNexthops(entry, nexthops, !isNewDownstream, now) {
+		entry.insertOutRecord(nh, interest.Nonce, now, expiry)
+		nh.tx <- out
 	}
 }
 
-func (fw *forwarder) forwardDataNack(pkt fwRxPkt) {
-	var id uint32
-	id, pkt.Lp.PitToken = tokenStripID(pkt.Lp.PitToken)
-	if f := fw.faces[id]; f != nil {
-		f.tx <- pkt.Packet
+func (fw *forwarder) processData(pkt fwRxPkt) {
+	now := time.Now()
+	entries := fw.pit.MatchData(*pkt.Data, now)
+	if len(entries) == 0 { // unsolicited
+		return
+	}
+	fw.cs.Insert(pkt.Data, now)
+
+	// a face with in-records in several matched PIT entries receives the Data once
+	seen := map[*fwFace]bool{pkt.rxFace: true}
+	for _, entry := range entries {
+		for _, rec := range entry.inRecords {
+			if !seen[rec.face] && now.Before(rec.expiry) {
+				seen[rec.face] = true
+				fw.sendData(rec.face, ndn.LpL3{PitToken: rec.token, CongMark: pkt.Lp.CongMark}, pkt.Data)
+			}
+		}
+		fw.erase(entry, now)
+	}
+}
+
+func (fw *forwarder) processNack(pkt fwRxPkt) {
+	entry := fw.pit.FindToken(pkt.Lp.PitToken)
+	if entry == nil || !entry.matchInterest(pkt.Nack.Interest) {
+		if entry = fw.pit.Find(pkt.Nack.Interest); entry == nil {
+			return
+		}
+	}
+
+	rec := entry.findOutRecord(pkt.rxFace)
+	if rec == nil || rec.nonce != pkt.Nack.Interest.Nonce {
+		return
+	}
+	rec.nacked = pkt.Nack.Reason
+	if rec.nacked == an.NackNone {
+		rec.nacked = an.NackUnspecified
+	}
+
+	if now := time.Now(); !entry.isPending(now) {
+		fw.rejectPending(entry, fw.leastSevereNack(entry))
+	}
+}
+
+// leastSevereNack returns the least severe Nack reason among Nacked out-records.
+func (fw *forwarder) leastSevereNack(entry *pitEntry) (reason uint8) {
+	reason = an.NackUnspecified
+	for _, rec := range entry.outRecords {
+		if rec.nacked != an.NackNone && rec.nacked < reason {
+			reason = rec.nacked
+		}
+	}
+	return reason
+}
+
+// rejectPending sends Nacks to all downstreams of a PIT entry and erases the entry.
+func (fw *forwarder) rejectPending(entry *pitEntry, reason uint8) {
+	now := time.Now()
+	for _, rec := range entry.inRecords {
+		if now.Before(rec.expiry) {
+			interest := entry.interest
+			interest.Nonce = rec.nonce
+			fw.sendNack(rec.face, rec.token, interest, reason)
+		}
+	}
+	fw.erase(entry, now)
+}
+
+func (fw *forwarder) erase(entry *pitEntry, now time.Time) {
+	fw.pit.Erase(entry)
+	fw.dnl.Add(entry, now.Add(fw.cfg.DeadNonceLifetime))
+}
+
+func (fw *forwarder) sendData(f *fwFace, lph ndn.LpL3, data *ndn.Data) {
+	if !f.isAlive() {
+		return
+	}
+	f.tx <- &ndn.Packet{
+		Lp:   lph,
+		Data: data,
+	}
+}
+
+func (fw *forwarder) sendNack(f *fwFace, token []byte, interest ndn.Interest, reason uint8) {
+	if !f.isAlive() {
+		return
+	}
+	f.tx <- &ndn.Packet{
+		Lp: ndn.LpL3{PitToken: token},
+		Nack: &ndn.Nack{
+			Reason:   reason,
+			Interest: interest,
+		},
 	}
 }
 
@@ -0,0 +1,273 @@
+package l3_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+)
+
+type testFace struct {
+	fw l3.FwFace
+	rx chan *ndn.Packet
+	tx chan ndn.L3Packet
+}
+
+func (f *testFace) Transport() l3.Transport {
+	return nil
+}
+
+func (f *testFace) Rx() <-chan *ndn.Packet {
+	return f.rx
+}
+
+func (f *testFace) Tx() chan<- ndn.L3Packet {
+	return f.tx
+}
+
+func (f *testFace) State() l3.TransportState {
+	return l3.TransportUp
+}
+
+func (f *testFace) OnStateChange(cb func(st l3.TransportState)) (cancel func()) {
+	return func() {}
+}
+
+func (f *testFace) Send(l3packet ndn.L3Packet, token byte) {
+	pkt := *l3packet.ToPacket()
+	pkt.Lp.PitToken = []byte{token}
+	f.rx <- &pkt
+}
+
+// Recv returns the next packet received within 100ms, or nil.
+func (f *testFace) Recv() *ndn.Packet {
+	select {
+	case l3packet := <-f.tx:
+		return l3packet.ToPacket()
+	case <-time.After(100 * time.Millisecond):
+		return nil
+	}
+}
+
+func newTestFace(t *testing.T, fw l3.Forwarder, routes ...string) *testFace {
+	_, require := makeAR(t)
+	f := &testFace{
+		rx: make(chan *ndn.Packet),
+		tx: make(chan ndn.L3Packet, 16),
+	}
+	var e error
+	f.fw, e = fw.AddFace(f)
+	require.NoError(e)
+	for _, route := range routes {
+		f.fw.AddRoute(ndn.ParseName(route))
+	}
+	t.Cleanup(func() { f.fw.Close() })
+	return f
+}
+
+func TestAggregation(t *testing.T) {
+	assert, require := makeAR(t)
+	fw := l3.NewForwarder()
+	p := newTestFace(t, fw, "/A")
+	c1, c2 := newTestFace(t, fw), newTestFace(t, fw)
+
+	c1.Send(ndn.MakeInterest("/A/1", ndn.NonceFromUint(1)), 0xC1)
+	pkt := p.Recv()
+	require.NotNil(pkt)
+	require.NotNil(pkt.Interest)
+	nameEqual(assert, "/A/1", pkt.Interest)
+	assert.Len(pkt.Lp.PitToken, 8)
+
+	c2.Send(ndn.MakeInterest("/A/1", ndn.NonceFromUint(2)), 0xC2)
+	assert.Nil(p.Recv())
+
+	p.rx <- &ndn.Packet{Lp: pkt.Lp, Data: &ndn.Data{Name: ndn.ParseName("/A/1")}}
+	for i, c := range []*testFace{c1, c2} {
+		pkt := c.Recv()
+		if assert.NotNil(pkt, i) && assert.NotNil(pkt.Data, i) {
+			nameEqual(assert, "/A/1", pkt.Data)
+			assert.Equal([]byte{byte(0xC1 + i)}, pkt.Lp.PitToken)
+		}
+	}
+
+	// PIT entry is erased after Data
+	c2.Send(ndn.MakeInterest("/A/1", ndn.NonceFromUint(3)), 0xC2)
+	assert.NotNil(p.Recv())
+}
+
+func TestDataDedup(t *testing.T) {
+	assert, require := makeAR(t)
+	fw := l3.NewForwarder()
+	p := newTestFace(t, fw, "/A")
+	c := newTestFace(t, fw)
+
+	c.Send(ndn.MakeInterest("/A", ndn.CanBePrefixFlag, ndn.NonceFromUint(1)), 0xC1)
+	require.NotNil(p.Recv())
+	c.Send(ndn.MakeInterest("/A/1", ndn.NonceFromUint(2)), 0xC2)
+	pkt := p.Recv()
+	require.NotNil(pkt)
+
+	// Data matches both PIT entries, but the face should receive it only once
+	p.rx <- &ndn.Packet{Lp: pkt.Lp, Data: &ndn.Data{Name: ndn.ParseName("/A/1")}}
+	if pkt := c.Recv(); assert.NotNil(pkt) && assert.NotNil(pkt.Data) {
+		nameEqual(assert, "/A/1", pkt.Data)
+	}
+	assert.Nil(c.Recv())
+}
+
+func TestExpiry(t *testing.T) {
+	assert, _ := makeAR(t)
+	fw := l3.NewForwarder()
+	p := newTestFace(t, fw, "/A")
+	c1, c2 := newTestFace(t, fw), newTestFace(t, fw)
+
+	c1.Send(ndn.MakeInterest("/A/1", 50*time.Millisecond), 0xC1)
+	assert.NotNil(p.Recv())
+	time.Sleep(300 * time.Millisecond)
+
+	c2.Send(ndn.MakeInterest("/A/1"), 0xC2)
+	pkt := p.Recv()
+	if assert.NotNil(pkt) {
+		p.rx <- &ndn.Packet{Lp: pkt.Lp, Data: &ndn.Data{Name: ndn.ParseName("/A/1")}}
+	}
+	assert.NotNil(c2.Recv())
+	assert.Nil(c1.Recv())
+}
+
+func TestDuplicateNonce(t *testing.T) {
+	assert, require := makeAR(t)
+	fw := l3.NewForwarder()
+	p := newTestFace(t, fw, "/A")
+	c1, c2 := newTestFace(t, fw), newTestFace(t, fw)
+
+	checkNackDuplicate := func(pkt *ndn.Packet, token byte) {
+		if assert.NotNil(pkt) && assert.NotNil(pkt.Nack) {
+			assert.EqualValues(an.NackDuplicate, pkt.Nack.Reason)
+			assert.Equal([]byte{token}, pkt.Lp.PitToken)
+		}
+	}
+
+	c1.Send(ndn.MakeInterest("/A/1", ndn.NonceFromUint(1)), 0xC1)
+	pkt := p.Recv()
+	require.NotNil(pkt)
+
+	// same Nonce from another downstream is a loop
+	c2.Send(ndn.MakeInterest("/A/1", ndn.NonceFromUint(1)), 0xC2)
+	checkNackDuplicate(c2.Recv(), 0xC2)
+
+	// same Nonce from the same downstream is a retransmission
+	c1.Send(ndn.MakeInterest("/A/1", ndn.NonceFromUint(1)), 0xC1)
+	assert.NotNil(p.Recv())
+
+	// Nonce is remembered after the PIT entry is satisfied
+	p.rx <- &ndn.Packet{Lp: pkt.Lp, Data: &ndn.Data{Name: ndn.ParseName("/A/1")}}
+	assert.NotNil(c1.Recv())
+	c2.Send(ndn.MakeInterest("/A/1", ndn.NonceFromUint(1)), 0xC2)
+	checkNackDuplicate(c2.Recv(), 0xC2)
+	assert.Nil(p.Recv())
+}
+
+func TestNack(t *testing.T) {
+	assert, require := makeAR(t)
+	fw := l3.NewForwarder()
+	fw.SetStrategy(ndn.ParseName("/A"), l3.StrategyMulticast)
+	p1, p2 := newTestFace(t, fw, "/A"), newTestFace(t, fw, "/A")
+	c := newTestFace(t, fw)
+
+	c.Send(ndn.MakeInterest("/Z/1"), 0xC1)
+	if pkt := c.Recv(); assert.NotNil(pkt) && assert.NotNil(pkt.Nack) {
+		assert.EqualValues(an.NackNoRoute, pkt.Nack.Reason)
+	}
+
+	c.Send(ndn.MakeInterest("/A/1"), 0xC1)
+	pkt1, pkt2 := p1.Recv(), p2.Recv()
+	require.NotNil(pkt1)
+	require.NotNil(pkt2)
+
+	p1.rx <- &ndn.Packet{Lp: pkt1.Lp, Nack: &ndn.Nack{Reason: an.NackNoRoute, Interest: *pkt1.Interest}}
+	assert.Nil(c.Recv())
+	p2.rx <- &ndn.Packet{Lp: pkt2.Lp, Nack: &ndn.Nack{Reason: an.NackCongestion, Interest: *pkt2.Interest}}
+	if pkt := c.Recv(); assert.NotNil(pkt) && assert.NotNil(pkt.Nack) {
+		assert.EqualValues(an.NackCongestion, pkt.Nack.Reason)
+		assert.Equal([]byte{0xC1}, pkt.Lp.PitToken)
+		nameEqual(assert, "/A/1", pkt.Nack)
+	}
+}
+
+func TestStrategy(t *testing.T) {
+	assert, _ := makeAR(t)
+	fw := l3.NewForwarder()
+	p1, p2 := newTestFace(t, fw, "/A", "/M"), newTestFace(t, fw, "/A", "/M")
+	p3 := newTestFace(t, fw, "/A/B")
+	c := newTestFace(t, fw)
+	fw.SetStrategy(ndn.ParseName("/A"), l3.StrategyBestRoute)
+
+	countReceived := func(faces ...*testFace) (n int) {
+		for _, f := range faces {
+			if f.Recv() != nil {
+				n++
+			}
+		}
+		return n
+	}
+
+	// best-route sends to one nexthop, and tries another nexthop upon retransmission
+	c.Send(ndn.MakeInterest("/A/1"), 0xC1)
+	assert.Equal(1, countReceived(p1, p2, p3))
+	c.Send(ndn.MakeInterest("/A/1"), 0xC1)
+	assert.Equal(1, countReceived(p1, p2, p3))
+	c.Send(ndn.MakeInterest("/A/1"), 0xC1)
+	assert.Equal(1, countReceived(p1, p2, p3))
+
+	// longest prefix match
+	c.Send(ndn.MakeInterest("/A/B/1"), 0xC1)
+	assert.Equal(0, countReceived(p1, p2))
+	assert.Equal(1, countReceived(p3))
+
+	// multicast is the default, which sends to all nexthops
+	c.Send(ndn.MakeInterest("/M/1"), 0xC1)
+	assert.Equal(2, countReceived(p1, p2, p3))
+
+	fw.SetStrategy(ndn.ParseName("/M"), l3.StrategyBestRoute)
+	c.Send(ndn.MakeInterest("/M/2"), 0xC1)
+	assert.Equal(1, countReceived(p1, p2, p3))
+
+	fw.UnsetStrategy(ndn.ParseName("/M"))
+	c.Send(ndn.MakeInterest("/M/3"), 0xC1)
+	assert.Equal(2, countReceived(p1, p2, p3))
+}
+
+func TestCs(t *testing.T) {
+	assert, require := makeAR(t)
+	fw := l3.NewForwarderWithConfig(l3.ForwarderConfig{CsCapacity: 16})
+	p := newTestFace(t, fw, "/A")
+	c := newTestFace(t, fw)
+
+	c.Send(ndn.MakeInterest("/A/1"), 0xC1)
+	pkt := p.Recv()
+	require.NotNil(pkt)
+	p.rx <- &ndn.Packet{Lp: pkt.Lp, Data: &ndn.Data{Name: ndn.ParseName("/A/1"), Freshness: 100 * time.Millisecond}}
+	require.NotNil(c.Recv())
+
+	c.Send(ndn.MakeInterest("/A/1", ndn.MustBeFreshFlag), 0xC2)
+	if pkt := c.Recv(); assert.NotNil(pkt) && assert.NotNil(pkt.Data) {
+		nameEqual(assert, "/A/1", pkt.Data)
+		assert.Equal([]byte{0xC2}, pkt.Lp.PitToken)
+	}
+	c.Send(ndn.MakeInterest("/A", ndn.CanBePrefixFlag), 0xC3)
+	if pkt := c.Recv(); assert.NotNil(pkt) && assert.NotNil(pkt.Data) {
+		nameEqual(assert, "/A/1", pkt.Data)
+	}
+	assert.Nil(p.Recv())
+	c.Send(ndn.MakeInterest("/A/10", ndn.CanBePrefixFlag), 0xC3)
+	assert.Nil(c.Recv())
+	assert.NotNil(p.Recv())
+
+	time.Sleep(200 * time.Millisecond)
+	c.Send(ndn.MakeInterest("/A/1", ndn.MustBeFreshFlag), 0xC4)
+	assert.NotNil(p.Recv())
+	c.Send(ndn.MakeInterest("/A/1"), 0xC5)
+	assert.NotNil(c.Recv())
+}
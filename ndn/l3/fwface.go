@@ -1,10 +1,9 @@
 package l3
 
 import (
-	"encoding/binary"
 	"errors"
 	"io"
-	"unsafe"
+	"sort"
 
 	"github.com/usnistgov/ndn-dpdk/ndn"
 )
@@ -31,23 +30,6 @@ type FwFace interface {
 	RemoveAnnouncement(name ndn.Name)
 }
 
-const tokenSize = int(unsafe.Sizeof(uint32(0)))
-
-func tokenInsertID(oldToken []byte, id uint32) (token []byte) {
-	token = make([]byte, tokenSize, tokenSize+len(oldToken))
-	binary.LittleEndian.PutUint32(token, id)
-	return append(token, oldToken...)
-}
-
-func tokenStripID(token []byte) (id uint32, newToken []byte) {
-	if len(token) < tokenSize {
-		return 0, nil
-	}
-	id = binary.LittleEndian.Uint32(token)
-	newToken = token[tokenSize:]
-	return
-}
-
 type fwFace struct {
 	Face
 	fw            *forwarder
@@ -59,11 +41,7 @@ type fwFace struct {
 
 func (f *fwFace) rxLoop() {
 	for pkt := range f.Rx() {
-		switch {
-		case pkt.Interest != nil:
-			pkt.Lp.PitToken = tokenInsertID(pkt.Lp.PitToken, f.id)
-		case pkt.Data != nil, pkt.Nack != nil:
-		default:
+		if pkt.Interest == nil && pkt.Data == nil && pkt.Nack == nil {
 			continue
 		}
 		f.fw.rx <- fwRxPkt{
@@ -89,13 +67,24 @@ func (f *fwFace) RemoveRoute(name ndn.Name) {
 	})
 }
 
-func (f *fwFace) lpmRoute(query ndn.Name) int {
+// isAlive determines whether the face is still attached to the forwarder.
+func (f *fwFace) isAlive() bool {
+	return f.fw.faces[f.id] == f
+}
+
+func (f *fwFace) lpmRoute(query ndn.Name) (matchLen int) {
+	matchLen = -1
 	for _, name := range f.routes {
-		if name.IsPrefixOf(query) {
-			return len(name)
+		if len(name) > matchLen && name.IsPrefixOf(query) {
+			matchLen = len(name)
 		}
 	}
-	return -1
+	return matchLen
+}
+
+// sortFwFaces sorts faces by ID, so that strategies make stable choices.
+func sortFwFaces(faces []*fwFace) {
+	sort.Slice(faces, func(i, j int) bool { return faces[i].id < faces[j].id })
 }
 
 func (f *fwFace) AddAnnouncement(name ndn.Name) {
@@ -0,0 +1,342 @@
+package l3
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// pitTokenSize is the length of PIT token on Interests sent by the forwarder.
+const pitTokenSize = 8
+
+type pitInRecord struct {
+	face   *fwFace
+	token  []byte
+	nonce  ndn.Nonce
+	expiry time.Time
+}
+
+type pitOutRecord struct {
+	face   *fwFace
+	nonce  ndn.Nonce
+	last   time.Time
+	expiry time.Time
+	nacked uint8 // NackReason, or NackNone if not Nacked
+}
+
+// isPending determines whether Data or Nack is still expected from the upstream.
+func (rec *pitOutRecord) isPending(now time.Time) bool {
+	return rec.nacked == an.NackNone && now.Before(rec.expiry) && rec.face.isAlive()
+}
+
+// pitEntry represents a pending Interest.
+type pitEntry struct {
+	key        string
+	token      uint64
+	interest   ndn.Interest
+	strategy   Strategy
+	inRecords  []*pitInRecord
+	outRecords []*pitOutRecord
+	expiry     time.Time
+	scheduled  time.Time // expiry in the latest pitExpiryItem
+}
+
+func (entry *pitEntry) matchInterest(interest ndn.Interest) bool {
+	return entry.interest.CanBePrefix == interest.CanBePrefix && entry.interest.MustBeFresh == interest.MustBeFresh &&
+		entry.interest.Name.Equal(interest.Name)
+}
+
+func (entry *pitEntry) findInRecord(face *fwFace) *pitInRecord {
+	for _, rec := range entry.inRecords {
+		if rec.face == face {
+			return rec
+		}
+	}
+	return nil
+}
+
+func (entry *pitEntry) findOutRecord(face *fwFace) *pitOutRecord {
+	for _, rec := range entry.outRecords {
+		if rec.face == face {
+			return rec
+		}
+	}
+	return nil
+}
+
+// hasDuplicateNonce determines whether an Interest with the nonce from the face indicates a loop.
+// The same nonce from the same downstream is a retransmission, not a loop.
+func (entry *pitEntry) hasDuplicateNonce(face *fwFace, nonce ndn.Nonce) bool {
+	if rec := entry.findInRecord(face); rec != nil && rec.nonce == nonce {
+		return false
+	}
+	for _, rec := range entry.inRecords {
+		if rec.nonce == nonce && rec.face != face {
+			return true
+		}
+	}
+	for _, rec := range entry.outRecords {
+		if rec.nonce == nonce {
+			return true
+		}
+	}
+	return false
+}
+
+// isPending determines whether any upstream is still expected to respond.
+func (entry *pitEntry) isPending(now time.Time) bool {
+	for _, rec := range entry.outRecords {
+		if rec.isPending(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// insertInRecord inserts or refreshes an in-record.
+// It returns true if the face was not a downstream of this entry.
+func (entry *pitEntry) insertInRecord(face *fwFace, token []byte, nonce ndn.Nonce, expiry time.Time) (isNew bool) {
+	rec := entry.findInRecord(face)
+	if isNew = rec == nil; isNew {
+		rec = &pitInRecord{face: face}
+		entry.inRecords = append(entry.inRecords, rec)
+	}
+	rec.token, rec.nonce, rec.expiry = append([]byte{}, token...), nonce, expiry
+	if expiry.After(entry.expiry) {
+		entry.expiry = expiry
+	}
+	return isNew
+}
+
+func (entry *pitEntry) insertOutRecord(face *fwFace, nonce ndn.Nonce, now, expiry time.Time) {
+	rec := entry.findOutRecord(face)
+	if rec == nil {
+		rec = &pitOutRecord{face: face}
+		entry.outRecords = append(entry.outRecords, rec)
+	}
+	rec.nonce, rec.last, rec.expiry, rec.nacked = nonce, now, expiry, an.NackNone
+}
+
+// pitExpiryItem is an item in pitExpiryQueue.
+type pitExpiryItem struct {
+	expiry time.Time
+	entry  *pitEntry
+}
+
+// pitExpiryQueue is a min-heap of PIT entry expiry times.
+// An entry may have multiple items as its expiry is extended; only the latest item is effective.
+type pitExpiryQueue []pitExpiryItem
+
+func (q pitExpiryQueue) Len() int {
+	return len(q)
+}
+
+func (q pitExpiryQueue) Less(i, j int) bool {
+	return q[i].expiry.Before(q[j].expiry)
+}
+
+func (q pitExpiryQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *pitExpiryQueue) Push(x interface{}) {
+	*q = append(*q, x.(pitExpiryItem))
+}
+
+func (q *pitExpiryQueue) Pop() interface{} {
+	old := *q
+	n := len(old) - 1
+	item := old[n]
+	old[n] = pitExpiryItem{}
+	*q = old[:n]
+	return item
+}
+
+// pit is the pending Interest table.
+type pit struct {
+	byName    map[string][]*pitEntry // name TLV-VALUE without implicit digest => entries
+	byToken   map[uint64]*pitEntry
+	expiry    pitExpiryQueue
+	lastToken uint64
+}
+
+func newPit() *pit {
+	return &pit{
+		byName:  map[string][]*pitEntry{},
+		byToken: map[uint64]*pitEntry{},
+	}
+}
+
+// pitKey returns the name index key of an Interest name.
+func pitKey(name ndn.Name) string {
+	if len(name) > 0 && name[len(name)-1].Type == an.TtImplicitSha256DigestComponent {
+		name = name.GetPrefix(-1)
+	}
+	value, _ := name.MarshalBinary()
+	return string(value)
+}
+
+// Find returns the PIT entry that exactly matches an Interest.
+func (p *pit) Find(interest ndn.Interest) *pitEntry {
+	for _, entry := range p.byName[pitKey(interest.Name)] {
+		if entry.matchInterest(interest) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// FindToken returns the PIT entry associated with a PIT token.
+func (p *pit) FindToken(token []byte) *pitEntry {
+	if len(token) != pitTokenSize {
+		return nil
+	}
+	return p.byToken[binary.LittleEndian.Uint64(token)]
+}
+
+// Insert creates a PIT entry for an Interest.
+// Caller should ensure no existing entry matches the Interest.
+func (p *pit) Insert(interest ndn.Interest, strategy Strategy) *pitEntry {
+	entry := &pitEntry{
+		key: pitKey(interest.Name),
+		interest: ndn.Interest{
+			Name:        interest.Name,
+			CanBePrefix: interest.CanBePrefix,
+			MustBeFresh: interest.MustBeFresh,
+		},
+		strategy: strategy,
+	}
+	for entry.token == 0 || p.byToken[entry.token] != nil {
+		p.lastToken++
+		entry.token = p.lastToken
+	}
+	p.byName[entry.key] = append(p.byName[entry.key], entry)
+	p.byToken[entry.token] = entry
+	return entry
+}
+
+// InsertInRecord inserts or refreshes an in-record, and schedules expiry of the entry.
+// It returns true if the face was not a downstream of this entry.
+func (p *pit) InsertInRecord(entry *pitEntry, face *fwFace, token []byte, nonce ndn.Nonce, expiry time.Time) (isNew bool) {
+	isNew = entry.insertInRecord(face, token, nonce, expiry)
+	if !entry.expiry.Equal(entry.scheduled) {
+		entry.scheduled = entry.expiry
+		heap.Push(&p.expiry, pitExpiryItem{expiry: entry.expiry, entry: entry})
+	}
+	return isNew
+}
+
+// Token returns the PIT token on Interests sent by the forwarder for an entry.
+func (p *pit) Token(entry *pitEntry) []byte {
+	token := make([]byte, pitTokenSize)
+	binary.LittleEndian.PutUint64(token, entry.token)
+	return token
+}
+
+// Erase deletes a PIT entry.
+func (p *pit) Erase(entry *pitEntry) {
+	entries := p.byName[entry.key]
+	for i, e := range entries {
+		if e == entry {
+			entries = append(entries[:i], entries[i+1:]...)
+			break
+		}
+	}
+	if len(entries) == 0 {
+		delete(p.byName, entry.key)
+	} else {
+		p.byName[entry.key] = entries
+	}
+	delete(p.byToken, entry.token)
+}
+
+// MatchData returns unexpired PIT entries that can be satisfied by a Data packet.
+func (p *pit) MatchData(data ndn.Data, now time.Time) (entries []*pitEntry) {
+	var key []byte
+	match := func() {
+		for _, entry := range p.byName[string(key)] {
+			if now.Before(entry.expiry) && data.CanSatisfy(entry.interest) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	match()
+	for _, comp := range data.Name {
+		wire, _ := tlv.EncodeFrom(comp)
+		key = append(key, wire...)
+		match()
+	}
+	return entries
+}
+
+// EraseExpired deletes expired PIT entries and passes each of them to a callback.
+func (p *pit) EraseExpired(now time.Time, cb func(entry *pitEntry)) {
+	for len(p.expiry) > 0 && !now.Before(p.expiry[0].expiry) {
+		item := heap.Pop(&p.expiry).(pitExpiryItem)
+		entry := item.entry
+		if p.byToken[entry.token] != entry || !entry.scheduled.Equal(item.expiry) {
+			continue // entry has been erased or its expiry has been extended
+		}
+		p.Erase(entry)
+		cb(entry)
+	}
+}
+
+// deadNonceItem is an item in deadNonceList expiry queue.
+type deadNonceItem struct {
+	key    string
+	expiry time.Time
+}
+
+// deadNonceList remembers Nonces of erased PIT entries, to detect looping Interests.
+type deadNonceList struct {
+	records map[string]time.Time // key+nonce => expiry
+	queue   []deadNonceItem      // in insertion order, which is also expiry order
+}
+
+func newDeadNonceList() *deadNonceList {
+	return &deadNonceList{records: map[string]time.Time{}}
+}
+
+func (dnl *deadNonceList) makeKey(key string, nonce ndn.Nonce) string {
+	return key + string(nonce[:])
+}
+
+// Has determines whether a Nonce was recently seen with a name.
+func (dnl *deadNonceList) Has(key string, nonce ndn.Nonce, now time.Time) bool {
+	expiry, ok := dnl.records[dnl.makeKey(key, nonce)]
+	return ok && now.Before(expiry)
+}
+
+// Add inserts Nonces of a PIT entry.
+// expiry should not be earlier than that of previously added Nonces; otherwise, the records are erased late.
+func (dnl *deadNonceList) Add(entry *pitEntry, expiry time.Time) {
+	for _, rec := range entry.inRecords {
+		dnl.add(dnl.makeKey(entry.key, rec.nonce), expiry)
+	}
+	for _, rec := range entry.outRecords {
+		dnl.add(dnl.makeKey(entry.key, rec.nonce), expiry)
+	}
+}
+
+func (dnl *deadNonceList) add(k string, expiry time.Time) {
+	dnl.records[k] = expiry
+	dnl.queue = append(dnl.queue, deadNonceItem{key: k, expiry: expiry})
+}
+
+// EraseExpired deletes expired records.
+func (dnl *deadNonceList) EraseExpired(now time.Time) {
+	n := 0
+	for ; n < len(dnl.queue) && !now.Before(dnl.queue[n].expiry); n++ {
+		item := dnl.queue[n]
+		if expiry, ok := dnl.records[item.key]; ok && expiry.Equal(item.expiry) {
+			delete(dnl.records, item.key)
+		}
+	}
+	dnl.queue = dnl.queue[n:]
+}
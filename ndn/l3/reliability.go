@@ -0,0 +1,207 @@
+package l3
+
+import (
+	"container/list"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/math"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+)
+
+// ReliabilityConfig defaults and limits.
+const (
+	DefaultReliabilityWindowSize = 256
+	DefaultReliabilityRTO        = 100 * time.Millisecond
+	DefaultReliabilityMaxRetx    = 3
+
+	// reliabilityIdleAckDelay is the delay before sending IDLE frames for pending Acks that cannot be piggybacked.
+	reliabilityIdleAckDelay = 5 * time.Millisecond
+
+	// reliabilityMaxPiggybackAcks is the maximum number of Acks piggybacked on each frame.
+	reliabilityMaxPiggybackAcks = 4
+
+	// reliabilitySizeofField is the encoded size of TxSequence or Ack field.
+	reliabilitySizeofField = 3 + 1 + 8
+
+	// reliabilityOverhead is the maximum size of reliability fields in a frame carrying a fragment.
+	reliabilityOverhead = (1 + reliabilityMaxPiggybackAcks) * reliabilitySizeofField
+)
+
+// ReliabilityConfig contains NDNLPv2 link reliability options.
+//
+// When enabled, each outgoing frame carries a TxSequence field, and is retransmitted if it is not acknowledged
+// within the retransmission timeout. Acks for incoming frames are piggybacked on outgoing frames, or sent in
+// IDLE frames if there is no outgoing traffic.
+type ReliabilityConfig struct {
+	Enabled bool
+
+	// WindowSize is the maximum number of unacknowledged frames.
+	// If the window is full, the oldest unacknowledged frame is considered lost.
+	// The default is DefaultReliabilityWindowSize.
+	WindowSize int
+
+	// RTO is the retransmission timeout.
+	// The default is DefaultReliabilityRTO.
+	RTO time.Duration
+
+	// MaxRetx is the maximum number of retransmissions of a frame.
+	// The default is DefaultReliabilityMaxRetx.
+	MaxRetx int
+}
+
+func (cfg *ReliabilityConfig) applyDefaults() {
+	if cfg.WindowSize <= 0 {
+		cfg.WindowSize = DefaultReliabilityWindowSize
+	}
+	if cfg.RTO <= 0 {
+		cfg.RTO = DefaultReliabilityRTO
+	}
+	if cfg.MaxRetx <= 0 {
+		cfg.MaxRetx = DefaultReliabilityMaxRetx
+	}
+}
+
+type lpRelEntry struct {
+	txSeq uint64
+	frame *ndn.Packet
+	sent  time.Time
+	nRetx int
+}
+
+// lpReliability implements NDNLPv2 link reliability on a face.
+type lpReliability struct {
+	cfg         ReliabilityConfig
+	maxIdleAcks int // zero means unlimited
+	ackNotify   chan struct{}
+
+	mutex       sync.Mutex
+	nextTxSeq   uint64
+	unacked     map[uint64]*list.Element // TxSequence => element in window
+	window      *list.List               // *lpRelEntry in TxSequence order, oldest at front
+	pendingAcks []uint64
+}
+
+func newLpReliability(cfg ReliabilityConfig, mtu int) *lpReliability {
+	cfg.applyDefaults()
+	rel := &lpReliability{
+		cfg:       cfg,
+		ackNotify: make(chan struct{}, 1),
+		nextTxSeq: rand.Uint64(),
+		unacked:   map[uint64]*list.Element{},
+		window:    list.New(),
+	}
+	if mtu > 0 {
+		rel.maxIdleAcks = math.MaxInt(1, (mtu-4)/reliabilitySizeofField)
+	}
+	return rel
+}
+
+// Rx processes reliability fields on an incoming frame.
+func (rel *lpReliability) Rx(frame *ndn.Packet) {
+	rel.mutex.Lock()
+	defer rel.mutex.Unlock()
+
+	for _, ack := range frame.Reliability.Acks {
+		if elem := rel.unacked[ack]; elem != nil {
+			rel.erase(elem)
+		}
+	}
+
+	if frame.Reliability.TxSequence != 0 {
+		rel.pendingAcks = append(rel.pendingAcks, frame.Reliability.TxSequence)
+		select {
+		case rel.ackNotify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Tx assigns TxSequence and piggybacks Acks on an outgoing frame.
+// It returns a copy of the frame.
+func (rel *lpReliability) Tx(frame *ndn.Packet, now time.Time) *ndn.Packet {
+	rel.mutex.Lock()
+	defer rel.mutex.Unlock()
+	return rel.tx(frame, 0, now)
+}
+
+func (rel *lpReliability) tx(frame *ndn.Packet, nRetx int, now time.Time) *ndn.Packet {
+	if rel.window.Len() >= rel.cfg.WindowSize {
+		rel.erase(rel.window.Front())
+	}
+
+	if rel.nextTxSeq == 0 {
+		rel.nextTxSeq++
+	}
+	txSeq := rel.nextTxSeq
+	rel.nextTxSeq++
+
+	txFrame := *frame
+	txFrame.Reliability = ndn.LpReliability{
+		TxSequence: txSeq,
+		Acks:       rel.takeAcks(reliabilityMaxPiggybackAcks),
+	}
+	rel.unacked[txSeq] = rel.window.PushBack(&lpRelEntry{
+		txSeq: txSeq,
+		frame: frame,
+		sent:  now,
+		nRetx: nRetx,
+	})
+	return &txFrame
+}
+
+// erase removes an unacknowledged frame from the window.
+// Caller must hold the mutex.
+func (rel *lpReliability) erase(elem *list.Element) *lpRelEntry {
+	entry := rel.window.Remove(elem).(*lpRelEntry)
+	delete(rel.unacked, entry.txSeq)
+	return entry
+}
+
+func (rel *lpReliability) takeAcks(max int) (acks []uint64) {
+	n := math.MinInt(max, len(rel.pendingAcks))
+	if n == 0 {
+		return nil
+	}
+	acks = append(acks, rel.pendingAcks[:n]...)
+	rel.pendingAcks = rel.pendingAcks[n:]
+	return acks
+}
+
+// Retransmit returns frames that should be retransmitted.
+// Frames that have reached MaxRetx are given up.
+func (rel *lpReliability) Retransmit(now time.Time) (frames []*ndn.Packet) {
+	rel.mutex.Lock()
+	defer rel.mutex.Unlock()
+
+	// frames are sent in TxSequence order, so that expired frames are at the front;
+	// a retransmitted frame is appended with the current timestamp, which ends the loop
+	for elem := rel.window.Front(); elem != nil; elem = rel.window.Front() {
+		if now.Sub(elem.Value.(*lpRelEntry).sent) < rel.cfg.RTO {
+			break
+		}
+		entry := rel.erase(elem)
+		if entry.nRetx < rel.cfg.MaxRetx {
+			frames = append(frames, rel.tx(entry.frame, entry.nRetx+1, now))
+		}
+	}
+	return frames
+}
+
+// Idle returns IDLE frames that carry pending Acks.
+func (rel *lpReliability) Idle() (frames []*ndn.Packet) {
+	rel.mutex.Lock()
+	defer rel.mutex.Unlock()
+
+	maxAcks := rel.maxIdleAcks
+	if maxAcks == 0 {
+		maxAcks = len(rel.pendingAcks)
+	}
+	for len(rel.pendingAcks) > 0 {
+		frames = append(frames, &ndn.Packet{
+			Reliability: ndn.LpReliability{Acks: rel.takeAcks(maxAcks)},
+		})
+	}
+	return frames
+}
@@ -0,0 +1,70 @@
+package l3
+
+import (
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+)
+
+// Strategy indicates a forwarding strategy.
+type Strategy int
+
+const (
+	// StrategyBestRoute forwards each Interest to one nexthop.
+	// A new Interest goes to the first nexthop that has not Nacked recently.
+	// A retransmitted Interest goes to an unused nexthop, or the least recently used nexthop if all have been tried.
+	StrategyBestRoute Strategy = iota
+
+	// StrategyMulticast forwards each Interest to all nexthops.
+	// This is the default strategy, consistent with forwarder behavior before strategies were introduced.
+	StrategyMulticast
+)
+
+// strategyChoice is the strategy choice table.
+type strategyChoice map[string]Strategy // name TLV-VALUE => strategy
+
+// Find returns the strategy of the longest matching prefix.
+func (sc strategyChoice) Find(name ndn.Name) Strategy {
+	for i := len(name); i > 0; i-- {
+		value, _ := name.GetPrefix(i).MarshalBinary()
+		if strategy, ok := sc[string(value)]; ok {
+			return strategy
+		}
+	}
+	if strategy, ok := sc[""]; ok {
+		return strategy
+	}
+	return StrategyMulticast
+}
+
+// selectNexthops chooses upstream faces for an Interest.
+// nexthops must be sorted by face ID and must not contain the incoming face.
+// isRetx indicates the Interest is a retransmission from an existing downstream.
+func (strategy Strategy) selectNexthops(entry *pitEntry, nexthops []*fwFace, isRetx bool, now time.Time) []*fwFace {
+	if strategy == StrategyMulticast || len(nexthops) <= 1 {
+		return nexthops
+	}
+
+	if !isRetx {
+		for _, nh := range nexthops {
+			if rec := entry.findOutRecord(nh); rec == nil || rec.nacked == an.NackNone {
+				return []*fwFace{nh}
+			}
+		}
+		return nexthops[:1]
+	}
+
+	var earliest *fwFace
+	var earliestTime time.Time
+	for _, nh := range nexthops {
+		rec := entry.findOutRecord(nh)
+		if rec == nil {
+			return []*fwFace{nh}
+		}
+		if earliest == nil || rec.last.Before(earliestTime) {
+			earliest, earliestTime = nh, rec.last
+		}
+	}
+	return []*fwFace{earliest}
+}
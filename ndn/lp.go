@@ -58,6 +58,37 @@ func (lph *LpL3) inheritFrom(src LpL3) {
 	lph.CongMark = src.CongMark
 }
 
+// LpReliability contains link reliability fields in NDNLPv2 header.
+type LpReliability struct {
+	// TxSequence is the transmit sequence number of this frame.
+	// Zero means the field is omitted.
+	TxSequence uint64
+
+	// Acks contains TxSequence numbers of received frames.
+	Acks []uint64
+}
+
+// Empty returns true if LpReliability has zero fields.
+func (rel LpReliability) Empty() bool {
+	return rel.TxSequence == 0 && len(rel.Acks) == 0
+}
+
+func (rel LpReliability) encode() (fields []tlv.Field) {
+	for _, ack := range rel.Acks {
+		fields = append(fields, tlv.TLVBytes(an.TtAck, encodeLpUint64(ack)))
+	}
+	if rel.TxSequence != 0 {
+		fields = append(fields, tlv.TLVBytes(an.TtTxSequence, encodeLpUint64(rel.TxSequence)))
+	}
+	return fields
+}
+
+func encodeLpUint64(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
+
 // LpFragment represents an NDNLPv2 fragmented frame.
 type LpFragment struct {
 	SeqNum    uint64
@@ -75,17 +106,22 @@ func (frag LpFragment) String() string {
 
 // Field implements tlv.Fielder interface.
 func (frag LpFragment) Field() tlv.Field {
+	return frag.field(nil)
+}
+
+func (frag LpFragment) field(rel []tlv.Field) tlv.Field {
 	if frag.FragIndex < 0 || frag.FragIndex >= frag.FragCount {
 		return tlv.FieldError(ErrFragment)
 	}
-	seqNum := make([]byte, 8)
-	binary.BigEndian.PutUint64(seqNum, frag.SeqNum)
-	return tlv.TLV(an.TtLpPacket,
-		tlv.TLVBytes(an.TtLpSeqNum, seqNum),
+	fields := []tlv.Field{
+		tlv.TLVBytes(an.TtLpSeqNum, encodeLpUint64(frag.SeqNum)),
 		tlv.TLVNNI(an.TtFragIndex, uint64(frag.FragIndex)),
 		tlv.TLVNNI(an.TtFragCount, uint64(frag.FragCount)),
 		tlv.Bytes(frag.header),
-		tlv.TLVBytes(an.TtLpPayload, frag.payload))
+	}
+	fields = append(fields, rel...)
+	fields = append(fields, tlv.TLVBytes(an.TtLpPayload, frag.payload))
+	return tlv.TLV(an.TtLpPacket, fields...)
 }
 
 // LpFragmenter splits Packet into fragments.
@@ -73,3 +73,44 @@ func TestLpReassembler(t *testing.T) {
 	}
 	assert.Len(packetSet, 0)
 }
+
+func TestLpReliability(t *testing.T) {
+	assert, require := makeAR(t)
+
+	interest := ndn.MakeInterest("/A")
+	pkt := interest.ToPacket()
+	pkt.Reliability.TxSequence = 0x0102030405060708
+	pkt.Reliability.Acks = []uint64{0xA1, 0xA2}
+	wire, e := tlv.EncodeFrom(pkt)
+	require.NoError(e)
+
+	var decoded ndn.Packet
+	require.NoError(tlv.Decode(wire, &decoded))
+	require.NotNil(decoded.Interest)
+	nameEqual(assert, "/A", decoded.Interest)
+	assert.EqualValues(0x0102030405060708, decoded.Reliability.TxSequence)
+	assert.Equal([]uint64{0xA1, 0xA2}, decoded.Reliability.Acks)
+
+	data := ndn.MakeData("/D", bytes.Repeat([]byte{0xCC}, 3000))
+	frags, e := ndn.NewLpFragmenter(1000).Fragment(data.ToPacket())
+	require.NoError(e)
+	require.Greater(len(frags), 1)
+	frags[1].Reliability.TxSequence = 0xB1
+	wire, e = tlv.EncodeFrom(frags[1])
+	require.NoError(e)
+	require.NoError(tlv.Decode(wire, &decoded))
+	require.NotNil(decoded.Fragment)
+	assert.Equal(1, decoded.Fragment.FragIndex)
+	assert.EqualValues(0xB1, decoded.Reliability.TxSequence)
+
+	idle := &ndn.Packet{Reliability: ndn.LpReliability{Acks: []uint64{0xC1}}}
+	wire, e = tlv.EncodeFrom(idle)
+	require.NoError(e)
+	require.NoError(tlv.Decode(wire, &decoded))
+	assert.Nil(decoded.Fragment)
+	assert.Nil(decoded.Interest)
+	assert.Nil(decoded.Data)
+	assert.Nil(decoded.Nack)
+	assert.Equal([]uint64{0xC1}, decoded.Reliability.Acks)
+	assert.Equal("IDLE", decoded.String())
+}
@@ -60,8 +60,8 @@ func (c *Client) OpenMemif(loc memiftransport.Locator) (mgmt.Face, error) {
 	f := &face{
 		client: c,
 		id:     id,
-		routes: map[string]string{},
 	}
+	f.rv = c.Readvertise(id, mgmt.ReadvertiseOptions{})
 	return f, f.openMemif(loc)
 }
 
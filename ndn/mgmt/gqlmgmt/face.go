@@ -6,6 +6,8 @@ import (
 
 	"github.com/usnistgov/ndn-dpdk/ndn"
 	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"github.com/usnistgov/ndn-dpdk/ndn/mgmt"
+	"go.uber.org/multierr"
 )
 
 // Error conditions.
@@ -17,7 +19,7 @@ type face struct {
 	client *Client
 	id     string
 	l3face l3.Face
-	routes map[string]string
+	rv     *mgmt.Readvertiser
 }
 
 func (f *face) ID() string {
@@ -33,54 +35,22 @@ func (f *face) Close() (e error) {
 		return ErrFaceClosed
 	}
 
-	_, e = f.client.Delete(context.TODO(), f.ID())
+	e = f.rv.Close()
+	_, eDelete := f.client.Delete(context.TODO(), f.ID())
 	f.client = nil
-	return e
+	return multierr.Append(e, eDelete)
 }
 
 func (f *face) Advertise(name ndn.Name) error {
 	if f.client == nil {
 		return ErrFaceClosed
 	}
-
-	nameV, _ := name.MarshalBinary()
-	if _, ok := f.routes[string(nameV)]; ok {
-		return nil
-	}
-
-	var fibEntryJ struct {
-		ID string `json:"id"`
-	}
-	e := f.client.Do(context.TODO(), `
-		mutation insertFibEntry($name: Name!, $nexthops: [ID!]!) {
-			insertFibEntry(name: $name, nexthops: $nexthops) {
-				id
-			}
-		}
-	`, map[string]interface{}{
-		"name":     name.String(),
-		"nexthops": []string{f.ID()},
-	}, "insertFibEntry", &fibEntryJ)
-	if e == nil {
-		f.routes[string(nameV)] = fibEntryJ.ID
-	}
-	return e
+	return f.rv.Advertise(name)
 }
 
-func (f *face) Withdraw(name ndn.Name) (e error) {
+func (f *face) Withdraw(name ndn.Name) error {
 	if f.client == nil {
 		return ErrFaceClosed
 	}
-
-	nameV, _ := name.MarshalBinary()
-	id, ok := f.routes[string(nameV)]
-	if !ok {
-		return nil
-	}
-
-	_, e = f.client.Delete(context.TODO(), id)
-	if e == nil {
-		delete(f.routes, string(nameV))
-	}
-	return e
+	return f.rv.Withdraw(name)
 }
@@ -0,0 +1,63 @@
+package gqlmgmt
+
+import (
+	"context"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/mgmt"
+)
+
+// fibReadvertiseOps advertises prefixes by inserting FIB entries on NDN-DPDK forwarder.
+type fibReadvertiseOps struct {
+	client  *Client
+	nexthop string
+	entries map[string]string // name TLV-VALUE => FIB entry ID
+}
+
+func (ops *fibReadvertiseOps) Advertise(ctx context.Context, name ndn.Name) error {
+	var fibEntryJ struct {
+		ID string `json:"id"`
+	}
+	e := ops.client.Do(ctx, `
+		mutation insertFibEntry($name: Name!, $nexthops: [ID!]!) {
+			insertFibEntry(name: $name, nexthops: $nexthops) {
+				id
+			}
+		}
+	`, map[string]interface{}{
+		"name":     name.String(),
+		"nexthops": []string{ops.nexthop},
+	}, "insertFibEntry", &fibEntryJ)
+	if e != nil {
+		return e
+	}
+
+	nameV, _ := name.MarshalBinary()
+	ops.entries[string(nameV)] = fibEntryJ.ID
+	return nil
+}
+
+func (ops *fibReadvertiseOps) Withdraw(ctx context.Context, name ndn.Name) error {
+	nameV, _ := name.MarshalBinary()
+	id, ok := ops.entries[string(nameV)]
+	if !ok {
+		return nil
+	}
+
+	if _, e := ops.client.Delete(ctx, id); e != nil {
+		return e
+	}
+	delete(ops.entries, string(nameV))
+	return nil
+}
+
+// Readvertise creates a readvertise destination that inserts FIB entries on NDN-DPDK forwarder.
+// Each advertised prefix becomes a FIB entry whose only nexthop is the specified face.
+// Caller must close the Readvertiser, which also erases the FIB entries.
+func (c *Client) Readvertise(nexthop string, opts mgmt.ReadvertiseOptions) *mgmt.Readvertiser {
+	return mgmt.NewReadvertiser(&fibReadvertiseOps{
+		client:  c,
+		nexthop: nexthop,
+		entries: map[string]string{},
+	}, opts)
+}
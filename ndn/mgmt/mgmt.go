@@ -25,10 +25,12 @@ type Face interface {
 	Face() l3.Face
 
 	// Close requests the face to be closed.
+	// Advertised prefixes are withdrawn before closing.
 	Close() error
 
 	// Advertise advertises a prefix announcement.
 	// The connected forwarder should start delivering Interests matching this prefix to this face.
+	// The advertisement is processed asynchronously and retried upon failure.
 	// Advertising the same name more than once is not an error but has no effect.
 	Advertise(name ndn.Name) error
 
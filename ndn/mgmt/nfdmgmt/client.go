@@ -4,33 +4,34 @@ package nfdmgmt
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/usnistgov/ndn-dpdk/ndn"
 	"github.com/usnistgov/ndn-dpdk/ndn/an"
 	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
 	"github.com/usnistgov/ndn-dpdk/ndn/mgmt"
+	"github.com/usnistgov/ndn-dpdk/ndn/segmented"
 	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
 )
 
-const (
-	ttControlParameters = 0x68
-	ttOrigin            = 0x6F
-	ttFlags             = 0x6C
-
-	ttControlResponse = 0x65
-	ttStatusCode      = 0x66
-
-	originClient = 65
-	flagCapture  = 2
+// Error conditions.
+var (
+	ErrDatasetName = errors.New("unexpected dataset Data name")
 )
 
+// notificationLifetime is the InterestLifetime of notification stream Interests.
+const notificationLifetime = 60 * time.Second
+
 // Client provides access to NFD Management API.
 type Client struct {
 	ConsumerOpts endpoint.ConsumerOptions
 	Prefix       string
 	Signer       ndn.Signer
+
+	lastSigTime uint64 // atomic
 }
 
 var _ mgmt.Client = (*Client)(nil)
@@ -56,40 +57,253 @@ func (c *Client) Close() error {
 	return nil
 }
 
-func (c *Client) invoke(command string, parameters ...tlv.Fielder) (status int, e error) {
+// Invoke sends a control command and returns the ControlResponse.
+// command is module and verb separated by slash, such as "faces/create".
+// A ControlResponse with unsuccessful status code is not an error; use cr.Err() to check it.
+func (c *Client) Invoke(ctx context.Context, command string, params ControlParameters) (cr ControlResponse, e error) {
 	name := ndn.ParseName(c.Prefix + "/" + command)
-	name = append(name, ndn.NameComponentFrom(an.TtGenericNameComponent, tlv.TLVFrom(ttControlParameters, parameters...)))
+	paramsValue, e := tlv.EncodeFrom(params)
+	if e != nil {
+		return cr, e
+	}
+	name = append(name, ndn.MakeNameComponent(an.TtGenericNameComponent, paramsValue))
 	interest := ndn.Interest{
 		Name:        name,
 		MustBeFresh: true,
 		SigInfo: &ndn.SigInfo{
 			Nonce: make([]byte, 4),
-			Time:  uint64(time.Now().UnixMilli()),
+			Time:  c.nextSigTime(),
 		},
 	}
-	rand.Read(interest.SigInfo.Nonce)
+	if _, e = rand.Read(interest.SigInfo.Nonce); e != nil {
+		return cr, e
+	}
 	if e = c.Signer.Sign(&interest); e != nil {
-		return 0, fmt.Errorf("signing error: %w", e)
+		return cr, fmt.Errorf("signing error: %w", e)
+	}
+
+	data, e := endpoint.Consume(ctx, interest, c.ConsumerOpts)
+	if e != nil {
+		return cr, fmt.Errorf("consumer error: %w", e)
+	}
+
+	if e = tlv.Decode(data.Content, &cr); e != nil {
+		return cr, fmt.Errorf("decode error: %w", e)
+	}
+	return cr, nil
+}
+
+func (c *Client) invokeBody(ctx context.Context, command string, params ControlParameters) (body ControlParameters, e error) {
+	cr, e := c.Invoke(ctx, command, params)
+	if e != nil {
+		return body, e
+	}
+	if cr.Body != nil {
+		body = *cr.Body
+	}
+	return body, cr.Err()
+}
+
+// CreateFace creates a face.
+// params should contain URI, and may contain LocalURI, FacePersistency, Flags, Mask, and other face properties.
+// It returns ControlParameters of the created face.
+// If the face already exists, it returns ControlParameters of the existing face, and StatusError with StatusConflict.
+func (c *Client) CreateFace(ctx context.Context, params ControlParameters) (ControlParameters, error) {
+	return c.invokeBody(ctx, "faces/create", params)
+}
+
+// UpdateFace updates properties of a face.
+// params should contain FaceID, and properties to be updated.
+func (c *Client) UpdateFace(ctx context.Context, params ControlParameters) (ControlParameters, error) {
+	return c.invokeBody(ctx, "faces/update", params)
+}
+
+// DestroyFace destroys a face.
+// Destroying a non-existent face is not an error.
+func (c *Client) DestroyFace(ctx context.Context, id uint64) error {
+	_, e := c.invokeBody(ctx, "faces/destroy", ControlParameters{FaceID: id})
+	return e
+}
+
+// SetStrategy sets the forwarding strategy of a namespace.
+func (c *Client) SetStrategy(ctx context.Context, name ndn.Name, strategy ndn.Name) error {
+	_, e := c.invokeBody(ctx, "strategy-choice/set", ControlParameters{
+		Name:     name,
+		Strategy: &Strategy{Name: strategy},
+	})
+	return e
+}
+
+// UnsetStrategy removes the forwarding strategy choice of a namespace.
+func (c *Client) UnsetStrategy(ctx context.Context, name ndn.Name) error {
+	_, e := c.invokeBody(ctx, "strategy-choice/unset", ControlParameters{Name: name})
+	return e
+}
+
+// Dataset retrieves a status dataset and returns its payload.
+// dataset is module and dataset name separated by slash, such as "faces/list".
+//
+// The first segment is requested with CanBePrefix and MustBeFresh, which determines the version.
+// If there are more segments, they are retrieved with segmented.Fetch.
+func (c *Client) Dataset(ctx context.Context, dataset string) (payload []byte, e error) {
+	prefix := ndn.ParseName(c.Prefix + "/" + dataset)
+	interest := ndn.Interest{
+		Name:        prefix,
+		CanBePrefix: true,
+		MustBeFresh: true,
+	}
+	data, e := endpoint.Consume(ctx, interest, c.ConsumerOpts)
+	if e != nil {
+		return nil, fmt.Errorf("consumer error: %w", e)
+	}
+
+	if len(data.Name) != len(prefix)+2 || !ndn.VersionConvention.Match(data.Name.Get(-2)) {
+		return nil, ErrDatasetName
+	}
+	if seg, ok := ndn.SegmentConvention.Parse(data.Name.Get(-1)); !ok || seg != 0 {
+		return nil, ErrDatasetName
+	}
+	if data.IsFinalBlock() {
+		return data.Content, nil
 	}
 
-	data, e := endpoint.Consume(context.TODO(), interest, c.ConsumerOpts)
+	rest, e := segmented.Fetch(data.Name.GetPrefix(-1), segmented.FetchOptions{
+		Fw:           c.ConsumerOpts.Fw,
+		SegmentBegin: 1,
+		RetxLimit:    2,
+		Verifier:     c.ConsumerOpts.Verifier,
+	}).Payload(ctx)
 	if e != nil {
-		return 0, fmt.Errorf("consumer error: %w", e)
-	}
-
-	d0 := tlv.DecodingBuffer(data.Content)
-	var de0, de1 tlv.DecodingElement
-	de0, e = d0.Element()
-	if e == nil && de0.Type == ttControlResponse {
-		d1 := tlv.DecodingBuffer(de0.Value)
-		de1, e = d1.Element()
-		if e == nil && de1.Type == ttStatusCode {
-			if status = int(de1.UnmarshalNNI(999, &e, tlv.ErrRange)); e == nil {
-				return status, nil
-			}
+		return nil, fmt.Errorf("segmented fetch error: %w", e)
+	}
+	return append(append([]byte{}, data.Content...), rest...), nil
+}
+
+// decodeDataset decodes a dataset payload that contains zero or more entries.
+func (c *Client) decodeDataset(ctx context.Context, dataset string, newEntry func() tlv.Unmarshaler) error {
+	payload, e := c.Dataset(ctx, dataset)
+	if e != nil {
+		return e
+	}
+
+	d := tlv.DecodingBuffer(payload)
+	for _, de := range d.Elements() {
+		if e := de.Unmarshal(newEntry()); e != nil {
+			return fmt.Errorf("decode error: %w", e)
+		}
+	}
+	return d.ErrUnlessEOF()
+}
+
+// ListFaces retrieves faces/list dataset.
+func (c *Client) ListFaces(ctx context.Context) (list []FaceStatus, e error) {
+	e = c.decodeDataset(ctx, "faces/list", func() tlv.Unmarshaler {
+		list = append(list, FaceStatus{})
+		return &list[len(list)-1]
+	})
+	return
+}
+
+// ListFib retrieves fib/list dataset.
+func (c *Client) ListFib(ctx context.Context) (list []FibEntry, e error) {
+	e = c.decodeDataset(ctx, "fib/list", func() tlv.Unmarshaler {
+		list = append(list, FibEntry{})
+		return &list[len(list)-1]
+	})
+	return
+}
+
+// ListRib retrieves rib/list dataset.
+func (c *Client) ListRib(ctx context.Context) (list []RibEntry, e error) {
+	e = c.decodeDataset(ctx, "rib/list", func() tlv.Unmarshaler {
+		list = append(list, RibEntry{})
+		return &list[len(list)-1]
+	})
+	return
+}
+
+// ListStrategies retrieves strategy-choice/list dataset.
+func (c *Client) ListStrategies(ctx context.Context) (list []StrategyChoice, e error) {
+	e = c.decodeDataset(ctx, "strategy-choice/list", func() tlv.Unmarshaler {
+		list = append(list, StrategyChoice{})
+		return &list[len(list)-1]
+	})
+	return
+}
+
+// CsInfo retrieves cs/info dataset.
+func (c *Client) CsInfo(ctx context.Context) (info CsInfo, e error) {
+	payload, e := c.Dataset(ctx, "cs/info")
+	if e != nil {
+		return info, e
+	}
+	if e = tlv.Decode(payload, &info); e != nil {
+		return info, fmt.Errorf("decode error: %w", e)
+	}
+	return info, nil
+}
+
+// FaceEvents subscribes to faces/events notification stream.
+// Notifications are sent to the channel as they arrive.
+// This function blocks until ctx is canceled or an error occurs; it does not close the channel.
+//
+// If a notification cannot be decoded, the subscriber restarts from the latest notification.
+func (c *Client) FaceEvents(ctx context.Context, events chan<- FaceEventNotification) error {
+	prefix := ndn.ParseName(c.Prefix + "/faces/events")
+	opts := endpoint.ConsumerOptions{
+		Fw:       c.ConsumerOpts.Fw,
+		Verifier: c.ConsumerOpts.Verifier,
+	}
+	initial := ndn.Interest{
+		Name:        prefix,
+		CanBePrefix: true,
+		MustBeFresh: true,
+		Lifetime:    notificationLifetime,
+	}
+
+	interest := initial
+	for {
+		data, e := endpoint.Consume(ctx, interest, opts)
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.Is(e, endpoint.ErrExpire):
+			continue
+		case e != nil:
+			return fmt.Errorf("consumer error: %w", e)
+		}
+
+		seq, ok := ndn.SequenceNumConvention.Parse(data.Name.Get(-1))
+		var n FaceEventNotification
+		if !ok || len(data.Name) != len(prefix)+1 || tlv.Decode(data.Content, &n) != nil {
+			interest = initial
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case events <- n:
+		}
+		interest = ndn.Interest{
+			Name:     prefix.Append(ndn.SequenceNumConvention.Make(seq + 1)),
+			Lifetime: notificationLifetime,
+		}
+	}
+}
+
+// nextSigTime returns SigTime of the next command.
+// It is strictly increasing, because NFD rejects a command whose SigTime is not greater than the previous one.
+func (c *Client) nextSigTime() uint64 {
+	for {
+		last, t := atomic.LoadUint64(&c.lastSigTime), uint64(time.Now().UnixMilli())
+		if t <= last {
+			t = last + 1
+		}
+		if atomic.CompareAndSwapUint64(&c.lastSigTime, last, t) {
+			return t
 		}
 	}
-	return 0, fmt.Errorf("decode error: %w", e)
 }
 
 // New creates a Client.
@@ -0,0 +1,241 @@
+package nfdmgmt_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"github.com/usnistgov/ndn-dpdk/ndn/mgmt/nfdmgmt"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+	"go4.org/must"
+)
+
+var nfdPrefix = ndn.ParseName("/localhost/nfd")
+
+func TestControlParameters(t *testing.T) {
+	assert, require := makeAR(t)
+
+	origin, cost := uint64(nfdmgmt.RouteOriginApp), uint64(0)
+	wire, e := tlv.EncodeFrom(nfdmgmt.ControlParameters{
+		Name:   ndn.ParseName("/A"),
+		Origin: &origin,
+		Cost:   &cost,
+	})
+	require.NoError(e)
+	assert.Equal([]byte{
+		0x68, 0x0B,
+		0x07, 0x03, 0x08, 0x01, 0x41,
+		0x6F, 0x01, 0x00,
+		0x6A, 0x01, 0x00,
+	}, wire)
+
+	var cp nfdmgmt.ControlParameters
+	require.NoError(tlv.Decode(wire, &cp))
+	if assert.NotNil(cp.Origin) {
+		assert.EqualValues(0, *cp.Origin)
+	}
+	if assert.NotNil(cp.Cost) {
+		assert.EqualValues(0, *cp.Cost)
+	}
+
+	wire, e = tlv.EncodeFrom(nfdmgmt.ControlParameters{Name: ndn.ParseName("/A")})
+	require.NoError(e)
+	require.NoError(tlv.Decode(wire, &cp))
+	assert.Nil(cp.Origin)
+	assert.Nil(cp.Cost)
+}
+
+func TestCommand(t *testing.T) {
+	assert, require := makeAR(t)
+	fw := l3.NewForwarder()
+	client, e := nfdmgmt.New()
+	require.NoError(e)
+	client.ConsumerOpts.Fw = fw
+
+	pFaces, e := endpoint.Produce(context.Background(), endpoint.ProducerOptions{
+		Prefix: nfdPrefix.Append(ndn.ParseNameComponent("faces")),
+		Handler: func(ctx context.Context, interest ndn.Interest) (ndn.Data, error) {
+			var cp nfdmgmt.ControlParameters
+			if e := tlv.Decode(interest.Name[4].Value, &cp); e != nil {
+				return ndn.Data{}, e
+			}
+			cr := nfdmgmt.ControlResponse{StatusCode: nfdmgmt.StatusOK, StatusText: "OK", Body: &cp}
+			switch interest.Name[3].String() {
+			case "8=create":
+				if cp.URI == "udp4://192.0.2.1:6363" {
+					cr.StatusCode, cr.StatusText = nfdmgmt.StatusConflict, "Conflict"
+				}
+				cp.FaceID = 300
+			case "8=update":
+				if cp.FaceID != 300 {
+					cr.StatusCode, cr.StatusText, cr.Body = nfdmgmt.StatusNotFound, "Not Found", nil
+				}
+			}
+			wire, e := tlv.EncodeFrom(cr)
+			if e != nil {
+				return ndn.Data{}, e
+			}
+			return ndn.MakeData(interest, wire), nil
+		},
+		Fw: fw,
+	})
+	require.NoError(e)
+	defer must.Close(pFaces)
+
+	persistency := uint64(nfdmgmt.FacePersistencyPermanent)
+	body, e := client.CreateFace(context.Background(), nfdmgmt.ControlParameters{
+		URI:             "udp4://192.0.2.2:6363",
+		FacePersistency: &persistency,
+	})
+	require.NoError(e)
+	assert.EqualValues(300, body.FaceID)
+	assert.Equal("udp4://192.0.2.2:6363", body.URI)
+	if assert.NotNil(body.FacePersistency) {
+		assert.EqualValues(nfdmgmt.FacePersistencyPermanent, *body.FacePersistency)
+	}
+
+	body, e = client.CreateFace(context.Background(), nfdmgmt.ControlParameters{URI: "udp4://192.0.2.1:6363"})
+	var statusErr nfdmgmt.StatusError
+	if assert.ErrorAs(e, &statusErr) {
+		assert.EqualValues(nfdmgmt.StatusConflict, statusErr.Code)
+	}
+	assert.EqualValues(300, body.FaceID)
+
+	mtu := uint64(1400)
+	body, e = client.UpdateFace(context.Background(), nfdmgmt.ControlParameters{FaceID: 300, MTU: mtu})
+	assert.NoError(e)
+	assert.Equal(mtu, body.MTU)
+
+	e = client.DestroyFace(context.Background(), 301)
+	assert.NoError(e)
+
+	_, e = client.UpdateFace(context.Background(), nfdmgmt.ControlParameters{FaceID: 301})
+	if assert.ErrorAs(e, &statusErr) {
+		assert.EqualValues(nfdmgmt.StatusNotFound, statusErr.Code)
+	}
+}
+
+func TestDataset(t *testing.T) {
+	assert, require := makeAR(t)
+	fw := l3.NewForwarder()
+	client, e := nfdmgmt.New()
+	require.NoError(e)
+	client.ConsumerOpts.Fw = fw
+
+	faces := []nfdmgmt.FaceStatus{
+		{FaceID: 256, URI: "udp4://192.0.2.1:6363", LocalURI: "udp4://192.0.2.254:6363", NInInterests: 10},
+		{FaceID: 257, URI: "fd://30", LocalURI: "unix:///run/nfd.sock", FaceScope: nfdmgmt.FaceScopeLocal,
+			FacePersistency: nfdmgmt.FacePersistencyOnDemand},
+	}
+	var fields []tlv.Fielder
+	for _, face := range faces {
+		fields = append(fields, face)
+	}
+	payload, e := tlv.EncodeFrom(fields...)
+	require.NoError(e)
+	segments := [][]byte{payload[:40], payload[40:80], payload[80:]}
+
+	version := ndn.VersionConvention.Make(1)
+	lastSeg := ndn.SegmentConvention.Make(uint64(len(segments) - 1))
+	pFaces, e := endpoint.Produce(context.Background(), endpoint.ProducerOptions{
+		Prefix: nfdPrefix.Append(ndn.ParseNameComponent("faces")),
+		Handler: func(ctx context.Context, interest ndn.Interest) (ndn.Data, error) {
+			name := interest.Name
+			seg := uint64(0)
+			if len(name) == 4 {
+				name = name.Append(version, ndn.SegmentConvention.Make(0))
+			} else if seg, _ = ndn.SegmentConvention.Parse(name.Get(-1)); seg >= uint64(len(segments)) {
+				return ndn.Data{}, endpoint.ReplyNack(0)
+			}
+			return ndn.MakeData(name, time.Second, ndn.FinalBlock(lastSeg), segments[seg]), nil
+		},
+		Fw: fw,
+	})
+	require.NoError(e)
+	defer must.Close(pFaces)
+
+	pCs, e := endpoint.Produce(context.Background(), endpoint.ProducerOptions{
+		Prefix: nfdPrefix.Append(ndn.ParseNameComponent("cs")),
+		Handler: func(ctx context.Context, interest ndn.Interest) (ndn.Data, error) {
+			info := nfdmgmt.CsInfo{Capacity: 65536, Flags: nfdmgmt.CsFlagEnableAdmit | nfdmgmt.CsFlagEnableServe, NHits: 7}
+			wire, e := tlv.EncodeFrom(info)
+			if e != nil {
+				return ndn.Data{}, e
+			}
+			name := interest.Name.Append(version, ndn.SegmentConvention.Make(0))
+			return ndn.MakeData(name, time.Second, ndn.FinalBlockFlag, wire), nil
+		},
+		Fw: fw,
+	})
+	require.NoError(e)
+	defer must.Close(pCs)
+
+	list, e := client.ListFaces(context.Background())
+	require.NoError(e)
+	assert.Equal(faces, list)
+
+	info, e := client.CsInfo(context.Background())
+	require.NoError(e)
+	assert.EqualValues(65536, info.Capacity)
+	assert.EqualValues(7, info.NHits)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	_, e = client.ListRib(ctx)
+	assert.Error(e)
+}
+
+func TestFaceEvents(t *testing.T) {
+	assert, require := makeAR(t)
+	fw := l3.NewForwarder()
+	client, e := nfdmgmt.New()
+	require.NoError(e)
+	client.ConsumerOpts.Fw = fw
+
+	pFaces, e := endpoint.Produce(context.Background(), endpoint.ProducerOptions{
+		Prefix: nfdPrefix.Append(ndn.ParseNameComponent("faces")),
+		Handler: func(ctx context.Context, interest ndn.Interest) (ndn.Data, error) {
+			name := interest.Name
+			seq := uint64(5)
+			if len(name) == 4 {
+				name = name.Append(ndn.SequenceNumConvention.Make(seq))
+			} else if seq, _ = ndn.SequenceNumConvention.Parse(name.Get(-1)); seq > 6 {
+				<-ctx.Done()
+				return ndn.Data{}, ctx.Err()
+			}
+			wire, e := tlv.EncodeFrom(nfdmgmt.FaceEventNotification{
+				Kind:   nfdmgmt.FaceEventCreated,
+				FaceID: 256 + seq,
+			})
+			if e != nil {
+				return ndn.Data{}, e
+			}
+			return ndn.MakeData(name, time.Second, wire), nil
+		},
+		Fw: fw,
+	})
+	require.NoError(e)
+	defer must.Close(pFaces)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan nfdmgmt.FaceEventNotification)
+	done := make(chan error)
+	go func() { done <- client.FaceEvents(ctx, events) }()
+
+	for _, faceID := range []uint64{261, 262} {
+		select {
+		case n := <-events:
+			assert.EqualValues(nfdmgmt.FaceEventCreated, n.Kind)
+			assert.Equal(faceID, n.FaceID)
+		case <-time.After(time.Second):
+			assert.Fail("notification timeout")
+		}
+	}
+
+	cancel()
+	assert.True(errors.Is(<-done, context.Canceled))
+}
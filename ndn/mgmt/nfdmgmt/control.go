@@ -0,0 +1,170 @@
+package nfdmgmt
+
+import (
+	"fmt"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// TLV-TYPE numbers of top-level elements in NFD Management protocol.
+const (
+	TtControlParameters = 0x68
+	TtControlResponse   = 0x65
+	TtDatasetEntry      = 0x80
+	TtFaceEvent         = 0xC0
+)
+
+// FacePersistency values.
+const (
+	FacePersistencyPersistent = 0
+	FacePersistencyOnDemand   = 1
+	FacePersistencyPermanent  = 2
+)
+
+// FaceScope values.
+const (
+	FaceScopeNonLocal = 0
+	FaceScopeLocal    = 1
+)
+
+// LinkType values.
+const (
+	LinkTypePointToPoint = 0
+	LinkTypeMultiAccess  = 1
+	LinkTypeAdHoc        = 2
+)
+
+// Face flag bits.
+const (
+	FaceFlagLocalFields       = 1 << 0
+	FaceFlagLpReliability     = 1 << 1
+	FaceFlagCongestionMarking = 1 << 2
+)
+
+// Route origin values.
+const (
+	RouteOriginApp       = 0
+	RouteOriginAutoreg   = 64
+	RouteOriginClient    = 65
+	RouteOriginAutoconf  = 66
+	RouteOriginNLSR      = 128
+	RouteOriginPrefixAnn = 129
+	RouteOriginStatic    = 255
+)
+
+// Route flag bits.
+const (
+	RouteFlagChildInherit = 1 << 0
+	RouteFlagCapture      = 1 << 1
+)
+
+// Status codes.
+const (
+	StatusOK            = 200
+	StatusBadParams     = 400
+	StatusForbidden     = 403
+	StatusNotFound      = 404
+	StatusNotAcceptable = 406
+	StatusConflict      = 409
+	StatusGone          = 410
+	StatusInternal      = 500
+	StatusNotSupport    = 501
+)
+
+// Strategy contains a strategy name.
+type Strategy struct {
+	Name ndn.Name `tlv:"0x07"`
+}
+
+// ControlParameters contains command parameters.
+//
+// Pointer fields are optional parameters where zero is a meaningful value.
+type ControlParameters struct {
+	Name                          ndn.Name  `tlv:"0x07,omitempty"`
+	FaceID                        uint64    `tlv:"0x69,omitempty"`
+	URI                           string    `tlv:"0x72,omitempty"`
+	LocalURI                      string    `tlv:"0x81,omitempty"`
+	Origin                        *uint64   `tlv:"0x6F"`
+	Cost                          *uint64   `tlv:"0x6A"`
+	Capacity                      uint64    `tlv:"0x83,omitempty"`
+	Count                         uint64    `tlv:"0x84,omitempty"`
+	Flags                         *uint64   `tlv:"0x6C"`
+	Mask                          *uint64   `tlv:"0x70"`
+	Strategy                      *Strategy `tlv:"0x6B"`
+	ExpirationPeriod              *uint64   `tlv:"0x6D"`
+	FacePersistency               *uint64   `tlv:"0x85"`
+	BaseCongestionMarkingInterval uint64    `tlv:"0x87,omitempty"`
+	DefaultCongestionThreshold    uint64    `tlv:"0x88,omitempty"`
+	MTU                           uint64    `tlv:"0x89,omitempty"`
+}
+
+var (
+	_ tlv.Fielder     = ControlParameters{}
+	_ tlv.Unmarshaler = (*ControlParameters)(nil)
+)
+
+// Field implements tlv.Fielder interface.
+func (cp ControlParameters) Field() tlv.Field {
+	return marshalField(TtControlParameters, cp)
+}
+
+// UnmarshalTLV decodes from wire format.
+func (cp *ControlParameters) UnmarshalTLV(typ uint32, value []byte) error {
+	return unmarshalField(TtControlParameters, typ, value, cp)
+}
+
+// ControlResponse contains command response.
+type ControlResponse struct {
+	StatusCode uint               `tlv:"0x66"`
+	StatusText string             `tlv:"0x67"`
+	Body       *ControlParameters `tlv:"0x68"`
+}
+
+var (
+	_ tlv.Fielder     = ControlResponse{}
+	_ tlv.Unmarshaler = (*ControlResponse)(nil)
+)
+
+// Field implements tlv.Fielder interface.
+func (cr ControlResponse) Field() tlv.Field {
+	return marshalField(TtControlResponse, cr)
+}
+
+// UnmarshalTLV decodes from wire format.
+func (cr *ControlResponse) UnmarshalTLV(typ uint32, value []byte) error {
+	return unmarshalField(TtControlResponse, typ, value, cr)
+}
+
+// Err returns StatusError if StatusCode is not StatusOK, otherwise nil.
+func (cr ControlResponse) Err() error {
+	if cr.StatusCode == StatusOK {
+		return nil
+	}
+	return StatusError{Code: cr.StatusCode, Text: cr.StatusText}
+}
+
+// StatusError indicates a command has failed.
+type StatusError struct {
+	Code uint
+	Text string
+}
+
+func (e StatusError) Error() string {
+	return fmt.Sprintf("unexpected response status %d %s", e.Code, e.Text)
+}
+
+func marshalField(typ uint32, v interface{}) tlv.Field {
+	value, e := tlv.Marshal(v)
+	if e != nil {
+		return tlv.FieldError(e)
+	}
+	return tlv.TLVBytes(typ, value)
+}
+
+func unmarshalField(expectedType, typ uint32, value []byte, v interface{}) error {
+	if typ != expectedType {
+		return tlv.ErrType
+	}
+	return tlv.Unmarshal(value, v)
+}
@@ -0,0 +1,157 @@
+package nfdmgmt
+
+import (
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// FaceStatus is an entry in faces/list dataset.
+type FaceStatus struct {
+	FaceID                        uint64  `tlv:"0x69"`
+	URI                           string  `tlv:"0x72"`
+	LocalURI                      string  `tlv:"0x81"`
+	ExpirationPeriod              *uint64 `tlv:"0x6D"`
+	FaceScope                     uint64  `tlv:"0x84"`
+	FacePersistency               uint64  `tlv:"0x85"`
+	LinkType                      uint64  `tlv:"0x86"`
+	BaseCongestionMarkingInterval uint64  `tlv:"0x87,omitempty"`
+	DefaultCongestionThreshold    uint64  `tlv:"0x88,omitempty"`
+	MTU                           uint64  `tlv:"0x89,omitempty"`
+	NInInterests                  uint64  `tlv:"0x90"`
+	NInData                       uint64  `tlv:"0x91"`
+	NInNacks                      uint64  `tlv:"0x97"`
+	NOutInterests                 uint64  `tlv:"0x92"`
+	NOutData                      uint64  `tlv:"0x93"`
+	NOutNacks                     uint64  `tlv:"0x98"`
+	NInBytes                      uint64  `tlv:"0x94"`
+	NOutBytes                     uint64  `tlv:"0x95"`
+	Flags                         uint64  `tlv:"0x6C"`
+}
+
+// Field implements tlv.Fielder interface.
+func (fs FaceStatus) Field() tlv.Field {
+	return marshalField(TtDatasetEntry, fs)
+}
+
+// UnmarshalTLV decodes from wire format.
+func (fs *FaceStatus) UnmarshalTLV(typ uint32, value []byte) error {
+	return unmarshalField(TtDatasetEntry, typ, value, fs)
+}
+
+// NextHopRecord is a nexthop in FibEntry.
+type NextHopRecord struct {
+	FaceID uint64 `tlv:"0x69"`
+	Cost   uint64 `tlv:"0x6A"`
+}
+
+// FibEntry is an entry in fib/list dataset.
+type FibEntry struct {
+	Name     ndn.Name        `tlv:"0x07"`
+	NextHops []NextHopRecord `tlv:"0x81"`
+}
+
+// Field implements tlv.Fielder interface.
+func (fe FibEntry) Field() tlv.Field {
+	return marshalField(TtDatasetEntry, fe)
+}
+
+// UnmarshalTLV decodes from wire format.
+func (fe *FibEntry) UnmarshalTLV(typ uint32, value []byte) error {
+	return unmarshalField(TtDatasetEntry, typ, value, fe)
+}
+
+// Route is a route in RibEntry.
+type Route struct {
+	FaceID           uint64  `tlv:"0x69"`
+	Origin           uint64  `tlv:"0x6F"`
+	Cost             uint64  `tlv:"0x6A"`
+	Flags            uint64  `tlv:"0x6C"`
+	ExpirationPeriod *uint64 `tlv:"0x6D"`
+}
+
+// RibEntry is an entry in rib/list dataset.
+type RibEntry struct {
+	Name   ndn.Name `tlv:"0x07"`
+	Routes []Route  `tlv:"0x81"`
+}
+
+// Field implements tlv.Fielder interface.
+func (re RibEntry) Field() tlv.Field {
+	return marshalField(TtDatasetEntry, re)
+}
+
+// UnmarshalTLV decodes from wire format.
+func (re *RibEntry) UnmarshalTLV(typ uint32, value []byte) error {
+	return unmarshalField(TtDatasetEntry, typ, value, re)
+}
+
+// StrategyChoice is an entry in strategy-choice/list dataset.
+type StrategyChoice struct {
+	Name     ndn.Name `tlv:"0x07"`
+	Strategy Strategy `tlv:"0x6B"`
+}
+
+// Field implements tlv.Fielder interface.
+func (sc StrategyChoice) Field() tlv.Field {
+	return marshalField(TtDatasetEntry, sc)
+}
+
+// UnmarshalTLV decodes from wire format.
+func (sc *StrategyChoice) UnmarshalTLV(typ uint32, value []byte) error {
+	return unmarshalField(TtDatasetEntry, typ, value, sc)
+}
+
+// CsFlagEnableAdmit and CsFlagEnableServe are bits in CsInfo.Flags.
+const (
+	CsFlagEnableAdmit = 1 << 0
+	CsFlagEnableServe = 1 << 1
+)
+
+// CsInfo is the cs/info dataset.
+type CsInfo struct {
+	Capacity   uint64 `tlv:"0x83"`
+	Flags      uint64 `tlv:"0x6C"`
+	NCsEntries uint64 `tlv:"0x87"`
+	NHits      uint64 `tlv:"0x81"`
+	NMisses    uint64 `tlv:"0x82"`
+}
+
+// Field implements tlv.Fielder interface.
+func (ci CsInfo) Field() tlv.Field {
+	return marshalField(TtDatasetEntry, ci)
+}
+
+// UnmarshalTLV decodes from wire format.
+func (ci *CsInfo) UnmarshalTLV(typ uint32, value []byte) error {
+	return unmarshalField(TtDatasetEntry, typ, value, ci)
+}
+
+// FaceEventKind values.
+const (
+	FaceEventCreated   = 1
+	FaceEventDestroyed = 2
+	FaceEventUp        = 3
+	FaceEventDown      = 4
+)
+
+// FaceEventNotification is a notification in faces/events stream.
+type FaceEventNotification struct {
+	Kind            uint64 `tlv:"0xC1"`
+	FaceID          uint64 `tlv:"0x69"`
+	URI             string `tlv:"0x72"`
+	LocalURI        string `tlv:"0x81"`
+	FaceScope       uint64 `tlv:"0x84"`
+	FacePersistency uint64 `tlv:"0x85"`
+	LinkType        uint64 `tlv:"0x86"`
+	Flags           uint64 `tlv:"0x6C"`
+}
+
+// Field implements tlv.Fielder interface.
+func (n FaceEventNotification) Field() tlv.Field {
+	return marshalField(TtFaceEvent, n)
+}
+
+// UnmarshalTLV decodes from wire format.
+func (n *FaceEventNotification) UnmarshalTLV(typ uint32, value []byte) error {
+	return unmarshalField(TtFaceEvent, typ, value, n)
+}
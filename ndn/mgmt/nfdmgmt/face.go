@@ -7,13 +7,14 @@ import (
 
 	"github.com/usnistgov/ndn-dpdk/ndn"
 	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"github.com/usnistgov/ndn-dpdk/ndn/mgmt"
 	"github.com/usnistgov/ndn-dpdk/ndn/sockettransport"
-	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
 )
 
 type nfdFace struct {
 	client *Client
 	l3face l3.Face
+	rv     *mgmt.Readvertiser
 }
 
 func (f *nfdFace) ID() string {
@@ -25,36 +26,15 @@ func (f *nfdFace) Face() l3.Face {
 }
 
 func (f *nfdFace) Close() error {
-	return nil
+	return f.rv.Close()
 }
 
 func (f *nfdFace) Advertise(name ndn.Name) error {
-	status, e := f.client.invoke("rib/register",
-		name,
-		tlv.TLVNNI(ttOrigin, originClient),
-		tlv.TLVNNI(ttFlags, flagCapture),
-	)
-	if e != nil {
-		return e
-	}
-	if status != 200 {
-		return fmt.Errorf("unexpected response status %d", status)
-	}
-	return nil
+	return f.rv.Advertise(name)
 }
 
 func (f *nfdFace) Withdraw(name ndn.Name) error {
-	status, e := f.client.invoke("rib/unregister",
-		name,
-		tlv.TLVNNI(ttOrigin, originClient),
-	)
-	if e != nil {
-		return e
-	}
-	if status != 200 {
-		return fmt.Errorf("unexpected response status %d", status)
-	}
-	return nil
+	return f.rv.Withdraw(name)
 }
 
 func newNfdFace(c *Client) (f *nfdFace, e error) {
@@ -86,5 +66,6 @@ func newNfdFace(c *Client) (f *nfdFace, e error) {
 	return &nfdFace{
 		client: c,
 		l3face: l3face,
+		rv:     c.Readvertise(mgmt.ReadvertiseOptions{}),
 	}, nil
 }
@@ -0,0 +1,41 @@
+package nfdmgmt
+
+import (
+	"context"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/mgmt"
+)
+
+// ribReadvertiseOps advertises prefixes by sending rib/register commands to NFD.
+type ribReadvertiseOps struct {
+	client *Client
+}
+
+func (ops ribReadvertiseOps) Advertise(ctx context.Context, name ndn.Name) error {
+	origin, cost, flags := uint64(RouteOriginClient), uint64(0), uint64(RouteFlagCapture)
+	_, e := ops.client.invokeBody(ctx, "rib/register", ControlParameters{
+		Name:   name,
+		Origin: &origin,
+		Cost:   &cost,
+		Flags:  &flags,
+	})
+	return e
+}
+
+func (ops ribReadvertiseOps) Withdraw(ctx context.Context, name ndn.Name) error {
+	origin := uint64(RouteOriginClient)
+	_, e := ops.client.invokeBody(ctx, "rib/unregister", ControlParameters{
+		Name:   name,
+		Origin: &origin,
+	})
+	return e
+}
+
+// Readvertise creates a readvertise destination that registers prefixes on NFD.
+// Each advertised prefix becomes a route toward the face on which NFD receives the command,
+// so that c.ConsumerOpts.Fw should have a route for c.Prefix toward the NFD face.
+// Caller must close the Readvertiser, which also unregisters the prefixes.
+func (c *Client) Readvertise(opts mgmt.ReadvertiseOptions) *mgmt.Readvertiser {
+	return mgmt.NewReadvertiser(ribReadvertiseOps{client: c}, opts)
+}
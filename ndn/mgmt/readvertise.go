@@ -0,0 +1,291 @@
+package mgmt
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"go.uber.org/multierr"
+)
+
+// Error conditions.
+var (
+	ErrReadvertiserClosed = errors.New("readvertiser is closed")
+)
+
+// ReadvertiseOps contains operations that propagate a prefix to a remote forwarder.
+// They are invoked sequentially on a background goroutine.
+type ReadvertiseOps interface {
+	// Advertise registers a prefix on the remote forwarder.
+	Advertise(ctx context.Context, name ndn.Name) error
+
+	// Withdraw unregisters a prefix on the remote forwarder.
+	Withdraw(ctx context.Context, name ndn.Name) error
+}
+
+// ReadvertiseOptions contains Readvertiser options.
+type ReadvertiseOptions struct {
+	// Timeout is the timeout of each operation.
+	// Default is 4 seconds.
+	Timeout time.Duration
+
+	// RetryInitial is the initial retry interval after an operation fails.
+	// Default is 500 milliseconds.
+	RetryInitial time.Duration
+
+	// RetryMaximum is the maximum retry interval.
+	// The retry interval doubles after each failure, until it reaches this value.
+	// Default is 60 seconds.
+	RetryMaximum time.Duration
+
+	// CloseTimeout is the maximum duration of withdrawing advertised prefixes during Close.
+	// Default is Timeout.
+	CloseTimeout time.Duration
+}
+
+func (opts *ReadvertiseOptions) applyDefaults() {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 4 * time.Second
+	}
+	if opts.RetryInitial <= 0 {
+		opts.RetryInitial = 500 * time.Millisecond
+	}
+	if opts.RetryMaximum <= 0 {
+		opts.RetryMaximum = 60 * time.Second
+	}
+	if opts.RetryMaximum < opts.RetryInitial {
+		opts.RetryMaximum = opts.RetryInitial
+	}
+	if opts.CloseTimeout <= 0 {
+		opts.CloseTimeout = opts.Timeout
+	}
+}
+
+// readvertiseEntry tracks the state of a prefix.
+type readvertiseEntry struct {
+	name      ndn.Name
+	want      bool          // whether the prefix should be advertised
+	have      bool          // whether the prefix has been advertised successfully
+	retryAt   time.Time     // earliest time of next attempt
+	retryWait time.Duration // retry interval after next failure
+}
+
+// Readvertiser implements l3.ReadvertiseDestination with deduplication and retry.
+//
+// Advertise and Withdraw calls record the desired state of each prefix and return immediately.
+// A background goroutine invokes ReadvertiseOps to bring the remote forwarder to the desired state.
+// Repeated calls on the same prefix are coalesced, so that each prefix is advertised at most once,
+// and an Advertise followed by a Withdraw before the first has been processed causes no operation.
+// A failed operation is retried with exponential backoff, until it succeeds or is no longer needed.
+type Readvertiser struct {
+	ops    ReadvertiseOps
+	opts   ReadvertiseOptions
+	wake   chan struct{}
+	closed chan struct{}
+	done   chan struct{}
+
+	mutex   sync.Mutex
+	entries map[string]*readvertiseEntry
+	closing bool
+}
+
+var _ l3.ReadvertiseDestination = (*Readvertiser)(nil)
+
+// Advertise requests a prefix to be advertised.
+func (r *Readvertiser) Advertise(name ndn.Name) error {
+	return r.set(name, true)
+}
+
+// Withdraw requests a prefix to be withdrawn.
+func (r *Readvertiser) Withdraw(name ndn.Name) error {
+	return r.set(name, false)
+}
+
+func (r *Readvertiser) set(name ndn.Name, want bool) error {
+	nameV, _ := name.MarshalBinary()
+	nameS := string(nameV)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.closing {
+		return ErrReadvertiserClosed
+	}
+
+	entry := r.entries[nameS]
+	switch {
+	case entry == nil && !want:
+		return nil
+	case entry == nil:
+		entry = &readvertiseEntry{name: name}
+		r.entries[nameS] = entry
+	case entry.want == want:
+		return nil
+	}
+	entry.want = want
+	entry.retryAt, entry.retryWait = time.Time{}, r.opts.RetryInitial
+
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Advertised returns prefixes that have been advertised successfully.
+func (r *Readvertiser) Advertised() (names []ndn.Name) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, entry := range r.entries {
+		if entry.have {
+			names = append(names, entry.name)
+		}
+	}
+	return names
+}
+
+// Close withdraws advertised prefixes and stops the background goroutine.
+// Withdrawal is attempted once for each prefix, bounded by CloseTimeout.
+func (r *Readvertiser) Close() error {
+	r.mutex.Lock()
+	if r.closing {
+		r.mutex.Unlock()
+		return nil
+	}
+	r.closing = true
+	r.mutex.Unlock()
+
+	close(r.closed)
+	<-r.done
+
+	r.mutex.Lock()
+	var names []ndn.Name
+	for _, entry := range r.entries {
+		if entry.have {
+			names = append(names, entry.name)
+		}
+	}
+	r.entries = nil
+	r.mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.opts.CloseTimeout)
+	defer cancel()
+	var errs []error
+	for _, name := range names {
+		errs = append(errs, r.ops.Withdraw(ctx, name))
+	}
+	return multierr.Combine(errs...)
+}
+
+func (r *Readvertiser) loop() {
+	defer close(r.done)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-r.closed:
+			return
+		case <-r.wake:
+		case <-timer.C:
+		}
+
+		nextRetry := r.processOnce()
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		if !nextRetry.IsZero() {
+			timer.Reset(time.Until(nextRetry))
+		}
+	}
+}
+
+// processOnce performs due operations, and returns the earliest time of a pending retry.
+func (r *Readvertiser) processOnce() (nextRetry time.Time) {
+	for {
+		entry, want, ok := r.pickDue(time.Now())
+		if !ok {
+			break
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), r.opts.Timeout)
+		var e error
+		if want {
+			e = r.ops.Advertise(ctx, entry.name)
+		} else {
+			e = r.ops.Withdraw(ctx, entry.name)
+		}
+		cancel()
+		r.complete(entry, want, e)
+
+		select {
+		case <-r.closed:
+			return time.Time{}
+		default:
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, entry := range r.entries {
+		if entry.want != entry.have && (nextRetry.IsZero() || entry.retryAt.Before(nextRetry)) {
+			nextRetry = entry.retryAt
+		}
+	}
+	return nextRetry
+}
+
+// pickDue finds an entry whose state differs from the desired state and is due for an attempt.
+func (r *Readvertiser) pickDue(now time.Time) (entry *readvertiseEntry, want, ok bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, entry := range r.entries {
+		if entry.want != entry.have && !entry.retryAt.After(now) {
+			return entry, entry.want, true
+		}
+	}
+	return nil, false, false
+}
+
+// complete records the result of an operation.
+func (r *Readvertiser) complete(entry *readvertiseEntry, want bool, e error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if e != nil {
+		if entry.want == want { // otherwise, desired state has changed and retryAt has been reset
+			entry.retryAt = time.Now().Add(entry.retryWait)
+			entry.retryWait *= 2
+			if entry.retryWait > r.opts.RetryMaximum {
+				entry.retryWait = r.opts.RetryMaximum
+			}
+		}
+		return
+	}
+
+	entry.have = want
+	if !entry.want && !entry.have {
+		nameV, _ := entry.name.MarshalBinary()
+		delete(r.entries, string(nameV))
+	}
+}
+
+// NewReadvertiser creates a Readvertiser.
+// Caller must invoke Close to stop the background goroutine.
+func NewReadvertiser(ops ReadvertiseOps, opts ReadvertiseOptions) *Readvertiser {
+	opts.applyDefaults()
+	r := &Readvertiser{
+		ops:     ops,
+		opts:    opts,
+		wake:    make(chan struct{}, 1),
+		closed:  make(chan struct{}),
+		done:    make(chan struct{}),
+		entries: map[string]*readvertiseEntry{},
+	}
+	go r.loop()
+	return r
+}
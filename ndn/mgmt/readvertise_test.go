@@ -0,0 +1,85 @@
+package mgmt_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/mgmt"
+)
+
+// readvertiseOpsMock reports each operation on calls channel.
+// ReadvertiseOps are invoked sequentially, so that no locking is needed.
+type readvertiseOpsMock struct {
+	fail  int
+	calls chan string
+}
+
+func (ops *readvertiseOpsMock) Advertise(ctx context.Context, name ndn.Name) error {
+	if ops.fail > 0 {
+		ops.fail--
+		ops.calls <- "!+" + name.String()
+		return errors.New("mock failure")
+	}
+	ops.calls <- "+" + name.String()
+	return nil
+}
+
+func (ops *readvertiseOpsMock) Withdraw(ctx context.Context, name ndn.Name) error {
+	ops.calls <- "-" + name.String()
+	return nil
+}
+
+func TestReadvertiser(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	ops := &readvertiseOpsMock{fail: 2, calls: make(chan string, 16)}
+	r := mgmt.NewReadvertiser(ops, mgmt.ReadvertiseOptions{
+		RetryInitial: 10 * time.Millisecond,
+		RetryMaximum: 20 * time.Millisecond,
+	})
+
+	expectCalls := func(calls ...string) {
+		for _, expected := range calls {
+			select {
+			case call := <-ops.calls:
+				assert.Equal(expected, call)
+			case <-time.After(time.Second):
+				assert.Fail("operation not invoked", expected)
+			}
+		}
+	}
+	advertised := func(names ...string) func() bool {
+		return func() bool {
+			var list []string
+			for _, name := range r.Advertised() {
+				list = append(list, name.String())
+			}
+			return reflect.DeepEqual(names, list)
+		}
+	}
+
+	nameA, nameB := ndn.ParseName("/A"), ndn.ParseName("/B")
+	assert.NoError(r.Advertise(nameA))
+	assert.NoError(r.Advertise(nameA))
+	expectCalls("!+/8=A", "!+/8=A", "+/8=A")
+	assert.Eventually(advertised("/8=A"), time.Second, time.Millisecond)
+
+	assert.NoError(r.Withdraw(nameB))
+	assert.NoError(r.Advertise(nameB))
+	expectCalls("+/8=B")
+	assert.NoError(r.Withdraw(nameA))
+	assert.NoError(r.Withdraw(nameA))
+	expectCalls("-/8=A")
+	assert.Eventually(advertised("/8=B"), time.Second, time.Millisecond)
+	assert.Len(ops.calls, 0)
+
+	assert.NoError(r.Close())
+	expectCalls("-/8=B")
+	assert.Len(ops.calls, 0)
+	assert.ErrorIs(r.Advertise(nameA), mgmt.ErrReadvertiserClosed)
+	assert.NoError(r.Close())
+}
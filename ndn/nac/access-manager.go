@@ -0,0 +1,177 @@
+package nac
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// AccessManagerOptions contains arguments to NewAccessManager function.
+type AccessManagerOptions struct {
+	// Identity is the access prefix.
+	Identity ndn.Name
+
+	// Dataset is the dataset name, appended to Identity/NAC.
+	Dataset ndn.Name
+
+	// Signer signs KEK and KDK Data packets.
+	// It should be a key of Identity.
+	Signer ndn.Signer
+
+	// Freshness is the FreshnessPeriod of KEK and KDK Data packets.
+	// Default is 1 hour.
+	Freshness time.Duration
+
+	// Fw specifies the L3 Forwarder.
+	// Default is the default Forwarder.
+	Fw l3.Forwarder
+}
+
+func (opts *AccessManagerOptions) applyDefaults() {
+	if opts.Freshness <= 0 {
+		opts.Freshness = time.Hour
+	}
+}
+
+// AccessManager publishes KEK and KDKs of a dataset.
+type AccessManager struct {
+	opts      AccessManagerOptions
+	prefix    ndn.Name
+	nacKey    keychain.PrivateKey
+	nacCert   *keychain.Certificate
+	kek       ndn.Data
+	kdkPrefix ndn.Name
+	producer  endpoint.Producer
+
+	mutex sync.RWMutex
+	kdks  map[string]ndn.Data // member key name => KDK
+}
+
+// Prefix returns the NAC prefix /<access-prefix>/NAC/<dataset>.
+// This should be passed to EncryptorOptions.AccessPrefix.
+func (am *AccessManager) Prefix() ndn.Name {
+	return am.prefix
+}
+
+// KekName returns the KEK name.
+func (am *AccessManager) KekName() ndn.Name {
+	return am.kek.Name
+}
+
+// AddMember grants access to a member, by publishing a KDK encrypted to its RSA public key.
+func (am *AccessManager) AddMember(cert *keychain.Certificate) (kdk ndn.Data, e error) {
+	secret := make([]byte, kdkSecretLen)
+	if _, e = rand.Read(secret); e != nil {
+		return kdk, e
+	}
+
+	var ec EncryptedContent
+	if ec.PayloadKey, e = keychain.RSAEncrypt(cert.PublicKey(), secret); e != nil {
+		return kdk, e
+	}
+	sb, e := keychain.MakeSafeBag(am.nacKey, am.nacCert, secret)
+	if e != nil {
+		return kdk, e
+	}
+	if ec.Payload, e = tlv.EncodeFrom(sb); e != nil {
+		return kdk, e
+	}
+
+	keyName := keychain.ToKeyName(cert.Name())
+	kdkName := am.kdkPrefix.Append(ComponentEncryptedBy).Append(keyName...)
+	if kdk, e = makeEncryptedData(kdkName, ec, am.opts.Freshness); e != nil {
+		return kdk, e
+	}
+	if e = am.opts.Signer.Sign(&kdk); e != nil {
+		return kdk, e
+	}
+
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+	am.kdks[keyName.String()] = kdk
+	return kdk, nil
+}
+
+// RemoveMember stops publishing the KDK of a member.
+// keyName may be a key name or certificate name.
+//
+// This does not revoke access to content encrypted under existing CKs, because a removed member may have
+// retrieved the KDK already. To revoke access, create a new AccessManager, which generates a new KEK.
+func (am *AccessManager) RemoveMember(keyName ndn.Name) {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+	delete(am.kdks, keychain.ToKeyName(keyName).String())
+}
+
+// Close stops publishing KEK and KDKs.
+func (am *AccessManager) Close() error {
+	return am.producer.Close()
+}
+
+func (am *AccessManager) handle(ctx context.Context, interest ndn.Interest) (ndn.Data, error) {
+	if am.kek.CanSatisfy(interest) {
+		return am.kek, nil
+	}
+
+	am.mutex.RLock()
+	defer am.mutex.RUnlock()
+	for _, kdk := range am.kdks {
+		if kdk.CanSatisfy(interest) {
+			return kdk, nil
+		}
+	}
+	return ndn.Data{}, endpoint.ReplyNack(an.NackNoRoute)
+}
+
+// NewAccessManager creates an AccessManager.
+// It generates an RSA key pair for the dataset, and starts publishing the KEK.
+func NewAccessManager(ctx context.Context, opts AccessManagerOptions) (am *AccessManager, e error) {
+	opts.applyDefaults()
+	if len(opts.Identity) == 0 || opts.Signer == nil {
+		return nil, errors.New("identity and signer are required")
+	}
+
+	am = &AccessManager{
+		opts:   opts,
+		prefix: opts.Identity.Append(ComponentNAC).Append(opts.Dataset...),
+		kdks:   map[string]ndn.Data{},
+	}
+
+	nacKey, nacPub, e := keychain.NewRSAKeyPair(am.prefix)
+	if e != nil {
+		return nil, e
+	}
+	am.nacKey = nacKey
+	if am.nacCert, e = keychain.MakeCert(nacPub, nacKey, keychain.MakeCertOptions{}); e != nil {
+		return nil, e
+	}
+
+	spki, e := nacPub.SPKI()
+	if e != nil {
+		return nil, e
+	}
+	keyID := nacKey.Name().Get(-1)
+	am.kek = ndn.MakeData(am.prefix.Append(ComponentKEK, keyID), opts.Freshness, spki)
+	if e = opts.Signer.Sign(&am.kek); e != nil {
+		return nil, e
+	}
+	am.kdkPrefix = am.prefix.Append(ComponentKDK, keyID)
+
+	if am.producer, e = endpoint.Produce(ctx, endpoint.ProducerOptions{
+		Prefix:  am.prefix,
+		Handler: am.handle,
+		Fw:      opts.Fw,
+	}); e != nil {
+		return nil, e
+	}
+	return am, nil
+}
@@ -0,0 +1,93 @@
+package nac
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+)
+
+const (
+	ckLen        = 32
+	kdkSecretLen = 32
+	gcmIVLen     = 12
+)
+
+// Cipher selects the content encryption algorithm.
+type Cipher int
+
+// Cipher values.
+const (
+	// CipherAesCbc is AES-256-CBC with PKCS#7 padding and 16-octet IV, as used by ndn-cxx NAC library.
+	CipherAesCbc Cipher = iota
+
+	// CipherAesGcm is AES-256-GCM with 12-octet IV.
+	// Payload contains ciphertext followed by 16-octet authentication tag.
+	// This is not supported by ndn-cxx NAC library.
+	CipherAesGcm
+)
+
+// aesEncrypt encrypts plaintext with a random IV.
+func aesEncrypt(c Cipher, key, plaintext []byte) (iv, ciphertext []byte, e error) {
+	block, e := aes.NewCipher(key)
+	if e != nil {
+		return nil, nil, e
+	}
+
+	switch c {
+	case CipherAesGcm:
+		aead, e := cipher.NewGCM(block)
+		if e != nil {
+			return nil, nil, e
+		}
+		iv = make([]byte, gcmIVLen)
+		if _, e = rand.Read(iv); e != nil {
+			return nil, nil, e
+		}
+		return iv, aead.Seal(nil, iv, plaintext, nil), nil
+	default:
+		iv = make([]byte, aes.BlockSize)
+		if _, e = rand.Read(iv); e != nil {
+			return nil, nil, e
+		}
+		padLen := aes.BlockSize - len(plaintext)%aes.BlockSize
+		ciphertext = append(append([]byte{}, plaintext...), bytes.Repeat([]byte{byte(padLen)}, padLen)...)
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+		return iv, ciphertext, nil
+	}
+}
+
+// aesDecrypt decrypts ciphertext.
+// The algorithm is determined from IV length.
+func aesDecrypt(key, iv, ciphertext []byte) (plaintext []byte, e error) {
+	block, e := aes.NewCipher(key)
+	if e != nil {
+		return nil, e
+	}
+
+	switch len(iv) {
+	case gcmIVLen:
+		aead, e := cipher.NewGCM(block)
+		if e != nil {
+			return nil, e
+		}
+		if plaintext, e = aead.Open(nil, iv, ciphertext, nil); e != nil {
+			return nil, ErrDecrypt
+		}
+		return plaintext, nil
+	case aes.BlockSize:
+		if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+			return nil, ErrDecrypt
+		}
+		plaintext = make([]byte, len(ciphertext))
+		cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+		padLen := int(plaintext[len(plaintext)-1])
+		if padLen == 0 || padLen > aes.BlockSize ||
+			!bytes.Equal(plaintext[len(plaintext)-padLen:], bytes.Repeat([]byte{byte(padLen)}, padLen)) {
+			return nil, ErrDecrypt
+		}
+		return plaintext[:len(plaintext)-padLen], nil
+	default:
+		return nil, ErrEncryptedContent
+	}
+}
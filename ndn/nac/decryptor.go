@@ -0,0 +1,144 @@
+package nac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+)
+
+// DecryptorOptions contains arguments to NewDecryptor function.
+type DecryptorOptions struct {
+	// ConsumerOptions retrieves CKs and KDKs.
+	// Verifier should verify CK Data is signed by a trusted producer, and KDK Data is signed by the access manager.
+	endpoint.ConsumerOptions
+
+	// Credentials is the member's RSA private key.
+	// Its certificate must have been added to the access manager with AccessManager.AddMember.
+	Credentials keychain.PrivateKey
+}
+
+// Decryptor decrypts content encrypted by Encryptor.
+// It retrieves and caches CKs and KDKs as needed.
+type Decryptor struct {
+	opts  DecryptorOptions
+	mutex sync.Mutex
+	cks   map[string][]byte              // CK name => CK
+	kdks  map[string]keychain.PrivateKey // KDK prefix => KDK
+}
+
+// Decrypt decrypts content.
+func (dec *Decryptor) Decrypt(ctx context.Context, ec EncryptedContent) (plaintext []byte, e error) {
+	if len(ec.Name) == 0 {
+		return nil, ErrEncryptedContent
+	}
+	ck, e := dec.getCK(ctx, ec.Name)
+	if e != nil {
+		return nil, e
+	}
+	return aesDecrypt(ck, ec.IV, ec.Payload)
+}
+
+// DecryptData decrypts content of a Data packet.
+// Caller should verify the Data packet.
+func (dec *Decryptor) DecryptData(ctx context.Context, data ndn.Data) (plaintext []byte, e error) {
+	ec, e := ParseEncryptedContent(data.Content)
+	if e != nil {
+		return nil, e
+	}
+	return dec.Decrypt(ctx, ec)
+}
+
+func (dec *Decryptor) getCK(ctx context.Context, ckName ndn.Name) (ck []byte, e error) {
+	dec.mutex.Lock()
+	ck = dec.cks[ckName.String()]
+	dec.mutex.Unlock()
+	if ck != nil {
+		return ck, nil
+	}
+
+	ckData, e := endpoint.Consume(ctx, ndn.MakeInterest(ckName, ndn.CanBePrefixFlag), dec.opts.ConsumerOptions)
+	if e != nil {
+		return nil, fmt.Errorf("CK: %w", e)
+	}
+	suffix := ckData.Name[len(ckName):]
+	if len(suffix) < 2 || !suffix[0].Equal(ComponentEncryptedBy) {
+		return nil, fmt.Errorf("CK: %w", ErrName)
+	}
+	kekName := suffix[1:]
+
+	kdk, e := dec.getKDK(ctx, kekName)
+	if e != nil {
+		return nil, e
+	}
+
+	ec, e := ParseEncryptedContent(ckData.Content)
+	if e != nil {
+		return nil, fmt.Errorf("CK: %w", e)
+	}
+	if ck, e = keychain.RSADecrypt(kdk, ec.Payload); e != nil {
+		return nil, fmt.Errorf("CK: %w: %v", ErrDecrypt, e)
+	}
+
+	dec.mutex.Lock()
+	defer dec.mutex.Unlock()
+	dec.cks[ckName.String()] = ck
+	return ck, nil
+}
+
+func (dec *Decryptor) getKDK(ctx context.Context, kekName ndn.Name) (kdk keychain.PrivateKey, e error) {
+	kdkPrefix, e := kekToKdkPrefix(kekName)
+	if e != nil {
+		return nil, fmt.Errorf("KEK: %w", e)
+	}
+
+	dec.mutex.Lock()
+	kdk = dec.kdks[kdkPrefix.String()]
+	dec.mutex.Unlock()
+	if kdk != nil {
+		return kdk, nil
+	}
+
+	kdkName := kdkPrefix.Append(ComponentEncryptedBy).Append(dec.opts.Credentials.Name()...)
+	kdkData, e := endpoint.Consume(ctx, ndn.MakeInterest(kdkName, ndn.MustBeFreshFlag), dec.opts.ConsumerOptions)
+	if e != nil {
+		return nil, fmt.Errorf("KDK: %w", e)
+	}
+
+	ec, e := ParseEncryptedContent(kdkData.Content)
+	if e != nil {
+		return nil, fmt.Errorf("KDK: %w", e)
+	}
+	secret, e := keychain.RSADecrypt(dec.opts.Credentials, ec.PayloadKey)
+	if e != nil {
+		return nil, fmt.Errorf("KDK: %w: %v", ErrDecrypt, e)
+	}
+	sb, e := keychain.ParseSafeBag(ec.Payload)
+	if e != nil {
+		return nil, fmt.Errorf("KDK: %w", e)
+	}
+	if kdk, _, e = sb.Decrypt(secret); e != nil {
+		return nil, fmt.Errorf("KDK: %w: %v", ErrDecrypt, e)
+	}
+
+	dec.mutex.Lock()
+	defer dec.mutex.Unlock()
+	dec.kdks[kdkPrefix.String()] = kdk
+	return kdk, nil
+}
+
+// NewDecryptor creates a Decryptor.
+func NewDecryptor(opts DecryptorOptions) (*Decryptor, error) {
+	if opts.Credentials == nil {
+		return nil, errors.New("credentials are required")
+	}
+	return &Decryptor{
+		opts: opts,
+		cks:  map[string][]byte{},
+		kdks: map[string]keychain.PrivateKey{},
+	}, nil
+}
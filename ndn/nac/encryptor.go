@@ -0,0 +1,182 @@
+package nac
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// EncryptorOptions contains arguments to NewEncryptor function.
+type EncryptorOptions struct {
+	// ConsumerOptions retrieves the KEK.
+	// Verifier should verify the KEK is signed by the access manager.
+	endpoint.ConsumerOptions
+
+	// AccessPrefix is the NAC prefix /<access-prefix>/NAC/<dataset>.
+	AccessPrefix ndn.Name
+
+	// CkPrefix is the name prefix of CK Data packets.
+	CkPrefix ndn.Name
+
+	// Signer signs CK Data packets.
+	// Default is keeping the Null signature.
+	Signer ndn.Signer
+
+	// Cipher selects the content encryption algorithm.
+	// Default is AES-CBC.
+	Cipher Cipher
+
+	// Freshness is the FreshnessPeriod of CK Data packets.
+	// Default is 1 hour.
+	Freshness time.Duration
+}
+
+func (opts *EncryptorOptions) applyDefaults() {
+	if opts.Freshness <= 0 {
+		opts.Freshness = time.Hour
+	}
+}
+
+// Encryptor encrypts content under a CK, and publishes the CK encrypted by the KEK.
+type Encryptor struct {
+	opts     EncryptorOptions
+	producer endpoint.Producer
+
+	mutex       sync.RWMutex
+	ckName      ndn.Name
+	ck          []byte
+	lastVersion uint64
+	cks         []ndn.Data
+}
+
+// CkName returns the current CK name.
+func (enc *Encryptor) CkName() ndn.Name {
+	enc.mutex.RLock()
+	defer enc.mutex.RUnlock()
+	return enc.ckName
+}
+
+// Encrypt encrypts content under the current CK.
+// The returned EncryptedContent should be encoded as Data content with tlv.EncodeFrom.
+func (enc *Encryptor) Encrypt(plaintext []byte) (ec EncryptedContent, e error) {
+	enc.mutex.RLock()
+	ckName, ck := enc.ckName, enc.ck
+	enc.mutex.RUnlock()
+
+	if ec.IV, ec.Payload, e = aesEncrypt(enc.opts.Cipher, ck, plaintext); e != nil {
+		return ec, e
+	}
+	ec.Name = ckName
+	return ec, nil
+}
+
+// EncryptData creates a Data packet whose content is encrypted under the current CK.
+// Caller should sign the Data packet.
+func (enc *Encryptor) EncryptData(name ndn.Name, plaintext []byte) (data ndn.Data, e error) {
+	ec, e := enc.Encrypt(plaintext)
+	if e != nil {
+		return data, e
+	}
+	content, e := tlv.EncodeFrom(ec)
+	if e != nil {
+		return data, e
+	}
+	return ndn.MakeData(name, content), nil
+}
+
+// RegenerateCK retrieves the KEK and generates a new CK.
+// Subsequent Encrypt calls use the new CK. Previous CKs continue to be published.
+func (enc *Encryptor) RegenerateCK(ctx context.Context) error {
+	interest := ndn.MakeInterest(enc.opts.AccessPrefix.Append(ComponentKEK), ndn.CanBePrefixFlag, ndn.MustBeFreshFlag)
+	kekData, e := endpoint.Consume(ctx, interest, enc.opts.ConsumerOptions)
+	if e != nil {
+		return fmt.Errorf("KEK: %w", e)
+	}
+	kekName := kekData.Name
+	if !kekName.Get(-2).Equal(ComponentKEK) {
+		return fmt.Errorf("KEK: %w", ErrName)
+	}
+	kek, e := keychain.ParsePublicKey(kekName.GetPrefix(-2).Append(keychain.ComponentKEY, kekName.Get(-1)), kekData.Content)
+	if e != nil {
+		return fmt.Errorf("KEK: %w", e)
+	}
+
+	ck := make([]byte, ckLen)
+	if _, e = rand.Read(ck); e != nil {
+		return e
+	}
+	var ec EncryptedContent
+	if ec.Payload, e = keychain.RSAEncrypt(kek, ck); e != nil {
+		return e
+	}
+
+	enc.mutex.Lock()
+	defer enc.mutex.Unlock()
+
+	version := uint64(time.Now().UnixMicro())
+	if version <= enc.lastVersion {
+		version = enc.lastVersion + 1
+	}
+	ckName := enc.opts.CkPrefix.Append(ComponentCK, ndn.VersionConvention.Make(version))
+	ckData, e := makeEncryptedData(ckName.Append(ComponentEncryptedBy).Append(kekName...), ec, enc.opts.Freshness)
+	if e != nil {
+		return e
+	}
+	if enc.opts.Signer != nil {
+		if e = enc.opts.Signer.Sign(&ckData); e != nil {
+			return e
+		}
+	}
+
+	enc.ckName, enc.ck, enc.lastVersion = ckName, ck, version
+	enc.cks = append(enc.cks, ckData)
+	return nil
+}
+
+// Close stops publishing CKs.
+func (enc *Encryptor) Close() error {
+	return enc.producer.Close()
+}
+
+func (enc *Encryptor) handle(ctx context.Context, interest ndn.Interest) (ndn.Data, error) {
+	enc.mutex.RLock()
+	defer enc.mutex.RUnlock()
+	for _, ckData := range enc.cks {
+		if ckData.CanSatisfy(interest) {
+			return ckData, nil
+		}
+	}
+	return ndn.Data{}, endpoint.ReplyNack(an.NackNoRoute)
+}
+
+// NewEncryptor creates an Encryptor.
+// It retrieves the KEK, generates the first CK, and starts publishing CKs.
+func NewEncryptor(ctx context.Context, opts EncryptorOptions) (enc *Encryptor, e error) {
+	opts.applyDefaults()
+	if len(opts.AccessPrefix) == 0 || len(opts.CkPrefix) == 0 {
+		return nil, errors.New("access prefix and CK prefix are required")
+	}
+
+	enc = &Encryptor{opts: opts}
+	if e = enc.RegenerateCK(ctx); e != nil {
+		return nil, e
+	}
+
+	if enc.producer, e = endpoint.Produce(ctx, endpoint.ProducerOptions{
+		Prefix:  opts.CkPrefix.Append(ComponentCK),
+		Handler: enc.handle,
+		Fw:      opts.Fw,
+	}); e != nil {
+		return nil, e
+	}
+	return enc, nil
+}
@@ -0,0 +1,186 @@
+package nac_test
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/core/testenv"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"github.com/usnistgov/ndn-dpdk/ndn/nac"
+	"github.com/usnistgov/ndn-dpdk/ndn/ndntestenv"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+	"go4.org/must"
+)
+
+var (
+	makeAR    = testenv.MakeAR
+	nameEqual = ndntestenv.NameEqual
+)
+
+func TestEncryptedContent(t *testing.T) {
+	assert, require := makeAR(t)
+
+	ec := nac.EncryptedContent{
+		Payload: []byte{0xA0, 0xA1},
+		IV:      []byte{0xB0},
+		Name:    ndn.ParseName("/C"),
+	}
+	wire, e := tlv.EncodeFrom(ec)
+	require.NoError(e)
+	assert.Equal([]byte{
+		0x82, 0x0C,
+		0x84, 0x02, 0xA0, 0xA1,
+		0x85, 0x01, 0xB0,
+		0x07, 0x03, 0x08, 0x01, 0x43,
+	}, wire)
+
+	decoded, e := nac.ParseEncryptedContent(wire)
+	require.NoError(e)
+	assert.Equal(ec.Payload, decoded.Payload)
+	assert.Equal(ec.IV, decoded.IV)
+	assert.Len(decoded.PayloadKey, 0)
+	nameEqual(assert, ec.Name, decoded.Name)
+
+	_, e = nac.ParseEncryptedContent([]byte{0x82, 0x00})
+	assert.ErrorIs(e, nac.ErrEncryptedContent)
+	_, e = nac.ParseEncryptedContent([]byte{0x83, 0x00})
+	assert.Error(e)
+}
+
+func testEncryptDecrypt(t *testing.T, cipher nac.Cipher) {
+	assert, require := makeAR(t)
+	fw := l3.NewForwarder()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	idPvt, idPub, e := keychain.NewECDSAKeyPair(ndn.ParseName("/access"))
+	require.NoError(e)
+	am, e := nac.NewAccessManager(ctx, nac.AccessManagerOptions{
+		Identity: ndn.ParseName("/access"),
+		Dataset:  ndn.ParseName("/dataset"),
+		Signer:   idPvt,
+		Fw:       fw,
+	})
+	require.NoError(e)
+	defer must.Close(am)
+	assert.Equal("/8=access/8=NAC/8=dataset", am.Prefix().String())
+
+	makeMember := func(subject string) (keychain.PrivateKey, *keychain.Certificate) {
+		pvt, pub, e := keychain.NewRSAKeyPair(ndn.ParseName(subject))
+		require.NoError(e)
+		cert, e := keychain.MakeCert(pub, pvt, keychain.MakeCertOptions{})
+		require.NoError(e)
+		return pvt, cert
+	}
+	makeDecryptor := func(pvt keychain.PrivateKey) *nac.Decryptor {
+		dec, e := nac.NewDecryptor(nac.DecryptorOptions{
+			ConsumerOptions: endpoint.ConsumerOptions{Fw: fw},
+			Credentials:     pvt,
+		})
+		require.NoError(e)
+		return dec
+	}
+
+	memberPvt, memberCert := makeMember("/member")
+	kdk, e := am.AddMember(memberCert)
+	require.NoError(e)
+	assert.True(am.KekName().Get(-2).Equal(nac.ComponentKEK))
+	assert.Equal(am.KekName().Get(-1), kdk.Name.Get(4))
+	nameEqual(assert, memberPvt, kdk.Name.Slice(6))
+
+	producerPvt, producerPub, e := keychain.NewECDSAKeyPair(ndn.ParseName("/producer"))
+	require.NoError(e)
+	enc, e := nac.NewEncryptor(ctx, nac.EncryptorOptions{
+		ConsumerOptions: endpoint.ConsumerOptions{Fw: fw, Verifier: idPub},
+		AccessPrefix:    am.Prefix(),
+		CkPrefix:        ndn.ParseName("/producer"),
+		Signer:          producerPvt,
+		Cipher:          cipher,
+	})
+	require.NoError(e)
+	defer must.Close(enc)
+	ckName0 := enc.CkName()
+	assert.Equal("/8=producer/8=CK", ckName0.GetPrefix(2).String())
+
+	content0 := make([]byte, 3000)
+	rand.Read(content0)
+	data0, e := enc.EncryptData(ndn.ParseName("/producer/data/0"), content0)
+	require.NoError(e)
+	require.NoError(producerPvt.Sign(&data0))
+	require.NoError(producerPub.Verify(data0))
+
+	require.NoError(enc.RegenerateCK(ctx))
+	ckName1 := enc.CkName()
+	assert.False(ckName1.Equal(ckName0))
+	content1 := []byte("hello")
+	ec1, e := enc.Encrypt(content1)
+	require.NoError(e)
+	nameEqual(assert, ckName1, ec1.Name)
+
+	dec := makeDecryptor(memberPvt)
+	plaintext0, e := dec.DecryptData(ctx, data0)
+	require.NoError(e)
+	assert.Equal(content0, plaintext0)
+	plaintext1, e := dec.Decrypt(ctx, ec1)
+	require.NoError(e)
+	assert.Equal(content1, plaintext1)
+
+	ec1.Payload[len(ec1.Payload)-1] ^= 0xFF
+	_, e = dec.Decrypt(ctx, ec1)
+	if cipher == nac.CipherAesGcm {
+		assert.ErrorIs(e, nac.ErrDecrypt)
+	}
+
+	otherPvt, _ := makeMember("/other")
+	removedPvt, removedCert := makeMember("/removed")
+	_, e = am.AddMember(removedCert)
+	require.NoError(e)
+	am.RemoveMember(removedCert.Name())
+	for _, pvt := range []keychain.PrivateKey{otherPvt, removedPvt} {
+		ctx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+		_, e = makeDecryptor(pvt).DecryptData(ctx, data0)
+		cancel()
+		assert.Error(e)
+	}
+}
+
+func TestCbc(t *testing.T) {
+	testEncryptDecrypt(t, nac.CipherAesCbc)
+}
+
+func TestGcm(t *testing.T) {
+	testEncryptDecrypt(t, nac.CipherAesGcm)
+}
+
+func TestWrongKek(t *testing.T) {
+	_, require := makeAR(t)
+	fw := l3.NewForwarder()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	idPvt, _, e := keychain.NewECDSAKeyPair(ndn.ParseName("/access"))
+	require.NoError(e)
+	am, e := nac.NewAccessManager(ctx, nac.AccessManagerOptions{
+		Identity: ndn.ParseName("/access"),
+		Dataset:  ndn.ParseName("/dataset"),
+		Signer:   idPvt,
+		Fw:       fw,
+	})
+	require.NoError(e)
+	defer must.Close(am)
+
+	_, wrongPub, e := keychain.NewECDSAKeyPair(ndn.ParseName("/wrong"))
+	require.NoError(e)
+
+	_, e = nac.NewEncryptor(ctx, nac.EncryptorOptions{
+		ConsumerOptions: endpoint.ConsumerOptions{Fw: fw, Verifier: wrongPub},
+		AccessPrefix:    am.Prefix(),
+		CkPrefix:        ndn.ParseName("/producer"),
+	})
+	require.Error(e)
+}
@@ -0,0 +1,111 @@
+// Package nac implements Name-based Access Control (NAC) content encryption.
+// https://github.com/named-data/name-based-access-control
+//
+// An access manager publishes a key-encryption key (KEK) and, for each authorized member, a key-decryption key (KDK)
+// that is encrypted to the member's RSA public key.
+// A producer encrypts content with a content key (CK), and publishes the CK encrypted by the KEK.
+// A consumer retrieves the CK and its KDK to decrypt the content.
+//
+// Naming conventions and TLV encoding are compatible with the ndn-cxx NAC library:
+//   - KEK: /<access-prefix>/NAC/<dataset>/KEK/<key-id>
+//   - KDK: /<access-prefix>/NAC/<dataset>/KDK/<key-id>/ENCRYPTED-BY/<member-key-name>
+//   - CK: /<ck-prefix>/CK/<ck-id>/ENCRYPTED-BY/<kek-name>
+package nac
+
+import (
+	"errors"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// TLV-TYPE assigned numbers.
+const (
+	TtEncryptedContent     = 0x82
+	TtEncryptedPayload     = 0x84
+	TtInitializationVector = 0x85
+	TtEncryptedPayloadKey  = 0x86
+)
+
+// Name components in NAC.
+var (
+	ComponentNAC         = ndn.ParseNameComponent("NAC")
+	ComponentKEK         = ndn.ParseNameComponent("KEK")
+	ComponentKDK         = ndn.ParseNameComponent("KDK")
+	ComponentCK          = ndn.ParseNameComponent("CK")
+	ComponentEncryptedBy = ndn.ParseNameComponent("ENCRYPTED-BY")
+)
+
+// Error conditions.
+var (
+	ErrEncryptedContent = errors.New("bad EncryptedContent")
+	ErrName             = errors.New("bad NAC name")
+	ErrDecrypt          = errors.New("cannot decrypt")
+)
+
+// EncryptedContent is the encrypted payload in content of a NAC Data packet.
+//
+// In encrypted application content, Payload is AES ciphertext, IV is the initialization vector,
+// and Name is the CK name.
+// In CK Data, Payload is the CK encrypted by the KEK.
+// In KDK Data, Payload is a SafeBag of the KDK, and PayloadKey is the SafeBag passphrase encrypted by the member key.
+type EncryptedContent struct {
+	Payload    []byte   `tlv:"0x84"`
+	IV         []byte   `tlv:"0x85,omitempty"`
+	PayloadKey []byte   `tlv:"0x86,omitempty"`
+	Name       ndn.Name `tlv:"0x07,omitempty"`
+}
+
+var (
+	_ tlv.Fielder     = EncryptedContent{}
+	_ tlv.Unmarshaler = (*EncryptedContent)(nil)
+)
+
+// Field implements tlv.Fielder interface.
+func (ec EncryptedContent) Field() tlv.Field {
+	value, e := tlv.Marshal(ec)
+	if e != nil {
+		return tlv.FieldError(e)
+	}
+	return tlv.TLVBytes(TtEncryptedContent, value)
+}
+
+// UnmarshalTLV decodes from wire format.
+func (ec *EncryptedContent) UnmarshalTLV(typ uint32, value []byte) error {
+	if typ != TtEncryptedContent {
+		return tlv.ErrType
+	}
+	*ec = EncryptedContent{}
+	if e := tlv.Unmarshal(value, ec); e != nil {
+		return e
+	}
+	if len(ec.Payload) == 0 {
+		return ErrEncryptedContent
+	}
+	return nil
+}
+
+// ParseEncryptedContent decodes EncryptedContent from Data content.
+func ParseEncryptedContent(content []byte) (ec EncryptedContent, e error) {
+	e = tlv.Decode(content, &ec)
+	return ec, e
+}
+
+// makeEncryptedData creates a Data packet whose content is EncryptedContent.
+func makeEncryptedData(name ndn.Name, ec EncryptedContent, freshness time.Duration) (data ndn.Data, e error) {
+	content, e := tlv.EncodeFrom(ec)
+	if e != nil {
+		return data, e
+	}
+	return ndn.MakeData(name, freshness, content), nil
+}
+
+// kekToKdkPrefix converts a KEK name to KDK name prefix, i.e. KDK name without ENCRYPTED-BY suffix.
+func kekToKdkPrefix(kekName ndn.Name) (ndn.Name, error) {
+	if !kekName.Get(-2).Equal(ComponentKEK) {
+		return nil, ErrName
+	}
+	kdkPrefix := kekName.GetPrefix(-2).Append(ComponentKDK, kekName.Get(-1))
+	return kdkPrefix, nil
+}
@@ -122,8 +122,13 @@ func NameComponentFrom(typ uint32, value tlv.Fielder) NameComponent {
 }
 
 // ParseNameComponent parses URI representation of name component.
+// It accepts alternate URI representation of NamingConventions, such as "seg=1".
 // It uses best effort and can accept any input.
 func ParseNameComponent(input string) (comp NameComponent) {
+	if comp, ok := parseAltNameComponent(input); ok {
+		return comp
+	}
+
 	comp.Type = uint32(an.TtGenericNameComponent)
 	pos := strings.IndexByte(input, '=')
 	if pos >= 0 {
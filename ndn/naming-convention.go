@@ -0,0 +1,152 @@
+package ndn
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// NamingConvention represents a typed naming convention, as defined in NDN naming conventions rev3.
+// Each convention uses a distinct name component TLV-TYPE, whose TLV-VALUE is a NonNegativeInteger.
+type NamingConvention struct {
+	// Type is the name component TLV-TYPE.
+	Type uint32
+
+	// Alt is the alternate URI prefix, such as "seg" in "seg=1".
+	Alt string
+}
+
+// Naming conventions.
+var (
+	// SegmentConvention is the segment number convention.
+	SegmentConvention = NamingConvention{an.TtSegmentNameComponent, "seg"}
+
+	// ByteOffsetConvention is the byte offset convention.
+	ByteOffsetConvention = NamingConvention{an.TtByteOffsetNameComponent, "off"}
+
+	// VersionConvention is the version convention.
+	VersionConvention = NamingConvention{an.TtVersionNameComponent, "v"}
+
+	// TimestampConvention is the timestamp convention.
+	// Its value is microseconds since Unix epoch.
+	TimestampConvention = NamingConvention{an.TtTimestampNameComponent, "t"}
+
+	// SequenceNumConvention is the sequence number convention.
+	SequenceNumConvention = NamingConvention{an.TtSequenceNumNameComponent, "seq"}
+)
+
+// NamingConventions lists known naming conventions.
+var NamingConventions = []NamingConvention{
+	SegmentConvention,
+	ByteOffsetConvention,
+	VersionConvention,
+	TimestampConvention,
+	SequenceNumConvention,
+}
+
+// Make constructs a name component.
+func (c NamingConvention) Make(v uint64) NameComponent {
+	return MakeNameComponent(c.Type, tlv.NNI(v).Encode(nil))
+}
+
+// MakeTime constructs a name component from a timestamp.
+// It is meaningful for TimestampConvention and VersionConvention, where the value is microseconds since Unix epoch.
+func (c NamingConvention) MakeTime(t time.Time) NameComponent {
+	return c.Make(uint64(t.UnixMicro()))
+}
+
+// Match determines whether comp follows this convention.
+func (c NamingConvention) Match(comp NameComponent) bool {
+	_, ok := c.Parse(comp)
+	return ok
+}
+
+// Parse extracts the value from a name component.
+// ok is false if comp does not follow this convention.
+func (c NamingConvention) Parse(comp NameComponent) (v uint64, ok bool) {
+	if comp.Type != c.Type {
+		return 0, false
+	}
+	var n tlv.NNI
+	if e := n.UnmarshalBinary(comp.Value); e != nil {
+		return 0, false
+	}
+	return uint64(n), true
+}
+
+// ParseTime extracts a timestamp from a name component.
+// It is meaningful for TimestampConvention and VersionConvention, where the value is microseconds since Unix epoch.
+func (c NamingConvention) ParseTime(comp NameComponent) (t time.Time, ok bool) {
+	v, ok := c.Parse(comp)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.UnixMicro(int64(v)), true
+}
+
+// FormatAlt returns alternate URI representation of comp, such as "seg=1".
+// If comp does not follow this convention, returns canonical URI representation.
+func (c NamingConvention) FormatAlt(comp NameComponent) string {
+	v, ok := c.Parse(comp)
+	if !ok {
+		return comp.String()
+	}
+	return c.Alt + "=" + strconv.FormatUint(v, 10)
+}
+
+// AltString returns alternate URI representation of this component.
+// If it follows one of NamingConventions, it is written like "seg=1".
+// Otherwise, it is same as String().
+func (comp NameComponent) AltString() string {
+	for _, c := range NamingConventions {
+		if c.Match(comp) {
+			return c.FormatAlt(comp)
+		}
+	}
+	return comp.String()
+}
+
+// parseAltNameComponent parses alternate URI representation of a naming convention component.
+func parseAltNameComponent(input string) (comp NameComponent, ok bool) {
+	alt, value, found := strings.Cut(input, "=")
+	if !found {
+		return comp, false
+	}
+	for _, c := range NamingConventions {
+		if c.Alt == alt {
+			v, e := strconv.ParseUint(value, 10, 64)
+			if e != nil {
+				return comp, false
+			}
+			return c.Make(v), true
+		}
+	}
+	return comp, false
+}
+
+// FindConvention returns the index and value of the last component following naming convention c.
+// If no such component exists, index is -1.
+func (name Name) FindConvention(c NamingConvention) (index int, v uint64) {
+	for i := len(name) - 1; i >= 0; i-- {
+		if v, ok := c.Parse(name[i]); ok {
+			return i, v
+		}
+	}
+	return -1, 0
+}
+
+// ReplaceConvention returns a copy of this name, where the last component following naming convention c
+// is replaced with value v.
+// If no such component exists, the new component is appended.
+func (name Name) ReplaceConvention(c NamingConvention, v uint64) (copy Name) {
+	copy = name.Append()
+	comp := c.Make(v)
+	if i, _ := name.FindConvention(c); i >= 0 {
+		copy[i] = comp
+		return copy
+	}
+	return append(copy, comp)
+}
@@ -0,0 +1,74 @@
+package ndn_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+)
+
+func TestNamingConvention(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	tests := []struct {
+		c   ndn.NamingConvention
+		v   uint64
+		str string
+		alt string
+	}{
+		{c: ndn.SegmentConvention, v: 0, str: "50=%00", alt: "seg=0"},
+		{c: ndn.ByteOffsetConvention, v: 0x0102, str: "52=%01%02", alt: "off=258"},
+		{c: ndn.VersionConvention, v: 0x01020304, str: "54=%01%02%03%04", alt: "v=16909060"},
+		{c: ndn.TimestampConvention, v: 0x0102030405, str: "56=%00%00%00%01%02%03%04%05", alt: "t=4328719365"},
+		{c: ndn.SequenceNumConvention, v: 0xFF, str: "58=%FF", alt: "seq=255"},
+	}
+	for _, tt := range tests {
+		comp := tt.c.Make(tt.v)
+		assert.Equal(tt.str, comp.String())
+		assert.Equal(tt.alt, comp.AltString())
+		assert.Equal(tt.alt, tt.c.FormatAlt(comp))
+		assert.True(tt.c.Match(comp))
+		v, ok := tt.c.Parse(comp)
+		assert.True(ok)
+		assert.Equal(tt.v, v)
+
+		assert.True(comp.Equal(ndn.ParseNameComponent(tt.str)), tt.str)
+		assert.True(comp.Equal(ndn.ParseNameComponent(tt.alt)), tt.alt)
+	}
+
+	generic := ndn.ParseNameComponent("A")
+	assert.False(ndn.SegmentConvention.Match(generic))
+	assert.Equal("8=A", generic.AltString())
+	assert.Equal("8=A", ndn.SegmentConvention.FormatAlt(generic))
+	assert.False(ndn.SegmentConvention.Match(ndn.VersionConvention.Make(1)))
+	assert.False(ndn.SegmentConvention.Match(ndn.ParseNameComponent("50=%01%02%03")))
+	assert.False(ndn.SegmentConvention.Match(ndn.ParseNameComponent("seg=A")))
+	assert.Equal("8=xyz%3D1", ndn.ParseNameComponent("xyz=1").String())
+
+	now := time.UnixMicro(time.Now().UnixMicro())
+	ts, ok := ndn.TimestampConvention.ParseTime(ndn.TimestampConvention.MakeTime(now))
+	assert.True(ok)
+	assert.True(now.Equal(ts))
+	_, ok = ndn.TimestampConvention.ParseTime(generic)
+	assert.False(ok)
+}
+
+func TestNameConvention(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	name := ndn.ParseName("/A/v=1/B/v=2/seg=3")
+	nameEqual(assert, "/A/54=%01/B/54=%02/50=%03", name)
+
+	i, v := name.FindConvention(ndn.VersionConvention)
+	assert.Equal(3, i)
+	assert.EqualValues(2, v)
+	i, _ = name.FindConvention(ndn.TimestampConvention)
+	assert.Equal(-1, i)
+
+	replaced := name.ReplaceConvention(ndn.SegmentConvention, 4)
+	nameEqual(assert, "/A/v=1/B/v=2/seg=4", replaced)
+	nameEqual(assert, "/A/v=1/B/v=2/seg=3", name)
+	replaced = name.ReplaceConvention(ndn.SequenceNumConvention, 5)
+	nameEqual(assert, "/A/v=1/B/v=2/seg=3/seq=5", replaced)
+	nameEqual(assert, "/A/v=1/B/v=2/seg=3", name)
+}
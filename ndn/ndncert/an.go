@@ -0,0 +1,57 @@
+// Package ndncert implements NDNCERT certificate management protocol v0.3.
+// https://github.com/named-data/ndncert/wiki/NDNCERT-Protocol-0.3
+package ndncert
+
+// TLV-TYPE assigned numbers.
+const (
+	TtCaPrefix             = 0x81
+	TtCaInfo               = 0x83
+	TtParameterKey         = 0x85
+	TtParameterValue       = 0x87
+	TtCaCertificate        = 0x89
+	TtMaxValidityPeriod    = 0x8B
+	TtProbeResponse        = 0x8D
+	TtMaxSuffixLength      = 0x8F
+	TtEcdhPub              = 0x91
+	TtCertRequest          = 0x93
+	TtSalt                 = 0x95
+	TtRequestID            = 0x97
+	TtChallenge            = 0x99
+	TtStatus               = 0x9B
+	TtInitializationVector = 0x9D
+	TtEncryptedPayload     = 0x9F
+	TtSelectedChallenge    = 0xA1
+	TtChallengeStatus      = 0xA3
+	TtRemainingTries       = 0xA5
+	TtRemainingTime        = 0xA7
+	TtIssuedCertName       = 0xA9
+	TtErrorCode            = 0xAB
+	TtErrorInfo            = 0xAD
+	TtAuthenticationTag    = 0xAF
+	TtCertToRevoke         = 0xB1
+	TtProbeRedirect        = 0xB3
+
+	_ = "enumgen::TtNdncert:Tt"
+)
+
+// Status assigned numbers.
+const (
+	StatusBeforeChallenge = 0
+	StatusChallenge       = 1
+	StatusPending         = 2
+	StatusSuccess         = 3
+	StatusFailure         = 4
+)
+
+// ErrorCode assigned numbers.
+const (
+	ErrorBadInterestFormat  = 1
+	ErrorBadParameterFormat = 2
+	ErrorBadSignature       = 3
+	ErrorInvalidParameter   = 4
+	ErrorNameNotAllowed     = 5
+	ErrorBadValidityPeriod  = 6
+	ErrorOutOfTries         = 7
+	ErrorOutOfTime          = 8
+	ErrorNoAvailableNames   = 9
+)
@@ -0,0 +1,87 @@
+package ndncert
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+)
+
+const (
+	pinChallengeName = "pin"
+	pinParamCode     = "code"
+	pinNeedCode      = "need-code"
+	pinWrongCode     = "wrong-code"
+)
+
+// ServerPinChallenge is the CA side of PIN challenge.
+// The CA generates a 6-digit code, which should be delivered to the requester out of band.
+type ServerPinChallenge struct {
+	// NotifyPin is invoked when a PIN code is generated.
+	// It should deliver the PIN code to the requester or the operator.
+	NotifyPin func(requestID []byte, certRequest *keychain.Certificate, pin string)
+}
+
+var _ ServerChallenge = (*ServerPinChallenge)(nil)
+
+// Name returns "pin".
+func (ServerPinChallenge) Name() string {
+	return pinChallengeName
+}
+
+// Process handles a CHALLENGE request.
+func (ch *ServerPinChallenge) Process(ctx context.Context, req ServerChallengeRequest) (res ServerChallengeResponse, e error) {
+	pin, ok := req.State.(string)
+	if !ok {
+		n, e := rand.Int(rand.Reader, big.NewInt(1000000))
+		if e != nil {
+			return res, e
+		}
+		pin = fmt.Sprintf("%06d", n)
+		if ch.NotifyPin != nil {
+			ch.NotifyPin(req.RequestID, req.CertRequest, pin)
+		}
+		return ServerChallengeResponse{ChallengeStatus: pinNeedCode, State: pin}, nil
+	}
+
+	code := req.Parameters[pinParamCode]
+	if subtle.ConstantTimeCompare(code, []byte(pin)) == 1 {
+		return ServerChallengeResponse{Success: true}, nil
+	}
+	return ServerChallengeResponse{ChallengeStatus: pinWrongCode, State: pin, DecrementTries: true}, nil
+}
+
+// ClientPinChallenge is the requester side of PIN challenge.
+type ClientPinChallenge struct {
+	// Prompt asks the user for the PIN code.
+	Prompt func(ctx context.Context) (string, error)
+}
+
+var _ ClientChallenge = (*ClientPinChallenge)(nil)
+
+// Name returns "pin".
+func (ClientPinChallenge) Name() string {
+	return pinChallengeName
+}
+
+// Next creates parameters for the next CHALLENGE request.
+func (ch *ClientPinChallenge) Next(ctx context.Context, challengeStatus string, params Parameters) (Parameters, error) {
+	switch challengeStatus {
+	case "":
+		return Parameters{}, nil
+	case pinNeedCode, pinWrongCode:
+		if ch.Prompt == nil {
+			return nil, errors.New("PIN prompt not available")
+		}
+		pin, e := ch.Prompt(ctx)
+		if e != nil {
+			return nil, e
+		}
+		return Parameters{pinParamCode: []byte(pin)}, nil
+	}
+	return nil, fmt.Errorf("%w: unexpected challenge status %s", ErrMessage, challengeStatus)
+}
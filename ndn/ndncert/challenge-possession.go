@@ -0,0 +1,188 @@
+package ndncert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+const (
+	possessionChallengeName = "possession"
+	possessionParamCert     = "issued-cert"
+	possessionParamNonce    = "nonce"
+	possessionParamProof    = "proof"
+	possessionNeedProof     = "need-proof"
+	possessionNonceLen      = 16
+)
+
+// ErrPossession indicates the requester failed to prove possession of an existing certificate.
+var ErrPossession = errors.New("proof of possession failed")
+
+// rawSigned is a Signable and Verifiable that signs a byte string directly.
+type rawSigned struct {
+	input []byte
+	si    ndn.SigInfo
+	sig   []byte
+}
+
+func (r *rawSigned) SignWith(signer func(name ndn.Name, si *ndn.SigInfo) (ndn.LLSign, error)) (e error) {
+	llSign, e := signer(nil, &r.si)
+	if e != nil {
+		return e
+	}
+	r.sig, e = llSign(r.input)
+	return e
+}
+
+func (r rawSigned) VerifyWith(verifier func(name ndn.Name, si ndn.SigInfo) (ndn.LLVerify, error)) error {
+	llVerify, e := verifier(nil, r.si)
+	if e != nil {
+		return e
+	}
+	return llVerify(r.input, r.sig)
+}
+
+// sigTypeFromCert determines the signature type usable with the public key in a certificate.
+func sigTypeFromCert(cert keychain.Certificate) (uint32, error) {
+	key, e := x509.ParsePKIXPublicKey(cert.Data().Content)
+	if e != nil {
+		return 0, e
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey:
+		return an.SigSha256WithEcdsa, nil
+	case *rsa.PublicKey:
+		return an.SigSha256WithRsa, nil
+	case ed25519.PublicKey:
+		return an.SigEd25519, nil
+	}
+	return 0, keychain.ErrX509PublicKey
+}
+
+type possessionState struct {
+	cert  *keychain.Certificate
+	nonce []byte
+}
+
+// ServerPossessionChallenge is the CA side of proof-of-possession challenge.
+// The requester must possess the private key of an existing certificate trusted by the CA.
+// The existing certificate must be within its ValidityPeriod,
+// and the requested subject name must be under the subject name of the existing certificate.
+type ServerPossessionChallenge struct {
+	// Verifier verifies the existing certificate, such as a keychain.Validator.
+	Verifier ndn.Verifier
+}
+
+var _ ServerChallenge = (*ServerPossessionChallenge)(nil)
+
+// Name returns "possession".
+func (ServerPossessionChallenge) Name() string {
+	return possessionChallengeName
+}
+
+// Process handles a CHALLENGE request.
+func (ch *ServerPossessionChallenge) Process(ctx context.Context, req ServerChallengeRequest) (res ServerChallengeResponse, e error) {
+	state, ok := req.State.(possessionState)
+	if !ok {
+		var data ndn.Data
+		d := tlv.DecodingBuffer(req.Parameters[possessionParamCert])
+		de, e := d.Element()
+		if e == nil && de.Type != an.TtData {
+			e = ErrMessage
+		}
+		if e == nil {
+			e = data.UnmarshalBinary(de.Value)
+		}
+		if e != nil {
+			return res, fmt.Errorf("%w: %v", ErrPossession, e)
+		}
+		if state.cert, e = keychain.CertFromData(data); e != nil {
+			return res, fmt.Errorf("%w: %v", ErrPossession, e)
+		}
+		if !state.cert.Validity().Includes(time.Now()) {
+			return res, fmt.Errorf("%w: certificate is outside ValidityPeriod", ErrPossession)
+		}
+		if !state.cert.SubjectName().IsPrefixOf(req.CertRequest.SubjectName()) {
+			return res, fmt.Errorf("%w: certificate subject does not cover requested name", ErrPossession)
+		}
+		if ch.Verifier == nil || ch.Verifier.Verify(data) != nil {
+			return res, fmt.Errorf("%w: certificate not trusted", ErrPossession)
+		}
+		state.nonce = make([]byte, possessionNonceLen)
+		if _, e = rand.Read(state.nonce); e != nil {
+			return res, e
+		}
+		return ServerChallengeResponse{
+			ChallengeStatus: possessionNeedProof,
+			Parameters:      Parameters{possessionParamNonce: state.nonce},
+			State:           state,
+		}, nil
+	}
+
+	sigType, e := sigTypeFromCert(*state.cert)
+	if e != nil {
+		return res, e
+	}
+	proof := rawSigned{
+		input: state.nonce,
+		si: ndn.SigInfo{
+			Type:       sigType,
+			KeyLocator: ndn.KeyLocator{Name: state.cert.Name()},
+		},
+		sig: req.Parameters[possessionParamProof],
+	}
+	if e = state.cert.PublicKey().Verify(proof); e != nil {
+		return res, fmt.Errorf("%w: %v", ErrPossession, e)
+	}
+	return ServerChallengeResponse{Success: true}, nil
+}
+
+// ClientPossessionChallenge is the requester side of proof-of-possession challenge.
+type ClientPossessionChallenge struct {
+	// Cert is the existing certificate.
+	Cert *keychain.Certificate
+
+	// Signer is the private key of the existing certificate.
+	Signer ndn.Signer
+}
+
+var _ ClientChallenge = (*ClientPossessionChallenge)(nil)
+
+// Name returns "possession".
+func (ClientPossessionChallenge) Name() string {
+	return possessionChallengeName
+}
+
+// Next creates parameters for the next CHALLENGE request.
+func (ch *ClientPossessionChallenge) Next(ctx context.Context, challengeStatus string, params Parameters) (Parameters, error) {
+	switch challengeStatus {
+	case "":
+		wire, e := tlv.EncodeFrom(ch.Cert.Data())
+		if e != nil {
+			return nil, e
+		}
+		return Parameters{possessionParamCert: wire}, nil
+	case possessionNeedProof:
+		nonce := params[possessionParamNonce]
+		if len(nonce) != possessionNonceLen {
+			return nil, fmt.Errorf("%w: bad nonce", ErrMessage)
+		}
+		proof := rawSigned{input: nonce}
+		if e := ch.Signer.Sign(&proof); e != nil {
+			return nil, e
+		}
+		return Parameters{possessionParamProof: proof.sig}, nil
+	}
+	return nil, fmt.Errorf("%w: unexpected challenge status %s", ErrMessage, challengeStatus)
+}
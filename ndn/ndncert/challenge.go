@@ -0,0 +1,64 @@
+package ndncert
+
+import (
+	"context"
+
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+)
+
+// ServerChallengeRequest contains information about a CHALLENGE request, passed to ServerChallenge.
+type ServerChallengeRequest struct {
+	// RequestID is the request identifier assigned in NEW step.
+	RequestID []byte
+
+	// CertRequest is the self-signed certificate submitted in NEW step.
+	CertRequest *keychain.Certificate
+
+	// Parameters are the challenge parameters submitted in this step.
+	Parameters Parameters
+
+	// State is the challenge-specific state returned by the previous step.
+	// It is nil in the first CHALLENGE request.
+	State interface{}
+}
+
+// ServerChallengeResponse contains the outcome of a CHALLENGE step, returned by ServerChallenge.
+type ServerChallengeResponse struct {
+	// Success indicates the requester has passed the challenge.
+	// If true, the CA issues the certificate.
+	Success bool
+
+	// ChallengeStatus is a challenge-specific status string sent to the requester.
+	ChallengeStatus string
+
+	// Parameters are sent to the requester.
+	Parameters Parameters
+
+	// State is the challenge-specific state, passed to the next step.
+	State interface{}
+
+	// DecrementTries indicates the requester has made an incorrect attempt.
+	// The request fails when it runs out of tries.
+	DecrementTries bool
+}
+
+// ServerChallenge is the CA side of a challenge.
+type ServerChallenge interface {
+	// Name returns the challenge type, such as "pin".
+	Name() string
+
+	// Process handles a CHALLENGE request.
+	// Returning an error causes the request to fail.
+	Process(ctx context.Context, req ServerChallengeRequest) (ServerChallengeResponse, error)
+}
+
+// ClientChallenge is the requester side of a challenge.
+type ClientChallenge interface {
+	// Name returns the challenge type, such as "pin".
+	Name() string
+
+	// Next creates parameters for the next CHALLENGE request.
+	// challengeStatus and params are from the previous CHALLENGE response;
+	// they are empty in the first CHALLENGE request.
+	Next(ctx context.Context, challengeStatus string, params Parameters) (Parameters, error)
+}
@@ -0,0 +1,231 @@
+package ndncert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// Error conditions for client.
+var (
+	ErrChallengeUnavailable = errors.New("challenge not offered by CA")
+	ErrChallengeFailure     = errors.New("challenge failed")
+)
+
+// FetchProfile retrieves the CA profile.
+//
+// The profile is verified against the CA certificate within, which only proves its integrity.
+// The caller must decide whether to trust the CA certificate, such as comparing with a known certificate.
+func FetchProfile(ctx context.Context, caPrefix ndn.Name, opts endpoint.ConsumerOptions) (*CaProfile, error) {
+	interest := ndn.MakeInterest(caPrefix.Append(ComponentCA, ComponentINFO), ndn.CanBePrefixFlag, ndn.MustBeFreshFlag)
+	data, e := endpoint.Consume(ctx, interest, opts)
+	if e != nil {
+		return nil, e
+	}
+
+	var profile CaProfile
+	if e = profile.UnmarshalBinary(data.Content); e != nil {
+		return nil, e
+	}
+	if !profile.Prefix.Equal(caPrefix) {
+		return nil, fmt.Errorf("%w: CA prefix mismatch", ErrMessage)
+	}
+	if e = profile.Cert.PublicKey().Verify(data); e != nil {
+		return nil, e
+	}
+	return &profile, nil
+}
+
+// consumeCommand sends a command Interest to the CA and returns the response content.
+func consumeCommand(ctx context.Context, profile CaProfile, opts endpoint.ConsumerOptions,
+	name ndn.Name, appParams []byte, signer ndn.Signer) ([]byte, error) {
+	interest := ndn.Interest{
+		Name:          name,
+		MustBeFresh:   true,
+		AppParameters: appParams,
+	}
+	if signer != nil {
+		if e := signer.Sign(&interest); e != nil {
+			return nil, e
+		}
+	} else {
+		interest.UpdateParamsDigest()
+	}
+
+	opts.Verifier = profile.Cert.PublicKey()
+	data, e := endpoint.Consume(ctx, interest, opts)
+	if e != nil {
+		return nil, e
+	}
+	if e = checkErrorResponse(data.Content); e != nil {
+		return nil, e
+	}
+	return data.Content, nil
+}
+
+// Probe sends a PROBE request and returns available names.
+func Probe(ctx context.Context, profile CaProfile, params Parameters, opts endpoint.ConsumerOptions) ([]ndn.Name, error) {
+	appParams, e := tlv.Encode(params.Fields()...)
+	if e != nil {
+		return nil, e
+	}
+	content, e := consumeCommand(ctx, profile, opts, profile.Prefix.Append(ComponentCA, ComponentPROBE), appParams, nil)
+	if e != nil {
+		return nil, e
+	}
+
+	var res probeResponse
+	if e = res.UnmarshalBinary(content); e != nil {
+		return nil, e
+	}
+	return res.Names, nil
+}
+
+// ClientOptions contains arguments to Request function.
+type ClientOptions struct {
+	// ConsumerOptions includes setting L3 forwarder and retransmission policy.
+	// Verifier will be overwritten.
+	endpoint.ConsumerOptions
+
+	// Profile is the CA profile.
+	Profile CaProfile
+
+	// PrivateKey is the requester private key.
+	// Its subject name must be under the CA prefix.
+	PrivateKey keychain.PrivateKey
+
+	// PublicKey is the requester public key.
+	PublicKey keychain.PublicKey
+
+	// Validity is the requested ValidityPeriod.
+	// Default is from now until MaxValidityPeriod of the CA.
+	Validity keychain.ValidityPeriod
+
+	// Challenge is the requester side of a challenge.
+	Challenge ClientChallenge
+}
+
+func (opts *ClientOptions) applyDefaults() {
+	if !opts.Validity.Valid() {
+		now := time.Now().Truncate(time.Second)
+		opts.Validity = keychain.ValidityPeriod{
+			NotBefore: now,
+			NotAfter:  now.Add(opts.Profile.MaxValidityPeriod),
+		}
+	}
+}
+
+// Request requests a certificate from the CA.
+func Request(ctx context.Context, opts ClientOptions) (*keychain.Certificate, error) {
+	opts.applyDefaults()
+	caPrefix := opts.Profile.Prefix
+
+	certRequest, e := keychain.MakeCert(opts.PublicKey, opts.PrivateKey, keychain.MakeCertOptions{
+		IssuerID: keychain.ComponentSelfIssuer,
+		Validity: opts.Validity,
+	})
+	if e != nil {
+		return nil, e
+	}
+	ecdhPvt, ecdhPub, e := makeEcdhKey()
+	if e != nil {
+		return nil, e
+	}
+
+	appParams, e := tlv.Encode(newRequest{
+		EcdhPub:     ecdhPub,
+		CertRequest: certRequest.Data(),
+	}.Fields()...)
+	if e != nil {
+		return nil, e
+	}
+	content, e := consumeCommand(ctx, opts.Profile, opts.ConsumerOptions,
+		caPrefix.Append(ComponentCA, ComponentNEW), appParams, opts.PrivateKey)
+	if e != nil {
+		return nil, e
+	}
+
+	var newRes newResponse
+	if e = newRes.UnmarshalBinary(content); e != nil {
+		return nil, e
+	}
+	if !containsString(newRes.Challenges, opts.Challenge.Name()) {
+		return nil, fmt.Errorf("%w: %s", ErrChallengeUnavailable, opts.Challenge.Name())
+	}
+	sess, e := newSession(ecdhPvt, newRes.EcdhPub, newRes.Salt, newRes.RequestID)
+	if e != nil {
+		return nil, e
+	}
+
+	challengeName := caPrefix.Append(ComponentCA, ComponentCHALLENGE, ndn.MakeNameComponent(an.TtGenericNameComponent, newRes.RequestID))
+	var res challengeResponse
+	for res.Status != StatusSuccess {
+		params, e := opts.Challenge.Next(ctx, res.ChallengeStatus, res.Parameters)
+		if e != nil {
+			return nil, e
+		}
+
+		plaintext, e := tlv.Encode(challengeRequest{
+			SelectedChallenge: opts.Challenge.Name(),
+			Parameters:        params,
+		}.Fields()...)
+		if e != nil {
+			return nil, e
+		}
+		appParams, e := tlv.Encode(sess.Encrypt(plaintext).Fields()...)
+		if e != nil {
+			return nil, e
+		}
+		content, e := consumeCommand(ctx, opts.Profile, opts.ConsumerOptions,
+			challengeName, appParams, opts.PrivateKey)
+		if e != nil {
+			return nil, e
+		}
+
+		var msg encryptedMessage
+		if e = msg.UnmarshalBinary(content); e != nil {
+			return nil, e
+		}
+		if plaintext, e = sess.Decrypt(msg); e != nil {
+			return nil, e
+		}
+		if e = res.UnmarshalBinary(plaintext); e != nil {
+			return nil, e
+		}
+		if res.Status == StatusFailure {
+			return nil, fmt.Errorf("%w: %s", ErrChallengeFailure, res.ChallengeStatus)
+		}
+	}
+
+	interest := ndn.MakeInterest(res.IssuedCertName, ndn.ForwardingHint{caPrefix.Append(ComponentCA)})
+	copts := opts.ConsumerOptions
+	copts.Verifier = opts.Profile.Cert.PublicKey()
+	data, e := endpoint.Consume(ctx, interest, copts)
+	if e != nil {
+		return nil, e
+	}
+	cert, e := keychain.CertFromData(*data)
+	if e != nil {
+		return nil, e
+	}
+	if !keychain.ToKeyName(cert.Name()).Equal(opts.PublicKey.Name()) {
+		return nil, fmt.Errorf("%w: issued certificate name mismatch", ErrMessage)
+	}
+	return cert, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package ndncert
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Error conditions for encryption.
+var (
+	ErrEcdhPub = errors.New("bad EcdhPub")
+	ErrDecrypt = errors.New("cannot decrypt message")
+)
+
+const (
+	saltLen      = 32
+	requestIDLen = 8
+	aesKeyLen    = 16
+	ivRandomLen  = 8
+	ivLen        = 12
+)
+
+// makeEcdhKey generates an ECDH key pair on P-256 curve.
+// pub is the public key in uncompressed form.
+func makeEcdhKey() (pvt *ecdh.PrivateKey, pub []byte, e error) {
+	if pvt, e = ecdh.P256().GenerateKey(rand.Reader); e != nil {
+		return nil, nil, e
+	}
+	return pvt, pvt.PublicKey().Bytes(), nil
+}
+
+// ecdhSecret computes the ECDH shared secret.
+func ecdhSecret(pvt *ecdh.PrivateKey, peerPub []byte) ([]byte, error) {
+	pub, e := ecdh.P256().NewPublicKey(peerPub)
+	if e != nil {
+		return nil, ErrEcdhPub
+	}
+	secret, e := pvt.ECDH(pub)
+	if e != nil {
+		return nil, ErrEcdhPub
+	}
+	return secret, nil
+}
+
+// session is an AES-GCM encryption session between requester and CA.
+type session struct {
+	aead      cipher.AEAD
+	requestID []byte
+	mutex     sync.Mutex
+	ivRandom  [ivRandomLen]byte
+	ivCounter uint32
+}
+
+// newSession derives a session key from ECDH shared secret.
+func newSession(ecdhPvt *ecdh.PrivateKey, peerPub, salt, requestID []byte) (*session, error) {
+	secret, e := ecdhSecret(ecdhPvt, peerPub)
+	if e != nil {
+		return nil, e
+	}
+	key := make([]byte, aesKeyLen)
+	if _, e = io.ReadFull(hkdf.New(sha256.New, secret, salt, requestID), key); e != nil {
+		return nil, e
+	}
+	block, e := aes.NewCipher(key)
+	if e != nil {
+		return nil, e
+	}
+	s := &session{requestID: requestID}
+	if s.aead, e = cipher.NewGCM(block); e != nil {
+		return nil, e
+	}
+	if _, e = rand.Read(s.ivRandom[:]); e != nil {
+		return nil, e
+	}
+	return s, nil
+}
+
+// Encrypt encrypts a message.
+// The IV consists of a random part fixed in the session and a block counter, as in ndncert library.
+func (s *session) Encrypt(plaintext []byte) (msg encryptedMessage) {
+	s.mutex.Lock()
+	iv := make([]byte, ivLen)
+	copy(iv, s.ivRandom[:])
+	binary.BigEndian.PutUint32(iv[ivRandomLen:], s.ivCounter)
+	s.ivCounter += uint32((len(plaintext) + aes.BlockSize - 1) / aes.BlockSize)
+	s.mutex.Unlock()
+
+	sealed := s.aead.Seal(nil, iv, plaintext, s.requestID)
+	tagPos := len(sealed) - s.aead.Overhead()
+	return encryptedMessage{
+		IV:      iv,
+		Tag:     sealed[tagPos:],
+		Payload: sealed[:tagPos],
+	}
+}
+
+// Decrypt decrypts a message.
+func (s *session) Decrypt(msg encryptedMessage) ([]byte, error) {
+	if len(msg.IV) != ivLen || len(msg.Tag) != s.aead.Overhead() {
+		return nil, ErrDecrypt
+	}
+	sealed := append(append([]byte{}, msg.Payload...), msg.Tag...)
+	plaintext, e := s.aead.Open(nil, msg.IV, sealed, s.requestID)
+	if e != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+// encryptedMessage contains encrypted payload in CHALLENGE request and response.
+type encryptedMessage struct {
+	IV      []byte
+	Tag     []byte
+	Payload []byte
+}
+
+var _ encoding.BinaryUnmarshaler = (*encryptedMessage)(nil)
+
+// Fields returns TLV fields.
+func (msg encryptedMessage) Fields() []tlv.Field {
+	return []tlv.Field{
+		tlv.TLVBytes(TtInitializationVector, msg.IV),
+		tlv.TLVBytes(TtAuthenticationTag, msg.Tag),
+		tlv.TLVBytes(TtEncryptedPayload, msg.Payload),
+	}
+}
+
+// UnmarshalBinary decodes from TLV-VALUE.
+func (msg *encryptedMessage) UnmarshalBinary(wire []byte) error {
+	*msg = encryptedMessage{}
+	d := tlv.DecodingBuffer(wire)
+	for _, de := range d.Elements() {
+		switch de.Type {
+		case TtInitializationVector:
+			msg.IV = de.Value
+		case TtAuthenticationTag:
+			msg.Tag = de.Value
+		case TtEncryptedPayload:
+			msg.Payload = de.Value
+		default:
+			if de.IsCriticalType() {
+				return tlv.ErrCritical
+			}
+		}
+	}
+	if msg.IV == nil || msg.Tag == nil {
+		return ErrDecrypt
+	}
+	return d.ErrUnlessEOF()
+}
@@ -0,0 +1,319 @@
+package ndncert_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/core/testenv"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"github.com/usnistgov/ndn-dpdk/ndn/ndncert"
+	"github.com/usnistgov/ndn-dpdk/ndn/ndntestenv"
+	"go4.org/must"
+)
+
+var (
+	makeAR    = testenv.MakeAR
+	nameEqual = ndntestenv.NameEqual
+)
+
+func TestPin(t *testing.T) {
+	assert, require := makeAR(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	fw := l3.NewForwarder()
+	cOpt := endpoint.ConsumerOptions{Fw: fw}
+
+	caPvt, caPub, e := keychain.NewECDSAKeyPair(ndn.ParseName("/authority"))
+	require.NoError(e)
+	caCert, e := keychain.MakeCert(caPub, caPvt, keychain.MakeCertOptions{})
+	require.NoError(e)
+
+	pins := make(chan string, 1)
+	p, e := ndncert.Serve(ctx, ndncert.ServerOptions{
+		Profile: ndncert.CaProfile{
+			Prefix:            ndn.ParseName("/authority"),
+			Info:              "test CA",
+			MaxValidityPeriod: 24 * time.Hour,
+			Cert:              caCert,
+		},
+		Signer: caPvt.WithKeyLocator(caCert.Name()),
+		Fw:     fw,
+		Challenges: []ndncert.ServerChallenge{&ndncert.ServerPinChallenge{
+			NotifyPin: func(requestID []byte, certRequest *keychain.Certificate, pin string) {
+				pins <- pin
+			},
+		}},
+	})
+	require.NoError(e)
+	defer must.Close(p)
+
+	profile, e := ndncert.FetchProfile(ctx, ndn.ParseName("/authority"), cOpt)
+	require.NoError(e)
+	nameEqual(assert, caCert.Name(), profile.Cert.Name())
+	assert.Equal("test CA", profile.Info)
+
+	reqPvt, reqPub, e := keychain.NewECDSAKeyPair(ndn.ParseName("/authority/user"))
+	require.NoError(e)
+	nPrompts := 0
+	cert, e := ndncert.Request(ctx, ndncert.ClientOptions{
+		ConsumerOptions: cOpt,
+		Profile:         *profile,
+		PrivateKey:      reqPvt,
+		PublicKey:       reqPub,
+		Challenge: &ndncert.ClientPinChallenge{
+			Prompt: func(ctx context.Context) (string, error) {
+				if nPrompts++; nPrompts == 1 {
+					return "wrong", nil
+				}
+				return <-pins, nil
+			},
+		},
+	})
+	require.NoError(e)
+	assert.Equal(2, nPrompts)
+	nameEqual(assert, reqPub.Name(), keychain.ToKeyName(cert.Name()))
+	nameEqual(assert, caCert.Name(), cert.Issuer())
+	assert.NoError(caCert.PublicKey().Verify(cert.Data()))
+
+	// issued certificate takes effect when issued, and is served until its ValidityPeriod ends
+	reqPvt, reqPub, e = keychain.NewECDSAKeyPair(ndn.ParseName("/authority/short"))
+	require.NoError(e)
+	now := time.Now()
+	cert, e = ndncert.Request(ctx, ndncert.ClientOptions{
+		ConsumerOptions: cOpt,
+		Profile:         *profile,
+		PrivateKey:      reqPvt,
+		PublicKey:       reqPub,
+		Validity:        keychain.ValidityPeriod{NotBefore: now.Add(-time.Hour), NotAfter: now.Add(3 * time.Second)},
+		Challenge: &ndncert.ClientPinChallenge{
+			Prompt: func(ctx context.Context) (string, error) { return <-pins, nil },
+		},
+	})
+	require.NoError(e)
+	assert.False(cert.Validity().NotBefore.Before(now.Truncate(time.Second))) // NotBefore clamped to issuance time
+
+	fetchCert := func() error {
+		ctx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+		defer cancel()
+		_, e := endpoint.Consume(ctx, ndn.MakeInterest(cert.Name()), cOpt)
+		return e
+	}
+	assert.NoError(fetchCert())
+	time.Sleep(time.Until(cert.Validity().NotAfter.Add(100 * time.Millisecond)))
+	assert.Error(fetchCert())
+}
+
+func TestPinOutOfTries(t *testing.T) {
+	assert, require := makeAR(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	fw := l3.NewForwarder()
+	cOpt := endpoint.ConsumerOptions{Fw: fw}
+
+	caPvt, caPub, e := keychain.NewECDSAKeyPair(ndn.ParseName("/authority"))
+	require.NoError(e)
+	caCert, e := keychain.MakeCert(caPub, caPvt, keychain.MakeCertOptions{})
+	require.NoError(e)
+
+	p, e := ndncert.Serve(ctx, ndncert.ServerOptions{
+		Profile: ndncert.CaProfile{
+			Prefix:            ndn.ParseName("/authority"),
+			MaxValidityPeriod: 24 * time.Hour,
+			Cert:              caCert,
+		},
+		Signer:         caPvt.WithKeyLocator(caCert.Name()),
+		Fw:             fw,
+		Challenges:     []ndncert.ServerChallenge{&ndncert.ServerPinChallenge{}},
+		RemainingTries: 2,
+	})
+	require.NoError(e)
+	defer must.Close(p)
+
+	profile, e := ndncert.FetchProfile(ctx, ndn.ParseName("/authority"), cOpt)
+	require.NoError(e)
+
+	reqPvt, reqPub, e := keychain.NewECDSAKeyPair(ndn.ParseName("/authority/user"))
+	require.NoError(e)
+	_, e = ndncert.Request(ctx, ndncert.ClientOptions{
+		ConsumerOptions: cOpt,
+		Profile:         *profile,
+		PrivateKey:      reqPvt,
+		PublicKey:       reqPub,
+		Challenge: &ndncert.ClientPinChallenge{
+			Prompt: func(ctx context.Context) (string, error) { return "wrong", nil },
+		},
+	})
+	var er ndncert.ErrorResponse
+	if assert.True(errors.As(e, &er)) {
+		assert.EqualValues(ndncert.ErrorOutOfTries, er.Code)
+	}
+}
+
+func TestPossession(t *testing.T) {
+	assert, require := makeAR(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	fw := l3.NewForwarder()
+	cOpt := endpoint.ConsumerOptions{Fw: fw}
+
+	caPvt, caPub, e := keychain.NewECDSAKeyPair(ndn.ParseName("/authority"))
+	require.NoError(e)
+	caCert, e := keychain.MakeCert(caPub, caPvt, keychain.MakeCertOptions{})
+	require.NoError(e)
+	caSigner := caPvt.WithKeyLocator(caCert.Name())
+
+	oldPvt, oldPub, e := keychain.NewECDSAKeyPair(ndn.ParseName("/authority/device"))
+	require.NoError(e)
+	oldCert, e := keychain.MakeCert(oldPub, caSigner, keychain.MakeCertOptions{})
+	require.NoError(e)
+
+	p, e := ndncert.Serve(ctx, ndncert.ServerOptions{
+		Profile: ndncert.CaProfile{
+			Prefix:            ndn.ParseName("/authority"),
+			MaxValidityPeriod: 24 * time.Hour,
+			Cert:              caCert,
+		},
+		Signer: caSigner,
+		Fw:     fw,
+		Challenges: []ndncert.ServerChallenge{&ndncert.ServerPossessionChallenge{
+			Verifier: caCert.PublicKey(),
+		}},
+	})
+	require.NoError(e)
+	defer must.Close(p)
+
+	profile, e := ndncert.FetchProfile(ctx, ndn.ParseName("/authority"), cOpt)
+	require.NoError(e)
+
+	otherPvt, _, e := keychain.NewECDSAKeyPair(ndn.ParseName("/authority/device"))
+	require.NoError(e)
+	now := time.Now()
+	expiredCert, e := keychain.MakeCert(oldPub, caSigner, keychain.MakeCertOptions{
+		Validity: keychain.ValidityPeriod{NotBefore: now.Add(-2 * time.Hour), NotAfter: now.Add(-time.Hour)},
+	})
+	require.NoError(e)
+	siblingPvt, siblingPub, e := keychain.NewECDSAKeyPair(ndn.ParseName("/authority/sibling"))
+	require.NoError(e)
+	siblingCert, e := keychain.MakeCert(siblingPub, caSigner, keychain.MakeCertOptions{})
+	require.NoError(e)
+
+	for i, tt := range []struct {
+		cert   *keychain.Certificate
+		signer keychain.PrivateKey
+		ok     bool
+	}{
+		{oldCert, oldPvt, true},
+		{oldCert, otherPvt, false},
+		{expiredCert, oldPvt, false},
+		{siblingCert, siblingPvt, false},
+	} {
+		reqPvt, reqPub, e := keychain.NewECDSAKeyPair(ndn.ParseName("/authority/device"))
+		require.NoError(e)
+		cert, e := ndncert.Request(ctx, ndncert.ClientOptions{
+			ConsumerOptions: cOpt,
+			Profile:         *profile,
+			PrivateKey:      reqPvt,
+			PublicKey:       reqPub,
+			Challenge: &ndncert.ClientPossessionChallenge{
+				Cert:   tt.cert,
+				Signer: tt.signer,
+			},
+		})
+		if tt.ok {
+			require.NoError(e, i)
+			nameEqual(assert, reqPub.Name(), keychain.ToKeyName(cert.Name()))
+		} else {
+			assert.Error(e, i)
+		}
+	}
+}
+
+func TestRejects(t *testing.T) {
+	assert, require := makeAR(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	fw := l3.NewForwarder()
+	cOpt := endpoint.ConsumerOptions{Fw: fw}
+
+	caPvt, caPub, e := keychain.NewECDSAKeyPair(ndn.ParseName("/authority"))
+	require.NoError(e)
+	caCert, e := keychain.MakeCert(caPub, caPvt, keychain.MakeCertOptions{})
+	require.NoError(e)
+
+	p, e := ndncert.Serve(ctx, ndncert.ServerOptions{
+		Profile: ndncert.CaProfile{
+			Prefix:            ndn.ParseName("/authority"),
+			ProbeKeys:         []string{"email"},
+			MaxValidityPeriod: 24 * time.Hour,
+			Cert:              caCert,
+		},
+		Signer:     caPvt.WithKeyLocator(caCert.Name()),
+		Fw:         fw,
+		Challenges: []ndncert.ServerChallenge{&ndncert.ServerPinChallenge{}},
+		Probe: func(ctx context.Context, params ndncert.Parameters) ([]ndn.Name, error) {
+			if email := string(params["email"]); email != "" {
+				return []ndn.Name{ndn.ParseName("/authority").Append(ndn.ParseNameComponent(email))}, nil
+			}
+			return nil, nil
+		},
+	})
+	require.NoError(e)
+	defer must.Close(p)
+
+	profile, e := ndncert.FetchProfile(ctx, ndn.ParseName("/authority"), cOpt)
+	require.NoError(e)
+	assert.Equal([]string{"email"}, profile.ProbeKeys)
+
+	names, e := ndncert.Probe(ctx, *profile, ndncert.Parameters{"email": []byte("user")}, cOpt)
+	require.NoError(e)
+	require.Len(names, 1)
+	nameEqual(assert, "/authority/user", names[0])
+
+	var er ndncert.ErrorResponse
+	_, e = ndncert.Probe(ctx, *profile, ndncert.Parameters{}, cOpt)
+	if assert.True(errors.As(e, &er)) {
+		assert.EqualValues(ndncert.ErrorNoAvailableNames, er.Code)
+	}
+
+	otherPvt, otherPub, e := keychain.NewECDSAKeyPair(ndn.ParseName("/other/user"))
+	require.NoError(e)
+	_, e = ndncert.Request(ctx, ndncert.ClientOptions{
+		ConsumerOptions: cOpt,
+		Profile:         *profile,
+		PrivateKey:      otherPvt,
+		PublicKey:       otherPub,
+		Challenge:       &ndncert.ClientPinChallenge{},
+	})
+	if assert.True(errors.As(e, &er)) {
+		assert.EqualValues(ndncert.ErrorNameNotAllowed, er.Code)
+	}
+
+	reqPvt, reqPub, e := keychain.NewECDSAKeyPair(ndn.ParseName("/authority/user"))
+	require.NoError(e)
+	now := time.Now()
+	_, e = ndncert.Request(ctx, ndncert.ClientOptions{
+		ConsumerOptions: cOpt,
+		Profile:         *profile,
+		PrivateKey:      reqPvt,
+		PublicKey:       reqPub,
+		Validity:        keychain.ValidityPeriod{NotBefore: now, NotAfter: now.Add(48 * time.Hour)},
+		Challenge:       &ndncert.ClientPinChallenge{},
+	})
+	if assert.True(errors.As(e, &er)) {
+		assert.EqualValues(ndncert.ErrorBadValidityPeriod, er.Code)
+	}
+
+	_, e = ndncert.Request(ctx, ndncert.ClientOptions{
+		ConsumerOptions: cOpt,
+		Profile:         *profile,
+		PrivateKey:      reqPvt,
+		PublicKey:       reqPub,
+		Challenge:       &ndncert.ClientPossessionChallenge{},
+	})
+	assert.ErrorIs(e, ndncert.ErrChallengeUnavailable)
+}
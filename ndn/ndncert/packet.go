@@ -0,0 +1,444 @@
+package ndncert
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// Name components in NDNCERT.
+var (
+	ComponentCA        = ndn.ParseNameComponent("CA")
+	ComponentINFO      = ndn.ParseNameComponent("INFO")
+	ComponentPROBE     = ndn.ParseNameComponent("PROBE")
+	ComponentNEW       = ndn.ParseNameComponent("NEW")
+	ComponentCHALLENGE = ndn.ParseNameComponent("CHALLENGE")
+
+	// ComponentIssuer is the IssuerId component of issued certificates.
+	ComponentIssuer = ndn.ParseNameComponent("NDNCERT")
+)
+
+// ErrMessage indicates a malformed NDNCERT message.
+var ErrMessage = errors.New("bad NDNCERT message")
+
+// ErrorResponse is an error reported by the CA.
+type ErrorResponse struct {
+	Code uint64
+	Info string
+}
+
+var _ error = ErrorResponse{}
+
+func (e ErrorResponse) Error() string {
+	return fmt.Sprintf("NDNCERT error %d: %s", e.Code, e.Info)
+}
+
+// Fields returns TLV fields.
+func (e ErrorResponse) Fields() []tlv.Field {
+	return []tlv.Field{
+		tlv.TLVNNI(TtErrorCode, e.Code),
+		tlv.TLVBytes(TtErrorInfo, []byte(e.Info)),
+	}
+}
+
+// checkErrorResponse returns ErrorResponse if the content is an error message.
+func checkErrorResponse(content []byte) error {
+	d := tlv.DecodingBuffer(content)
+	var er ErrorResponse
+	isError := false
+	for _, de := range d.Elements() {
+		switch de.Type {
+		case TtErrorCode:
+			var e error
+			er.Code = de.UnmarshalNNI(math.MaxUint64, &e, ErrMessage)
+			isError = true
+		case TtErrorInfo:
+			er.Info = string(de.Value)
+		}
+	}
+	if isError {
+		return er
+	}
+	return nil
+}
+
+// Parameters contains PROBE or challenge parameters.
+type Parameters map[string][]byte
+
+// Fields returns TLV fields, sorted by key.
+func (p Parameters) Fields() (fields []tlv.Field) {
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fields = append(fields, tlv.TLVBytes(TtParameterKey, []byte(k)), tlv.TLVBytes(TtParameterValue, p[k]))
+	}
+	return fields
+}
+
+// parametersDecoder decodes ParameterKey-ParameterValue pairs.
+type parametersDecoder struct {
+	p   Parameters
+	key *string
+}
+
+func (pd *parametersDecoder) Decode(de tlv.DecodingElement) error {
+	switch de.Type {
+	case TtParameterKey:
+		if pd.key != nil {
+			return ErrMessage
+		}
+		k := string(de.Value)
+		pd.key = &k
+	case TtParameterValue:
+		if pd.key == nil {
+			return ErrMessage
+		}
+		if pd.p == nil {
+			pd.p = Parameters{}
+		}
+		pd.p[*pd.key] = de.Value
+		pd.key = nil
+	}
+	return nil
+}
+
+// CaProfile contains CA profile information.
+type CaProfile struct {
+	// Prefix is the CA prefix.
+	Prefix ndn.Name
+
+	// Info is a human-readable description of the CA.
+	Info string
+
+	// ProbeKeys is a list of parameter keys accepted in PROBE step.
+	ProbeKeys []string
+
+	// MaxValidityPeriod is the maximum validity period of issued certificates.
+	MaxValidityPeriod time.Duration
+
+	// Cert is the CA certificate.
+	Cert *keychain.Certificate
+}
+
+var (
+	_ encoding.BinaryMarshaler   = CaProfile{}
+	_ encoding.BinaryUnmarshaler = (*CaProfile)(nil)
+)
+
+// MarshalBinary encodes to TLV-VALUE.
+func (p CaProfile) MarshalBinary() (value []byte, e error) {
+	if p.Cert == nil {
+		return nil, errors.New("missing CA certificate")
+	}
+	fields := []tlv.Field{
+		tlv.TLVFrom(TtCaPrefix, p.Prefix),
+		tlv.TLVBytes(TtCaInfo, []byte(p.Info)),
+	}
+	for _, k := range p.ProbeKeys {
+		fields = append(fields, tlv.TLVBytes(TtParameterKey, []byte(k)))
+	}
+	fields = append(fields,
+		tlv.TLVNNI(TtMaxValidityPeriod, uint64(p.MaxValidityPeriod/time.Second)),
+		tlv.TLVFrom(TtCaCertificate, p.Cert.Data()),
+	)
+	return tlv.Encode(fields...)
+}
+
+// UnmarshalBinary decodes from TLV-VALUE.
+func (p *CaProfile) UnmarshalBinary(value []byte) (e error) {
+	*p = CaProfile{}
+	d := tlv.DecodingBuffer(value)
+	for _, de := range d.Elements() {
+		switch de.Type {
+		case TtCaPrefix:
+			if e = decodeNameIn(de, &p.Prefix); e != nil {
+				return e
+			}
+		case TtCaInfo:
+			p.Info = string(de.Value)
+		case TtParameterKey:
+			p.ProbeKeys = append(p.ProbeKeys, string(de.Value))
+		case TtMaxValidityPeriod:
+			if p.MaxValidityPeriod = time.Duration(de.UnmarshalNNI(math.MaxInt64/uint64(time.Second), &e, ErrMessage)) * time.Second; e != nil {
+				return e
+			}
+		case TtCaCertificate:
+			var data ndn.Data
+			if e = decodeDataIn(de, &data); e != nil {
+				return e
+			}
+			if p.Cert, e = keychain.CertFromData(data); e != nil {
+				return e
+			}
+		default:
+			if de.IsCriticalType() {
+				return tlv.ErrCritical
+			}
+		}
+	}
+	if len(p.Prefix) == 0 || p.Cert == nil {
+		return ErrMessage
+	}
+	return d.ErrUnlessEOF()
+}
+
+// decodeNameIn decodes a Name TLV nested in de.
+func decodeNameIn(de tlv.DecodingElement, name *ndn.Name) error {
+	d := tlv.DecodingBuffer(de.Value)
+	inner, e := d.Element()
+	if e != nil {
+		return e
+	}
+	if inner.Type != an.TtName {
+		return ErrMessage
+	}
+	if e = inner.UnmarshalValue(name); e != nil {
+		return e
+	}
+	return d.ErrUnlessEOF()
+}
+
+// decodeDataIn decodes a Data TLV nested in de.
+func decodeDataIn(de tlv.DecodingElement, data *ndn.Data) error {
+	d := tlv.DecodingBuffer(de.Value)
+	inner, e := d.Element()
+	if e != nil {
+		return e
+	}
+	if inner.Type != an.TtData {
+		return ErrMessage
+	}
+	if e = inner.UnmarshalValue(data); e != nil {
+		return e
+	}
+	return d.ErrUnlessEOF()
+}
+
+type probeResponse struct {
+	Names []ndn.Name
+}
+
+func (m probeResponse) Fields() (fields []tlv.Field) {
+	for _, name := range m.Names {
+		fields = append(fields, tlv.TLVFrom(TtProbeResponse, name))
+	}
+	return fields
+}
+
+func (m *probeResponse) UnmarshalBinary(value []byte) error {
+	*m = probeResponse{}
+	d := tlv.DecodingBuffer(value)
+	for _, de := range d.Elements() {
+		switch de.Type {
+		case TtProbeResponse:
+			var name ndn.Name
+			// ProbeResponse may contain MaxSuffixLength after Name, which is ignored
+			d1 := tlv.DecodingBuffer(de.Value)
+			inner, e := d1.Element()
+			if e != nil || inner.Type != an.TtName {
+				return ErrMessage
+			}
+			if e = inner.UnmarshalValue(&name); e != nil {
+				return e
+			}
+			m.Names = append(m.Names, name)
+		default:
+			if de.IsCriticalType() {
+				return tlv.ErrCritical
+			}
+		}
+	}
+	return d.ErrUnlessEOF()
+}
+
+type newRequest struct {
+	EcdhPub     []byte
+	CertRequest ndn.Data
+}
+
+func (m newRequest) Fields() []tlv.Field {
+	return []tlv.Field{
+		tlv.TLVBytes(TtEcdhPub, m.EcdhPub),
+		tlv.TLVFrom(TtCertRequest, m.CertRequest),
+	}
+}
+
+func (m *newRequest) UnmarshalBinary(value []byte) (e error) {
+	*m = newRequest{}
+	hasCertRequest := false
+	d := tlv.DecodingBuffer(value)
+	for _, de := range d.Elements() {
+		switch de.Type {
+		case TtEcdhPub:
+			m.EcdhPub = de.Value
+		case TtCertRequest:
+			if e = decodeDataIn(de, &m.CertRequest); e != nil {
+				return e
+			}
+			hasCertRequest = true
+		default:
+			if de.IsCriticalType() {
+				return tlv.ErrCritical
+			}
+		}
+	}
+	if len(m.EcdhPub) == 0 || !hasCertRequest {
+		return ErrMessage
+	}
+	return d.ErrUnlessEOF()
+}
+
+type newResponse struct {
+	EcdhPub    []byte
+	Salt       []byte
+	RequestID  []byte
+	Challenges []string
+}
+
+func (m newResponse) Fields() []tlv.Field {
+	fields := []tlv.Field{
+		tlv.TLVBytes(TtEcdhPub, m.EcdhPub),
+		tlv.TLVBytes(TtSalt, m.Salt),
+		tlv.TLVBytes(TtRequestID, m.RequestID),
+	}
+	for _, c := range m.Challenges {
+		fields = append(fields, tlv.TLVBytes(TtChallenge, []byte(c)))
+	}
+	return fields
+}
+
+func (m *newResponse) UnmarshalBinary(value []byte) error {
+	*m = newResponse{}
+	d := tlv.DecodingBuffer(value)
+	for _, de := range d.Elements() {
+		switch de.Type {
+		case TtEcdhPub:
+			m.EcdhPub = de.Value
+		case TtSalt:
+			m.Salt = de.Value
+		case TtRequestID:
+			m.RequestID = de.Value
+		case TtChallenge:
+			m.Challenges = append(m.Challenges, string(de.Value))
+		default:
+			if de.IsCriticalType() {
+				return tlv.ErrCritical
+			}
+		}
+	}
+	if len(m.EcdhPub) == 0 || len(m.Salt) != saltLen || len(m.RequestID) != requestIDLen {
+		return ErrMessage
+	}
+	return d.ErrUnlessEOF()
+}
+
+type challengeRequest struct {
+	SelectedChallenge string
+	Parameters        Parameters
+}
+
+func (m challengeRequest) Fields() []tlv.Field {
+	return append([]tlv.Field{tlv.TLVBytes(TtSelectedChallenge, []byte(m.SelectedChallenge))}, m.Parameters.Fields()...)
+}
+
+func (m *challengeRequest) UnmarshalBinary(value []byte) error {
+	*m = challengeRequest{}
+	var pd parametersDecoder
+	d := tlv.DecodingBuffer(value)
+	for _, de := range d.Elements() {
+		switch de.Type {
+		case TtSelectedChallenge:
+			m.SelectedChallenge = string(de.Value)
+		case TtParameterKey, TtParameterValue:
+			if e := pd.Decode(de); e != nil {
+				return e
+			}
+		default:
+			if de.IsCriticalType() {
+				return tlv.ErrCritical
+			}
+		}
+	}
+	m.Parameters = pd.p
+	if m.SelectedChallenge == "" {
+		return ErrMessage
+	}
+	return d.ErrUnlessEOF()
+}
+
+type challengeResponse struct {
+	Status          uint64
+	ChallengeStatus string
+	RemainingTries  int
+	RemainingTime   time.Duration
+	IssuedCertName  ndn.Name
+	Parameters      Parameters
+}
+
+func (m challengeResponse) Fields() []tlv.Field {
+	fields := []tlv.Field{tlv.TLVNNI(TtStatus, m.Status)}
+	if m.Status == StatusSuccess {
+		fields = append(fields, tlv.TLVFrom(TtIssuedCertName, m.IssuedCertName))
+	} else {
+		fields = append(fields,
+			tlv.TLVBytes(TtChallengeStatus, []byte(m.ChallengeStatus)),
+			tlv.TLVNNI(TtRemainingTries, uint64(m.RemainingTries)),
+			tlv.TLVNNI(TtRemainingTime, uint64(m.RemainingTime/time.Second)),
+		)
+	}
+	return append(fields, m.Parameters.Fields()...)
+}
+
+func (m *challengeResponse) UnmarshalBinary(value []byte) (e error) {
+	*m = challengeResponse{}
+	hasStatus := false
+	var pd parametersDecoder
+	d := tlv.DecodingBuffer(value)
+	for _, de := range d.Elements() {
+		switch de.Type {
+		case TtStatus:
+			if m.Status = de.UnmarshalNNI(StatusFailure, &e, ErrMessage); e != nil {
+				return e
+			}
+			hasStatus = true
+		case TtChallengeStatus:
+			m.ChallengeStatus = string(de.Value)
+		case TtRemainingTries:
+			if m.RemainingTries = int(de.UnmarshalNNI(math.MaxInt32, &e, ErrMessage)); e != nil {
+				return e
+			}
+		case TtRemainingTime:
+			if m.RemainingTime = time.Duration(de.UnmarshalNNI(math.MaxInt32, &e, ErrMessage)) * time.Second; e != nil {
+				return e
+			}
+		case TtIssuedCertName:
+			if e = decodeNameIn(de, &m.IssuedCertName); e != nil {
+				return e
+			}
+		case TtParameterKey, TtParameterValue:
+			if e := pd.Decode(de); e != nil {
+				return e
+			}
+		default:
+			if de.IsCriticalType() {
+				return tlv.ErrCritical
+			}
+		}
+	}
+	m.Parameters = pd.p
+	if !hasStatus || (m.Status == StatusSuccess && len(m.IssuedCertName) == 0) {
+		return ErrMessage
+	}
+	return d.ErrUnlessEOF()
+}
@@ -0,0 +1,366 @@
+package ndncert
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// ServerOptions contains arguments to Serve function.
+type ServerOptions struct {
+	// Profile is the CA profile.
+	// Prefix, Cert, and MaxValidityPeriod are required.
+	Profile CaProfile
+
+	// Signer is the CA private key, whose KeyLocator should be the CA certificate name.
+	// It signs every response and issued certificate.
+	Signer ndn.Signer
+
+	// Challenges are the supported challenges.
+	Challenges []ServerChallenge
+
+	// Probe handles a PROBE request and returns available names.
+	// Default is rejecting all PROBE requests.
+	Probe func(ctx context.Context, params Parameters) ([]ndn.Name, error)
+
+	// RemainingTries is the number of incorrect attempts allowed in a challenge.
+	// Default is 3.
+	RemainingTries int
+
+	// ChallengeLifetime is the maximum duration of a request from NEW step to successful challenge.
+	// Default is 5 minutes.
+	ChallengeLifetime time.Duration
+
+	// IssuedCapacity is the maximum number of issued certificates served by the CA.
+	// When exceeded, the least recently used certificate is no longer served.
+	// Default is 4096.
+	IssuedCapacity int
+
+	// Fw specifies the L3 Forwarder.
+	// Default is the default Forwarder.
+	Fw l3.Forwarder
+}
+
+func (opts *ServerOptions) applyDefaults() {
+	if opts.RemainingTries <= 0 {
+		opts.RemainingTries = 3
+	}
+	if opts.ChallengeLifetime <= 0 {
+		opts.ChallengeLifetime = 5 * time.Minute
+	}
+}
+
+type serverRequest struct {
+	mutex       sync.Mutex
+	session     *session
+	certRequest *keychain.Certificate
+	challenge   ServerChallenge
+	state       interface{}
+	tries       int
+	expiry      time.Time
+}
+
+type server struct {
+	ServerOptions
+	profileData ndn.Data
+	mutex       sync.Mutex
+	requests    map[string]*serverRequest // hex(RequestID) => request
+	issued      *endpoint.MemoryDataStore // served until ValidityPeriod ends
+}
+
+// Serve starts a CA.
+//
+// The CA answers Interests under CA prefix: NDNCERT protocol messages under /<CA-prefix>/CA,
+// and issued certificates elsewhere under the CA prefix.
+func Serve(ctx context.Context, opts ServerOptions) (endpoint.Producer, error) {
+	opts.applyDefaults()
+	if len(opts.Profile.Prefix) == 0 || opts.Profile.Cert == nil || opts.Signer == nil {
+		return nil, errors.New("CA prefix, certificate, and signer are required")
+	}
+
+	s := &server{
+		ServerOptions: opts,
+		requests:      map[string]*serverRequest{},
+		issued:        endpoint.NewMemoryDataStore(endpoint.MemoryDataStoreOptions{Capacity: opts.IssuedCapacity}),
+	}
+
+	profile, e := opts.Profile.MarshalBinary()
+	if e != nil {
+		return nil, e
+	}
+	version := ndn.VersionConvention.MakeTime(time.Now())
+	segment := ndn.SegmentConvention.Make(0)
+	s.profileData = ndn.MakeData(opts.Profile.Prefix.Append(ComponentCA, ComponentINFO, version, segment),
+		ndn.FinalBlockFlag, time.Second, profile)
+	if e = opts.Signer.Sign(&s.profileData); e != nil {
+		return nil, e
+	}
+
+	return endpoint.Produce(ctx, endpoint.ProducerOptions{
+		Prefix:     opts.Profile.Prefix,
+		Handler:    s.handle,
+		Fw:         opts.Fw,
+		DataSigner: opts.Signer,
+	})
+}
+
+func (s *server) handle(ctx context.Context, interest ndn.Interest) (ndn.Data, error) {
+	rel := interest.Name[len(s.Profile.Prefix):]
+	if len(rel) < 2 || !rel[0].Equal(ComponentCA) {
+		return s.serveCert(interest)
+	}
+
+	var content []tlv.Field
+	var e error
+	switch {
+	case rel[1].Equal(ComponentINFO):
+		return s.profileData, nil
+	case rel[1].Equal(ComponentPROBE):
+		content, e = s.handleProbe(ctx, interest)
+	case rel[1].Equal(ComponentNEW):
+		content, e = s.handleNew(interest)
+	case rel[1].Equal(ComponentCHALLENGE) && len(rel) >= 3:
+		content, e = s.handleChallenge(ctx, interest, rel[2].Value)
+	default:
+		e = ErrorResponse{ErrorBadInterestFormat, "unknown command"}
+	}
+
+	if e != nil {
+		var er ErrorResponse
+		if !errors.As(e, &er) {
+			er = ErrorResponse{ErrorInvalidParameter, e.Error()}
+		}
+		content = er.Fields()
+	}
+	wire, e := tlv.Encode(content...)
+	if e != nil {
+		return ndn.Data{}, e
+	}
+	return ndn.MakeData(interest, wire), nil
+}
+
+func (s *server) serveCert(interest ndn.Interest) (ndn.Data, error) {
+	now := time.Now()
+	for {
+		data, ok := s.issued.Get(interest)
+		if !ok {
+			return ndn.Data{}, nil
+		}
+		if cert, e := keychain.CertFromData(data); e == nil && !now.After(cert.Validity().NotAfter) {
+			return data, nil
+		}
+		s.issued.Delete(data.Name)
+	}
+}
+
+func (s *server) handleProbe(ctx context.Context, interest ndn.Interest) ([]tlv.Field, error) {
+	if s.Probe == nil {
+		return nil, ErrorResponse{ErrorInvalidParameter, "PROBE not supported"}
+	}
+
+	var pd parametersDecoder
+	d := tlv.DecodingBuffer(interest.AppParameters)
+	for _, de := range d.Elements() {
+		if e := pd.Decode(de); e != nil {
+			return nil, ErrorResponse{ErrorBadParameterFormat, e.Error()}
+		}
+	}
+	if e := d.ErrUnlessEOF(); e != nil {
+		return nil, ErrorResponse{ErrorBadParameterFormat, e.Error()}
+	}
+
+	names, e := s.Probe(ctx, pd.p)
+	if e != nil {
+		return nil, e
+	}
+	if len(names) == 0 {
+		return nil, ErrorResponse{ErrorNoAvailableNames, "no available names"}
+	}
+	return probeResponse{names}.Fields(), nil
+}
+
+func (s *server) handleNew(interest ndn.Interest) ([]tlv.Field, error) {
+	var req newRequest
+	if e := req.UnmarshalBinary(interest.AppParameters); e != nil {
+		return nil, ErrorResponse{ErrorBadParameterFormat, e.Error()}
+	}
+
+	certRequest, e := keychain.CertFromData(req.CertRequest)
+	if e != nil {
+		return nil, ErrorResponse{ErrorBadParameterFormat, e.Error()}
+	}
+	pub := certRequest.PublicKey()
+	if pub.Verify(req.CertRequest) != nil || pub.Verify(interest) != nil {
+		return nil, ErrorResponse{ErrorBadSignature, "bad signature"}
+	}
+
+	subject := certRequest.SubjectName()
+	if len(subject) <= len(s.Profile.Prefix) || !s.Profile.Prefix.IsPrefixOf(subject) {
+		return nil, ErrorResponse{ErrorNameNotAllowed, "name not allowed"}
+	}
+
+	now := time.Now()
+	validity := certRequest.Validity()
+	if validity.NotBefore.After(validity.NotAfter) || validity.NotAfter.Before(now) || validity.NotAfter.After(now.Add(s.Profile.MaxValidityPeriod)) {
+		return nil, ErrorResponse{ErrorBadValidityPeriod, "bad validity period"}
+	}
+
+	ecdhPvt, ecdhPub, e := makeEcdhKey()
+	if e != nil {
+		return nil, e
+	}
+	salt := make([]byte, saltLen)
+	requestID := make([]byte, requestIDLen)
+	if _, e = rand.Read(salt); e != nil {
+		return nil, e
+	}
+	if _, e = rand.Read(requestID); e != nil {
+		return nil, e
+	}
+	sess, e := newSession(ecdhPvt, req.EcdhPub, salt, requestID)
+	if e != nil {
+		return nil, ErrorResponse{ErrorBadParameterFormat, e.Error()}
+	}
+
+	res := newResponse{
+		EcdhPub:   ecdhPub,
+		Salt:      salt,
+		RequestID: requestID,
+	}
+	for _, ch := range s.Challenges {
+		res.Challenges = append(res.Challenges, ch.Name())
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.cleanup(now)
+	s.requests[hex.EncodeToString(requestID)] = &serverRequest{
+		session:     sess,
+		certRequest: certRequest,
+		tries:       s.RemainingTries,
+		expiry:      now.Add(s.ChallengeLifetime),
+	}
+	return res.Fields(), nil
+}
+
+// cleanup deletes expired requests.
+// Caller must hold s.mutex.
+func (s *server) cleanup(now time.Time) {
+	for id, req := range s.requests {
+		if now.After(req.expiry) {
+			delete(s.requests, id)
+		}
+	}
+}
+
+func (s *server) handleChallenge(ctx context.Context, interest ndn.Interest, requestID []byte) ([]tlv.Field, error) {
+	id := hex.EncodeToString(requestID)
+	s.mutex.Lock()
+	req := s.requests[id]
+	s.mutex.Unlock()
+	if req == nil {
+		return nil, ErrorResponse{ErrorInvalidParameter, "unknown request"}
+	}
+	req.mutex.Lock()
+	defer req.mutex.Unlock()
+	if time.Now().After(req.expiry) {
+		s.deleteRequest(id)
+		return nil, ErrorResponse{ErrorOutOfTime, "request expired"}
+	}
+	if req.certRequest.PublicKey().Verify(interest) != nil {
+		return nil, ErrorResponse{ErrorBadSignature, "bad signature"}
+	}
+
+	var msg encryptedMessage
+	if e := msg.UnmarshalBinary(interest.AppParameters); e != nil {
+		return nil, ErrorResponse{ErrorBadParameterFormat, e.Error()}
+	}
+	plaintext, e := req.session.Decrypt(msg)
+	if e != nil {
+		return nil, ErrorResponse{ErrorBadParameterFormat, e.Error()}
+	}
+	var creq challengeRequest
+	if e := creq.UnmarshalBinary(plaintext); e != nil {
+		return nil, ErrorResponse{ErrorBadParameterFormat, e.Error()}
+	}
+
+	if req.challenge == nil {
+		for _, ch := range s.Challenges {
+			if ch.Name() == creq.SelectedChallenge {
+				req.challenge = ch
+			}
+		}
+	}
+	if req.challenge == nil || req.challenge.Name() != creq.SelectedChallenge {
+		return nil, ErrorResponse{ErrorInvalidParameter, "unknown challenge"}
+	}
+
+	cres, e := req.challenge.Process(ctx, ServerChallengeRequest{
+		RequestID:   requestID,
+		CertRequest: req.certRequest,
+		Parameters:  creq.Parameters,
+		State:       req.state,
+	})
+	if e != nil {
+		s.deleteRequest(id)
+		return nil, e
+	}
+	req.state = cres.State
+
+	res := challengeResponse{
+		Status:          StatusChallenge,
+		ChallengeStatus: cres.ChallengeStatus,
+		RemainingTime:   time.Until(req.expiry),
+		Parameters:      cres.Parameters,
+	}
+	if cres.DecrementTries {
+		if req.tries--; req.tries <= 0 {
+			s.deleteRequest(id)
+			return nil, ErrorResponse{ErrorOutOfTries, "out of tries"}
+		}
+	}
+	res.RemainingTries = req.tries
+
+	if cres.Success {
+		// certificate does not take effect before it is issued
+		validity := req.certRequest.Validity()
+		if now := time.Now(); validity.NotBefore.Before(now) {
+			validity.NotBefore = now
+		}
+		if !validity.Valid() {
+			s.deleteRequest(id)
+			return nil, ErrorResponse{ErrorBadValidityPeriod, "bad validity period"}
+		}
+		cert, e := keychain.MakeCert(req.certRequest.PublicKey(), s.Signer, keychain.MakeCertOptions{
+			IssuerID: ComponentIssuer,
+			Validity: validity,
+		})
+		if e != nil {
+			return nil, e
+		}
+		s.deleteRequest(id)
+		s.issued.Put(cert.Data())
+		res.Status, res.IssuedCertName = StatusSuccess, cert.Name()
+	}
+
+	wire, e := tlv.Encode(res.Fields()...)
+	if e != nil {
+		return nil, e
+	}
+	return req.session.Encrypt(wire).Fields(), nil
+}
+
+func (s *server) deleteRequest(id string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.requests, id)
+}
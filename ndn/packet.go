@@ -28,15 +28,19 @@ type L3Packet interface {
 }
 
 // Packet represents an NDN layer 3 packet with associated LpL3.
+//
+// A Packet may also represent an NDNLPv2 fragment or an IDLE frame.
+// An IDLE frame has neither payload nor fragment, and carries only Reliability fields.
 type Packet struct {
-	Lp       LpL3
-	l3type   uint32
-	l3value  []byte
-	l3digest []byte
-	Fragment *LpFragment
-	Interest *Interest
-	Data     *Data
-	Nack     *Nack
+	Lp          LpL3
+	Reliability LpReliability
+	l3type      uint32
+	l3value     []byte
+	l3digest    []byte
+	Fragment    *LpFragment
+	Interest    *Interest
+	Data        *Data
+	Nack        *Nack
 }
 
 var (
@@ -58,6 +62,8 @@ func (pkt *Packet) String() string {
 		return "D " + pkt.Data.String() + suffix
 	case pkt.Nack != nil:
 		return "N " + pkt.Nack.String() + suffix
+	case !pkt.Reliability.Empty():
+		return "IDLE"
 	}
 	return "(bad-NDN-packet)"
 }
@@ -69,8 +75,12 @@ func (pkt *Packet) ToPacket() *Packet {
 
 // Field implements tlv.Fielder interface.
 func (pkt *Packet) Field() tlv.Field {
-	if pkt.Fragment != nil {
-		return pkt.Fragment.Field()
+	rel := pkt.Reliability.encode()
+	switch {
+	case pkt.Fragment != nil:
+		return pkt.Fragment.field(rel)
+	case pkt.Interest == nil && pkt.Data == nil && pkt.Nack == nil && len(rel) > 0:
+		return tlv.TLV(an.TtLpPacket, rel...)
 	}
 
 	header, payload, e := pkt.encodeL3()
@@ -78,10 +88,12 @@ func (pkt *Packet) Field() tlv.Field {
 		return tlv.FieldError(e)
 	}
 
-	if len(header) == 0 {
+	if len(header) == 0 && len(rel) == 0 {
 		return tlv.Bytes(payload)
 	}
-	return tlv.TLV(an.TtLpPacket, tlv.Bytes(header), tlv.TLVBytes(an.TtLpPayload, payload))
+	fields := append([]tlv.Field{tlv.Bytes(header)}, rel...)
+	fields = append(fields, tlv.TLVBytes(an.TtLpPayload, payload))
+	return tlv.TLV(an.TtLpPacket, fields...)
 }
 
 func (pkt *Packet) encodeL3() (header, payload []byte, e error) {
@@ -167,6 +179,16 @@ func (pkt *Packet) decodeValue(value []byte) (e error) {
 			if pkt.Lp.CongMark = uint8(de.UnmarshalNNI(math.MaxUint8, &e, tlv.ErrRange)); e != nil {
 				return e
 			}
+		case an.TtAck:
+			if de.Length() != 8 {
+				return tlv.ErrRange
+			}
+			pkt.Reliability.Acks = append(pkt.Reliability.Acks, binary.BigEndian.Uint64(de.Value))
+		case an.TtTxSequence:
+			if de.Length() != 8 {
+				return tlv.ErrRange
+			}
+			pkt.Reliability.TxSequence = binary.BigEndian.Uint64(de.Value)
 		case an.TtLpPayload:
 			if e = pkt.decodePayload(de.Value); e != nil {
 				return e
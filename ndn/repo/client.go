@@ -0,0 +1,91 @@
+package repo
+
+import (
+	"context"
+	"crypto/rand"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+)
+
+// Client sends commands to a repo.
+type Client struct {
+	endpoint.ConsumerOptions
+
+	// Prefix is the repo command prefix.
+	Prefix ndn.Name
+
+	// Signer signs command Interests.
+	// Default is sending unsigned command Interests.
+	Signer ndn.Signer
+}
+
+// Invoke sends a command and returns the response.
+// A response with an error status code is not treated as an error.
+func (c Client) Invoke(ctx context.Context, verb string, param CommandParameter) (response CommandResponse, e error) {
+	interest := ndn.Interest{
+		Name: c.Prefix.Append(ndn.MakeNameComponent(an.TtGenericNameComponent, []byte(verb)), param.NameComponent()),
+	}
+	if c.Signer != nil {
+		interest.SigInfo = &ndn.SigInfo{
+			Nonce: make([]byte, 8),
+			Time:  uint64(time.Now().UnixMilli()),
+		}
+		if _, e = rand.Read(interest.SigInfo.Nonce); e != nil {
+			return response, e
+		}
+		if e = c.Signer.Sign(&interest); e != nil {
+			return response, e
+		}
+	}
+
+	data, e := endpoint.Consume(ctx, interest, c.ConsumerOptions)
+	if e != nil {
+		return response, e
+	}
+	e = decodeTLV(data.Content, TtCommandResponse, &response)
+	return response, e
+}
+
+// Insert starts an insert process.
+func (c Client) Insert(ctx context.Context, param CommandParameter) (CommandResponse, error) {
+	return c.Invoke(ctx, VerbInsert, param)
+}
+
+// InsertCheck queries the status of an insert process.
+func (c Client) InsertCheck(ctx context.Context, processID uint64) (CommandResponse, error) {
+	return c.Invoke(ctx, VerbInsertCheck, CommandParameter{ProcessID: processID})
+}
+
+// InsertWait starts an insert process and waits for its completion.
+// It returns the last response, whose status code is StatusOK if the process succeeded.
+func (c Client) InsertWait(ctx context.Context, param CommandParameter, interval time.Duration) (response CommandResponse, e error) {
+	if response, e = c.Insert(ctx, param); e != nil || response.StatusCode != StatusAccepted {
+		return response, e
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return response, ctx.Err()
+		case <-ticker.C:
+		}
+		if response, e = c.InsertCheck(ctx, response.ProcessID); e != nil || response.StatusCode != StatusInProgress {
+			return response, e
+		}
+	}
+}
+
+// Delete deletes Data.
+func (c Client) Delete(ctx context.Context, param CommandParameter) (CommandResponse, error) {
+	return c.Invoke(ctx, VerbDelete, param)
+}
+
+// DeleteCheck queries the status of a delete process.
+func (c Client) DeleteCheck(ctx context.Context, processID uint64) (CommandResponse, error) {
+	return c.Invoke(ctx, VerbDeleteCheck, CommandParameter{ProcessID: processID})
+}
@@ -0,0 +1,234 @@
+// Package repo implements a Data repository with repo-ng compatible command protocol.
+package repo
+
+import (
+	"encoding"
+	"errors"
+	"math"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// Assigned numbers.
+const (
+	TtCommandParameter = 0xC9
+	TtStartBlockID     = 0xCC
+	TtEndBlockID       = 0xCD
+	TtProcessID        = 0xCE
+	TtCommandResponse  = 0xCF
+	TtStatusCode       = 0xD0
+	TtInsertNum        = 0xD1
+	TtDeleteNum        = 0xD2
+	TtMaxInterestNum   = 0xD3
+	TtInterestLifetime = 0xD6
+
+	_ = "enumgen::TtRepo:Tt"
+)
+
+// Command verbs.
+const (
+	VerbInsert      = "insert"
+	VerbInsertCheck = "insert check"
+	VerbDelete      = "delete"
+	VerbDeleteCheck = "delete check"
+)
+
+// Status codes.
+const (
+	StatusAccepted     = 100 // insert command accepted
+	StatusOK           = 200 // insert or delete process completed
+	StatusInProgress   = 300 // insert process in progress
+	StatusUnauthorized = 401 // command Interest validation failure
+	StatusMalformed    = 403 // malformed command
+	StatusNotFound     = 404 // no such process
+	StatusFailed       = 405 // insert process failed, e.g. Data retrieval timeout
+)
+
+// ErrCommand indicates a malformed command parameter or response.
+var ErrCommand = errors.New("bad repo command")
+
+// CommandParameter is the parameter of a repo command, carried in the command Interest name.
+type CommandParameter struct {
+	Name ndn.Name
+
+	// StartBlockID and EndBlockID, if either is present, indicate a segmented object under Name.
+	// If EndBlockID is omitted, segments are retrieved until FinalBlockId.
+	StartBlockID *uint64
+	EndBlockID   *uint64
+
+	// ProcessID identifies the insert or delete process.
+	// Zero means the server should generate a ProcessID.
+	// A command is rejected if the ProcessID belongs to an existing process.
+	ProcessID uint64
+
+	// InterestLifetime is the InterestLifetime of Interests sent by the repo.
+	// Zero means default.
+	InterestLifetime uint64 // milliseconds
+}
+
+// IsSegmented determines whether this parameter refers to a segmented object.
+func (p CommandParameter) IsSegmented() bool {
+	return p.StartBlockID != nil || p.EndBlockID != nil
+}
+
+// Field implements tlv.Fielder interface.
+func (p CommandParameter) Field() tlv.Field {
+	fields := []tlv.Fielder{}
+	if len(p.Name) > 0 {
+		fields = append(fields, p.Name)
+	}
+	if p.StartBlockID != nil {
+		fields = append(fields, tlv.TLVNNI(TtStartBlockID, *p.StartBlockID))
+	}
+	if p.EndBlockID != nil {
+		fields = append(fields, tlv.TLVNNI(TtEndBlockID, *p.EndBlockID))
+	}
+	if p.ProcessID != 0 {
+		fields = append(fields, tlv.TLVNNI(TtProcessID, p.ProcessID))
+	}
+	if p.InterestLifetime != 0 {
+		fields = append(fields, tlv.TLVNNI(TtInterestLifetime, p.InterestLifetime))
+	}
+	return tlv.TLVFrom(TtCommandParameter, fields...)
+}
+
+// UnmarshalBinary decodes from TLV-VALUE.
+func (p *CommandParameter) UnmarshalBinary(value []byte) (e error) {
+	*p = CommandParameter{}
+	d := tlv.DecodingBuffer(value)
+	for _, de := range d.Elements() {
+		switch de.Type {
+		case an.TtName:
+			if e = de.UnmarshalValue(&p.Name); e != nil {
+				return e
+			}
+		case TtStartBlockID:
+			v := de.UnmarshalNNI(math.MaxUint64, &e, tlv.ErrRange)
+			if e != nil {
+				return e
+			}
+			p.StartBlockID = &v
+		case TtEndBlockID:
+			v := de.UnmarshalNNI(math.MaxUint64, &e, tlv.ErrRange)
+			if e != nil {
+				return e
+			}
+			p.EndBlockID = &v
+		case TtProcessID:
+			if p.ProcessID = de.UnmarshalNNI(math.MaxUint64, &e, tlv.ErrRange); e != nil {
+				return e
+			}
+		case TtInterestLifetime:
+			if p.InterestLifetime = de.UnmarshalNNI(math.MaxUint64, &e, tlv.ErrRange); e != nil {
+				return e
+			}
+		default:
+			if de.IsCriticalType() {
+				return tlv.ErrCritical
+			}
+		}
+	}
+	return d.ErrUnlessEOF()
+}
+
+// NameComponent encodes this parameter as a name component.
+func (p CommandParameter) NameComponent() ndn.NameComponent {
+	return ndn.NameComponentFrom(an.TtGenericNameComponent, p)
+}
+
+// CommandResponse is the response of a repo command, carried in the Data content.
+type CommandResponse struct {
+	StatusCode   int
+	ProcessID    uint64
+	StartBlockID *uint64
+	EndBlockID   *uint64
+	InsertNum    uint64
+	DeleteNum    uint64
+}
+
+// Field implements tlv.Fielder interface.
+func (r CommandResponse) Field() tlv.Field {
+	fields := []tlv.Fielder{
+		tlv.TLVNNI(TtProcessID, r.ProcessID),
+		tlv.TLVNNI(TtStatusCode, uint64(r.StatusCode)),
+	}
+	if r.StartBlockID != nil {
+		fields = append(fields, tlv.TLVNNI(TtStartBlockID, *r.StartBlockID))
+	}
+	if r.EndBlockID != nil {
+		fields = append(fields, tlv.TLVNNI(TtEndBlockID, *r.EndBlockID))
+	}
+	if r.InsertNum != 0 {
+		fields = append(fields, tlv.TLVNNI(TtInsertNum, r.InsertNum))
+	}
+	if r.DeleteNum != 0 {
+		fields = append(fields, tlv.TLVNNI(TtDeleteNum, r.DeleteNum))
+	}
+	return tlv.TLVFrom(TtCommandResponse, fields...)
+}
+
+// UnmarshalBinary decodes from TLV-VALUE.
+func (r *CommandResponse) UnmarshalBinary(value []byte) (e error) {
+	*r = CommandResponse{}
+	hasStatusCode := false
+	d := tlv.DecodingBuffer(value)
+	for _, de := range d.Elements() {
+		switch de.Type {
+		case TtProcessID:
+			if r.ProcessID = de.UnmarshalNNI(math.MaxUint64, &e, tlv.ErrRange); e != nil {
+				return e
+			}
+		case TtStatusCode:
+			if r.StatusCode = int(de.UnmarshalNNI(999, &e, tlv.ErrRange)); e != nil {
+				return e
+			}
+			hasStatusCode = true
+		case TtStartBlockID:
+			v := de.UnmarshalNNI(math.MaxUint64, &e, tlv.ErrRange)
+			if e != nil {
+				return e
+			}
+			r.StartBlockID = &v
+		case TtEndBlockID:
+			v := de.UnmarshalNNI(math.MaxUint64, &e, tlv.ErrRange)
+			if e != nil {
+				return e
+			}
+			r.EndBlockID = &v
+		case TtInsertNum:
+			if r.InsertNum = de.UnmarshalNNI(math.MaxUint64, &e, tlv.ErrRange); e != nil {
+				return e
+			}
+		case TtDeleteNum:
+			if r.DeleteNum = de.UnmarshalNNI(math.MaxUint64, &e, tlv.ErrRange); e != nil {
+				return e
+			}
+		default:
+			if de.IsCriticalType() {
+				return tlv.ErrCritical
+			}
+		}
+	}
+	if !hasStatusCode {
+		return ErrCommand
+	}
+	return d.ErrUnlessEOF()
+}
+
+// decodeTLV decodes a TLV element of expected type.
+func decodeTLV(wire []byte, typ uint32, u encoding.BinaryUnmarshaler) error {
+	d := tlv.DecodingBuffer(wire)
+	de, e := d.Element()
+	if e != nil {
+		return e
+	}
+	if de.Type != typ {
+		return ErrCommand
+	}
+	if e = u.UnmarshalBinary(de.Value); e != nil {
+		return e
+	}
+	return d.ErrUnlessEOF()
+}
@@ -0,0 +1,376 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"github.com/usnistgov/ndn-dpdk/ndn/segmented"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+	"go4.org/must"
+)
+
+// Storage is a persistent Data store used by the repo.
+type Storage interface {
+	endpoint.DataStore
+
+	// Insert saves a Data packet.
+	Insert(data ndn.Data) error
+
+	// Delete removes Data packets under the prefix whose names are accepted by the predicate.
+	// A nil predicate accepts every name.
+	Delete(prefix ndn.Name, pred func(name ndn.Name) bool) (n int, e error)
+}
+
+var _ Storage = (*DirStorage)(nil)
+
+// ServerOptions contains arguments to NewServer function.
+type ServerOptions struct {
+	// Prefix is the repo command prefix.
+	Prefix ndn.Name
+
+	// DataPrefixes are name prefixes under which stored Data are served.
+	// Data outside these prefixes are stored but not served.
+	DataPrefixes []ndn.Name
+
+	// Storage is the Data store.
+	Storage Storage
+
+	// Fw specifies the L3 Forwarder.
+	// Default is the default Forwarder.
+	Fw l3.Forwarder
+
+	// CommandVerifier verifies command Interests.
+	// Default is accepting all commands.
+	CommandVerifier ndn.Verifier
+
+	// Signer signs command responses.
+	// Default is keeping the Null signature.
+	Signer ndn.Signer
+
+	// DataVerifier verifies Data retrieved in insert processes.
+	// Default is no verification.
+	DataVerifier ndn.Verifier
+
+	// RetxLimit is the maximum number of retransmissions when retrieving each Data packet.
+	// Default is 3.
+	RetxLimit int
+
+	// ProcessLifetime is how long a completed process remains available for status check.
+	// Default is 60 seconds.
+	ProcessLifetime time.Duration
+}
+
+func (opts *ServerOptions) applyDefaults() {
+	if opts.CommandVerifier == nil {
+		opts.CommandVerifier = ndn.NopVerifier
+	}
+	if opts.DataVerifier == nil {
+		opts.DataVerifier = ndn.NopVerifier
+	}
+	if opts.RetxLimit <= 0 {
+		opts.RetxLimit = 3
+	}
+	if opts.ProcessLifetime <= 0 {
+		opts.ProcessLifetime = 60 * time.Second
+	}
+}
+
+type serverProcess struct {
+	verb     string
+	response CommandResponse
+}
+
+// Server is a repo that implements repo-ng command protocol.
+type Server struct {
+	opts      ServerOptions
+	ctx       context.Context
+	cancel    context.CancelFunc
+	producers []endpoint.Producer
+
+	mutex     sync.Mutex
+	processes map[uint64]*serverProcess
+}
+
+// NewServer starts a repo server.
+func NewServer(opts ServerOptions) (s *Server, e error) {
+	opts.applyDefaults()
+	if len(opts.Prefix) == 0 || opts.Storage == nil {
+		return nil, errors.New("Prefix and Storage are required")
+	}
+
+	s = &Server{
+		opts:      opts,
+		processes: map[uint64]*serverProcess{},
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	p, e := endpoint.Produce(s.ctx, endpoint.ProducerOptions{
+		Prefix:     opts.Prefix,
+		Handler:    s.handleCommand,
+		Fw:         opts.Fw,
+		DataSigner: opts.Signer,
+	})
+	if e != nil {
+		s.Close()
+		return nil, e
+	}
+	s.producers = append(s.producers, p)
+
+	for _, prefix := range opts.DataPrefixes {
+		p, e := endpoint.Produce(s.ctx, endpoint.ProducerOptions{
+			Prefix:    prefix,
+			DataStore: opts.Storage,
+			Fw:        opts.Fw,
+		})
+		if e != nil {
+			s.Close()
+			return nil, e
+		}
+		s.producers = append(s.producers, p)
+	}
+	return s, nil
+}
+
+// Close stops the server.
+// Ongoing insert processes are canceled.
+func (s *Server) Close() error {
+	s.cancel()
+	for _, p := range s.producers {
+		must.Close(p)
+	}
+	return nil
+}
+
+func (s *Server) handleCommand(ctx context.Context, interest ndn.Interest) (ndn.Data, error) {
+	rel := interest.Name[len(s.opts.Prefix):]
+	if len(rel) < 2 {
+		return ndn.Data{}, nil
+	}
+	verb := string(rel[0].Value)
+
+	var response CommandResponse
+	var param CommandParameter
+	if e := s.opts.CommandVerifier.Verify(interest); e != nil {
+		response.StatusCode = StatusUnauthorized
+	} else if e := decodeTLV(rel[1].Value, TtCommandParameter, &param); e != nil {
+		response.StatusCode = StatusMalformed
+	} else {
+		switch verb {
+		case VerbInsert:
+			response = s.insert(param)
+		case VerbDelete:
+			response = s.delete(param)
+		case VerbInsertCheck, VerbDeleteCheck:
+			response = s.check(verb, param)
+		default:
+			return ndn.Data{}, nil
+		}
+	}
+
+	content, e := tlv.EncodeFrom(response)
+	if e != nil {
+		return ndn.Data{}, e
+	}
+	return ndn.MakeData(interest, content), nil
+}
+
+// newProcessID returns the ProcessID requested by the client, or generates a random ProcessID.
+// ok is false if the requested ProcessID belongs to another process.
+// Caller must hold s.mutex.
+func (s *Server) newProcessID(param CommandParameter) (id uint64, ok bool) {
+	if param.ProcessID != 0 {
+		return param.ProcessID, s.processes[param.ProcessID] == nil
+	}
+	for {
+		if id := rand.Uint64(); id != 0 && s.processes[id] == nil {
+			return id, true
+		}
+	}
+}
+
+// completeProcess schedules removal of a completed process.
+func (s *Server) completeProcess(id uint64, proc *serverProcess) {
+	time.AfterFunc(s.opts.ProcessLifetime, func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		if s.processes[id] == proc {
+			delete(s.processes, id)
+		}
+	})
+}
+
+func (s *Server) insert(param CommandParameter) CommandResponse {
+	if len(param.Name) == 0 {
+		return CommandResponse{StatusCode: StatusMalformed}
+	}
+	if param.IsSegmented() && param.StartBlockID != nil && param.EndBlockID != nil && *param.StartBlockID > *param.EndBlockID {
+		return CommandResponse{StatusCode: StatusMalformed}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	id, ok := s.newProcessID(param)
+	if !ok {
+		return CommandResponse{StatusCode: StatusMalformed, ProcessID: id}
+	}
+	proc := &serverProcess{
+		verb: VerbInsert,
+		response: CommandResponse{
+			StatusCode:   StatusInProgress,
+			ProcessID:    id,
+			StartBlockID: param.StartBlockID,
+			EndBlockID:   param.EndBlockID,
+		},
+	}
+	s.processes[id] = proc
+	go s.runInsert(id, proc, param)
+
+	response := proc.response
+	response.StatusCode = StatusAccepted
+	return response
+}
+
+func (s *Server) runInsert(id uint64, proc *serverProcess, param CommandParameter) {
+	var e error
+	if param.IsSegmented() {
+		e = s.fetchSegmented(proc, param)
+	} else {
+		e = s.fetchSingle(proc, param)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if e == nil {
+		proc.response.StatusCode = StatusOK
+	} else {
+		proc.response.StatusCode = StatusFailed
+	}
+	s.completeProcess(id, proc)
+}
+
+func (s *Server) fetchSingle(proc *serverProcess, param CommandParameter) error {
+	interest := ndn.Interest{
+		Name:     param.Name,
+		Lifetime: time.Duration(param.InterestLifetime) * time.Millisecond,
+	}
+	data, e := endpoint.Consume(s.ctx, interest, endpoint.ConsumerOptions{
+		Fw:       s.opts.Fw,
+		Retx:     endpoint.RetxOptions{Limit: s.opts.RetxLimit},
+		Verifier: s.opts.DataVerifier,
+	})
+	if e != nil {
+		return e
+	}
+	return s.store(proc, *data)
+}
+
+func (s *Server) fetchSegmented(proc *serverProcess, param CommandParameter) error {
+	fetchOpts := segmented.FetchOptions{
+		Fw:        s.opts.Fw,
+		RetxLimit: s.opts.RetxLimit,
+		Verifier:  s.opts.DataVerifier,
+	}
+	if param.StartBlockID != nil {
+		fetchOpts.SegmentBegin = *param.StartBlockID
+	}
+	if param.EndBlockID != nil && *param.EndBlockID < math.MaxUint64 {
+		fetchOpts.SegmentEnd = *param.EndBlockID + 1
+	}
+
+	ch := make(chan *ndn.Data)
+	errC := make(chan error, 1)
+	go func() { errC <- segmented.Fetch(param.Name, fetchOpts).Unordered(s.ctx, ch) }()
+
+	var e error
+	for data := range ch {
+		if e == nil {
+			e = s.store(proc, *data)
+		}
+	}
+	if e1 := <-errC; e == nil {
+		e = e1
+	}
+	return e
+}
+
+func (s *Server) store(proc *serverProcess, data ndn.Data) error {
+	if e := s.opts.Storage.Insert(data); e != nil {
+		return e
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	proc.response.InsertNum++
+	return nil
+}
+
+func (s *Server) delete(param CommandParameter) CommandResponse {
+	if len(param.Name) == 0 {
+		return CommandResponse{StatusCode: StatusMalformed}
+	}
+
+	var pred func(name ndn.Name) bool
+	if param.IsSegmented() {
+		first, last := uint64(0), uint64(math.MaxUint64)
+		if param.StartBlockID != nil {
+			first = *param.StartBlockID
+		}
+		if param.EndBlockID != nil {
+			last = *param.EndBlockID
+		}
+		pred = func(name ndn.Name) bool {
+			if len(name) != len(param.Name)+1 {
+				return false
+			}
+			seg, ok := ndn.SegmentConvention.Parse(name.Get(-1))
+			return ok && seg >= first && seg <= last
+		}
+	}
+
+	s.mutex.Lock()
+	id, ok := s.newProcessID(param)
+	if !ok {
+		s.mutex.Unlock()
+		return CommandResponse{StatusCode: StatusMalformed, ProcessID: id}
+	}
+	proc := &serverProcess{
+		verb: VerbDelete,
+		response: CommandResponse{
+			StatusCode:   StatusInProgress,
+			ProcessID:    id,
+			StartBlockID: param.StartBlockID,
+			EndBlockID:   param.EndBlockID,
+		},
+	}
+	s.processes[id] = proc
+	s.mutex.Unlock()
+
+	n, e := s.opts.Storage.Delete(param.Name, pred)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	proc.response.DeleteNum = uint64(n)
+	if e == nil {
+		proc.response.StatusCode = StatusOK
+	} else {
+		proc.response.StatusCode = StatusFailed
+	}
+	s.completeProcess(id, proc)
+	return proc.response
+}
+
+func (s *Server) check(verb string, param CommandParameter) CommandResponse {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	proc := s.processes[param.ProcessID]
+	if proc == nil || verb != proc.verb+" check" {
+		return CommandResponse{StatusCode: StatusNotFound, ProcessID: param.ProcessID}
+	}
+	return proc.response
+}
@@ -0,0 +1,119 @@
+package repo_test
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"github.com/usnistgov/ndn-dpdk/ndn/repo"
+	"github.com/usnistgov/ndn-dpdk/ndn/segmented"
+	"go4.org/must"
+)
+
+func TestServer(t *testing.T) {
+	assert, require := makeAR(t)
+	fw := l3.NewForwarder()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	payload := make([]byte, 5000)
+	rand.Read(payload)
+	source, e := segmented.Serve(ctx, bytes.NewReader(payload), segmented.ServeOptions{
+		ProducerOptions: endpoint.ProducerOptions{
+			Prefix: ndn.ParseName("/S/obj"),
+			Fw:     fw,
+		},
+		ChunkSize: 1000,
+	})
+	require.NoError(e)
+	single, e := endpoint.Produce(ctx, endpoint.ProducerOptions{
+		Prefix: ndn.ParseName("/S/single"),
+		Handler: func(ctx context.Context, interest ndn.Interest) (ndn.Data, error) {
+			return ndn.MakeData(interest, []byte{0x01}), nil
+		},
+		Fw: fw,
+	})
+	require.NoError(e)
+
+	storage, e := repo.OpenDirStorage(t.TempDir())
+	require.NoError(e)
+	server, e := repo.NewServer(repo.ServerOptions{
+		Prefix:       ndn.ParseName("/R"),
+		DataPrefixes: []ndn.Name{ndn.ParseName("/S")},
+		Storage:      storage,
+		Fw:           fw,
+	})
+	require.NoError(e)
+	defer server.Close()
+
+	client := repo.Client{
+		ConsumerOptions: endpoint.ConsumerOptions{Fw: fw},
+		Prefix:          ndn.ParseName("/R"),
+	}
+
+	res, e := client.InsertWait(ctx, repo.CommandParameter{Name: ndn.ParseName("/S/single/1")}, 10*time.Millisecond)
+	require.NoError(e)
+	assert.Equal(repo.StatusOK, res.StatusCode)
+	assert.EqualValues(1, res.InsertNum)
+
+	start := uint64(0)
+	res, e = client.InsertWait(ctx, repo.CommandParameter{Name: ndn.ParseName("/S/obj"), StartBlockID: &start}, 10*time.Millisecond)
+	require.NoError(e)
+	assert.Equal(repo.StatusOK, res.StatusCode)
+	assert.EqualValues(5, res.InsertNum)
+	assert.Equal(6, storage.Len())
+
+	res, e = client.InsertWait(ctx, repo.CommandParameter{Name: ndn.ParseName("/S/none"), InterestLifetime: 100}, 10*time.Millisecond)
+	require.NoError(e)
+	assert.Equal(repo.StatusFailed, res.StatusCode)
+
+	res, e = client.InsertCheck(ctx, 0xFFFF)
+	require.NoError(e)
+	assert.Equal(repo.StatusNotFound, res.StatusCode)
+
+	res, e = client.Invoke(ctx, repo.VerbInsert, repo.CommandParameter{})
+	require.NoError(e)
+	assert.Equal(repo.StatusMalformed, res.StatusCode)
+
+	must.Close(source)
+	must.Close(single)
+
+	fetched, e := segmented.Fetch(ndn.ParseName("/S/obj"), segmented.FetchOptions{Fw: fw}).Payload(ctx)
+	require.NoError(e)
+	assert.Equal(payload, fetched)
+
+	first, last := uint64(1), uint64(2)
+	res, e = client.Delete(ctx, repo.CommandParameter{Name: ndn.ParseName("/S/obj"), StartBlockID: &first, EndBlockID: &last})
+	require.NoError(e)
+	assert.Equal(repo.StatusOK, res.StatusCode)
+	assert.EqualValues(2, res.DeleteNum)
+	assert.Equal(4, storage.Len())
+
+	res2, e := client.DeleteCheck(ctx, res.ProcessID)
+	require.NoError(e)
+	assert.Equal(repo.StatusOK, res2.StatusCode)
+	assert.EqualValues(2, res2.DeleteNum)
+
+	res2, e = client.Delete(ctx, repo.CommandParameter{Name: ndn.ParseName("/S/obj"), ProcessID: res.ProcessID})
+	require.NoError(e)
+	assert.Equal(repo.StatusMalformed, res2.StatusCode) // ProcessID in use
+	assert.Equal(4, storage.Len())
+	res2, e = client.DeleteCheck(ctx, res.ProcessID)
+	require.NoError(e)
+	assert.EqualValues(2, res2.DeleteNum)
+
+	data, e := endpoint.Consume(ctx, ndn.MakeInterest("/S/single/1"), endpoint.ConsumerOptions{Fw: fw})
+	if assert.NoError(e) {
+		assert.Equal([]byte{0x01}, data.Content)
+	}
+
+	res, e = client.Delete(ctx, repo.CommandParameter{Name: ndn.ParseName("/S")})
+	require.NoError(e)
+	assert.EqualValues(4, res.DeleteNum)
+	assert.Equal(0, storage.Len())
+}
@@ -0,0 +1,188 @@
+package repo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+const dirStorageExt = ".data"
+
+// ErrStorage indicates a corrupted storage file.
+var ErrStorage = errors.New("bad repo storage file")
+
+// DirStorage is a persistent Data store in a filesystem directory.
+// Each Data packet is saved as a file named after its name digest.
+// Names are indexed in memory, while packets are read from disk upon retrieval.
+type DirStorage struct {
+	dir   string
+	mutex sync.RWMutex
+	names []ndn.Name // sorted in canonical order
+}
+
+var _ endpoint.DataStore = (*DirStorage)(nil)
+
+// OpenDirStorage opens or creates a DirStorage.
+func OpenDirStorage(dir string) (*DirStorage, error) {
+	if e := os.MkdirAll(dir, 0o755); e != nil {
+		return nil, e
+	}
+
+	s := &DirStorage{dir: dir}
+	entries, e := os.ReadDir(dir)
+	if e != nil {
+		return nil, e
+	}
+	for _, entry := range entries {
+		filename := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(filename, dirStorageExt) {
+			continue
+		}
+		data, e := s.readFile(filepath.Join(dir, filename))
+		if e != nil {
+			return nil, e
+		}
+		s.names = append(s.names, data.Name)
+	}
+	sort.Slice(s.names, func(i, j int) bool { return s.names[i].Compare(s.names[j]) < 0 })
+	return s, nil
+}
+
+// Len returns number of stored Data packets.
+func (s *DirStorage) Len() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.names)
+}
+
+func (s *DirStorage) filename(name ndn.Name) string {
+	value, _ := name.MarshalBinary()
+	digest := sha256.Sum256(value)
+	return filepath.Join(s.dir, hex.EncodeToString(digest[:])+dirStorageExt)
+}
+
+func (s *DirStorage) readFile(filename string) (data ndn.Data, e error) {
+	wire, e := os.ReadFile(filename)
+	if e != nil {
+		return data, e
+	}
+	d := tlv.DecodingBuffer(wire)
+	de, e := d.Element()
+	if e != nil {
+		return data, e
+	}
+	if de.Type != an.TtData {
+		return data, ErrStorage
+	}
+	if e = data.UnmarshalBinary(de.Value); e != nil {
+		return data, e
+	}
+	return data, d.ErrUnlessEOF()
+}
+
+// search returns the position of the first name not less than name.
+// Caller must hold the mutex.
+func (s *DirStorage) search(name ndn.Name) int {
+	return sort.Search(len(s.names), func(i int) bool { return s.names[i].Compare(name) >= 0 })
+}
+
+// Put implements endpoint.DataStore interface.
+// Errors are silently ignored; use Insert to receive errors.
+func (s *DirStorage) Put(data ndn.Data) {
+	s.Insert(data)
+}
+
+// Insert saves a Data packet.
+// It replaces any existing Data packet of the same name.
+func (s *DirStorage) Insert(data ndn.Data) error {
+	wire, e := tlv.EncodeFrom(data)
+	if e != nil {
+		return e
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	filename := s.filename(data.Name)
+	tmp := filename + ".tmp"
+	if e := os.WriteFile(tmp, wire, 0o644); e != nil {
+		return e
+	}
+	if e := os.Rename(tmp, filename); e != nil {
+		os.Remove(tmp)
+		return e
+	}
+
+	i := s.search(data.Name)
+	if i < len(s.names) && s.names[i].Equal(data.Name) {
+		return nil
+	}
+	s.names = append(s.names, nil)
+	copy(s.names[i+1:], s.names[i:])
+	s.names[i] = data.Name
+	return nil
+}
+
+// Delete removes Data packets whose names are accepted by the predicate.
+// The predicate is only invoked on names under the prefix.
+// It returns the number of removed packets.
+func (s *DirStorage) Delete(prefix ndn.Name, pred func(name ndn.Name) bool) (n int, e error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	begin := s.search(prefix)
+	end := begin
+	for end < len(s.names) && prefix.IsPrefixOf(s.names[end]) {
+		end++
+	}
+
+	kept := append([]ndn.Name{}, s.names[:begin]...)
+	for _, name := range s.names[begin:end] {
+		if pred == nil || pred(name) {
+			e1 := os.Remove(s.filename(name))
+			if e1 == nil || os.IsNotExist(e1) {
+				n++
+				continue
+			}
+			e = e1
+		}
+		kept = append(kept, name)
+	}
+	s.names = append(kept, s.names[end:]...)
+	return n, e
+}
+
+// Get implements endpoint.DataStore interface.
+func (s *DirStorage) Get(interest ndn.Interest) (data ndn.Data, ok bool) {
+	if len(interest.Name) == 0 {
+		return data, false
+	}
+
+	name := interest.Name
+	if name[len(name)-1].Type == an.TtImplicitSha256DigestComponent {
+		name = name.GetPrefix(-1)
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for i := s.search(name); i < len(s.names) && name.IsPrefixOf(s.names[i]); i++ {
+		if !interest.CanBePrefix && len(s.names[i]) != len(name) {
+			break
+		}
+		data, e := s.readFile(s.filename(s.names[i]))
+		if e == nil && data.CanSatisfy(interest) {
+			return data, true
+		}
+	}
+	return data, false
+}
@@ -0,0 +1,64 @@
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/core/testenv"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/ndntestenv"
+	"github.com/usnistgov/ndn-dpdk/ndn/repo"
+)
+
+var (
+	makeAR    = testenv.MakeAR
+	nameEqual = ndntestenv.NameEqual
+)
+
+func TestDirStorage(t *testing.T) {
+	assert, require := makeAR(t)
+	dir := t.TempDir()
+
+	s, e := repo.OpenDirStorage(dir)
+	require.NoError(e)
+	assert.Equal(0, s.Len())
+
+	dataA1 := ndn.MakeData("/A/1", []byte{0xA1})
+	require.NoError(s.Insert(dataA1))
+	require.NoError(s.Insert(ndn.MakeData("/A/2", []byte{0xA2}, time.Second)))
+	require.NoError(s.Insert(ndn.MakeData("/B/1", []byte{0xB1})))
+	require.NoError(s.Insert(ndn.MakeData("/A/1", []byte{0xA3})))
+	assert.Equal(3, s.Len())
+
+	s, e = repo.OpenDirStorage(dir)
+	require.NoError(e)
+	assert.Equal(3, s.Len())
+
+	if data, ok := s.Get(ndn.MakeInterest("/A/1")); assert.True(ok) {
+		nameEqual(assert, "/A/1", data)
+		assert.Equal([]byte{0xA3}, data.Content)
+	}
+	_, ok := s.Get(ndn.MakeInterest("/A"))
+	assert.False(ok)
+	if data, ok := s.Get(ndn.MakeInterest("/A", ndn.CanBePrefixFlag)); assert.True(ok) {
+		nameEqual(assert, "/A/1", data)
+	}
+	if data, ok := s.Get(ndn.MakeInterest("/A", ndn.CanBePrefixFlag, ndn.MustBeFreshFlag)); assert.True(ok) {
+		nameEqual(assert, "/A/2", data)
+	}
+	_, ok = s.Get(ndn.MakeInterest(dataA1.FullName()))
+	assert.False(ok)
+
+	n, e := s.Delete(ndn.ParseName("/A"), func(name ndn.Name) bool { return name.Get(-1).Equal(ndn.ParseNameComponent("1")) })
+	assert.NoError(e)
+	assert.Equal(1, n)
+	_, ok = s.Get(ndn.MakeInterest("/A/1"))
+	assert.False(ok)
+	n, e = s.Delete(ndn.ParseName("/"), nil)
+	assert.NoError(e)
+	assert.Equal(2, n)
+
+	s, e = repo.OpenDirStorage(dir)
+	require.NoError(e)
+	assert.Equal(0, s.Len())
+}
@@ -111,7 +111,9 @@ func (ca *cubic) Decrease(now time.Time) {
 	ca.ssthresh = math.Max(ca.cwnd, 2)
 }
 
-func newCubic() (ca *cubic) {
+// NewCubic creates a CUBIC congestion control instance.
+// This is the default algorithm.
+func NewCubic() CongestionControl {
 	return &cubic{
 		cwnd:     cubicIw,
 		ssthresh: math.Inf(1),
@@ -0,0 +1,156 @@
+package segmented
+
+import (
+	"math"
+	"time"
+
+	mathpkg "github.com/pkg/math"
+)
+
+// CongestionControl is a congestion control algorithm instance.
+// It is used by one fetch and does not need to be thread-safe.
+type CongestionControl interface {
+	// Cwnd returns the current congestion window, in number of Interests.
+	Cwnd() int
+
+	// Increase is invoked when a Data arrives without congestion mark.
+	// rtt is measured from the latest transmission of the Interest.
+	Increase(now time.Time, rtt time.Duration)
+
+	// Decrease is invoked upon a congestion event, either a congestion mark or a timeout.
+	Decrease(now time.Time)
+}
+
+const (
+	aimdIw   = 2
+	aimdAi   = 1.0
+	aimdMd   = 0.5
+	aimdMinW = 2.0
+)
+
+type aimd struct {
+	cwnd     float64
+	ssthresh float64
+	lastDec  time.Time
+	rtt      time.Duration
+}
+
+// NewAIMD creates an Additive-Increase Multiplicative-Decrease congestion control instance.
+// It has slow start, and reduces the window at most once per RTT.
+func NewAIMD() CongestionControl {
+	return &aimd{
+		cwnd:     aimdIw,
+		ssthresh: math.Inf(1),
+	}
+}
+
+func (ca *aimd) Cwnd() int {
+	return mathpkg.MaxInt(aimdIw, int(ca.cwnd))
+}
+
+func (ca *aimd) Increase(now time.Time, rtt time.Duration) {
+	ca.rtt = rtt
+	if ca.cwnd < ca.ssthresh { // slow start
+		ca.cwnd += aimdAi
+		return
+	}
+	ca.cwnd += aimdAi / ca.cwnd
+}
+
+func (ca *aimd) Decrease(now time.Time) {
+	if now.Sub(ca.lastDec) < ca.rtt { // conservative window adaptation
+		return
+	}
+	ca.lastDec = now
+	ca.cwnd = math.Max(ca.cwnd*aimdMd, aimdMinW)
+	ca.ssthresh = ca.cwnd
+}
+
+const (
+	bbrIw           = 2
+	bbrCwndGain     = 2.0
+	bbrMinRttWindow = 10 * time.Second
+	bbrBwWindow     = 10 // rounds
+	bbrFullBwThresh = 1.25
+	bbrFullBwCount  = 3
+)
+
+type bbr struct {
+	cwnd        float64
+	minRtt      time.Duration
+	minRttStamp time.Time
+	roundStart  time.Time
+	delivered   int
+	bwSamples   []float64 // delivery rate per round, in Data per second
+	fullBw      float64
+	fullBwCount int
+	filledPipe  bool
+}
+
+// NewBBR creates a delay-based congestion control instance, modeled after TCP BBR.
+//
+// It estimates bottleneck bandwidth as the maximum delivery rate over recent rounds, and propagation delay as
+// the minimum RTT in recent 10 seconds. After a startup phase with exponential growth, the congestion window
+// tracks twice the estimated bandwidth-delay product. Congestion marks and timeouts end the startup phase,
+// but do not reduce the window otherwise.
+func NewBBR() CongestionControl {
+	return &bbr{
+		cwnd: bbrIw,
+	}
+}
+
+func (ca *bbr) Cwnd() int {
+	return mathpkg.MaxInt(bbrIw, int(ca.cwnd))
+}
+
+func (ca *bbr) btlBw() (bw float64) {
+	for _, sample := range ca.bwSamples {
+		bw = math.Max(bw, sample)
+	}
+	return bw
+}
+
+func (ca *bbr) Increase(now time.Time, rtt time.Duration) {
+	if ca.minRtt == 0 || rtt <= ca.minRtt || now.Sub(ca.minRttStamp) > bbrMinRttWindow {
+		ca.minRtt, ca.minRttStamp = rtt, now
+	}
+
+	ca.delivered++
+	if ca.roundStart.IsZero() {
+		ca.roundStart = now
+	} else if elapsed := now.Sub(ca.roundStart); elapsed >= ca.minRtt {
+		ca.endRound(float64(ca.delivered) / elapsed.Seconds())
+		ca.roundStart, ca.delivered = now, 0
+	}
+
+	if !ca.filledPipe { // startup
+		ca.cwnd++
+		return
+	}
+	target := bbrCwndGain * ca.btlBw() * ca.minRtt.Seconds()
+	if ca.cwnd < target {
+		ca.cwnd++
+	} else {
+		ca.cwnd = target
+	}
+}
+
+func (ca *bbr) endRound(rate float64) {
+	ca.bwSamples = append(ca.bwSamples, rate)
+	if n := len(ca.bwSamples); n > bbrBwWindow {
+		ca.bwSamples = ca.bwSamples[n-bbrBwWindow:]
+	}
+
+	if ca.filledPipe {
+		return
+	}
+	if bw := ca.btlBw(); bw >= ca.fullBw*bbrFullBwThresh {
+		ca.fullBw, ca.fullBwCount = bw, 0
+	} else if ca.fullBwCount++; ca.fullBwCount >= bbrFullBwCount {
+		ca.filledPipe = true
+	}
+}
+
+func (ca *bbr) Decrease(now time.Time) {
+	ca.filledPipe = true
+}
@@ -0,0 +1,63 @@
+package segmented_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn/segmented"
+)
+
+func TestAIMD(t *testing.T) {
+	assert, _ := makeAR(t)
+	ca := segmented.NewAIMD()
+	t0 := time.Unix(0, 0)
+	rtt := 100 * time.Millisecond
+
+	assert.Equal(2, ca.Cwnd())
+	for i := 0; i < 8; i++ {
+		ca.Increase(t0, rtt)
+	}
+	assert.Equal(10, ca.Cwnd()) // slow start
+
+	ca.Decrease(t0.Add(time.Second))
+	assert.Equal(5, ca.Cwnd())
+	ca.Decrease(t0.Add(time.Second + rtt/2)) // within one RTT, ignored
+	assert.Equal(5, ca.Cwnd())
+
+	for i := 0; i < 6; i++ {
+		ca.Increase(t0, rtt)
+	}
+	assert.Equal(6, ca.Cwnd()) // congestion avoidance
+}
+
+func TestCubic(t *testing.T) {
+	assert, _ := makeAR(t)
+	ca := segmented.NewCubic()
+	t0 := time.Unix(1, 0)
+	rtt := 100 * time.Millisecond
+
+	for i := 0; i < 18; i++ {
+		ca.Increase(t0, rtt)
+	}
+	assert.Equal(20, ca.Cwnd())
+	ca.Decrease(t0.Add(time.Second))
+	assert.Equal(14, ca.Cwnd())
+}
+
+func TestBBR(t *testing.T) {
+	assert, _ := makeAR(t)
+	ca := segmented.NewBBR()
+	rtt := 100 * time.Millisecond
+	const bw = 200.0 // Data per second, BDP is 20
+
+	// deliver Data at bottleneck rate, which does not grow with cwnd
+	now := time.Unix(0, 0)
+	for i := 0; i < 400; i++ {
+		now = now.Add(time.Duration(float64(time.Second) / bw))
+		ca.Increase(now, rtt)
+	}
+	assert.InDelta(2*bw*rtt.Seconds(), ca.Cwnd(), 4)
+
+	ca.Decrease(now)
+	assert.InDelta(2*bw*rtt.Seconds(), ca.Cwnd(), 4)
+}
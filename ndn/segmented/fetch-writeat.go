@@ -0,0 +1,184 @@
+package segmented
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+)
+
+// checkpointInterval is the interval of saving checkpoint file during WriteAt.
+const checkpointInterval = time.Second
+
+// fetchCheckpoint records progress of WriteAt.
+type fetchCheckpoint struct {
+	// Name is the name prefix of segments, after version discovery.
+	Name ndn.Name `json:"name"`
+	// ChunkSize is the payload length of every segment except the last.
+	ChunkSize int `json:"chunkSize,omitempty"`
+	// FinalBlock is the last segment number plus one, zero if unknown.
+	FinalBlock uint64 `json:"finalBlock,omitempty"`
+	// Completed is a bitmap of segments that have been written.
+	Completed []byte `json:"completed"`
+}
+
+func (cp fetchCheckpoint) Has(seg uint64) bool {
+	i := seg / 8
+	return i < uint64(len(cp.Completed)) && cp.Completed[i]&(1<<(seg%8)) != 0
+}
+
+func (cp *fetchCheckpoint) Set(seg uint64) {
+	i := seg / 8
+	if n := i + 1; n > uint64(len(cp.Completed)) {
+		cp.Completed = append(cp.Completed, make([]byte, n-uint64(len(cp.Completed)))...)
+	}
+	cp.Completed[i] |= 1 << (seg % 8)
+}
+
+func (cp *fetchCheckpoint) Load(filename string) bool {
+	file, e := os.Open(filename)
+	if e != nil {
+		return false
+	}
+	defer file.Close()
+	return json.NewDecoder(file).Decode(cp) == nil
+}
+
+func (cp fetchCheckpoint) Save(filename string) error {
+	j, e := json.Marshal(cp)
+	if e != nil {
+		return e
+	}
+	tmp := filename + ".tmp"
+	if e = os.WriteFile(tmp, j, 0o644); e != nil {
+		return e
+	}
+	return os.Rename(tmp, filename)
+}
+
+// errSegmentSize indicates segments have inconsistent payload lengths, so that offsets cannot be determined.
+var errSegmentSize = errors.New("inconsistent segment payload length")
+
+func (f *fetcher) WriteAt(ctx context.Context, w io.WriterAt, checkpoint string) (e error) {
+	if e = f.discover(ctx); e != nil {
+		return e
+	}
+
+	var cp fetchCheckpoint
+	if checkpoint == "" || !cp.Load(checkpoint) || !cp.Name.Equal(f.prefix) {
+		cp = fetchCheckpoint{}
+	}
+	cp.Name = f.prefix
+	if cp.FinalBlock != 0 {
+		f.finalBlock = cp.FinalBlock
+	}
+	resumed := fetchCheckpoint{Completed: append([]byte(nil), cp.Completed...)}
+	f.skip = resumed.Has
+
+	save := func() error {
+		if checkpoint == "" {
+			return nil
+		}
+		if syncer, ok := w.(interface{ Sync() error }); ok {
+			if e := syncer.Sync(); e != nil {
+				return e
+			}
+		}
+		return cp.Save(checkpoint)
+	}
+
+	pending := map[uint64]*ndn.Data{} // segments received before ChunkSize is known
+	write := func(seg uint64, data *ndn.Data) error {
+		if _, e := w.WriteAt(data.Content, int64(seg)*int64(cp.ChunkSize)); e != nil {
+			return e
+		}
+		cp.Set(seg)
+		return nil
+	}
+	receive := func(data *ndn.Data) error {
+		seg, ok := extractSegment(data.Name, len(f.prefix))
+		if !ok {
+			return nil
+		}
+		if finalSeg, ok := ndn.SegmentConvention.Parse(data.FinalBlock); ok {
+			cp.FinalBlock = finalSeg + 1
+		}
+		if !data.IsFinalBlock() {
+			switch cp.ChunkSize {
+			case 0:
+				cp.ChunkSize = len(data.Content)
+			case len(data.Content):
+			default:
+				return errSegmentSize
+			}
+		} else if len(data.Content) > cp.ChunkSize && cp.ChunkSize != 0 {
+			return errSegmentSize
+		}
+
+		if cp.ChunkSize == 0 && seg != 0 {
+			pending[seg] = data
+			return nil
+		}
+		if e := write(seg, data); e != nil {
+			return e
+		}
+		for seg, data := range pending {
+			if e := write(seg, data); e != nil {
+				return e
+			}
+			delete(pending, seg)
+		}
+		return nil
+	}
+
+	ctx1, cancel1 := context.WithCancel(ctx)
+	defer cancel1()
+	unordered := make(chan *ndn.Data)
+	done := make(chan error, 1)
+	go func() { done <- f.Unordered(ctx1, unordered) }()
+
+	ticker := time.NewTicker(checkpointInterval)
+	defer ticker.Stop()
+L:
+	for {
+		select {
+		case data, ok := <-unordered:
+			if !ok {
+				break L
+			}
+			if e == nil {
+				if e = receive(data); e != nil {
+					cancel1()
+				}
+			}
+		case <-ticker.C:
+			if e == nil {
+				if e = save(); e != nil {
+					cancel1()
+				}
+			}
+		}
+	}
+
+	if e1 := <-done; e == nil {
+		e = e1
+	}
+	if e == nil && len(pending) > 0 {
+		e = fmt.Errorf("%d segments are not written: %w", len(pending), errSegmentSize)
+	}
+	if e != nil {
+		if e1 := save(); e1 != nil {
+			return fmt.Errorf("%w (checkpoint not saved: %v)", e, e1)
+		}
+		return e
+	}
+	if checkpoint != "" {
+		os.Remove(checkpoint)
+	}
+	return nil
+}
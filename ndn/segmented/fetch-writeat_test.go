@@ -0,0 +1,69 @@
+package segmented_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type interruptWriterAt struct {
+	*os.File
+	n      int
+	cancel context.CancelFunc
+}
+
+func (w *interruptWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	if w.n--; w.n == 0 {
+		w.cancel()
+	}
+	return w.File.WriteAt(p, off)
+}
+
+func TestWriteAt(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := NewServeFetchFixture(t)
+	fixture.EnableBridge()
+	fixture.FOpt.RetxLimit = 10
+
+	fixture.Prepare(100000, 1000)
+	defer fixture.Serve()()
+
+	dir := t.TempDir()
+	filename, checkpoint := filepath.Join(dir, "output.bin"), filepath.Join(dir, "output.checkpoint")
+	file, e := os.Create(filename)
+	require.NoError(e)
+	defer file.Close()
+
+	ctx1, cancel1 := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel1()
+	f1 := fixture.Fetch()
+	e = f1.WriteAt(ctx1, &interruptWriterAt{File: file, n: 40, cancel: cancel1}, checkpoint)
+	assert.ErrorIs(e, context.Canceled)
+	assert.FileExists(checkpoint)
+
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel2()
+	f2 := fixture.Fetch()
+	e = f2.WriteAt(ctx2, file, checkpoint)
+	require.NoError(e)
+	assert.NoFileExists(checkpoint)
+	assert.Less(f2.Count(), 100)
+	assert.GreaterOrEqual(f1.Count()+f2.Count(), 100)
+
+	payload, e := os.ReadFile(filename)
+	require.NoError(e)
+	assert.Equal(fixture.Payload, payload)
+
+	file2, e := os.Create(filepath.Join(dir, "output2.bin"))
+	require.NoError(e)
+	defer file2.Close()
+	f3 := fixture.Fetch()
+	e = f3.WriteAt(ctx2, file2, "")
+	require.NoError(e)
+	assert.Equal(100, f3.Count())
+	payload, e = os.ReadFile(file2.Name())
+	require.NoError(e)
+	assert.Equal(fixture.Payload, payload)
+}
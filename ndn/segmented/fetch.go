@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"sync"
 	"time"
 
 	mathpkg "github.com/pkg/math"
@@ -15,7 +16,7 @@ import (
 	"github.com/usnistgov/ndn-dpdk/ndn/an"
 	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
 	"github.com/usnistgov/ndn-dpdk/ndn/l3"
-	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+	"github.com/usnistgov/ndn-dpdk/ndn/rdr"
 )
 
 // FetchOptions contains options for Fetch function.
@@ -45,6 +46,25 @@ type FetchOptions struct {
 	// Verifier is a public key to verify Data.
 	// Default is NopVerifier.
 	Verifier ndn.Verifier
+
+	// CongestionControl creates a congestion control algorithm instance for each fetch.
+	// Default is NewCubic.
+	CongestionControl func() CongestionControl
+
+	// TraceStats enables recording RTT samples and congestion window trace in FetchStats.
+	// Default is recording counters only.
+	TraceStats bool
+
+	// DiscoverVersion enables version discovery.
+	// The name passed to Fetch should be unversioned. Before retrieving segments, an RDR discovery
+	// Interest for name/32=metadata determines the latest versioned name.
+	DiscoverVersion bool
+
+	// Manifest enables manifest verification.
+	// Before retrieving segments, the manifest tree under name/32=manifest is retrieved, where the root node is
+	// verified with Verifier. Each segment is then requested by full name with implicit digest, and verified
+	// against the manifest instead of Verifier.
+	Manifest bool
 }
 
 func (opts *FetchOptions) applyDefaults() {
@@ -57,6 +77,39 @@ func (opts *FetchOptions) applyDefaults() {
 	if opts.Verifier == nil {
 		opts.Verifier = ndn.NopVerifier
 	}
+	if opts.CongestionControl == nil {
+		opts.CongestionControl = NewCubic
+	}
+}
+
+// RttSample is an RTT measurement.
+type RttSample struct {
+	Time    time.Time
+	Segment uint64
+	Rtt     time.Duration
+}
+
+// CwndSample is a congestion window change.
+type CwndSample struct {
+	Time time.Time
+	Cwnd int
+}
+
+// FetchStats contains statistics of a fetch.
+type FetchStats struct {
+	// RttSamples are RTT measurements of segments that are not retransmitted.
+	// This is recorded only if TraceStats is enabled.
+	RttSamples []RttSample
+
+	// CwndTrace contains congestion window changes.
+	// This is recorded only if TraceStats is enabled.
+	CwndTrace []CwndSample
+
+	// NRetx is the number of retransmitted Interests.
+	NRetx int
+
+	// NCongMarks is the number of Data packets with congestion mark.
+	NCongMarks int
 }
 
 // FetchResult contains result of Fetch function.
@@ -72,13 +125,23 @@ type FetchResult interface {
 	Chunks(ctx context.Context, chunks chan<- []byte) error
 	// Pipe writes the payload to the Writer.
 	Pipe(ctx context.Context, w io.Writer) error
+	// WriteAt writes the payload to the WriterAt, as segments arrive in any order.
+	// If checkpoint is not empty, it names a file that records completed segments; if the file exists, the fetch
+	// resumes from where it was left off. The checkpoint file is deleted after the fetch completes.
+	WriteAt(ctx context.Context, w io.WriterAt, checkpoint string) error
 	// Packet returns a slice of Data packets.
 	Packets(ctx context.Context) ([]*ndn.Data, error)
 	// Payload returns reassembled payload.
 	Payload(ctx context.Context) ([]byte, error)
 
+	// Name returns the name prefix of segments.
+	// If version discovery is enabled, this is available after discovery succeeds.
+	Name() ndn.Name
+
 	// Count returns the number of segments retrieved so far.
 	Count() int
+	// Stats returns statistics collected so far.
+	Stats() FetchStats
 	// EstimatedTotal returns the estimated number of total segments.
 	// Returns -1 if unknown.
 	EstimatedTotal() int
@@ -96,18 +159,98 @@ func Fetch(name ndn.Name, opts FetchOptions) FetchResult {
 
 type fetcher struct {
 	FetchOptions
-	prefix     ndn.Name
+	prefix     ndn.Name // written only by discover, while holding statsMutex
 	count      int
 	finalBlock uint64
+	skip       func(seg uint64) bool // segments already retrieved, not to be requested
+	digests    [][32]byte            // segment implicit digests from manifest
+
+	statsMutex sync.Mutex
+	stats      FetchStats
+	lastCwnd   int
+}
+
+func (f *fetcher) recordRtt(now time.Time, seg uint64, rtt time.Duration) {
+	if !f.TraceStats {
+		return
+	}
+	f.statsMutex.Lock()
+	defer f.statsMutex.Unlock()
+	f.stats.RttSamples = append(f.stats.RttSamples, RttSample{Time: now, Segment: seg, Rtt: rtt})
+}
+
+func (f *fetcher) recordCwnd(now time.Time, cwnd int) {
+	if !f.TraceStats || cwnd == f.lastCwnd {
+		return
+	}
+	f.lastCwnd = cwnd
+	f.statsMutex.Lock()
+	defer f.statsMutex.Unlock()
+	f.stats.CwndTrace = append(f.stats.CwndTrace, CwndSample{Time: now, Cwnd: cwnd})
+}
+
+func (f *fetcher) countStats(nRetx, nCongMarks int) {
+	f.statsMutex.Lock()
+	defer f.statsMutex.Unlock()
+	f.stats.NRetx += nRetx
+	f.stats.NCongMarks += nCongMarks
 }
 
 func (f *fetcher) makeInterest(seg uint64) ndn.Interest {
-	name := f.prefix.Append(makeSegmentNameComponent(seg))
+	name := f.prefix.Append(ndn.SegmentConvention.Make(seg))
+	if f.digests != nil && seg < uint64(len(f.digests)) {
+		name = append(name, ndn.MakeNameComponent(an.TtImplicitSha256DigestComponent, f.digests[seg][:]))
+	}
 	return ndn.MakeInterest(name)
 }
 
+// discover determines the versioned name via RDR, if version discovery is enabled.
+func (f *fetcher) discover(ctx context.Context) error {
+	if !f.DiscoverVersion {
+		return nil
+	}
+
+	var m rdr.Metadata
+	if e := rdr.RetrieveMetadata(ctx, &m, f.prefix, endpoint.ConsumerOptions{
+		Fw:       f.Fw,
+		Retx:     endpoint.RetxOptions{Limit: f.RetxLimit},
+		Verifier: f.Verifier,
+	}); e != nil {
+		return fmt.Errorf("version discovery: %w", e)
+	}
+	if len(m.Name) <= len(f.prefix) || !f.prefix.IsPrefixOf(m.Name) {
+		return fmt.Errorf("version discovery: %s is not under %s", m.Name, f.prefix)
+	}
+
+	f.statsMutex.Lock()
+	f.prefix = m.Name
+	f.statsMutex.Unlock()
+	f.DiscoverVersion = false
+	return nil
+}
+
+// loadManifest retrieves the manifest tree, if manifest verification is enabled.
+func (f *fetcher) loadManifest(ctx context.Context) (e error) {
+	if !f.Manifest || f.digests != nil {
+		return nil
+	}
+
+	if f.digests, e = f.fetchManifest(ctx); e != nil {
+		return e
+	}
+	f.finalBlock = uint64(len(f.digests))
+	return nil
+}
+
 func (f *fetcher) Unordered(ctx context.Context, unordered chan<- *ndn.Data) error {
 	defer close(unordered)
+	if e := f.discover(ctx); e != nil {
+		return e
+	}
+	if e := f.loadManifest(ctx); e != nil {
+		return e
+	}
+
 	face, e := endpoint.NewLFace(f.Fw)
 	if e != nil {
 		return e
@@ -115,11 +258,12 @@ func (f *fetcher) Unordered(ctx context.Context, unordered chan<- *ndn.Data) err
 	defer face.Close()
 
 	rtte := newRttEstimator()
-	ca := newCubic()
+	ca := f.CongestionControl()
+	f.recordCwnd(time.Now(), ca.Cwnd())
 	pendings := make(map[uint64]*fetchSeg)
 	retxQ := list.New()
 	ticker := time.NewTicker(time.Millisecond)
-	segNext, segLast := f.SegmentBegin, f.SegmentEnd-1
+	segNext, segLast := f.SegmentBegin, mathpkg.MinUint64(f.SegmentEnd, f.finalBlock)-1
 	defer ticker.Stop()
 
 	for {
@@ -132,35 +276,35 @@ func (f *fetcher) Unordered(ctx context.Context, unordered chan<- *ndn.Data) err
 
 		case l3pkt := <-face.Rx():
 			pkt := l3pkt.ToPacket()
-			if pkt.Data == nil || f.Verifier.Verify(pkt.Data) != nil {
+			if pkt.Data == nil {
 				break
 			}
 			now := time.Now()
 
 			seg, ok := extractSegment(pkt.Data.Name, len(f.prefix))
-			if !ok || !f.prefix.IsPrefixOf(pkt.Data.Name) {
+			if !ok || !f.prefix.IsPrefixOf(pkt.Data.Name) || f.verifySegment(seg, pkt.Data) != nil {
 				break
 			}
 			fs, ok := pendings[seg]
 			if !ok {
 				break
 			}
-			if pkt.Data.FinalBlock.Type == an.TtSegmentNameComponent {
-				var finalSeg tlv.NNI
-				if e := finalSeg.UnmarshalBinary(pkt.Data.FinalBlock.Value); e == nil {
-					f.finalBlock = uint64(finalSeg + 1)
-				}
+			if finalSeg, ok := ndn.SegmentConvention.Parse(pkt.Data.FinalBlock); ok {
+				f.finalBlock = finalSeg + 1
 			}
 
 			rtt := now.Sub(fs.TxTime)
 			if fs.NRetx == 0 {
 				rtte.Push(rtt, len(pendings))
+				f.recordRtt(now, seg, rtt)
 			}
 			if pkt.Lp.CongMark != 0 {
 				ca.Decrease(now)
+				f.countStats(0, 1)
 			} else {
 				ca.Increase(now, rtt)
 			}
+			f.recordCwnd(now, ca.Cwnd())
 
 			if pkt.Data.IsFinalBlock() {
 				segLast = seg
@@ -191,10 +335,15 @@ func (f *fetcher) Unordered(ctx context.Context, unordered chan<- *ndn.Data) err
 				}
 				rtte.Backoff()
 				ca.Decrease(fs.RtoExpiry)
+				f.recordCwnd(now, ca.Cwnd())
 				fs.RetxElement = retxQ.PushBack(seg)
 			}
 		}
 
+		for f.skip != nil && segNext <= segLast && f.skip(segNext) {
+			segNext++
+		}
+
 		switch {
 		case len(pendings)-retxQ.Len() >= mathpkg.Min(ca.Cwnd(), f.MaxCwnd):
 			// congestion window full
@@ -206,6 +355,7 @@ func (f *fetcher) Unordered(ctx context.Context, unordered chan<- *ndn.Data) err
 
 			fs.setTimeNow(rtte.Rto())
 			fs.NRetx++
+			f.countStats(1, 0)
 			face.Send(f.makeInterest(seg).ToPacket())
 
 		case segNext <= segLast:
@@ -316,10 +466,25 @@ func (f *fetcher) Payload(ctx context.Context) ([]byte, error) {
 	return bytes.Join(chunks, nil), nil
 }
 
+func (f *fetcher) Name() ndn.Name {
+	f.statsMutex.Lock()
+	defer f.statsMutex.Unlock()
+	return f.prefix
+}
+
 func (f *fetcher) Count() int {
 	return f.count
 }
 
+func (f *fetcher) Stats() (stats FetchStats) {
+	f.statsMutex.Lock()
+	defer f.statsMutex.Unlock()
+	stats = f.stats
+	stats.RttSamples = append([]RttSample(nil), f.stats.RttSamples...)
+	stats.CwndTrace = append([]CwndSample(nil), f.stats.CwndTrace...)
+	return stats
+}
+
 func (f *fetcher) EstimatedTotal() int {
 	segLast := mathpkg.MinUint64(f.SegmentEnd, f.finalBlock)
 	if segLast == math.MaxUint64 {
@@ -0,0 +1,267 @@
+package segmented
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// KeywordManifest is the 32=manifest component.
+//
+// The root manifest node of a segmented object is named <prefix>/32=manifest, and is signed.
+// Other manifest nodes are named <prefix>/32=manifest/32=node, and are retrieved by full name with implicit digest.
+var KeywordManifest = ndn.MakeNameComponent(an.TtKeywordNameComponent, []byte("manifest"))
+
+var keywordManifestNode = ndn.MakeNameComponent(an.TtKeywordNameComponent, []byte("node"))
+
+// TLV-TYPE numbers in manifest node encoding.
+// These are application-specific, because File-Like ICN Collection (FLIC) has no assigned numbers in NDN.
+const (
+	TtManifestHashGroup    = 0xE1
+	TtManifestStartSegment = 0xE3
+	TtManifestIsInternal   = 0xE5
+	TtManifestPtr          = 0xE7
+)
+
+// Error conditions.
+var (
+	ErrManifest = errors.New("bad manifest")
+)
+
+// ManifestHashGroup is a group of pointers in a manifest node.
+//
+// If IsInternal is false, each pointer is the implicit digest of a segment, where the first pointer is
+// segment StartSegment and subsequent pointers are consecutive segments.
+// If IsInternal is true, each pointer is the implicit digest of a child manifest node, where the first pointer
+// covers segments beginning at StartSegment.
+type ManifestHashGroup struct {
+	StartSegment uint64     `tlv:"0xE3"`
+	IsInternal   bool       `tlv:"0xE5"`
+	Ptrs         [][32]byte `tlv:"0xE7"`
+}
+
+// ManifestNode is the content of a manifest Data packet.
+// This is a simplified form of FLIC manifest, in which a node contains one or more hash groups.
+type ManifestNode struct {
+	Groups []ManifestHashGroup `tlv:"0xE1"`
+}
+
+// MarshalBinary encodes to TLV-VALUE.
+func (node ManifestNode) MarshalBinary() (value []byte, e error) {
+	return tlv.Marshal(node)
+}
+
+// UnmarshalBinary decodes from TLV-VALUE.
+func (node *ManifestNode) UnmarshalBinary(value []byte) error {
+	return tlv.Unmarshal(value, node)
+}
+
+// manifestTree is a manifest tree on the publisher side.
+type manifestTree struct {
+	rootName ndn.Name
+	nodeName ndn.Name
+	root     ndn.Data
+	nodes    map[[32]byte]ndn.Data
+}
+
+// buildManifestTree builds a manifest tree over segments.
+// makeSegment constructs a segment Data packet, which must be deterministic so that its implicit digest is stable.
+// The root node is signed by rootSigner; other nodes are signed with DigestSigning.
+func buildManifestTree(prefix ndn.Name, nSegments uint64, fanout int,
+	makeSegment func(seg uint64) (ndn.Data, error), rootSigner ndn.Signer) (tree *manifestTree, e error) {
+	tree = &manifestTree{
+		rootName: prefix.Append(KeywordManifest),
+		nodeName: prefix.Append(KeywordManifest, keywordManifestNode),
+		nodes:    map[[32]byte]ndn.Data{},
+	}
+
+	// pointers in current level, starting from segments, and first segment number covered by each pointer
+	digests, starts := make([][32]byte, nSegments), make([]uint64, nSegments)
+	for seg := range digests {
+		data, e := makeSegment(uint64(seg))
+		if e != nil {
+			return nil, e
+		}
+		copy(digests[seg][:], data.ComputeDigest())
+		starts[seg] = uint64(seg)
+	}
+
+	for isInternal := false; ; isInternal = true {
+		nNodes := (len(digests) + fanout - 1) / fanout
+		isRoot := nNodes <= 1
+		nextDigests, nextStarts := make([][32]byte, nNodes), make([]uint64, nNodes)
+		for i := 0; i < nNodes; i++ {
+			first, last := i*fanout, (i+1)*fanout
+			if last > len(digests) {
+				last = len(digests)
+			}
+			node := ManifestNode{Groups: []ManifestHashGroup{{
+				StartSegment: starts[first],
+				IsInternal:   isInternal,
+				Ptrs:         digests[first:last],
+			}}}
+			content, e := node.MarshalBinary()
+			if e != nil {
+				return nil, e
+			}
+
+			data := ndn.Data{Name: tree.nodeName, Content: content}
+			var signer ndn.Signer = ndn.DigestSigning
+			if isRoot {
+				data.Name, signer = tree.rootName, rootSigner
+			}
+			if e := signer.Sign(&data); e != nil {
+				return nil, e
+			}
+
+			if isRoot {
+				tree.root = data
+				return tree, nil
+			}
+			copy(nextDigests[i][:], data.ComputeDigest())
+			nextStarts[i] = starts[first]
+			tree.nodes[nextDigests[i]] = data
+		}
+		digests, starts = nextDigests, nextStarts
+	}
+}
+
+// Get retrieves a manifest node by name.
+func (tree *manifestTree) Get(name ndn.Name) (data ndn.Data, ok bool) {
+	switch {
+	case name.Equal(tree.rootName):
+		return tree.root, true
+	case len(name) == len(tree.nodeName)+1 && tree.nodeName.IsPrefixOf(name) &&
+		name[len(tree.nodeName)].Type == an.TtImplicitSha256DigestComponent:
+		var digest [32]byte
+		copy(digest[:], name[len(tree.nodeName)].Value)
+		data, ok = tree.nodes[digest]
+		return data, ok
+	}
+	return data, false
+}
+
+// manifestFetchConcurrency is the number of manifest nodes retrieved in parallel.
+const manifestFetchConcurrency = 16
+
+// fetchManifest retrieves and verifies the manifest tree, and returns segment implicit digests.
+func (f *fetcher) fetchManifest(ctx context.Context) (digests [][32]byte, e error) {
+	opts := endpoint.ConsumerOptions{
+		Fw:       f.Fw,
+		Retx:     endpoint.RetxOptions{Limit: f.RetxLimit},
+		Verifier: f.Verifier,
+	}
+	root, e := endpoint.Consume(ctx, ndn.MakeInterest(f.prefix.Append(KeywordManifest)), opts)
+	if e != nil {
+		return nil, fmt.Errorf("manifest root: %w", e)
+	}
+
+	type segDigest struct {
+		seg    uint64
+		digest [32]byte
+	}
+	var mutex sync.Mutex
+	var segs []segDigest
+	var firstErr error
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, manifestFetchConcurrency)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	fail := func(e error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if firstErr == nil {
+			firstErr = e
+			cancel()
+		}
+	}
+
+	var processNode func(data *ndn.Data)
+	processNode = func(data *ndn.Data) {
+		var node ManifestNode
+		if e := node.UnmarshalBinary(data.Content); e != nil {
+			fail(fmt.Errorf("%w %s: %v", ErrManifest, data.Name, e))
+			return
+		}
+		for _, g := range node.Groups {
+			if !g.IsInternal {
+				mutex.Lock()
+				for i, ptr := range g.Ptrs {
+					segs = append(segs, segDigest{g.StartSegment + uint64(i), ptr})
+				}
+				mutex.Unlock()
+				continue
+			}
+
+			for _, ptr := range g.Ptrs {
+				ptr := ptr
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					select {
+					case sem <- struct{}{}:
+					case <-ctx.Done():
+						return
+					}
+					child, e := f.consumeByDigest(ctx, f.prefix.Append(KeywordManifest, keywordManifestNode), ptr, opts)
+					<-sem
+					if e != nil {
+						fail(fmt.Errorf("manifest node: %w", e))
+						return
+					}
+					processNode(child)
+				}()
+			}
+		}
+	}
+	processNode(root)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	digests = make([][32]byte, len(segs))
+	found := make([]bool, len(segs))
+	for _, sd := range segs {
+		if sd.seg >= uint64(len(digests)) || found[sd.seg] {
+			return nil, fmt.Errorf("%w: segment numbers are not consecutive", ErrManifest)
+		}
+		digests[sd.seg], found[sd.seg] = sd.digest, true
+	}
+	if len(digests) == 0 {
+		return nil, fmt.Errorf("%w: no segment", ErrManifest)
+	}
+	return digests, nil
+}
+
+// consumeByDigest retrieves a Data packet by name and implicit digest, and verifies the digest.
+func (f *fetcher) consumeByDigest(ctx context.Context, name ndn.Name, digest [32]byte, opts endpoint.ConsumerOptions) (data *ndn.Data, e error) {
+	interest := ndn.MakeInterest(name.Append(ndn.MakeNameComponent(an.TtImplicitSha256DigestComponent, digest[:])))
+	opts.Verifier = ndn.NopVerifier
+	if data, e = endpoint.Consume(ctx, interest, opts); e != nil {
+		return data, e
+	}
+	if !bytes.Equal(data.ComputeDigest(), digest[:]) {
+		return nil, fmt.Errorf("%w: digest mismatch on %s", ErrManifest, data.Name)
+	}
+	return data, nil
+}
+
+// verifySegment verifies a segment against the manifest if it exists, otherwise with the Verifier.
+func (f *fetcher) verifySegment(seg uint64, data *ndn.Data) error {
+	if f.digests == nil {
+		return f.Verifier.Verify(data)
+	}
+	if seg >= uint64(len(f.digests)) || !bytes.Equal(data.ComputeDigest(), f.digests[seg][:]) {
+		return fmt.Errorf("%w: digest mismatch on segment %d", ErrManifest, seg)
+	}
+	return nil
+}
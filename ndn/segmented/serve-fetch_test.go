@@ -9,6 +9,7 @@ import (
 
 	"github.com/usnistgov/ndn-dpdk/core/testenv"
 	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
 	"github.com/usnistgov/ndn-dpdk/ndn/ndntestenv"
 	"github.com/usnistgov/ndn-dpdk/ndn/segmented"
 	"go4.org/must"
@@ -139,3 +140,130 @@ func TestEmpty(t *testing.T) {
 	require.NoError(e)
 	require.Len(payload, 0)
 }
+
+func TestVersioned(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := NewServeFetchFixture(t)
+	fixture.EnableBridge()
+
+	fixture.Prepare(5000, 1000)
+	fixture.SOpt.Versioned = true
+	fixture.SOpt.Version = 5
+	fixture.FOpt.DiscoverVersion = true
+	closeV5 := fixture.Serve()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+	defer cancel()
+
+	f := fixture.Fetch()
+	payload, e := f.Payload(ctx)
+	require.NoError(e)
+	assert.Equal(fixture.Payload, payload)
+	ndntestenv.NameEqual(assert, "/D/54=%05", f.Name())
+
+	payloadV5 := fixture.Payload
+	closeV5()
+	fixture.Prepare(3000, 1000)
+	fixture.SOpt.Version = 6
+	defer fixture.Serve()()
+
+	f = fixture.Fetch()
+	nameDone := make(chan struct{})
+	go func() { // Name may be called during the fetch
+		for {
+			select {
+			case <-nameDone:
+				return
+			case <-time.After(time.Millisecond):
+				f.Name()
+			}
+		}
+	}()
+	payload, e = f.Payload(ctx)
+	close(nameDone)
+	require.NoError(e)
+	assert.Equal(fixture.Payload, payload)
+	assert.NotEqual(payloadV5, payload)
+	ndntestenv.NameEqual(assert, "/D/54=%06", f.Name())
+
+	ctx1, cancel1 := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel1()
+	fixture.FOpt.DiscoverVersion = false
+	_, e = fixture.Fetch().Payload(ctx1)
+	assert.Error(e)
+}
+
+func TestCongestionControl(t *testing.T) {
+	algos := map[string]func() segmented.CongestionControl{
+		"AIMD":  segmented.NewAIMD,
+		"CUBIC": segmented.NewCubic,
+		"BBR":   segmented.NewBBR,
+	}
+	for name, newCC := range algos {
+		newCC := newCC
+		t.Run(name, func(t *testing.T) {
+			assert, require := makeAR(t)
+			fixture := NewServeFetchFixture(t)
+			fixture.EnableBridge()
+
+			fixture.Prepare(100000, 1000)
+			fixture.FOpt.RetxLimit = 10
+			fixture.FOpt.CongestionControl = newCC
+			fixture.FOpt.TraceStats = true
+			defer fixture.Serve()()
+
+			f := fixture.Fetch()
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			payload, e := f.Payload(ctx)
+			require.NoError(e)
+			assert.Equal(fixture.Payload, payload)
+
+			stats := f.Stats()
+			assert.NotEmpty(stats.RttSamples)
+			assert.LessOrEqual(len(stats.RttSamples), 100)
+			for _, sample := range stats.RttSamples {
+				assert.GreaterOrEqual(sample.Rtt, 80*time.Millisecond)
+			}
+			if assert.NotEmpty(stats.CwndTrace) {
+				assert.Equal(2, stats.CwndTrace[0].Cwnd)
+			}
+			assert.GreaterOrEqual(stats.NRetx, 0)
+		})
+	}
+}
+
+func TestManifest(t *testing.T) {
+	assert, require := makeAR(t)
+	fixture := NewServeFetchFixture(t)
+	fixture.EnableBridge()
+
+	fixture.Prepare(20000, 1000)
+	fixture.SOpt.Versioned = true
+	fixture.SOpt.Manifest = true
+	fixture.SOpt.ManifestFanout = 3
+	keyName := ndn.ParseName("/K/KEY/k")
+	signer, _ := keychain.NewHMACPrivateKey(keyName, []byte("secret"))
+	fixture.SOpt.DataSigner = signer
+	fixture.FOpt.DiscoverVersion = true
+	fixture.FOpt.Manifest = true
+	fixture.FOpt.Verifier, _ = keychain.NewHMACVerifier(keyName, []byte("secret"))
+	defer fixture.Serve()()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+	defer cancel()
+
+	f := fixture.Fetch()
+	pkts, e := f.Packets(ctx)
+	require.NoError(e)
+	require.Len(pkts, 20)
+	for i, pkt := range pkts {
+		assert.Equal(fixture.Payload[i*1000:(i+1)*1000], pkt.Content)
+	}
+
+	ctx1, cancel1 := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel1()
+	fixture.FOpt.Verifier, _ = keychain.NewHMACVerifier(keyName, []byte("other"))
+	_, e = fixture.Fetch().Payload(ctx1)
+	assert.Error(e)
+}
@@ -6,31 +6,18 @@ import (
 	"io"
 	"time"
 
+	mathpkg "github.com/pkg/math"
 	"github.com/usnistgov/ndn-dpdk/ndn"
 	"github.com/usnistgov/ndn-dpdk/ndn/an"
 	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
-	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+	"github.com/usnistgov/ndn-dpdk/ndn/rdr"
 )
 
-func makeSegmentNameComponent(seg uint64) ndn.NameComponent {
-	return ndn.NameComponentFrom(an.TtSegmentNameComponent, tlv.NNI(seg))
-}
-
 func extractSegment(name ndn.Name, prefixLen int) (segment uint64, ok bool) {
 	if len(name) != prefixLen+1 {
 		return 0, false
 	}
-
-	comp := name.Get(-1)
-	if comp.Type != an.TtSegmentNameComponent {
-		return 0, false
-	}
-
-	var value tlv.NNI
-	if e := value.UnmarshalBinary(comp.Value); e != nil {
-		return 0, false
-	}
-	return uint64(value), true
+	return ndn.SegmentConvention.Parse(name.Get(-1))
 }
 
 // ServeOptions contains options for Serve function.
@@ -50,6 +37,30 @@ type ServeOptions struct {
 	// ChunkSize is Data payload length.
 	// Default is 4096.
 	ChunkSize int
+
+	// Versioned enables versioned publishing.
+	// Segments are named Prefix/<version>/<segment>, and RDR discovery Interests for Prefix/32=metadata
+	// are answered with the versioned name.
+	// To publish an updated object, close the previous producer and serve again with a newer version.
+	Versioned bool
+
+	// Version is the version number, used when Versioned is true.
+	// Default is current Unix timestamp in microseconds.
+	Version uint64
+
+	// MetadataFreshness is RDR metadata packet FreshnessPeriod, used when Versioned is true.
+	// Default is 10 milliseconds.
+	MetadataFreshness time.Duration
+
+	// Manifest enables manifest publishing.
+	// A manifest tree containing implicit digests of all segments is published under Prefix/32=manifest,
+	// where only the root node is signed with DataSigner; segments and other manifest nodes are signed with
+	// DigestSigning. Source must implement io.Seeker so that its size can be determined.
+	Manifest bool
+
+	// ManifestFanout is the maximum number of pointers in a manifest node, used when Manifest is true.
+	// Default is as many as fit in ChunkSize.
+	ManifestFanout int
 }
 
 func (opts *ServeOptions) applyDefaults() {
@@ -57,56 +68,124 @@ func (opts *ServeOptions) applyDefaults() {
 	if opts.ChunkSize <= 0 {
 		opts.ChunkSize = 4096
 	}
+	if opts.Versioned {
+		if opts.Version == 0 {
+			opts.Version = uint64(time.Now().UnixMicro())
+		}
+		if opts.MetadataFreshness <= 0 {
+			opts.MetadataFreshness = 10 * time.Millisecond
+		}
+	}
+	if opts.Manifest && opts.ManifestFanout <= 0 {
+		opts.ManifestFanout = mathpkg.Max(2, (opts.ChunkSize-16)/34)
+	}
+}
+
+// makeMetadata creates an RDR metadata packet for a versioned object.
+func (opts ServeOptions) makeMetadata(interest ndn.Interest, versioned ndn.Name) (data ndn.Data, e error) {
+	content, e := rdr.Metadata{Name: versioned}.MarshalBinary()
+	if e != nil {
+		return data, e
+	}
+	name := interest.Name.Append(versioned[len(versioned)-1], ndn.SegmentConvention.Make(0))
+	return ndn.MakeData(name, opts.MetadataFreshness, ndn.FinalBlockFlag, content), nil
 }
 
 // Serve publishes a segmented object.
 func Serve(ctx context.Context, source io.ReaderAt, opts ServeOptions) (endpoint.Producer, error) {
 	opts.applyDefaults()
-	prefixLen := len(opts.Prefix)
+	prefix := opts.Prefix
+	if opts.Versioned {
+		prefix = prefix.Append(ndn.VersionConvention.Make(opts.Version))
+	}
+	prefixLen := len(prefix)
 
 	var finalBlock ndn.NameComponent
+	var nSegments uint64
 	if seeker, ok := source.(io.Seeker); ok {
 		if size, e := seeker.Seek(0, io.SeekEnd); e == nil {
-			lastSeg := uint64(size)/uint64(opts.ChunkSize) - 1
-			if size%int64(opts.ChunkSize) != 0 {
-				lastSeg++
-			}
-			finalBlock = makeSegmentNameComponent(lastSeg)
+			nSegments = mathpkg.MaxUint64(1, (uint64(size)+uint64(opts.ChunkSize)-1)/uint64(opts.ChunkSize))
+			finalBlock = ndn.SegmentConvention.Make(nSegments - 1)
+		}
+	}
+
+	makeSegment := func(name ndn.Name) (data ndn.Data, e error) {
+		if data, e = opts.makeSegment(source, name, finalBlock); e == nil && opts.Manifest {
+			e = ndn.DigestSigning.Sign(&data)
+		}
+		return data, e
+	}
+
+	var manifest *manifestTree
+	if opts.Manifest {
+		if nSegments == 0 {
+			return nil, errors.New("manifest requires source size")
+		}
+		rootSigner := opts.DataSigner
+		if rootSigner == nil {
+			rootSigner = ndn.NullSigner
+		}
+
+		var e error
+		if manifest, e = buildManifestTree(prefix, nSegments, opts.ManifestFanout, func(seg uint64) (ndn.Data, error) {
+			return makeSegment(prefix.Append(ndn.SegmentConvention.Make(seg)))
+		}, rootSigner); e != nil {
+			return nil, e
 		}
 	}
 
 	opts.Handler = func(ctx context.Context, interest ndn.Interest) (data ndn.Data, e error) {
-		seg, ok := extractSegment(interest.Name, prefixLen)
-		if !ok {
-			return data, errors.New("segment component not found")
+		if opts.Versioned && rdr.IsDiscoveryInterest(interest) && len(interest.Name) == len(opts.Prefix)+1 {
+			return opts.makeMetadata(interest, prefix)
 		}
 
-		data.Name = interest.Name
-		data.ContentType = opts.ContentType
-		data.Freshness = opts.Freshness
-		data.FinalBlock = finalBlock
-
-		payload := make([]byte, opts.ChunkSize+1)
-		n, e := source.ReadAt(payload, int64(seg)*int64(opts.ChunkSize))
-		switch n {
-		case 0:
-			if errors.Is(e, io.EOF) {
-				e = nil
+		name := interest.Name
+		if manifest != nil {
+			if node, ok := manifest.Get(name); ok {
+				return node, nil
 			}
-			if seg == 0 {
-				data.FinalBlock = data.Name[prefixLen]
-			} else {
-				e = io.EOF
+			if len(name) > 0 && name.Get(-1).Type == an.TtImplicitSha256DigestComponent {
+				name = name.GetPrefix(-1)
 			}
-			return data, e
-		case opts.ChunkSize + 1:
-			data.Content = payload[:opts.ChunkSize]
-		default:
-			data.Content = payload[:n]
-			data.FinalBlock = data.Name[prefixLen]
 		}
-		return data, nil
+
+		if _, ok := extractSegment(name, prefixLen); !ok || !prefix.IsPrefixOf(name) {
+			return data, errors.New("segment component not found")
+		}
+		return makeSegment(name)
 	}
 
 	return endpoint.Produce(ctx, opts.ProducerOptions)
 }
+
+// makeSegment creates a segment Data packet, whose name ends with a segment number component.
+func (opts ServeOptions) makeSegment(source io.ReaderAt, name ndn.Name, finalBlock ndn.NameComponent) (data ndn.Data, e error) {
+	seg, _ := ndn.SegmentConvention.Parse(name.Get(-1))
+	prefixLen := len(name) - 1
+
+	data.Name = name
+	data.ContentType = opts.ContentType
+	data.Freshness = opts.Freshness
+	data.FinalBlock = finalBlock
+
+	payload := make([]byte, opts.ChunkSize+1)
+	n, e := source.ReadAt(payload, int64(seg)*int64(opts.ChunkSize))
+	switch n {
+	case 0:
+		if errors.Is(e, io.EOF) {
+			e = nil
+		}
+		if seg == 0 {
+			data.FinalBlock = data.Name[prefixLen]
+		} else {
+			e = io.EOF
+		}
+		return data, e
+	case opts.ChunkSize + 1:
+		data.Content = payload[:opts.ChunkSize]
+	default:
+		data.Content = payload[:n]
+		data.FinalBlock = data.Name[prefixLen]
+	}
+	return data, nil
+}
@@ -0,0 +1,257 @@
+package ndn
+
+import (
+	"crypto/rand"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+)
+
+// Error conditions for signed Interest policy.
+var (
+	ErrSigTime   = errors.New("SigTime missing or outside acceptable window")
+	ErrSigSeqNum = errors.New("SigSeqNum missing or not increasing")
+	ErrReplay    = errors.New("SigNonce has been seen")
+)
+
+// SignedInterestPolicy contains options for signed Interest v0.3 replay protection.
+//
+// It can wrap a Signer to add SigNonce, SigTime, and SigSeqNum fields to signed Interests,
+// and wrap a Verifier to check these fields against per-key replay state.
+// Data packets pass through the wrappers unchanged.
+type SignedInterestPolicy struct {
+	// Nonce enables SigNonce field.
+	// Verifier rejects a SigNonce that has been seen recently with the same KeyLocator.
+	Nonce bool
+
+	// NonceLength is the SigNonce length in octets.
+	// Default is 8.
+	NonceLength int
+
+	// NonceTrack is the number of recently seen SigNonce values tracked per key.
+	// Default is 1000.
+	NonceTrack int
+
+	// Time enables SigTime field.
+	// Verifier rejects a SigTime that is outside TimeWindow of current time,
+	// or is not greater than the last accepted SigTime with the same KeyLocator.
+	Time bool
+
+	// TimeWindow is the maximum allowed clock difference between signer and verifier.
+	// Default is 60 seconds.
+	TimeWindow time.Duration
+
+	// SeqNum enables SigSeqNum field.
+	// Verifier rejects a SigSeqNum that is not greater than the last accepted SigSeqNum with the same KeyLocator.
+	// Signer starts from 1, because zero cannot be distinguished from an absent field.
+	SeqNum bool
+
+	// KeyTrack is the maximum number of KeyLocators whose replay state is tracked by Verifier.
+	// When exceeded, the least recently accepted KeyLocator is evicted.
+	// If Time is enabled, replay state of a KeyLocator is also evicted when its last accepted SigTime falls outside
+	// TimeWindow, because any Interest that it could reject would fail the TimeWindow check.
+	// Default is 1000.
+	KeyTrack int
+}
+
+func (p *SignedInterestPolicy) applyDefaults() {
+	if p.NonceLength <= 0 {
+		p.NonceLength = 8
+	}
+	if p.NonceTrack <= 0 {
+		p.NonceTrack = 1000
+	}
+	if p.TimeWindow <= 0 {
+		p.TimeWindow = 60 * time.Second
+	}
+	if p.KeyTrack <= 0 {
+		p.KeyTrack = 1000
+	}
+}
+
+// Signer wraps a Signer to add replay protection fields to signed Interests.
+func (p SignedInterestPolicy) Signer(inner Signer) Signer {
+	p.applyDefaults()
+	return &sigPolicySigner{
+		policy: p,
+		inner:  inner,
+	}
+}
+
+// Verifier wraps a Verifier to check replay protection fields on signed Interests.
+// The wrapped Verifier must verify the signature; replay state is updated only if the signature is valid.
+func (p SignedInterestPolicy) Verifier(inner Verifier) Verifier {
+	p.applyDefaults()
+	v := &sigPolicyVerifier{
+		policy: p,
+		inner:  inner,
+	}
+	v.keys, _ = simplelru.NewLRU(p.KeyTrack, nil)
+	return v
+}
+
+type sigPolicySigner struct {
+	policy     SignedInterestPolicy
+	inner      Signer
+	mutex      sync.Mutex
+	lastTime   uint64
+	lastSeqNum uint64
+}
+
+func (signer *sigPolicySigner) Sign(packet Signable) error {
+	switch packet.(type) {
+	case *Interest:
+	default:
+		return signer.inner.Sign(packet)
+	}
+	return signer.inner.Sign(signableFunc(func(cb func(name Name, si *SigInfo) (LLSign, error)) error {
+		return packet.SignWith(func(name Name, si *SigInfo) (LLSign, error) {
+			llSign, e := cb(name, si)
+			if e != nil {
+				return nil, e
+			}
+			if e = signer.assign(si); e != nil {
+				return nil, e
+			}
+			return llSign, nil
+		})
+	}))
+}
+
+func (signer *sigPolicySigner) assign(si *SigInfo) error {
+	if signer.policy.Nonce {
+		si.Nonce = make([]byte, signer.policy.NonceLength)
+		if _, e := rand.Read(si.Nonce); e != nil {
+			return e
+		}
+	}
+
+	signer.mutex.Lock()
+	defer signer.mutex.Unlock()
+	if signer.policy.Time {
+		t := uint64(time.Now().UnixMilli())
+		if t <= signer.lastTime {
+			t = signer.lastTime + 1
+		}
+		si.Time, signer.lastTime = t, t
+	}
+	if signer.policy.SeqNum {
+		signer.lastSeqNum++
+		si.SeqNum = signer.lastSeqNum
+	}
+	return nil
+}
+
+type signableFunc func(signer func(name Name, si *SigInfo) (LLSign, error)) error
+
+func (f signableFunc) SignWith(signer func(name Name, si *SigInfo) (LLSign, error)) error {
+	return f(signer)
+}
+
+type verifiableFunc func(verifier func(name Name, si SigInfo) (LLVerify, error)) error
+
+func (f verifiableFunc) VerifyWith(verifier func(name Name, si SigInfo) (LLVerify, error)) error {
+	return f(verifier)
+}
+
+type sigPolicyKeyState struct {
+	lastTime   uint64
+	lastSeqNum uint64
+	nonces     *simplelru.LRU
+}
+
+type sigPolicyVerifier struct {
+	policy SignedInterestPolicy
+	inner  Verifier
+	mutex  sync.Mutex
+	keys   *simplelru.LRU // KeyLocator string => *sigPolicyKeyState, ordered by last acceptance
+}
+
+func (v *sigPolicyVerifier) Verify(packet Verifiable) error {
+	switch packet.(type) {
+	case Interest, *Interest:
+	default:
+		return v.inner.Verify(packet)
+	}
+
+	var si SigInfo
+	if e := v.inner.Verify(verifiableFunc(func(cb func(name Name, si SigInfo) (LLVerify, error)) error {
+		return packet.VerifyWith(func(name Name, siV SigInfo) (LLVerify, error) {
+			si = siV
+			return cb(name, siV)
+		})
+	})); e != nil {
+		return e
+	}
+	return v.check(si)
+}
+
+func (v *sigPolicyVerifier) check(si SigInfo) error {
+	now := time.Now()
+	if v.policy.Time {
+		t := time.UnixMilli(int64(si.Time))
+		if si.Time == 0 || t.Before(now.Add(-v.policy.TimeWindow)) || t.After(now.Add(v.policy.TimeWindow)) {
+			return ErrSigTime
+		}
+	}
+	if v.policy.SeqNum && si.SeqNum == 0 {
+		return ErrSigSeqNum
+	}
+	if v.policy.Nonce && len(si.Nonce) == 0 {
+		return ErrSigNonce
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.evictExpired(now)
+	key := si.KeyLocator.String()
+	var st *sigPolicyKeyState
+	if value, ok := v.keys.Peek(key); ok {
+		st = value.(*sigPolicyKeyState)
+	} else {
+		st = &sigPolicyKeyState{}
+		st.nonces, _ = simplelru.NewLRU(v.policy.NonceTrack, nil)
+	}
+
+	if v.policy.Time && si.Time <= st.lastTime {
+		return ErrSigTime
+	}
+	if v.policy.SeqNum && si.SeqNum <= st.lastSeqNum {
+		return ErrSigSeqNum
+	}
+	if v.policy.Nonce && st.nonces.Contains(string(si.Nonce)) {
+		return ErrReplay
+	}
+
+	if v.policy.Time {
+		st.lastTime = si.Time
+	}
+	if v.policy.SeqNum {
+		st.lastSeqNum = si.SeqNum
+	}
+	if v.policy.Nonce {
+		st.nonces.Add(string(si.Nonce), true)
+	}
+	v.keys.Add(key, st)
+	return nil
+}
+
+// evictExpired evicts replay state whose last accepted SigTime is outside TimeWindow.
+// Such state cannot reject any Interest, because a replayed Interest would fail the TimeWindow check.
+// Keys are visited from the least recently accepted, and the scan stops at the first unexpired entry.
+// Caller must hold v.mutex.
+func (v *sigPolicyVerifier) evictExpired(now time.Time) {
+	if !v.policy.Time {
+		return
+	}
+	cutoff := now.Add(-v.policy.TimeWindow)
+	for {
+		_, value, ok := v.keys.GetOldest()
+		if !ok || !time.UnixMilli(int64(value.(*sigPolicyKeyState).lastTime)).Before(cutoff) {
+			return
+		}
+		v.keys.RemoveOldest()
+	}
+}
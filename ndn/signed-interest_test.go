@@ -0,0 +1,119 @@
+package ndn_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/keychain"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+func TestSigInfoReplayFields(t *testing.T) {
+	assert, require := makeAR(t)
+
+	si := ndn.SigInfo{
+		Type:   an.SigSha256,
+		Nonce:  []byte{0xA0, 0xA1},
+		Time:   0x0178F3A9A000,
+		SeqNum: 7,
+	}
+	wire, e := tlv.EncodeFrom(si.EncodeAs(an.TtISigInfo))
+	require.NoError(e)
+	assert.Equal(bytesFromHex("2C14 1B0100 28080000 0178F3A9A000 2602A0A1 2A0107"), wire)
+
+	var decoded ndn.SigInfo
+	require.NoError(decoded.UnmarshalBinary(wire[2:]))
+	assert.Equal(si.Nonce, decoded.Nonce)
+	assert.Equal(si.Time, decoded.Time)
+	assert.Equal(si.SeqNum, decoded.SeqNum)
+}
+
+func TestSignedInterestPolicy(t *testing.T) {
+	assert, require := makeAR(t)
+
+	makeInterests := func(signer ndn.Signer, n int) (list []ndn.Interest) {
+		for i := 0; i < n; i++ {
+			interest := ndn.MakeInterest("/A", []byte{0xC0})
+			require.NoError(signer.Sign(&interest))
+			list = append(list, interest)
+		}
+		return list
+	}
+
+	policy := ndn.SignedInterestPolicy{Nonce: true, Time: true, SeqNum: true}
+	signer := policy.Signer(ndn.DigestSigning)
+	interests := makeInterests(signer, 3)
+	for i, interest := range interests {
+		assert.Len(interest.SigInfo.Nonce, 8, i)
+		assert.NotZero(interest.SigInfo.Time, i)
+		assert.EqualValues(i+1, interest.SigInfo.SeqNum, i)
+		if i > 0 {
+			assert.Greater(interest.SigInfo.Time, interests[i-1].SigInfo.Time)
+		}
+	}
+
+	verifier := policy.Verifier(ndn.DigestSigning)
+	assert.NoError(verifier.Verify(interests[0]))
+	assert.NoError(verifier.Verify(&interests[2]))
+	assert.Error(verifier.Verify(interests[1]))
+	assert.Error(verifier.Verify(interests[2]))
+
+	data := ndn.MakeData("/A")
+	require.NoError(signer.Sign(&data))
+	assert.Zero(data.SigInfo.Time)
+	assert.NoError(verifier.Verify(data))
+	assert.NoError(verifier.Verify(data))
+
+	unprotected := makeInterests(ndn.DigestSigning, 1)[0]
+	assert.ErrorIs(verifier.Verify(unprotected), ndn.ErrSigTime)
+
+	forged := makeInterests(signer, 1)[0]
+	forged.SigValue[0] ^= 0xFF
+	assert.ErrorIs(verifier.Verify(forged), ndn.ErrSigValue)
+
+	nonceOnly := ndn.SignedInterestPolicy{Nonce: true, NonceLength: 4}
+	interests = makeInterests(nonceOnly.Signer(ndn.DigestSigning), 2)
+	verifier = nonceOnly.Verifier(ndn.DigestSigning)
+	assert.Len(interests[0].SigInfo.Nonce, 4)
+	assert.NoError(verifier.Verify(interests[1]))
+	assert.NoError(verifier.Verify(interests[0]))
+	assert.ErrorIs(verifier.Verify(interests[1]), ndn.ErrReplay)
+
+	seqNumOnly := ndn.SignedInterestPolicy{SeqNum: true}
+	interests = makeInterests(seqNumOnly.Signer(ndn.DigestSigning), 2)
+	verifier = seqNumOnly.Verifier(ndn.DigestSigning)
+	assert.NoError(verifier.Verify(interests[1]))
+	assert.ErrorIs(verifier.Verify(interests[0]), ndn.ErrSigSeqNum)
+
+	timeOnly := ndn.SignedInterestPolicy{Time: true, TimeWindow: time.Second}
+	verifier = timeOnly.Verifier(ndn.DigestSigning)
+	stale := ndn.MakeInterest("/A", []byte{0xC0})
+	require.NoError(ndn.DigestSigning.Sign(&stale))
+	stale.SigInfo.Time = uint64(time.Now().Add(-2 * time.Second).UnixMilli())
+	require.NoError(ndn.DigestSigning.Sign(&stale))
+	assert.ErrorIs(verifier.Verify(stale), ndn.ErrSigTime)
+
+	seqNumKeys := ndn.SignedInterestPolicy{SeqNum: true, KeyTrack: 1}
+	signerA, e := keychain.NewHMACPrivateKey(ndn.ParseName("/KA"), []byte("A"))
+	require.NoError(e)
+	signerB, e := keychain.NewHMACPrivateKey(ndn.ParseName("/KB"), []byte("B"))
+	require.NoError(e)
+	interestsA := makeInterests(seqNumKeys.Signer(signerA), 2)
+	interestsB := makeInterests(seqNumKeys.Signer(signerB), 1)
+	verifier = seqNumKeys.Verifier(acceptVerifier{})
+	assert.NoError(verifier.Verify(interestsA[1]))
+	assert.ErrorIs(verifier.Verify(interestsA[0]), ndn.ErrSigSeqNum)
+	assert.NoError(verifier.Verify(interestsB[0])) // evicts replay state of /KA
+	assert.NoError(verifier.Verify(interestsA[0]))
+}
+
+// acceptVerifier accepts every signature.
+type acceptVerifier struct{}
+
+func (acceptVerifier) Verify(packet ndn.Verifiable) error {
+	return packet.VerifyWith(func(name ndn.Name, si ndn.SigInfo) (ndn.LLVerify, error) {
+		return func(input, sig []byte) error { return nil }, nil
+	})
+}
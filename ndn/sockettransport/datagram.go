@@ -28,7 +28,7 @@ type pipeImpl struct {
 	datagramImpl
 }
 
-func (pipeImpl) Dial(network, local, remote string) (net.Conn, error) {
+func (pipeImpl) Dial(network, local, remote string, cfg Config) (net.Conn, error) {
 	return nil, fmt.Errorf("cannot dial %s", network)
 }
 
@@ -36,7 +36,7 @@ type udpImpl struct {
 	datagramImpl
 }
 
-func (udpImpl) Dial(network, local, remote string) (net.Conn, error) {
+func (udpImpl) Dial(network, local, remote string, cfg Config) (net.Conn, error) {
 	return greuse.Dial(network, local, remote)
 }
 
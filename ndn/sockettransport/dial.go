@@ -23,7 +23,7 @@ func (dialer Dialer) Dial(network, local, remote string) (Transport, error) {
 		return nil, fmt.Errorf("unknown network %s", network)
 	}
 
-	conn, e := impl.Dial(network, local, remote)
+	conn, e := impl.Dial(network, local, remote, dialer.Config)
 	if e != nil {
 		return nil, e
 	}
@@ -6,7 +6,7 @@ import (
 
 type impl interface {
 	// Dial the socket.
-	Dial(network, local, remote string) (net.Conn, error)
+	Dial(network, local, remote string, cfg Config) (net.Conn, error)
 
 	// Redial the socket.
 	Redial(oldConn net.Conn) (net.Conn, error)
@@ -17,10 +17,15 @@ type impl interface {
 
 var implByNetwork = make(map[string]impl)
 
+// mtuLimiter is an optional interface of impl that limits the transport MTU.
+type mtuLimiter interface {
+	MaxMTU() int
+}
+
 // noLocalAddrDialer dials with only remote addr.
 type noLocalAddrDialer struct{}
 
-func (noLocalAddrDialer) Dial(network, local, remote string) (net.Conn, error) {
+func (noLocalAddrDialer) Dial(network, local, remote string, cfg Config) (net.Conn, error) {
 	return net.Dial(network, remote)
 }
 
@@ -2,6 +2,7 @@
 package sockettransport
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 	"sync/atomic"
@@ -17,6 +18,7 @@ type Config struct {
 
 	// MTU is maximum outgoing packet size.
 	// The default is 0, which means unlimited.
+	// With "h3" network, it cannot exceed WebTransportMTU.
 	MTU int
 
 	// RxBufferLength is the packet buffer length allocated for incoming packets.
@@ -32,6 +34,10 @@ type Config struct {
 	// The default is 60s.
 	// The minimum is RedialBackoffInitial.
 	RedialBackoffMaximum time.Duration
+
+	// TLSClientConfig is the TLS configuration when dialing "wss" and "h3" networks.
+	// The default verifies the server certificate against system roots.
+	TLSClientConfig *tls.Config
 }
 
 func (cfg *Config) applyDefaults() {
@@ -103,6 +109,9 @@ func New(conn net.Conn, cfg Config) (Transport, error) {
 		return nil, fmt.Errorf("unknown network %s", network)
 	}
 	cfg.applyDefaults()
+	if ml, ok := impl.(mtuLimiter); ok && (cfg.MTU <= 0 || cfg.MTU > ml.MaxMTU()) {
+		cfg.MTU = ml.MaxMTU()
+	}
 
 	tr := &transport{
 		cfg:     cfg,
@@ -191,8 +200,12 @@ func (tr *transport) handleError(e error) {
 	tr.setDown(true)
 
 	backoff := tr.cfg.RedialBackoffInitial
-	for !tr.isClosed() {
-		time.Sleep(backoff)
+	for {
+		select {
+		case <-tr.closing:
+			return
+		case <-time.After(backoff):
+		}
 		backoff = time.Duration(math.MinInt64(int64(backoff*2), int64(tr.cfg.RedialBackoffMaximum)))
 
 		conn, e := tr.impl.Redial(tr.Conn())
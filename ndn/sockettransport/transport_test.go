@@ -1,10 +1,15 @@
 package sockettransport_test
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"net"
+	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 
+	"github.com/gorilla/websocket"
 	"github.com/usnistgov/ndn-dpdk/core/testenv"
 	"github.com/usnistgov/ndn-dpdk/ndn/ndntestenv"
 	"github.com/usnistgov/ndn-dpdk/ndn/sockettransport"
@@ -92,3 +97,85 @@ func checkStream(t *testing.T, listener net.Listener) {
 	var c ndntestenv.L3FaceTester
 	c.CheckTransport(t, trA, trB)
 }
+
+func TestWebSocket(t *testing.T) {
+	assert, require := makeAR(t)
+
+	accepted := make(chan sockettransport.Transport, 1)
+	server := httptest.NewServer(sockettransport.WebSocketHandler{
+		Accept: func(tr sockettransport.Transport) { accepted <- tr },
+	})
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ndn"
+
+	var dialer sockettransport.Dialer
+	_, e := dialer.Dial("wss", "", url)
+	assert.Error(e)
+
+	trA, e := dialer.Dial("ws", "", url)
+	require.NoError(e)
+	trB := <-accepted
+
+	connA, connB := trA.Conn(), trB.Conn()
+	assert.Equal("ws", connA.RemoteAddr().Network())
+	assert.Equal(url, connA.RemoteAddr().String())
+	assert.Equal("ws", connB.RemoteAddr().Network())
+	assert.Equal(connA.LocalAddr().String(), connB.RemoteAddr().String())
+
+	var c ndntestenv.L3FaceTester
+	c.CheckTransport(t, trA, trB)
+
+	// oversized message fails the connection
+	limited := httptest.NewServer(sockettransport.WebSocketHandler{
+		Config: sockettransport.Config{RxBufferLength: 1024},
+		Accept: func(tr sockettransport.Transport) { accepted <- tr },
+	})
+	defer limited.Close()
+	ws, _, e := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(limited.URL, "http")+"/ndn", nil)
+	require.NoError(e)
+	defer ws.Close()
+	<-accepted
+	require.NoError(ws.WriteMessage(websocket.BinaryMessage, make([]byte, 2048)))
+	_, _, e = ws.ReadMessage()
+	assert.True(websocket.IsCloseError(e, websocket.CloseMessageTooBig), "%v", e)
+}
+
+func TestWebTransport(t *testing.T) {
+	assert, require := makeAR(t)
+
+	cert, _, e := sockettransport.GenerateWebTransportCertificate("127.0.0.1")
+	require.NoError(e)
+	accepted := make(chan sockettransport.Transport, 1)
+	listener, e := sockettransport.ListenWebTransport("127.0.0.1:0", sockettransport.WebTransportListenerConfig{
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+		Accept:    func(tr sockettransport.Transport) { accepted <- tr },
+	})
+	require.NoError(e)
+	defer listener.Close()
+	url := "https://" + listener.Addr().String() + "/ndn"
+
+	var dialer sockettransport.Dialer
+	_, e = dialer.Dial("h3", "", url)
+	assert.Error(e) // untrusted certificate
+
+	certificate, e := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(e)
+	dialer.TLSClientConfig = &tls.Config{RootCAs: x509.NewCertPool()}
+	dialer.TLSClientConfig.RootCAs.AddCert(certificate)
+	trA, e := dialer.Dial("h3", "", url)
+	require.NoError(e)
+	trB := <-accepted
+
+	assert.Equal(sockettransport.WebTransportMTU, trA.MTU())
+	assert.Equal(sockettransport.WebTransportMTU, trB.MTU())
+	connA, connB := trA.Conn(), trB.Conn()
+	assert.Equal("h3", connA.RemoteAddr().Network())
+	assert.Equal(url, connA.RemoteAddr().String())
+	assert.Equal("h3", connB.RemoteAddr().Network())
+	_, portA, _ := net.SplitHostPort(connA.LocalAddr().String())
+	_, portB, _ := net.SplitHostPort(connB.RemoteAddr().String())
+	assert.Equal(portA, portB)
+
+	var c ndntestenv.L3FaceTester
+	c.CheckTransport(t, trA, trB)
+}
@@ -0,0 +1,197 @@
+package sockettransport
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var errWebSocketNoRedial = errors.New("accepted WebSocket connection cannot be redialed")
+
+// connAddr is the address of a WebSocket or WebTransport connection.
+type connAddr struct {
+	network string
+	addr    string
+}
+
+func (a connAddr) Network() string {
+	return a.network
+}
+
+func (a connAddr) String() string {
+	return a.addr
+}
+
+// wsConn adapts a WebSocket connection to net.Conn.
+// Each binary message carries one NDN packet.
+type wsConn struct {
+	ws            *websocket.Conn
+	local, remote connAddr
+	url           string // server URL, empty if the connection is accepted by a server
+	tlsConfig     *tls.Config
+}
+
+func newWsConn(ws *websocket.Conn, network, serverURL string, tlsConfig *tls.Config) *wsConn {
+	c := &wsConn{
+		ws:        ws,
+		local:     connAddr{network, ws.LocalAddr().String()},
+		remote:    connAddr{network, serverURL},
+		url:       serverURL,
+		tlsConfig: tlsConfig,
+	}
+	if serverURL == "" {
+		c.remote.addr = ws.RemoteAddr().String()
+	}
+	return c
+}
+
+// Read receives a binary message.
+// Text messages are dropped. A message longer than len(b) fails the connection.
+func (c *wsConn) Read(b []byte) (int, error) {
+	c.ws.SetReadLimit(int64(len(b)))
+	for {
+		mt, msg, e := c.ws.ReadMessage()
+		if e != nil {
+			return 0, e
+		}
+		if mt == websocket.BinaryMessage {
+			return copy(b, msg), nil
+		}
+	}
+}
+
+// Write sends a binary message.
+func (c *wsConn) Write(b []byte) (n int, e error) {
+	if e = c.ws.WriteMessage(websocket.BinaryMessage, b); e != nil {
+		return 0, e
+	}
+	return len(b), nil
+}
+
+func (c *wsConn) Close() error {
+	c.ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(time.Second)) // ignore error
+	return c.ws.Close()
+}
+
+func (c *wsConn) LocalAddr() net.Addr {
+	return c.local
+}
+
+func (c *wsConn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+func (c *wsConn) SetDeadline(t time.Time) error {
+	if e := c.ws.SetReadDeadline(t); e != nil {
+		return e
+	}
+	return c.ws.SetWriteDeadline(t)
+}
+
+func (c *wsConn) SetReadDeadline(t time.Time) error {
+	return c.ws.SetReadDeadline(t)
+}
+
+func (c *wsConn) SetWriteDeadline(t time.Time) error {
+	return c.ws.SetWriteDeadline(t)
+}
+
+func dialWebSocket(network, remote string, tlsConfig *tls.Config) (*wsConn, error) {
+	u, e := url.Parse(remote)
+	if e != nil {
+		return nil, e
+	}
+	if u.Scheme != network {
+		return nil, fmt.Errorf("URL scheme %s does not match network %s", u.Scheme, network)
+	}
+
+	dialer := *websocket.DefaultDialer
+	dialer.TLSClientConfig = tlsConfig
+	ws, _, e := dialer.Dial(remote, nil)
+	if e != nil {
+		return nil, e
+	}
+	return newWsConn(ws, network, remote, tlsConfig), nil
+}
+
+type wsImpl struct{}
+
+func (wsImpl) Dial(network, local, remote string, cfg Config) (net.Conn, error) {
+	return dialWebSocket(network, remote, cfg.TLSClientConfig)
+}
+
+func (wsImpl) Redial(oldConn net.Conn) (net.Conn, error) {
+	c := oldConn.(*wsConn)
+	if c.url == "" {
+		return nil, errWebSocketNoRedial
+	}
+	c.Close() // ignore error
+	return dialWebSocket(c.remote.network, c.url, c.tlsConfig)
+}
+
+func (wsImpl) RxLoop(tr *transport) error {
+	c := tr.Conn().(*wsConn)
+	// A message longer than RxBufferLength fails the connection, instead of being buffered in full.
+	c.ws.SetReadLimit(int64(tr.cfg.RxBufferLength))
+	for {
+		mt, wire, e := c.ws.ReadMessage()
+		if e != nil {
+			return e
+		}
+		if mt == websocket.BinaryMessage {
+			tr.p.Rx <- wire
+		}
+	}
+}
+
+// WebSocketHandler is an http.Handler that accepts WebSocket connections as transports.
+//
+// Each accepted connection is wrapped as a Transport. Unlike a dialed transport, it cannot be redialed:
+// once the client disconnects, the transport remains in "down" status until its TX channel is closed.
+type WebSocketHandler struct {
+	Config
+
+	// Accept receives each accepted transport.
+	Accept func(tr Transport)
+
+	// CheckOrigin determines whether a request from a browser is acceptable.
+	// The default accepts any origin, so that web applications hosted elsewhere can connect.
+	CheckOrigin func(r *http.Request) bool
+}
+
+func (h WebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{
+		CheckOrigin: h.CheckOrigin,
+	}
+	if upgrader.CheckOrigin == nil {
+		upgrader.CheckOrigin = func(r *http.Request) bool { return true }
+	}
+
+	ws, e := upgrader.Upgrade(w, r, nil)
+	if e != nil { // Upgrade has replied with HTTP error
+		return
+	}
+
+	network := "ws"
+	if r.TLS != nil {
+		network = "wss"
+	}
+	tr, e := New(newWsConn(ws, network, "", nil), h.Config)
+	if e != nil {
+		ws.Close()
+		return
+	}
+	h.Accept(tr)
+}
+
+func init() {
+	implByNetwork["ws"] = wsImpl{}
+	implByNetwork["wss"] = wsImpl{}
+}
@@ -0,0 +1,311 @@
+package sockettransport
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/quic-go/webtransport-go"
+)
+
+// WebTransportMTU is the maximum NDN packet size carried in a QUIC datagram.
+// Larger packets should be fragmented by NDNLPv2.
+const WebTransportMTU = 1200
+
+// webTransportDialTimeout is the timeout of establishing a WebTransport session.
+const webTransportDialTimeout = 10 * time.Second
+
+var (
+	errWebTransportNoRedial = errors.New("accepted WebTransport session cannot be redialed")
+	errWebTransportDeadline = errors.New("WebTransport session does not support deadlines")
+)
+
+func makeWebTransportQUICConfig() *quic.Config {
+	return &quic.Config{
+		EnableDatagrams: true,
+		KeepAlivePeriod: 10 * time.Second,
+	}
+}
+
+// h3Conn adapts a WebTransport session to net.Conn.
+// Each QUIC datagram carries one NDN packet or NDNLPv2 fragment.
+type h3Conn struct {
+	sess          *webtransport.Session
+	qconn         quic.Connection // underlying QUIC connection, nil if the session is accepted by a server
+	local, remote connAddr
+	url           string // server URL, empty if the session is accepted by a server
+	tlsConfig     *tls.Config
+}
+
+func newH3Conn(sess *webtransport.Session, serverURL string) *h3Conn {
+	c := &h3Conn{
+		sess:   sess,
+		local:  connAddr{"h3", sess.LocalAddr().String()},
+		remote: connAddr{"h3", serverURL},
+		url:    serverURL,
+	}
+	if serverURL == "" {
+		c.remote.addr = sess.RemoteAddr().String()
+	}
+	return c
+}
+
+// Read receives a datagram.
+// Datagrams longer than len(b) are dropped.
+func (c *h3Conn) Read(b []byte) (int, error) {
+	for {
+		msg, e := c.sess.ReceiveDatagram(context.Background())
+		if e != nil {
+			return 0, e
+		}
+		if len(msg) <= len(b) {
+			return copy(b, msg), nil
+		}
+	}
+}
+
+// Write sends a datagram.
+func (c *h3Conn) Write(b []byte) (n int, e error) {
+	if e = c.sess.SendDatagram(b); e != nil {
+		return 0, e
+	}
+	return len(b), nil
+}
+
+func (c *h3Conn) Close() error {
+	e := c.sess.CloseWithError(0, "")
+	if c.qconn != nil {
+		c.qconn.CloseWithError(0, "") // ignore error
+	}
+	return e
+}
+
+func (c *h3Conn) LocalAddr() net.Addr {
+	return c.local
+}
+
+func (c *h3Conn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+func (c *h3Conn) SetDeadline(t time.Time) error {
+	return errWebTransportDeadline
+}
+
+func (c *h3Conn) SetReadDeadline(t time.Time) error {
+	return errWebTransportDeadline
+}
+
+func (c *h3Conn) SetWriteDeadline(t time.Time) error {
+	return errWebTransportDeadline
+}
+
+func dialWebTransport(remote string, tlsConfig *tls.Config) (*h3Conn, error) {
+	u, e := url.Parse(remote)
+	if e != nil {
+		return nil, e
+	}
+	if u.Scheme != "https" {
+		return nil, fmt.Errorf("URL scheme %s is not https", u.Scheme)
+	}
+
+	var qconn quic.EarlyConnection
+	dialer := webtransport.Dialer{
+		TLSClientConfig: tlsConfig,
+		QUICConfig:      makeWebTransportQUICConfig(),
+		DialAddr: func(ctx context.Context, addr string, tlsCfg *tls.Config, cfg *quic.Config) (quic.EarlyConnection, error) {
+			conn, e := quic.DialAddrEarly(ctx, addr, tlsCfg, cfg)
+			qconn = conn
+			return conn, e
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), webTransportDialTimeout)
+	defer cancel()
+	_, sess, e := dialer.Dial(ctx, remote, nil)
+	if e != nil {
+		if qconn != nil {
+			qconn.CloseWithError(0, "")
+		}
+		return nil, e
+	}
+
+	c := newH3Conn(sess, remote)
+	c.qconn = qconn
+	c.tlsConfig = tlsConfig
+	return c, nil
+}
+
+type h3Impl struct{}
+
+func (h3Impl) Dial(network, local, remote string, cfg Config) (net.Conn, error) {
+	return dialWebTransport(remote, cfg.TLSClientConfig)
+}
+
+func (h3Impl) Redial(oldConn net.Conn) (net.Conn, error) {
+	c := oldConn.(*h3Conn)
+	if c.url == "" {
+		return nil, errWebTransportNoRedial
+	}
+	c.Close() // ignore error
+	return dialWebTransport(c.url, c.tlsConfig)
+}
+
+func (h3Impl) RxLoop(tr *transport) error {
+	c := tr.Conn().(*h3Conn)
+	for {
+		wire, e := c.sess.ReceiveDatagram(context.Background())
+		if e != nil {
+			return e
+		}
+		if len(wire) <= tr.cfg.RxBufferLength {
+			tr.p.Rx <- wire
+		}
+	}
+}
+
+func (h3Impl) MaxMTU() int {
+	return WebTransportMTU
+}
+
+// WebTransportListenerConfig contains WebTransportListener configuration.
+type WebTransportListenerConfig struct {
+	Config
+
+	// TLSConfig is the TLS server configuration.
+	// It must contain a certificate.
+	TLSConfig *tls.Config
+
+	// Accept receives each accepted transport.
+	Accept func(tr Transport)
+
+	// CheckOrigin determines whether a request from a browser is acceptable.
+	// The default accepts any origin, so that web applications hosted elsewhere can connect.
+	CheckOrigin func(r *http.Request) bool
+}
+
+// WebTransportListener accepts HTTP/3 WebTransport sessions as transports.
+//
+// A session may be requested at any URL path. Each accepted session is wrapped as a Transport.
+// Unlike a dialed transport, it cannot be redialed: once the client disconnects, the transport
+// remains in "down" status until its TX channel is closed.
+type WebTransportListener struct {
+	cfg    WebTransportListenerConfig
+	conn   net.PacketConn
+	server *webtransport.Server
+}
+
+// ListenWebTransport starts a WebTransport listener on a UDP address.
+func ListenWebTransport(addr string, cfg WebTransportListenerConfig) (*WebTransportListener, error) {
+	if cfg.TLSConfig == nil {
+		return nil, errors.New("TLSConfig is required")
+	}
+	if cfg.CheckOrigin == nil {
+		cfg.CheckOrigin = func(r *http.Request) bool { return true }
+	}
+
+	conn, e := net.ListenPacket("udp", addr)
+	if e != nil {
+		return nil, e
+	}
+
+	l := &WebTransportListener{
+		cfg:  cfg,
+		conn: conn,
+	}
+	l.server = &webtransport.Server{
+		H3: http3.Server{
+			TLSConfig:  http3.ConfigureTLSConfig(cfg.TLSConfig),
+			QUICConfig: makeWebTransportQUICConfig(),
+			Handler:    http.HandlerFunc(l.serveHTTP),
+		},
+		CheckOrigin: cfg.CheckOrigin,
+	}
+	go l.server.Serve(conn)
+	return l, nil
+}
+
+// Addr returns the listening address.
+func (l *WebTransportListener) Addr() net.Addr {
+	return l.conn.LocalAddr()
+}
+
+// Close stops accepting sessions, and disconnects accepted sessions.
+func (l *WebTransportListener) Close() error {
+	e := l.server.Close()
+	l.conn.Close()
+	return e
+}
+
+func (l *WebTransportListener) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	sess, e := l.server.Upgrade(w, r)
+	if e != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	tr, e := New(newH3Conn(sess, ""), l.cfg.Config)
+	if e != nil {
+		sess.CloseWithError(0, "")
+		return
+	}
+	l.cfg.Accept(tr)
+}
+
+// GenerateWebTransportCertificate generates a self-signed ECDSA certificate for WebTransportListener.
+// hosts are DNS names or IP addresses of the listener.
+//
+// The certificate is valid for 14 days, so that browsers can accept it via serverCertificateHashes option.
+// hash is the SHA-256 digest of the certificate, which should be passed to serverCertificateHashes.
+func GenerateWebTransportCertificate(hosts ...string) (cert tls.Certificate, hash [sha256.Size]byte, e error) {
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if e != nil {
+		return cert, hash, e
+	}
+
+	serial, e := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if e != nil {
+		return cert, hash, e
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "NDN WebTransport"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(14*24*time.Hour - 2*time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, e := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if e != nil {
+		return cert, hash, e
+	}
+	cert.Certificate = [][]byte{der}
+	cert.PrivateKey = key
+	return cert, sha256.Sum256(der), nil
+}
+
+func init() {
+	implByNetwork["h3"] = h3Impl{}
+}
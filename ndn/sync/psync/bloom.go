@@ -0,0 +1,142 @@
+package psync
+
+import (
+	"errors"
+	"math"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// ErrBloomFilter indicates the Bloom filter cannot be decoded or has unsupported parameters.
+var ErrBloomFilter = errors.New("bad BloomFilter")
+
+// bloomPredefSalt contains predefined salts in PSync Bloom filter, derived from Arash Partow's Bloom filter library.
+var bloomPredefSalt = [...]uint32{
+	0xAAAAAAAA, 0x55555555, 0x33333333, 0xCCCCCCCC,
+	0x66666666, 0x99999999, 0xB5B5B5B5, 0x4B4B4B4B,
+	0xAA55AA55, 0x55335533, 0x33CC33CC, 0xCC66CC66,
+	0x66996699, 0x99B599B5, 0xB54BB54B, 0x4BAA4BAA,
+	0xAA33AA33, 0x55CC55CC, 0x33663366, 0xCC99CC99,
+	0x66B566B5, 0x994B994B, 0xB5AAB5AA, 0xAAAAAA33,
+	0x555555CC, 0x33333366, 0xCCCCCC99, 0x666666B5,
+	0x9999994B, 0xB5B5B5AA, 0xFFFFFFFF, 0xFFFF0000,
+}
+
+// bloomRandomSeed is the truncated random seed in PSync Bloom filter.
+var bloomRandomSeed = func() uint32 {
+	seed := uint64(0xA5A5A5A55A5A5A5A)
+	mul := uint64(0xA5A5A5A5)
+	return uint32(seed*mul + 1)
+}()
+
+// BloomFilter is a Bloom filter of names, used in partial sync to represent subscriptions.
+type BloomFilter struct {
+	count     int
+	fpp       float64
+	salt      []uint32
+	tableSize uint64 // in bits
+	table     []byte
+}
+
+// NewBloomFilter creates an empty Bloom filter.
+// count is the projected element count.
+// fpp is the desired false positive probability; only three decimal places are transmitted.
+func NewBloomFilter(count int, fpp float64) (bf BloomFilter, e error) {
+	if count <= 0 || !(fpp > 0 && fpp < 1) {
+		return bf, ErrBloomFilter
+	}
+
+	minM, minK := math.Inf(1), 0.0
+	for k := 1.0; k < 1000.0; k++ {
+		m := -k * float64(count) / math.Log(1.0-math.Pow(fpp, 1.0/k))
+		if m < minM {
+			minM, minK = m, k
+		}
+	}
+
+	nHashes := int(minK)
+	if nHashes < 1 {
+		nHashes = 1
+	}
+	if nHashes > len(bloomPredefSalt) {
+		return bf, ErrBloomFilter
+	}
+	bf = BloomFilter{
+		count:     count,
+		fpp:       fpp,
+		tableSize: uint64(minM),
+	}
+	if r := bf.tableSize % 8; r != 0 {
+		bf.tableSize += 8 - r
+	}
+	if bf.tableSize == 0 {
+		bf.tableSize = 8
+	}
+	bf.table = make([]byte, bf.tableSize/8)
+
+	bf.salt = append([]uint32{}, bloomPredefSalt[:nHashes]...)
+	for i := range bf.salt {
+		bf.salt[i] = bf.salt[i]*bf.salt[(i+3)%len(bf.salt)] + bloomRandomSeed
+	}
+	return bf, nil
+}
+
+// Insert inserts a name.
+func (bf *BloomFilter) Insert(name ndn.Name) {
+	for _, salt := range bf.salt {
+		bit := uint64(hashName(salt, name)) % bf.tableSize
+		bf.table[bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// Contains determines whether a name may have been inserted.
+func (bf BloomFilter) Contains(name ndn.Name) bool {
+	if bf.tableSize == 0 {
+		return false
+	}
+	for _, salt := range bf.salt {
+		bit := uint64(hashName(salt, name)) % bf.tableSize
+		if bf.table[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Clear removes all names.
+func (bf *BloomFilter) Clear() {
+	for i := range bf.table {
+		bf.table[i] = 0
+	}
+}
+
+// Encode encodes the Bloom filter as three name components: projected element count,
+// false positive probability in thousandth, and bit table.
+func (bf BloomFilter) Encode() []ndn.NameComponent {
+	return []ndn.NameComponent{
+		ndn.NameComponentFrom(an.TtGenericNameComponent, tlv.NNI(bf.count)),
+		ndn.NameComponentFrom(an.TtGenericNameComponent, tlv.NNI(int(bf.fpp*1000))),
+		ndn.MakeNameComponent(an.TtGenericNameComponent, bf.table),
+	}
+}
+
+// DecodeBloomFilter decodes a Bloom filter from three name components.
+func DecodeBloomFilter(comps []ndn.NameComponent) (bf BloomFilter, e error) {
+	if len(comps) != 3 {
+		return bf, ErrBloomFilter
+	}
+	var count, fpp tlv.NNI
+	if count.UnmarshalBinary(comps[0].Value) != nil || fpp.UnmarshalBinary(comps[1].Value) != nil || count > math.MaxInt32 {
+		return bf, ErrBloomFilter
+	}
+	if bf, e = NewBloomFilter(int(count), float64(fpp)/1000.0); e != nil {
+		return bf, e
+	}
+	if len(comps[2].Value) != len(bf.table) {
+		return bf, ErrBloomFilter
+	}
+	copy(bf.table, comps[2].Value)
+	return bf, nil
+}
@@ -0,0 +1,278 @@
+package psync
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+	"go4.org/must"
+)
+
+// FullOptions contains arguments to NewFull function.
+type FullOptions struct {
+	// SyncPrefix is the sync group prefix.
+	SyncPrefix ndn.Name
+
+	// Fw specifies the L3 Forwarder.
+	// Default is the default Forwarder.
+	// The multicast strategy is selected for SyncPrefix on this forwarder.
+	Fw l3.Forwarder
+
+	// ExpectedNumEntries is the expected number of publisher prefixes, which determines IBLT size.
+	// This must be the same among all nodes in the sync group.
+	// Default is 80.
+	ExpectedNumEntries int
+
+	// SyncInterestLifetime is the InterestLifetime of sync Interests.
+	// Default is 1 second.
+	SyncInterestLifetime time.Duration
+
+	// SyncReplyFreshness is the FreshnessPeriod of sync replies.
+	// Default is 1 second.
+	SyncReplyFreshness time.Duration
+
+	// IbltCompression is the compression scheme of IBLT in sync Interest name.
+	IbltCompression Compression
+
+	// ContentCompression is the compression scheme of sync reply content.
+	ContentCompression Compression
+
+	// Signer signs sync replies.
+	// Default is keeping the Null signature.
+	Signer ndn.Signer
+
+	// Verifier verifies sync replies.
+	// Default is accepting all sync replies.
+	Verifier ndn.Verifier
+
+	// OnUpdate is invoked when there are new sequence numbers.
+	OnUpdate func(updates []MissingData)
+}
+
+func (opts *FullOptions) applyDefaults() {
+	if opts.ExpectedNumEntries <= 0 {
+		opts.ExpectedNumEntries = 80
+	}
+	if opts.SyncInterestLifetime <= 0 {
+		opts.SyncInterestLifetime = time.Second
+	}
+	if opts.SyncReplyFreshness <= 0 {
+		opts.SyncReplyFreshness = time.Second
+	}
+}
+
+// Full is a full sync participant.
+// It publishes under its own prefixes, and learns sequence numbers of all other prefixes in the sync group.
+type Full struct {
+	producerBase
+	opts     FullOptions
+	producer endpoint.Producer
+	cancel   context.CancelFunc
+	closed   chan struct{}
+	resend   chan struct{}
+	ownNonce ndn.Nonce
+}
+
+// NewFull starts a full sync participant.
+func NewFull(opts FullOptions) (f *Full, e error) {
+	opts.applyDefaults()
+	if len(opts.SyncPrefix) == 0 {
+		return nil, errors.New("SyncPrefix is required")
+	}
+
+	f = &Full{
+		opts:   opts,
+		closed: make(chan struct{}),
+		resend: make(chan struct{}, 1),
+	}
+	f.init(opts.ExpectedNumEntries, opts.IbltCompression)
+
+	fw := opts.Fw
+	if fw == nil {
+		fw = l3.GetDefaultForwarder()
+	}
+	fw.SetStrategy(opts.SyncPrefix, l3.StrategyMulticast) // sync Interests must reach every participant
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f.cancel = cancel
+	if f.producer, e = endpoint.Produce(ctx, endpoint.ProducerOptions{
+		Prefix:     opts.SyncPrefix,
+		Handler:    f.handleSyncInterest,
+		Fw:         opts.Fw,
+		DataSigner: opts.Signer,
+	}); e != nil {
+		cancel()
+		return nil, e
+	}
+
+	go f.syncLoop(ctx)
+	return f, nil
+}
+
+// Publish increments the sequence number of a publisher prefix, and notifies the sync group.
+// It returns the new sequence number, or false if the prefix does not exist.
+func (f *Full) Publish(prefix ndn.Name) (seqNo uint64, ok bool) {
+	if seqNo, ok = f.producerBase.Publish(prefix); ok {
+		select {
+		case f.resend <- struct{}{}:
+		default:
+		}
+	}
+	return
+}
+
+// Close leaves the sync group.
+func (f *Full) Close() error {
+	f.cancel()
+	must.Close(f.producer)
+	<-f.closed
+	return nil
+}
+
+func (f *Full) handleSyncInterest(ctx context.Context, interest ndn.Interest) (ndn.Data, error) {
+	if data, ok := f.store.Get(interest.Name); ok {
+		return data, nil
+	}
+	if len(interest.Name) != len(f.opts.SyncPrefix)+1 {
+		return ndn.Data{}, nil
+	}
+
+	f.mutex.Lock()
+	isOwn := interest.Nonce == f.ownNonce
+	f.mutex.Unlock()
+	if isOwn {
+		return ndn.Data{}, nil
+	}
+
+	their := NewIBLT(f.opts.ExpectedNumEntries)
+	if e := their.Decode(interest.Name.Get(-1), f.opts.IbltCompression); e != nil {
+		return ndn.Data{}, nil
+	}
+
+	return f.waitPending(ctx, func() (ndn.Data, error) {
+		diff, e := f.iblt.Sub(their)
+		if e != nil {
+			return ndn.Data{}, e
+		}
+
+		var state State
+		if positive, _, ok := diff.ListEntries(); ok {
+			for _, hash := range positive {
+				if ent, ok := f.hashes[hash]; ok {
+					state = append(state, ent)
+				}
+			}
+		} else {
+			// difference too large to decode: reply with all prefixes
+			for _, entry := range f.prefixes {
+				if entry.seqNo > 0 {
+					state = append(state, StateEntry{Prefix: entry.prefix, SeqNo: entry.seqNo})
+				}
+			}
+		}
+		if len(state) == 0 {
+			return ndn.Data{}, nil
+		}
+		return f.makeReply(interest.Name, state)
+	})
+}
+
+// makeReply creates a sync reply.
+// Caller must hold the mutex.
+func (f *Full) makeReply(interestName ndn.Name, state State) (data ndn.Data, e error) {
+	wire, e := tlv.EncodeFrom(state)
+	if e != nil {
+		return data, e
+	}
+	content, e := f.opts.ContentCompression.compress(wire)
+	if e != nil {
+		return data, e
+	}
+	ibf, e := f.encodeIBLT()
+	if e != nil {
+		return data, e
+	}
+	return f.store.Publish(interestName.Append(ibf), content, f.opts.SyncReplyFreshness), nil
+}
+
+func (f *Full) syncLoop(ctx context.Context) {
+	defer close(f.closed)
+	for ctx.Err() == nil {
+		select {
+		case <-f.resend:
+		default:
+		}
+
+		f.mutex.Lock()
+		ibf, e := f.encodeIBLT()
+		f.ownNonce = ndn.NewNonce()
+		interest := ndn.Interest{
+			Name:        f.opts.SyncPrefix.Append(ibf),
+			CanBePrefix: true,
+			MustBeFresh: true,
+			Nonce:       f.ownNonce,
+			Lifetime:    f.opts.SyncInterestLifetime,
+		}
+		f.mutex.Unlock()
+		if e != nil {
+			return
+		}
+
+		ctx1, cancel1 := context.WithTimeout(ctx, f.opts.SyncInterestLifetime)
+		done := make(chan struct{})
+		go func() {
+			select {
+			case <-f.resend:
+				cancel1()
+			case <-done:
+			}
+		}()
+
+		_, content, e := fetchSegments(ctx1, interest, endpoint.ConsumerOptions{
+			Fw:       f.opts.Fw,
+			Verifier: f.opts.Verifier,
+		})
+		if e == nil {
+			f.onSyncData(content)
+		} else {
+			<-ctx1.Done()
+		}
+		close(done)
+		cancel1()
+	}
+}
+
+func (f *Full) onSyncData(content []byte) {
+	state, e := decodeState(content, f.opts.ContentCompression)
+	if e != nil {
+		return
+	}
+
+	var updates []MissingData
+	f.mutex.Lock()
+	for _, ent := range state {
+		key := nameKey(ent.Prefix)
+		entry := f.prefixes[key]
+		if entry == nil {
+			entry = &prefixEntry{prefix: ent.Prefix}
+			f.prefixes[key] = entry
+		}
+		if ent.SeqNo > entry.seqNo {
+			updates = append(updates, MissingData{
+				Prefix:    ent.Prefix,
+				LowSeqNo:  entry.seqNo + 1,
+				HighSeqNo: ent.SeqNo,
+			})
+			f.updateSeqNo(entry, ent.SeqNo)
+		}
+	}
+	f.mutex.Unlock()
+
+	if len(updates) > 0 && f.opts.OnUpdate != nil {
+		f.opts.OnUpdate(updates)
+	}
+}
@@ -0,0 +1,94 @@
+package psync_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"github.com/usnistgov/ndn-dpdk/ndn/sync/psync"
+	"go4.org/must"
+)
+
+type updateRecorder struct {
+	mutex   sync.Mutex
+	highest map[string]uint64
+}
+
+func (r *updateRecorder) OnUpdate(updates []psync.MissingData) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, u := range updates {
+		key := u.Prefix.String()
+		if r.highest[key]+1 != u.LowSeqNo {
+			panic("gap in MissingData")
+		}
+		r.highest[key] = u.HighSeqNo
+	}
+}
+
+func (r *updateRecorder) Get(prefix string) uint64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.highest[ndn.ParseName(prefix).String()]
+}
+
+func (r *updateRecorder) Set(prefix ndn.Name, seqNo uint64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.highest[prefix.String()] = seqNo
+}
+
+func newUpdateRecorder() *updateRecorder {
+	return &updateRecorder{highest: map[string]uint64{}}
+}
+
+func TestFull(t *testing.T) {
+	assert, require := makeAR(t)
+	fw := l3.NewForwarder()
+
+	makeNode := func(compression psync.Compression) (*psync.Full, *updateRecorder) {
+		rec := newUpdateRecorder()
+		f, e := psync.NewFull(psync.FullOptions{
+			SyncPrefix:           ndn.ParseName("/sync"),
+			Fw:                   fw,
+			SyncInterestLifetime: 400 * time.Millisecond,
+			IbltCompression:      compression,
+			ContentCompression:   compression,
+			OnUpdate:             rec.OnUpdate,
+		})
+		require.NoError(e)
+		t.Cleanup(func() { must.Close(f) })
+		return f, rec
+	}
+
+	for _, compression := range []psync.Compression{psync.CompressionZlib, psync.CompressionNone} {
+		nodeA, recA := makeNode(compression)
+		nodeB, recB := makeNode(compression)
+		assert.True(nodeA.AddPublisher(ndn.ParseName("/A")))
+		assert.False(nodeA.AddPublisher(ndn.ParseName("/A")))
+		assert.True(nodeB.AddPublisher(ndn.ParseName("/B")))
+		time.Sleep(100 * time.Millisecond)
+
+		seqNo, ok := nodeA.Publish(ndn.ParseName("/A"))
+		assert.True(ok)
+		assert.EqualValues(1, seqNo)
+		_, ok = nodeA.Publish(ndn.ParseName("/B"))
+		assert.False(ok)
+		time.Sleep(200 * time.Millisecond)
+		nodeA.Publish(ndn.ParseName("/A"))
+		nodeB.Publish(ndn.ParseName("/B"))
+		time.Sleep(500 * time.Millisecond)
+
+		assert.EqualValues(2, recB.Get("/A"))
+		assert.EqualValues(1, recA.Get("/B"))
+		assert.EqualValues(0, recA.Get("/A"))
+		seqNo, ok = nodeB.SeqNo(ndn.ParseName("/A"))
+		assert.True(ok)
+		assert.EqualValues(2, seqNo)
+
+		must.Close(nodeA)
+		must.Close(nodeB)
+	}
+}
@@ -0,0 +1,40 @@
+package psync
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/usnistgov/ndn-dpdk/core/testenv"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+)
+
+func TestMurmurHash3(t *testing.T) {
+	assert, _ := testenv.MakeAR(t)
+
+	// MurmurHash3 test vectors from Bitcoin Core, whose implementation is used in PSync C++ library.
+	for _, tt := range []struct {
+		expected uint32
+		seed     uint32
+		data     string
+	}{
+		{0x00000000, 0x00000000, ""},
+		{0x6a396f08, 0xFBA4C795, ""},
+		{0x81f16f39, 0xffffffff, ""},
+		{0x514e28b7, 0x00000000, "00"},
+		{0xea3f0b17, 0xFBA4C795, "00"},
+		{0xfd6cf10d, 0x00000000, "ff"},
+		{0x16c6b7ab, 0x00000000, "0011"},
+		{0x8eb51c3d, 0x00000000, "001122"},
+		{0xb4471bf8, 0x00000000, "00112233"},
+		{0xe2301fa8, 0x00000000, "0011223344"},
+		{0xfc2e4a15, 0x00000000, "001122334455"},
+		{0xb074502c, 0x00000000, "00112233445566"},
+		{0x8034d2a0, 0x00000000, "0011223344556677"},
+		{0xb4698def, 0x00000000, "001122334455667788"},
+	} {
+		data, _ := hex.DecodeString(tt.data)
+		assert.Equal(tt.expected, murmurHash3(tt.seed, data), "%x %s", tt.seed, tt.data)
+	}
+
+	assert.Equal(uint32(0x5c5bf267), hashName(ibltNHashCheck, ndn.ParseName("/test/memphis/%01")))
+}
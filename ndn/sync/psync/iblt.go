@@ -0,0 +1,170 @@
+package psync
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+)
+
+// ErrIBLT indicates the IBLT cannot be decoded or has a mismatched size.
+var ErrIBLT = errors.New("bad IBLT")
+
+const (
+	ibltNHash      = 3
+	ibltNHashCheck = 11
+	ibltEntrySize  = 12
+)
+
+type ibltEntry struct {
+	Count    int32
+	KeySum   uint32
+	KeyCheck uint32
+}
+
+func (entry ibltEntry) isPure() bool {
+	return (entry.Count == 1 || entry.Count == -1) && entry.KeyCheck == hashUint32(ibltNHashCheck, entry.KeySum)
+}
+
+func (entry ibltEntry) isEmpty() bool {
+	return entry.Count == 0 && entry.KeySum == 0 && entry.KeyCheck == 0
+}
+
+// IBLT is an Invertible Bloom Lookup Table.
+type IBLT struct {
+	table []ibltEntry
+}
+
+// NewIBLT creates an empty IBLT.
+// expectedNumEntries must be the same among all participants.
+func NewIBLT(expectedNumEntries int) IBLT {
+	nEntries := expectedNumEntries + expectedNumEntries/2
+	if remainder := nEntries % ibltNHash; remainder != 0 {
+		nEntries += ibltNHash - remainder
+	}
+	return IBLT{table: make([]ibltEntry, nEntries)}
+}
+
+func (iblt *IBLT) update(plusOrMinus int32, key uint32) {
+	bucketsPerHash := uint32(len(iblt.table) / ibltNHash)
+	if bucketsPerHash == 0 {
+		return
+	}
+	keyCheck := hashUint32(ibltNHashCheck, key)
+	for i := uint32(0); i < ibltNHash; i++ {
+		entry := &iblt.table[i*bucketsPerHash+hashUint32(i, key)%bucketsPerHash]
+		entry.Count += plusOrMinus
+		entry.KeySum ^= key
+		entry.KeyCheck ^= keyCheck
+	}
+}
+
+// Insert inserts a key.
+func (iblt *IBLT) Insert(key uint32) {
+	iblt.update(1, key)
+}
+
+// Erase erases a key.
+func (iblt *IBLT) Erase(key uint32) {
+	iblt.update(-1, key)
+}
+
+// Clone creates a copy of the IBLT.
+func (iblt IBLT) Clone() IBLT {
+	return IBLT{table: append([]ibltEntry{}, iblt.table...)}
+}
+
+// Equal determines whether two IBLTs have the same content.
+func (iblt IBLT) Equal(other IBLT) bool {
+	if len(iblt.table) != len(other.table) {
+		return false
+	}
+	for i, entry := range iblt.table {
+		if entry != other.table[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Sub computes the difference between two IBLTs of the same size.
+func (iblt IBLT) Sub(other IBLT) (diff IBLT, e error) {
+	if len(iblt.table) != len(other.table) {
+		return IBLT{}, ErrIBLT
+	}
+	diff = iblt.Clone()
+	for i, entry := range other.table {
+		d := &diff.table[i]
+		d.Count -= entry.Count
+		d.KeySum ^= entry.KeySum
+		d.KeyCheck ^= entry.KeyCheck
+	}
+	return diff, nil
+}
+
+// ListEntries extracts keys from a difference IBLT.
+// positive contains keys in the minuend but not in the subtrahend; negative contains the reverse.
+// ok is false if the IBLT cannot be fully decoded.
+func (iblt IBLT) ListEntries() (positive, negative []uint32, ok bool) {
+	peeled := iblt.Clone()
+	for nErased := 1; nErased > 0; {
+		nErased = 0
+		for i := range peeled.table {
+			entry := peeled.table[i]
+			if !entry.isPure() {
+				continue
+			}
+			if entry.Count == 1 {
+				positive = append(positive, entry.KeySum)
+			} else {
+				negative = append(negative, entry.KeySum)
+			}
+			peeled.update(-entry.Count, entry.KeySum)
+			nErased++
+		}
+	}
+
+	for _, entry := range peeled.table {
+		if !entry.isEmpty() {
+			return positive, negative, false
+		}
+	}
+	return positive, negative, true
+}
+
+// Encode encodes the IBLT as a name component.
+func (iblt IBLT) Encode(c Compression) (comp ndn.NameComponent, e error) {
+	table := make([]byte, ibltEntrySize*len(iblt.table))
+	for i, entry := range iblt.table {
+		b := table[i*ibltEntrySize:]
+		binary.LittleEndian.PutUint32(b[0:], uint32(entry.Count))
+		binary.LittleEndian.PutUint32(b[4:], entry.KeySum)
+		binary.LittleEndian.PutUint32(b[8:], entry.KeyCheck)
+	}
+	if table, e = c.compress(table); e != nil {
+		return comp, e
+	}
+	return ndn.MakeNameComponent(an.TtGenericNameComponent, table), nil
+}
+
+// Decode decodes the IBLT from a name component.
+// The IBLT must have been created with the same expectedNumEntries as the encoder.
+func (iblt *IBLT) Decode(comp ndn.NameComponent, c Compression) error {
+	table, e := c.decompress(comp.Value)
+	if e != nil {
+		return e
+	}
+	if len(table) != ibltEntrySize*len(iblt.table) {
+		return ErrIBLT
+	}
+	for i := range iblt.table {
+		b := table[i*ibltEntrySize:]
+		iblt.table[i] = ibltEntry{
+			Count:    int32(binary.LittleEndian.Uint32(b[0:])),
+			KeySum:   binary.LittleEndian.Uint32(b[4:]),
+			KeyCheck: binary.LittleEndian.Uint32(b[8:]),
+		}
+	}
+	return nil
+}
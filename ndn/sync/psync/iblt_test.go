@@ -0,0 +1,163 @@
+package psync_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/usnistgov/ndn-dpdk/core/testenv"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/sync/psync"
+)
+
+var makeAR = testenv.MakeAR
+
+func TestIBLT(t *testing.T) {
+	assert, require := makeAR(t)
+
+	a, b := psync.NewIBLT(40), psync.NewIBLT(40)
+	for i := uint32(1); i <= 30; i++ {
+		a.Insert(i * 7919)
+		b.Insert(i * 7919)
+	}
+	assert.True(a.Equal(b))
+	a.Insert(1001)
+	a.Insert(1002)
+	b.Insert(2001)
+
+	for _, c := range []psync.Compression{psync.CompressionZlib, psync.CompressionNone} {
+		comp, e := a.Encode(c)
+		require.NoError(e)
+		decoded := psync.NewIBLT(40)
+		require.NoError(decoded.Decode(comp, c))
+		assert.True(decoded.Equal(a))
+
+		small := psync.NewIBLT(20)
+		assert.ErrorIs(small.Decode(comp, c), psync.ErrIBLT)
+	}
+	comp, _ := a.Encode(psync.CompressionNone)
+	assert.Len(comp.Value, 60*12)
+
+	diff, e := a.Sub(b)
+	require.NoError(e)
+	positive, negative, ok := diff.ListEntries()
+	assert.True(ok)
+	assert.ElementsMatch([]uint32{1001, 1002}, positive)
+	assert.ElementsMatch([]uint32{2001}, negative)
+
+	b.Erase(2001)
+	a.Erase(1002)
+	diff, _ = a.Sub(b)
+	positive, negative, ok = diff.ListEntries()
+	assert.True(ok)
+	assert.ElementsMatch([]uint32{1001}, positive)
+	assert.Len(negative, 0)
+
+	for i := uint32(1); i <= 200; i++ {
+		a.Insert(i * 104729)
+	}
+	diff, _ = a.Sub(b)
+	_, _, ok = diff.ListEntries()
+	assert.False(ok)
+
+	_, e = a.Sub(psync.NewIBLT(80))
+	assert.ErrorIs(e, psync.ErrIBLT)
+}
+
+func TestIBLTVector(t *testing.T) {
+	assert, require := makeAR(t)
+
+	// PSync C++ library test-iblt.cpp NameAppendAndExtract:
+	// insert murmurHash3(11, /test/memphis/%01) into IBLT of expectedNumEntries=10.
+	iblt := psync.NewIBLT(10)
+	iblt.Insert(0x5C5BF267)
+
+	table := make([]byte, 15*12)
+	for _, i := range []int{2, 6, 13} {
+		copy(table[i*12:], []byte{0x01, 0x00, 0x00, 0x00, 0x67, 0xF2, 0x5B, 0x5C, 0x6C, 0xEE, 0x24, 0x42})
+	}
+	comp, e := iblt.Encode(psync.CompressionNone)
+	require.NoError(e)
+	assert.Equal(table, comp.Value)
+
+	// same table compressed by zlib with best_compression, as in PSync C++ library
+	compressed := []byte{
+		0x78, 0xDA, 0x63, 0x60, 0xC0, 0x0E, 0x18, 0x81, 0x38, 0xFD, 0x53, 0x74, 0x4C, 0xCE, 0x3B,
+		0x15, 0x27, 0x06, 0x22, 0x00, 0xA9, 0xEA, 0x29, 0x31, 0x13, 0x00, 0xFB, 0x19, 0x0B, 0x74,
+	}
+	decoded := psync.NewIBLT(10)
+	require.NoError(decoded.Decode(ndn.MakeNameComponent(an.TtGenericNameComponent, compressed), psync.CompressionZlib))
+	assert.True(decoded.Equal(iblt))
+
+	diff, e := decoded.Sub(psync.NewIBLT(10))
+	require.NoError(e)
+	positive, negative, ok := diff.ListEntries()
+	assert.True(ok)
+	assert.Equal([]uint32{0x5C5BF267}, positive)
+	assert.Len(negative, 0)
+}
+
+func TestBloomFilter(t *testing.T) {
+	assert, require := makeAR(t)
+
+	bf, e := psync.NewBloomFilter(80, 0.001)
+	require.NoError(e)
+	for i := 0; i < 40; i++ {
+		bf.Insert(ndn.ParseName(fmt.Sprintf("/P/%d", i)))
+	}
+	for i := 0; i < 40; i++ {
+		assert.True(bf.Contains(ndn.ParseName(fmt.Sprintf("/P/%d", i))))
+	}
+	nFalsePositives := 0
+	for i := 0; i < 1000; i++ {
+		if bf.Contains(ndn.ParseName(fmt.Sprintf("/Q/%d", i))) {
+			nFalsePositives++
+		}
+	}
+	assert.Less(nFalsePositives, 10)
+
+	comps := bf.Encode()
+	require.Len(comps, 3)
+	assert.Equal([]byte{80}, comps[0].Value)
+	assert.Equal([]byte{1}, comps[1].Value)
+	assert.Len(comps[2].Value, 144)
+
+	decoded, e := psync.DecodeBloomFilter(comps)
+	require.NoError(e)
+	assert.True(decoded.Contains(ndn.ParseName("/P/0")))
+	assert.False(decoded.Contains(ndn.ParseName("/Q/0")))
+
+	comps[2].Value = comps[2].Value[1:]
+	_, e = psync.DecodeBloomFilter(comps)
+	assert.ErrorIs(e, psync.ErrBloomFilter)
+	_, e = psync.NewBloomFilter(80, 0)
+	assert.ErrorIs(e, psync.ErrBloomFilter)
+}
+
+func TestBloomFilterVector(t *testing.T) {
+	assert, require := makeAR(t)
+
+	// PSync C++ library test-bloom-filter.cpp NameAppendAndExtract:
+	// insert /memphis into Bloom filter of projected element count 100 and false positive probability 0.001.
+	bf, e := psync.NewBloomFilter(100, 0.001)
+	require.NoError(e)
+	bf.Insert(ndn.ParseName("/memphis"))
+
+	table := make([]byte, 180)
+	for i, b := range map[int]byte{10: 0x40, 13: 0x40, 99: 0x40, 113: 0x05, 125: 0x02, 153: 0x20, 156: 0xC0, 173: 0x01} {
+		table[i] = b
+	}
+	comps := bf.Encode()
+	require.Len(comps, 3)
+	assert.Equal([]byte{100}, comps[0].Value)
+	assert.Equal([]byte{1}, comps[1].Value)
+	assert.Equal(table, comps[2].Value)
+
+	decoded, e := psync.DecodeBloomFilter([]ndn.NameComponent{
+		ndn.MakeNameComponent(an.TtGenericNameComponent, []byte{100}),
+		ndn.MakeNameComponent(an.TtGenericNameComponent, []byte{1}),
+		ndn.MakeNameComponent(an.TtGenericNameComponent, table),
+	})
+	require.NoError(e)
+	assert.True(decoded.Contains(ndn.ParseName("/memphis")))
+}
@@ -0,0 +1,408 @@
+package psync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// Name components in partial sync.
+var (
+	ComponentHello = ndn.ParseNameComponent("hello")
+	ComponentSync  = ndn.ParseNameComponent("sync")
+)
+
+// PartialProducerOptions contains arguments to NewPartialProducer function.
+type PartialProducerOptions struct {
+	// SyncPrefix is the sync prefix.
+	SyncPrefix ndn.Name
+
+	// Fw specifies the L3 Forwarder.
+	// Default is the default Forwarder.
+	Fw l3.Forwarder
+
+	// ExpectedNumEntries is the expected number of publisher prefixes, which determines IBLT size.
+	// Default is 40.
+	ExpectedNumEntries int
+
+	// HelloReplyFreshness is the FreshnessPeriod of hello replies.
+	// Default is 1 second.
+	HelloReplyFreshness time.Duration
+
+	// SyncReplyFreshness is the FreshnessPeriod of sync replies.
+	// Default is 1 second.
+	SyncReplyFreshness time.Duration
+
+	// IbltCompression is the compression scheme of IBLT in sync Interest name.
+	IbltCompression Compression
+
+	// Signer signs hello and sync replies.
+	// Default is keeping the Null signature.
+	Signer ndn.Signer
+}
+
+func (opts *PartialProducerOptions) applyDefaults() {
+	if opts.ExpectedNumEntries <= 0 {
+		opts.ExpectedNumEntries = 40
+	}
+	if opts.HelloReplyFreshness <= 0 {
+		opts.HelloReplyFreshness = time.Second
+	}
+	if opts.SyncReplyFreshness <= 0 {
+		opts.SyncReplyFreshness = time.Second
+	}
+}
+
+// PartialProducer is a partial sync producer.
+// It answers hello Interests with all publisher prefixes, and sync Interests with updates to subscribed prefixes.
+type PartialProducer struct {
+	producerBase
+	opts     PartialProducerOptions
+	producer endpoint.Producer
+}
+
+// NewPartialProducer starts a partial sync producer.
+func NewPartialProducer(opts PartialProducerOptions) (p *PartialProducer, e error) {
+	opts.applyDefaults()
+	if len(opts.SyncPrefix) == 0 {
+		return nil, errors.New("SyncPrefix is required")
+	}
+
+	p = &PartialProducer{opts: opts}
+	p.init(opts.ExpectedNumEntries, opts.IbltCompression)
+	if p.producer, e = endpoint.Produce(context.Background(), endpoint.ProducerOptions{
+		Prefix:     opts.SyncPrefix,
+		Handler:    p.handleInterest,
+		Fw:         opts.Fw,
+		DataSigner: opts.Signer,
+	}); e != nil {
+		return nil, e
+	}
+	return p, nil
+}
+
+// Close stops the producer.
+func (p *PartialProducer) Close() error {
+	return p.producer.Close()
+}
+
+func (p *PartialProducer) handleInterest(ctx context.Context, interest ndn.Interest) (ndn.Data, error) {
+	if data, ok := p.store.Get(interest.Name); ok {
+		return data, nil
+	}
+
+	rel := interest.Name[len(p.opts.SyncPrefix):]
+	switch {
+	case len(rel) == 1 && rel[0].Equal(ComponentHello):
+		return p.handleHello(interest)
+	case len(rel) == 5 && rel[0].Equal(ComponentSync):
+		return p.handleSync(ctx, interest, rel[1:4], rel[4])
+	}
+	return ndn.Data{}, nil
+}
+
+func (p *PartialProducer) handleHello(interest ndn.Interest) (ndn.Data, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var state State
+	for _, entry := range p.prefixes {
+		state = append(state, StateEntry{Prefix: entry.prefix, SeqNo: entry.seqNo})
+	}
+	return p.makeReply(interest.Name, state, p.opts.HelloReplyFreshness)
+}
+
+func (p *PartialProducer) handleSync(ctx context.Context, interest ndn.Interest, bfComps []ndn.NameComponent, ibfComp ndn.NameComponent) (ndn.Data, error) {
+	bf, e := DecodeBloomFilter(bfComps)
+	if e != nil {
+		return ndn.Data{}, nil
+	}
+	their := NewIBLT(p.opts.ExpectedNumEntries)
+	if e := their.Decode(ibfComp, p.opts.IbltCompression); e != nil {
+		return ndn.Data{}, nil
+	}
+
+	return p.waitPending(ctx, func() (ndn.Data, error) {
+		diff, e := p.iblt.Sub(their)
+		if e != nil {
+			return ndn.Data{}, e
+		}
+
+		var state State
+		if positive, _, ok := diff.ListEntries(); ok {
+			for _, hash := range positive {
+				if ent, ok := p.hashes[hash]; ok && bf.Contains(ent.Prefix) {
+					state = append(state, ent)
+				}
+			}
+		} else {
+			// difference too large to decode: reply with all subscribed prefixes
+			for _, entry := range p.prefixes {
+				if entry.seqNo > 0 && bf.Contains(entry.prefix) {
+					state = append(state, StateEntry{Prefix: entry.prefix, SeqNo: entry.seqNo})
+				}
+			}
+		}
+		if len(state) == 0 {
+			return ndn.Data{}, nil
+		}
+		return p.makeReply(interest.Name, state, p.opts.SyncReplyFreshness)
+	})
+}
+
+// makeReply creates a hello or sync reply.
+// Caller must hold the mutex.
+func (p *PartialProducer) makeReply(interestName ndn.Name, state State, freshness time.Duration) (data ndn.Data, e error) {
+	content, e := tlv.EncodeFrom(state)
+	if e != nil {
+		return data, e
+	}
+	ibf, e := p.encodeIBLT()
+	if e != nil {
+		return data, e
+	}
+	return p.store.Publish(interestName.Append(ibf), content, freshness), nil
+}
+
+// ConsumerOptions contains arguments to NewConsumer function.
+type ConsumerOptions struct {
+	// SyncPrefix is the sync prefix of the partial sync producer.
+	SyncPrefix ndn.Name
+
+	// Fw specifies the L3 Forwarder.
+	// Default is the default Forwarder.
+	Fw l3.Forwarder
+
+	// BloomFilterCount is the projected number of subscriptions, which determines Bloom filter size.
+	// Default is 80.
+	BloomFilterCount int
+
+	// BloomFilterFpp is the desired false positive probability of the Bloom filter.
+	// Default is 0.001.
+	BloomFilterFpp float64
+
+	// SyncInterestLifetime is the InterestLifetime of hello and sync Interests.
+	// Default is 1 second.
+	SyncInterestLifetime time.Duration
+
+	// Verifier verifies hello and sync replies.
+	// Default is accepting all replies.
+	Verifier ndn.Verifier
+
+	// OnUpdate is invoked when there are new sequence numbers in subscribed prefixes.
+	OnUpdate func(updates []MissingData)
+}
+
+func (opts *ConsumerOptions) applyDefaults() {
+	if opts.BloomFilterCount <= 0 {
+		opts.BloomFilterCount = 80
+	}
+	if opts.BloomFilterFpp <= 0 {
+		opts.BloomFilterFpp = 0.001
+	}
+	if opts.SyncInterestLifetime <= 0 {
+		opts.SyncInterestLifetime = time.Second
+	}
+}
+
+// Consumer is a partial sync consumer.
+//
+// Call Hello to discover available prefixes, then Subscribe to prefixes of interest.
+// The consumer sends sync Interests after the first successful Hello, as long as there are subscriptions.
+type Consumer struct {
+	opts   ConsumerOptions
+	cancel context.CancelFunc
+	closed chan struct{}
+
+	mutex         sync.Mutex
+	bf            BloomFilter
+	ibf           ndn.NameComponent
+	subscriptions map[string]*prefixEntry
+	changed       chan struct{}
+}
+
+// NewConsumer creates a partial sync consumer.
+func NewConsumer(opts ConsumerOptions) (c *Consumer, e error) {
+	opts.applyDefaults()
+	if len(opts.SyncPrefix) == 0 {
+		return nil, errors.New("SyncPrefix is required")
+	}
+
+	c = &Consumer{
+		opts:          opts,
+		closed:        make(chan struct{}),
+		subscriptions: map[string]*prefixEntry{},
+		changed:       make(chan struct{}, 1),
+	}
+	if c.bf, e = NewBloomFilter(opts.BloomFilterCount, opts.BloomFilterFpp); e != nil {
+		return nil, e
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	go c.syncLoop(ctx)
+	return c, nil
+}
+
+// Close stops the consumer.
+func (c *Consumer) Close() error {
+	c.cancel()
+	<-c.closed
+	return nil
+}
+
+func (c *Consumer) consumerOptions() endpoint.ConsumerOptions {
+	return endpoint.ConsumerOptions{
+		Fw:       c.opts.Fw,
+		Verifier: c.opts.Verifier,
+	}
+}
+
+func (c *Consumer) notifyChanged() {
+	select {
+	case c.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Hello retrieves available publisher prefixes and their latest sequence numbers from the producer.
+func (c *Consumer) Hello(ctx context.Context) (State, error) {
+	interest := ndn.Interest{
+		Name:        c.opts.SyncPrefix.Append(ComponentHello),
+		CanBePrefix: true,
+		MustBeFresh: true,
+		Lifetime:    c.opts.SyncInterestLifetime,
+	}
+	name, content, e := fetchSegments(ctx, interest, c.consumerOptions())
+	if e != nil {
+		return nil, e
+	}
+	state, e := decodeState(content, CompressionNone)
+	if e != nil {
+		return nil, e
+	}
+
+	c.mutex.Lock()
+	c.ibf = name.Get(-1)
+	c.mutex.Unlock()
+	c.notifyChanged()
+	return state, nil
+}
+
+// Subscribe adds a subscription.
+// seqNo is the latest sequence number already known, usually taken from Hello result.
+func (c *Consumer) Subscribe(prefix ndn.Name, seqNo uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	key := nameKey(prefix)
+	if c.subscriptions[key] == nil {
+		c.bf.Insert(prefix)
+	}
+	c.subscriptions[key] = &prefixEntry{prefix: prefix, seqNo: seqNo}
+	c.notifyChanged()
+}
+
+// Unsubscribe removes a subscription.
+func (c *Consumer) Unsubscribe(prefix ndn.Name) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.subscriptions, nameKey(prefix))
+	c.bf.Clear()
+	for _, entry := range c.subscriptions {
+		c.bf.Insert(entry.prefix)
+	}
+	c.notifyChanged()
+}
+
+// SeqNo returns the latest known sequence number of a subscribed prefix.
+func (c *Consumer) SeqNo(prefix ndn.Name) (seqNo uint64, ok bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if entry := c.subscriptions[nameKey(prefix)]; entry != nil {
+		return entry.seqNo, true
+	}
+	return 0, false
+}
+
+func (c *Consumer) syncLoop(ctx context.Context) {
+	defer close(c.closed)
+	for {
+		c.mutex.Lock()
+		var interest ndn.Interest
+		ready := c.ibf.Valid() && len(c.subscriptions) > 0
+		if ready {
+			interest = ndn.Interest{
+				Name:        c.opts.SyncPrefix.Append(ComponentSync).Append(c.bf.Encode()...).Append(c.ibf),
+				CanBePrefix: true,
+				MustBeFresh: true,
+				Lifetime:    c.opts.SyncInterestLifetime,
+			}
+		}
+		c.mutex.Unlock()
+
+		if !ready {
+			select {
+			case <-ctx.Done():
+				return
+			case <-c.changed:
+			}
+			continue
+		}
+
+		ctx1, cancel1 := context.WithTimeout(ctx, c.opts.SyncInterestLifetime)
+		done := make(chan struct{})
+		go func() {
+			select {
+			case <-c.changed:
+				cancel1()
+			case <-done:
+			}
+		}()
+
+		name, content, e := fetchSegments(ctx1, interest, c.consumerOptions())
+		if e == nil {
+			c.onSyncData(name, content)
+		} else {
+			<-ctx1.Done()
+		}
+		close(done)
+		cancel1()
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (c *Consumer) onSyncData(name ndn.Name, content []byte) {
+	state, e := decodeState(content, CompressionNone)
+	if e != nil {
+		return
+	}
+
+	var updates []MissingData
+	c.mutex.Lock()
+	c.ibf = name.Get(-1)
+	for _, ent := range state {
+		entry := c.subscriptions[nameKey(ent.Prefix)]
+		if entry == nil || ent.SeqNo <= entry.seqNo {
+			continue
+		}
+		updates = append(updates, MissingData{
+			Prefix:    ent.Prefix,
+			LowSeqNo:  entry.seqNo + 1,
+			HighSeqNo: ent.SeqNo,
+		})
+		entry.seqNo = ent.SeqNo
+	}
+	c.mutex.Unlock()
+
+	if len(updates) > 0 && c.opts.OnUpdate != nil {
+		c.opts.OnUpdate(updates)
+	}
+}
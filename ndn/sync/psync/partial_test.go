@@ -0,0 +1,75 @@
+package psync_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"github.com/usnistgov/ndn-dpdk/ndn/sync/psync"
+	"go4.org/must"
+)
+
+func TestPartial(t *testing.T) {
+	assert, require := makeAR(t)
+	fw := l3.NewForwarder()
+
+	p, e := psync.NewPartialProducer(psync.PartialProducerOptions{
+		SyncPrefix: ndn.ParseName("/psync"),
+		Fw:         fw,
+	})
+	require.NoError(e)
+	defer must.Close(p)
+	for i := 0; i < 1000; i++ {
+		prefix := ndn.ParseName(fmt.Sprintf("/P/%d", i))
+		p.AddPublisher(prefix)
+		if i%2 == 0 {
+			p.Publish(prefix)
+		}
+	}
+
+	rec := newUpdateRecorder()
+	c, e := psync.NewConsumer(psync.ConsumerOptions{
+		SyncPrefix:           ndn.ParseName("/psync"),
+		Fw:                   fw,
+		SyncInterestLifetime: 400 * time.Millisecond,
+		OnUpdate:             rec.OnUpdate,
+	})
+	require.NoError(e)
+	defer must.Close(c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	state, e := c.Hello(ctx)
+	require.NoError(e)
+	assert.Len(state, 1000)
+	for _, ent := range state {
+		if nameEqual(ent.Prefix, "/P/4") || nameEqual(ent.Prefix, "/P/7") {
+			c.Subscribe(ent.Prefix, ent.SeqNo)
+			rec.Set(ent.Prefix, ent.SeqNo)
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	p.Publish(ndn.ParseName("/P/4"))
+	p.Publish(ndn.ParseName("/P/5"))
+	time.Sleep(200 * time.Millisecond)
+	p.Publish(ndn.ParseName("/P/7"))
+	p.Publish(ndn.ParseName("/P/7"))
+	time.Sleep(500 * time.Millisecond)
+
+	assert.EqualValues(2, rec.Get("/P/4"))
+	assert.EqualValues(0, rec.Get("/P/5"))
+	assert.EqualValues(2, rec.Get("/P/7"))
+	seqNo, ok := c.SeqNo(ndn.ParseName("/P/4"))
+	assert.True(ok)
+	assert.EqualValues(2, seqNo)
+	_, ok = c.SeqNo(ndn.ParseName("/P/5"))
+	assert.False(ok)
+}
+
+func nameEqual(name ndn.Name, s string) bool {
+	return name.Equal(ndn.ParseName(s))
+}
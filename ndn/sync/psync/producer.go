@@ -0,0 +1,136 @@
+package psync
+
+import (
+	"context"
+	"sync"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+)
+
+type prefixEntry struct {
+	prefix ndn.Name
+	seqNo  uint64
+}
+
+// producerBase contains publisher prefixes and the IBLT representing their latest sequence numbers.
+type producerBase struct {
+	mutex           sync.Mutex
+	ibltCompression Compression
+	iblt            IBLT
+	prefixes        map[string]*prefixEntry // nameKey(prefix) => entry
+	hashes          map[uint32]StateEntry   // hash => prefix with latest sequence number
+	updated         chan struct{}           // closed and replaced when IBLT changes
+	store           segmentStore
+}
+
+func (p *producerBase) init(expectedNumEntries int, ibltCompression Compression) {
+	p.ibltCompression = ibltCompression
+	p.iblt = NewIBLT(expectedNumEntries)
+	p.prefixes = map[string]*prefixEntry{}
+	p.hashes = map[uint32]StateEntry{}
+	p.updated = make(chan struct{})
+}
+
+// AddPublisher adds a publisher prefix with zero sequence number.
+// It returns false if the prefix already exists.
+func (p *producerBase) AddPublisher(prefix ndn.Name) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	key := nameKey(prefix)
+	if p.prefixes[key] != nil {
+		return false
+	}
+	p.prefixes[key] = &prefixEntry{prefix: prefix}
+	return true
+}
+
+// RemovePublisher removes a publisher prefix.
+func (p *producerBase) RemovePublisher(prefix ndn.Name) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	key := nameKey(prefix)
+	if entry := p.prefixes[key]; entry != nil {
+		p.eraseHash(entry)
+		delete(p.prefixes, key)
+		p.notifyUpdated()
+	}
+}
+
+// SeqNo returns the latest sequence number of a publisher prefix.
+func (p *producerBase) SeqNo(prefix ndn.Name) (seqNo uint64, ok bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if entry := p.prefixes[nameKey(prefix)]; entry != nil {
+		return entry.seqNo, true
+	}
+	return 0, false
+}
+
+// Publish increments the sequence number of a publisher prefix.
+// It returns the new sequence number, or false if the prefix does not exist.
+func (p *producerBase) Publish(prefix ndn.Name) (seqNo uint64, ok bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	entry := p.prefixes[nameKey(prefix)]
+	if entry == nil {
+		return 0, false
+	}
+	p.updateSeqNo(entry, entry.seqNo+1)
+	return entry.seqNo, true
+}
+
+func (p *producerBase) eraseHash(entry *prefixEntry) {
+	if entry.seqNo == 0 {
+		return
+	}
+	hash := hashName(ibltNHashCheck, StateEntry{entry.prefix, entry.seqNo}.Name())
+	if _, ok := p.hashes[hash]; ok {
+		p.iblt.Erase(hash)
+		delete(p.hashes, hash)
+	}
+}
+
+// updateSeqNo changes the sequence number of a prefix.
+// Caller must hold the mutex.
+func (p *producerBase) updateSeqNo(entry *prefixEntry, seqNo uint64) {
+	p.eraseHash(entry)
+	entry.seqNo = seqNo
+	ent := StateEntry{entry.prefix, entry.seqNo}
+	hash := hashName(ibltNHashCheck, ent.Name())
+	p.hashes[hash] = ent
+	p.iblt.Insert(hash)
+	p.notifyUpdated()
+}
+
+// notifyUpdated wakes up pending sync Interest handlers.
+// Caller must hold the mutex.
+func (p *producerBase) notifyUpdated() {
+	close(p.updated)
+	p.updated = make(chan struct{})
+}
+
+// encodeIBLT encodes the current IBLT.
+// Caller must hold the mutex.
+func (p *producerBase) encodeIBLT() (ndn.NameComponent, error) {
+	return p.iblt.Encode(p.ibltCompression)
+}
+
+// waitPending invokes makeReply repeatedly, until it returns a Data or the Interest expires.
+// makeReply is invoked with mutex held; it should return zero Data if there is nothing to reply.
+func (p *producerBase) waitPending(ctx context.Context, makeReply func() (ndn.Data, error)) (ndn.Data, error) {
+	for {
+		p.mutex.Lock()
+		data, e := makeReply()
+		updated := p.updated
+		p.mutex.Unlock()
+		if e != nil || len(data.Name) > 0 {
+			return data, e
+		}
+
+		select {
+		case <-ctx.Done():
+			return ndn.Data{}, nil
+		case <-updated:
+		}
+	}
+}
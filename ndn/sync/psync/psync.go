@@ -0,0 +1,221 @@
+// Package psync implements PSync protocol.
+// https://github.com/named-data/PSync
+//
+// Full sync synchronizes the latest sequence numbers of all publishers among a group of nodes.
+// Partial sync allows a consumer to subscribe to a subset of publishers of a producer.
+// Wire format follows PSync C++ library: Invertible Bloom Lookup Table (IBLT) and Bloom filter are
+// encoded into name components, and sync replies carry PSyncContent TLV in segmented Data packets.
+package psync
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/bits"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// Assigned numbers.
+const (
+	TtPSyncContent = 0x80
+
+	_ = "enumgen::TtPSync:Tt"
+)
+
+// Error conditions.
+var (
+	ErrState       = errors.New("bad PSync state")
+	ErrCompression = errors.New("cannot decompress")
+)
+
+// maxDecompressed is the maximum size of decompressed IBLT or state.
+const maxDecompressed = 1 << 20
+
+// Compression indicates a compression scheme applied to IBLT or sync reply content.
+type Compression int
+
+// Compression schemes.
+const (
+	// CompressionZlib is zlib compression, the default in PSync C++ library.
+	CompressionZlib Compression = iota
+
+	// CompressionNone disables compression.
+	CompressionNone
+)
+
+func (c Compression) compress(input []byte) ([]byte, error) {
+	if c == CompressionNone {
+		return input, nil
+	}
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	if _, e := w.Write(input); e != nil {
+		return nil, e
+	}
+	if e := w.Close(); e != nil {
+		return nil, e
+	}
+	return b.Bytes(), nil
+}
+
+func (c Compression) decompress(input []byte) ([]byte, error) {
+	if c == CompressionNone {
+		return input, nil
+	}
+	r, e := zlib.NewReader(bytes.NewReader(input))
+	if e != nil {
+		return nil, ErrCompression
+	}
+	defer r.Close()
+	output, e := io.ReadAll(io.LimitReader(r, maxDecompressed+1))
+	if e != nil || len(output) > maxDecompressed {
+		return nil, ErrCompression
+	}
+	return output, nil
+}
+
+// murmurHash3 computes 32-bit MurmurHash3 on x86.
+func murmurHash3(seed uint32, data []byte) uint32 {
+	const c1, c2 = 0xcc9e2d51, 0x1b873593
+	h := seed
+	nBlocks := len(data) / 4
+	for i := 0; i < nBlocks; i++ {
+		k := binary.LittleEndian.Uint32(data[i*4:])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+	}
+
+	tail := data[nBlocks*4:]
+	var k uint32
+	switch len(tail) {
+	case 3:
+		k ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(tail[0])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+	}
+
+	h ^= uint32(len(data))
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return h
+}
+
+// hashUint32 computes MurmurHash3 of a 32-bit integer in host byte order of x86.
+func hashUint32(seed, value uint32) uint32 {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], value)
+	return murmurHash3(seed, b[:])
+}
+
+// hashName computes MurmurHash3 of a name's TLV-VALUE.
+func hashName(seed uint32, name ndn.Name) uint32 {
+	value, _ := name.MarshalBinary()
+	return murmurHash3(seed, value)
+}
+
+func nameKey(name ndn.Name) string {
+	value, _ := name.MarshalBinary()
+	return string(value)
+}
+
+// MissingData indicates Data packets published under a prefix have not been retrieved.
+// The range LowSeqNo..HighSeqNo is inclusive.
+type MissingData struct {
+	Prefix    ndn.Name
+	LowSeqNo  uint64
+	HighSeqNo uint64
+}
+
+// StateEntry is a publisher prefix and its latest sequence number.
+type StateEntry struct {
+	Prefix ndn.Name
+	SeqNo  uint64
+}
+
+// Name returns the prefix with sequence number appended as a generic name component.
+func (ent StateEntry) Name() ndn.Name {
+	return ent.Prefix.Append(ndn.NameComponentFrom(an.TtGenericNameComponent, tlv.NNI(ent.SeqNo)))
+}
+
+// State is the content of a sync reply.
+type State []StateEntry
+
+var (
+	_ tlv.Fielder                = State{}
+	_ encoding.BinaryUnmarshaler = (*State)(nil)
+)
+
+// Field implements tlv.Fielder interface.
+func (s State) Field() tlv.Field {
+	fields := make([]tlv.Fielder, len(s))
+	for i, ent := range s {
+		fields[i] = ent.Name()
+	}
+	return tlv.TLVFrom(TtPSyncContent, fields...)
+}
+
+// UnmarshalBinary decodes from TLV-VALUE.
+func (s *State) UnmarshalBinary(value []byte) error {
+	*s = State{}
+	d := tlv.DecodingBuffer(value)
+	for _, de := range d.Elements() {
+		switch de.Type {
+		case an.TtName:
+			var name ndn.Name
+			if e := de.UnmarshalValue(&name); e != nil {
+				return e
+			}
+			if len(name) < 2 {
+				return ErrState
+			}
+			var seqNo tlv.NNI
+			if e := seqNo.UnmarshalBinary(name.Get(-1).Value); e != nil {
+				return ErrState
+			}
+			*s = append(*s, StateEntry{Prefix: name.GetPrefix(-1), SeqNo: uint64(seqNo)})
+		default:
+			if de.IsCriticalType() {
+				return tlv.ErrCritical
+			}
+		}
+	}
+	return d.ErrUnlessEOF()
+}
+
+// decodeState decodes PSyncContent TLV from sync reply content.
+func decodeState(content []byte, c Compression) (s State, e error) {
+	if content, e = c.decompress(content); e != nil {
+		return nil, e
+	}
+	d := tlv.DecodingBuffer(content)
+	de, e := d.Element()
+	if e != nil {
+		return nil, e
+	}
+	if de.Type != TtPSyncContent {
+		return nil, ErrState
+	}
+	e = s.UnmarshalBinary(de.Value)
+	return s, e
+}
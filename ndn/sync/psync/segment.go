@@ -0,0 +1,107 @@
+package psync
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+)
+
+// segmentSize is the maximum content size of a sync reply segment.
+const segmentSize = 8000
+
+type segmentStoreEntry struct {
+	data   ndn.Data
+	expiry time.Time
+}
+
+// segmentStore keeps segmented sync replies, so that subsequent segments can be retrieved.
+type segmentStore struct {
+	mutex   sync.Mutex
+	entries map[string]segmentStoreEntry
+}
+
+// Publish splits content into segments under dataName, and stores them until freshness expires.
+// It returns the first segment.
+func (ss *segmentStore) Publish(dataName ndn.Name, content []byte, freshness time.Duration) ndn.Data {
+	now := time.Now()
+	version := ndn.VersionConvention.Make(uint64(now.UnixMilli()))
+	nSegments := (len(content) + segmentSize - 1) / segmentSize
+	if nSegments == 0 {
+		nSegments = 1
+	}
+	finalBlock := ndn.SegmentConvention.Make(uint64(nSegments - 1))
+
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+	if ss.entries == nil {
+		ss.entries = map[string]segmentStoreEntry{}
+	}
+	for key, entry := range ss.entries {
+		if now.After(entry.expiry) {
+			delete(ss.entries, key)
+		}
+	}
+
+	var first ndn.Data
+	for i := 0; i < nSegments; i++ {
+		chunk := content[i*segmentSize:]
+		if len(chunk) > segmentSize {
+			chunk = chunk[:segmentSize]
+		}
+		name := dataName.Append(version, ndn.SegmentConvention.Make(uint64(i)))
+		data := ndn.MakeData(name, freshness, ndn.FinalBlock(finalBlock), chunk)
+		if i == 0 {
+			first = data
+		}
+		ss.entries[name.String()] = segmentStoreEntry{data: data, expiry: now.Add(freshness)}
+	}
+	return first
+}
+
+// Get retrieves a stored segment by exact name.
+func (ss *segmentStore) Get(name ndn.Name) (data ndn.Data, ok bool) {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+	entry, ok := ss.entries[name.String()]
+	if !ok || time.Now().After(entry.expiry) {
+		return data, false
+	}
+	return entry.data, true
+}
+
+// fetchSegments retrieves a segmented sync reply.
+// It returns the Data name without version and segment components, and the reassembled content.
+func fetchSegments(ctx context.Context, interest ndn.Interest, opts endpoint.ConsumerOptions) (name ndn.Name, content []byte, e error) {
+	data, e := endpoint.Consume(ctx, interest, opts)
+	if e != nil {
+		return nil, nil, e
+	}
+	if segment, ok := ndn.SegmentConvention.Parse(data.Name.Get(-1)); !ok || segment != 0 ||
+		!ndn.VersionConvention.Match(data.Name.Get(-2)) {
+		return nil, nil, ErrState
+	}
+	versioned := data.Name.GetPrefix(-1)
+	content = append(content, data.Content...)
+
+	var segment, lastSegment uint64
+	if data.FinalBlock.Valid() {
+		var ok bool
+		if lastSegment, ok = ndn.SegmentConvention.Parse(data.FinalBlock); !ok {
+			return nil, nil, ErrState
+		}
+	}
+
+	for segment++; segment <= lastSegment; segment++ {
+		interest := ndn.Interest{
+			Name: versioned.Append(ndn.SegmentConvention.Make(segment)),
+		}
+		if data, e = endpoint.Consume(ctx, interest, opts); e != nil {
+			return nil, nil, e
+		}
+		content = append(content, data.Content...)
+	}
+	return versioned.GetPrefix(-1), content, nil
+}
@@ -0,0 +1,175 @@
+package svs
+
+import (
+	"encoding"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/an"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+// Assigned numbers.
+const (
+	TtStateVector      = 0xC9
+	TtStateVectorEntry = 0xCA
+	TtSeqNo            = 0xCC
+
+	_ = "enumgen::TtSvs:Tt"
+)
+
+// ErrStateVector indicates a malformed state vector.
+var ErrStateVector = errors.New("bad StateVector")
+
+// StateVectorEntry is a node name and its latest sequence number.
+type StateVectorEntry struct {
+	Name  ndn.Name
+	SeqNo uint64
+}
+
+// Field implements tlv.Fielder interface.
+func (ent StateVectorEntry) Field() tlv.Field {
+	return tlv.TLVFrom(TtStateVectorEntry, ent.Name, tlv.TLVNNI(TtSeqNo, ent.SeqNo))
+}
+
+// UnmarshalBinary decodes from TLV-VALUE.
+func (ent *StateVectorEntry) UnmarshalBinary(value []byte) (e error) {
+	*ent = StateVectorEntry{}
+	hasSeqNo := false
+	d := tlv.DecodingBuffer(value)
+	for _, de := range d.Elements() {
+		switch de.Type {
+		case an.TtName:
+			if e = de.UnmarshalValue(&ent.Name); e != nil {
+				return e
+			}
+		case TtSeqNo:
+			if ent.SeqNo = de.UnmarshalNNI(math.MaxUint64, &e, tlv.ErrRange); e != nil {
+				return e
+			}
+			hasSeqNo = true
+		default:
+			if de.IsCriticalType() {
+				return tlv.ErrCritical
+			}
+		}
+	}
+	if len(ent.Name) == 0 || !hasSeqNo {
+		return ErrStateVector
+	}
+	return d.ErrUnlessEOF()
+}
+
+// StateVector contains the latest sequence number of each node in a sync group.
+// The zero value is an empty state vector.
+type StateVector struct {
+	m map[string]StateVectorEntry // key is name TLV-VALUE
+}
+
+var (
+	_ tlv.Fielder                = StateVector{}
+	_ encoding.BinaryUnmarshaler = (*StateVector)(nil)
+)
+
+func svKey(name ndn.Name) string {
+	value, _ := name.MarshalBinary()
+	return string(value)
+}
+
+// Get returns the sequence number of a node.
+// It returns zero if the node does not exist.
+func (sv StateVector) Get(name ndn.Name) uint64 {
+	return sv.m[svKey(name)].SeqNo
+}
+
+// Set assigns the sequence number of a node.
+func (sv *StateVector) Set(name ndn.Name, seqNo uint64) {
+	if sv.m == nil {
+		sv.m = map[string]StateVectorEntry{}
+	}
+	sv.m[svKey(name)] = StateVectorEntry{Name: name, SeqNo: seqNo}
+}
+
+// Len returns the number of nodes.
+func (sv StateVector) Len() int {
+	return len(sv.m)
+}
+
+// Entries returns all entries, sorted by node name.
+func (sv StateVector) Entries() (list []StateVectorEntry) {
+	list = make([]StateVectorEntry, 0, len(sv.m))
+	for _, ent := range sv.m {
+		list = append(list, ent)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name.Compare(list[j].Name) < 0 })
+	return list
+}
+
+// Clone creates a copy of the state vector.
+func (sv StateVector) Clone() (copy StateVector) {
+	for key, ent := range sv.m {
+		if copy.m == nil {
+			copy.m = make(map[string]StateVectorEntry, len(sv.m))
+		}
+		copy.m[key] = ent
+	}
+	return copy
+}
+
+// Merge updates this state vector with newer sequence numbers in other.
+// It returns entries that are newer in other, with SeqNo being the previous value in this state vector.
+func (sv *StateVector) Merge(other StateVector) (updated []StateVectorEntry) {
+	for key, ent := range other.m {
+		if prev := sv.m[key]; ent.SeqNo > prev.SeqNo {
+			updated = append(updated, StateVectorEntry{Name: ent.Name, SeqNo: prev.SeqNo})
+			sv.Set(ent.Name, ent.SeqNo)
+		}
+	}
+	sort.Slice(updated, func(i, j int) bool { return updated[i].Name.Compare(updated[j].Name) < 0 })
+	return updated
+}
+
+// IsNewerThan determines whether this state vector contains any sequence number newer than other.
+func (sv StateVector) IsNewerThan(other StateVector) bool {
+	for key, ent := range sv.m {
+		if ent.SeqNo > other.m[key].SeqNo {
+			return true
+		}
+	}
+	return false
+}
+
+// Field implements tlv.Fielder interface.
+func (sv StateVector) Field() tlv.Field {
+	entries := sv.Entries()
+	fields := make([]tlv.Fielder, len(entries))
+	for i, ent := range entries {
+		fields[i] = ent
+	}
+	return tlv.TLVFrom(TtStateVector, fields...)
+}
+
+// UnmarshalBinary decodes from TLV-VALUE.
+func (sv *StateVector) UnmarshalBinary(value []byte) error {
+	*sv = StateVector{}
+	d := tlv.DecodingBuffer(value)
+	for _, de := range d.Elements() {
+		switch de.Type {
+		case TtStateVectorEntry:
+			var ent StateVectorEntry
+			if e := de.UnmarshalValue(&ent); e != nil {
+				return e
+			}
+			if ent.SeqNo > sv.Get(ent.Name) {
+				sv.Set(ent.Name, ent.SeqNo)
+			}
+		default:
+			if de.IsCriticalType() {
+				return tlv.ErrCritical
+			}
+		}
+	}
+	return d.ErrUnlessEOF()
+}
@@ -0,0 +1,290 @@
+// Package svs implements State Vector Sync (SVS) protocol.
+// https://named-data.github.io/StateVectorSync/Specification.html
+//
+// Each node in a sync group publishes a sequence of Data packets under its own node name.
+// Nodes exchange their knowledge of the latest sequence numbers as a state vector, carried in sync Interests.
+// This package synchronizes the state vector; application is notified about missing Data and retrieves them.
+package svs
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/endpoint"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+	"go4.org/must"
+)
+
+// ProtocolVersion is the version component appended to sync group prefix in sync Interest name.
+var ProtocolVersion = ndn.VersionConvention.Make(2)
+
+// MissingData indicates Data packets published by a node have not been retrieved.
+// The range LowSeqNo..HighSeqNo is inclusive.
+type MissingData struct {
+	Name      ndn.Name
+	LowSeqNo  uint64
+	HighSeqNo uint64
+}
+
+// Options contains arguments to New function.
+type Options struct {
+	// SyncPrefix is the sync group prefix.
+	SyncPrefix ndn.Name
+
+	// NodeName is the name of this node.
+	NodeName ndn.Name
+
+	// InitialSeqNo is the initial sequence number of this node.
+	// This should be set when resuming from a previous session.
+	InitialSeqNo uint64
+
+	// Fw specifies the L3 Forwarder.
+	// Default is the default Forwarder.
+	// The multicast strategy is selected for SyncPrefix on this forwarder.
+	Fw l3.Forwarder
+
+	// Signer signs outgoing sync Interests.
+	// Default is inserting ParametersSha256DigestComponent without signature.
+	Signer ndn.Signer
+
+	// Verifier verifies incoming sync Interests.
+	// Default is accepting all sync Interests.
+	Verifier ndn.Verifier
+
+	// PeriodicTimeout is the average interval of periodic sync Interests in steady state.
+	// The actual interval is randomized within ±10%.
+	// Default is 30 seconds.
+	PeriodicTimeout time.Duration
+
+	// SuppressionTimeout is the maximum delay before replying to an outdated sync Interest.
+	// Default is 200 milliseconds.
+	SuppressionTimeout time.Duration
+
+	// OnMissing is invoked when there are missing Data packets.
+	// This is invoked on a single goroutine, and should not block.
+	OnMissing func(missing []MissingData)
+}
+
+func (opts *Options) applyDefaults() {
+	if opts.PeriodicTimeout <= 0 {
+		opts.PeriodicTimeout = 30 * time.Second
+	}
+	if opts.SuppressionTimeout <= 0 {
+		opts.SuppressionTimeout = 200 * time.Millisecond
+	}
+	if opts.Verifier == nil {
+		opts.Verifier = ndn.NopVerifier
+	}
+}
+
+// Sync represents a node participating in a sync group.
+type Sync struct {
+	opts   Options
+	face   *endpoint.LFace
+	cancel context.CancelFunc
+	closed chan struct{}
+
+	mutex sync.Mutex
+	sv    StateVector
+
+	publish chan struct{}
+
+	// accessed on loop goroutine only
+	timer        *time.Timer
+	suppressing  bool
+	suppressedSv StateVector
+}
+
+// New creates a node and joins the sync group.
+func New(opts Options) (s *Sync, e error) {
+	opts.applyDefaults()
+	if len(opts.SyncPrefix) == 0 || len(opts.NodeName) == 0 {
+		return nil, errors.New("SyncPrefix and NodeName are required")
+	}
+
+	s = &Sync{
+		opts:    opts,
+		closed:  make(chan struct{}),
+		publish: make(chan struct{}, 1),
+	}
+	s.sv.Set(opts.NodeName, opts.InitialSeqNo)
+
+	fw := opts.Fw
+	if fw == nil {
+		fw = l3.GetDefaultForwarder()
+	}
+	fw.SetStrategy(opts.SyncPrefix, l3.StrategyMulticast) // sync Interests must reach every node
+
+	if s.face, e = endpoint.NewLFace(fw); e != nil {
+		return nil, e
+	}
+	s.face.FwFace.AddRoute(opts.SyncPrefix)
+	s.face.FwFace.AddAnnouncement(opts.SyncPrefix)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.timer = time.NewTimer(0)
+	go s.loop(ctx)
+	return s, nil
+}
+
+// SeqNo returns the latest sequence number of this node.
+func (s *Sync) SeqNo() uint64 {
+	return s.StateVector().Get(s.opts.NodeName)
+}
+
+// StateVector returns a copy of the current state vector.
+func (s *Sync) StateVector() StateVector {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.sv.Clone()
+}
+
+// Publish increments the sequence number of this node, and announces it to the sync group.
+// It returns the new sequence number.
+// The application should make the corresponding Data available before calling this function.
+func (s *Sync) Publish() (seqNo uint64) {
+	s.mutex.Lock()
+	seqNo = s.sv.Get(s.opts.NodeName) + 1
+	s.sv.Set(s.opts.NodeName, seqNo)
+	s.mutex.Unlock()
+
+	select {
+	case s.publish <- struct{}{}:
+	default:
+	}
+	return seqNo
+}
+
+// Close leaves the sync group.
+func (s *Sync) Close() error {
+	s.cancel()
+	<-s.closed
+	return nil
+}
+
+func (s *Sync) loop(ctx context.Context) {
+	defer func() {
+		s.timer.Stop()
+		must.Close(s.face)
+		close(s.closed)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case l3pkt := <-s.face.Rx():
+			if interest := l3pkt.ToPacket().Interest; interest != nil {
+				s.handleSyncInterest(*interest)
+			}
+		case <-s.publish:
+			s.sendSyncInterest()
+			s.resetPeriodic()
+		case <-s.timer.C:
+			// in suppression state, send sync Interest only if others have not reported the same knowledge
+			if !s.suppressing || s.StateVector().IsNewerThan(s.suppressedSv) {
+				s.sendSyncInterest()
+			}
+			s.resetPeriodic()
+		}
+	}
+}
+
+func (s *Sync) resetTimer(d time.Duration) {
+	if !s.timer.Stop() {
+		select {
+		case <-s.timer.C:
+		default:
+		}
+	}
+	s.timer.Reset(d)
+}
+
+func (s *Sync) resetPeriodic() {
+	s.suppressing = false
+	s.suppressedSv = StateVector{}
+	jitter := float64(s.opts.PeriodicTimeout) * 0.1
+	s.resetTimer(s.opts.PeriodicTimeout + time.Duration((rand.Float64()*2-1)*jitter))
+}
+
+func (s *Sync) sendSyncInterest() {
+	appParams, e := tlv.EncodeFrom(s.StateVector())
+	if e != nil {
+		return
+	}
+	interest := ndn.Interest{
+		Name:          s.opts.SyncPrefix.Append(ProtocolVersion),
+		AppParameters: appParams,
+		Lifetime:      time.Second,
+	}
+	if s.opts.Signer == nil {
+		interest.UpdateParamsDigest()
+	} else if e = s.opts.Signer.Sign(&interest); e != nil {
+		return
+	}
+	s.face.Send(interest.ToPacket())
+}
+
+func (s *Sync) handleSyncInterest(interest ndn.Interest) {
+	if len(interest.Name) < len(s.opts.SyncPrefix)+1 || !s.opts.SyncPrefix.IsPrefixOf(interest.Name) ||
+		!interest.Name[len(s.opts.SyncPrefix)].Equal(ProtocolVersion) {
+		return
+	}
+	if e := s.opts.Verifier.Verify(interest); e != nil {
+		return
+	}
+
+	d := tlv.DecodingBuffer(interest.AppParameters)
+	de, e := d.Element()
+	if e != nil || de.Type != TtStateVector {
+		return
+	}
+	var recv StateVector
+	if e := de.UnmarshalValue(&recv); e != nil {
+		return
+	}
+
+	s.mutex.Lock()
+	updated := s.sv.Merge(recv)
+	missing := make([]MissingData, 0, len(updated))
+	for _, ent := range updated {
+		if ent.Name.Equal(s.opts.NodeName) {
+			// another node knows a newer sequence number of this node, probably from a previous session
+			continue
+		}
+		missing = append(missing, MissingData{
+			Name:      ent.Name,
+			LowSeqNo:  ent.SeqNo + 1,
+			HighSeqNo: s.sv.Get(ent.Name),
+		})
+	}
+	local := s.sv.Clone()
+	s.mutex.Unlock()
+
+	if len(missing) > 0 && s.opts.OnMissing != nil {
+		s.opts.OnMissing(missing)
+	}
+
+	if !local.IsNewerThan(recv) {
+		// incoming state vector is up-to-date: no need to send own sync Interest in this period
+		if !s.suppressing {
+			s.resetPeriodic()
+		}
+		return
+	}
+
+	// incoming state vector is outdated: enter suppression state
+	if s.suppressing {
+		s.suppressedSv.Merge(recv)
+		return
+	}
+	s.suppressing = true
+	s.suppressedSv = recv.Clone()
+	s.resetTimer(time.Duration(rand.Int63n(int64(s.opts.SuppressionTimeout) + 1)))
+}
@@ -0,0 +1,160 @@
+package svs_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/usnistgov/ndn-dpdk/core/testenv"
+	"github.com/usnistgov/ndn-dpdk/ndn"
+	"github.com/usnistgov/ndn-dpdk/ndn/l3"
+	"github.com/usnistgov/ndn-dpdk/ndn/ndntestenv"
+	"github.com/usnistgov/ndn-dpdk/ndn/sockettransport"
+	"github.com/usnistgov/ndn-dpdk/ndn/sync/svs"
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+	"go4.org/must"
+)
+
+var (
+	makeAR    = testenv.MakeAR
+	nameEqual = ndntestenv.NameEqual
+)
+
+func TestStateVector(t *testing.T) {
+	assert, require := makeAR(t)
+
+	var sv svs.StateVector
+	sv.Set(ndn.ParseName("/B"), 2)
+	sv.Set(ndn.ParseName("/A"), 1)
+	wire, e := tlv.EncodeFrom(sv)
+	require.NoError(e)
+	assert.Equal([]byte{
+		0xC9, 0x14,
+		0xCA, 0x08, 0x07, 0x03, 0x08, 0x01, 0x41, 0xCC, 0x01, 0x01,
+		0xCA, 0x08, 0x07, 0x03, 0x08, 0x01, 0x42, 0xCC, 0x01, 0x02,
+	}, wire)
+
+	var decoded svs.StateVector
+	require.NoError(decoded.UnmarshalBinary(wire[2:]))
+	assert.Equal(2, decoded.Len())
+	assert.EqualValues(1, decoded.Get(ndn.ParseName("/A")))
+	assert.EqualValues(2, decoded.Get(ndn.ParseName("/B")))
+	assert.EqualValues(0, decoded.Get(ndn.ParseName("/C")))
+
+	var other svs.StateVector
+	other.Set(ndn.ParseName("/A"), 4)
+	other.Set(ndn.ParseName("/B"), 2)
+	other.Set(ndn.ParseName("/C"), 1)
+	assert.True(other.IsNewerThan(decoded))
+	assert.False(decoded.IsNewerThan(other))
+
+	updated := decoded.Merge(other)
+	require.Len(updated, 2)
+	nameEqual(assert, "/A", updated[0].Name)
+	assert.EqualValues(1, updated[0].SeqNo)
+	nameEqual(assert, "/C", updated[1].Name)
+	assert.EqualValues(0, updated[1].SeqNo)
+	assert.EqualValues(4, decoded.Get(ndn.ParseName("/A")))
+	assert.False(decoded.IsNewerThan(other))
+	assert.False(other.IsNewerThan(decoded))
+}
+
+type syncNode struct {
+	*svs.Sync
+	mutex   sync.Mutex
+	missing map[string]uint64
+}
+
+func (node *syncNode) Received(name string) uint64 {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	return node.missing[name]
+}
+
+func newSyncNode(t *testing.T, fw l3.Forwarder, nodeName string) (node *syncNode) {
+	_, require := makeAR(t)
+	node = &syncNode{missing: map[string]uint64{}}
+	s, e := svs.New(svs.Options{
+		SyncPrefix:         ndn.ParseName("/sync"),
+		NodeName:           ndn.ParseName(nodeName),
+		Fw:                 fw,
+		PeriodicTimeout:    500 * time.Millisecond,
+		SuppressionTimeout: 50 * time.Millisecond,
+		OnMissing: func(missing []svs.MissingData) {
+			node.mutex.Lock()
+			defer node.mutex.Unlock()
+			for _, m := range missing {
+				name := m.Name.String()
+				if node.missing[name]+1 != m.LowSeqNo {
+					panic(fmt.Errorf("gap in MissingData %s %d", name, m.LowSeqNo))
+				}
+				node.missing[name] = m.HighSeqNo
+			}
+		},
+	})
+	require.NoError(e)
+	node.Sync = s
+	t.Cleanup(func() { must.Close(s) })
+	return node
+}
+
+func TestForwarder(t *testing.T) {
+	assert, _ := makeAR(t)
+	fw := l3.NewForwarder()
+
+	nodeA := newSyncNode(t, fw, "/A")
+	nodeB := newSyncNode(t, fw, "/B")
+	assert.EqualValues(1, nodeA.Publish())
+	assert.EqualValues(2, nodeA.Publish())
+	assert.EqualValues(1, nodeB.Publish())
+	time.Sleep(100 * time.Millisecond)
+
+	nodeC := newSyncNode(t, fw, "/C")
+	assert.EqualValues(1, nodeC.Publish())
+	time.Sleep(100 * time.Millisecond)
+	assert.EqualValues(3, nodeA.Publish())
+	time.Sleep(800 * time.Millisecond)
+
+	for _, node := range []*syncNode{nodeA, nodeB, nodeC} {
+		sv := node.StateVector()
+		assert.Equal(3, sv.Len())
+		assert.EqualValues(3, sv.Get(ndn.ParseName("/A")))
+		assert.EqualValues(1, sv.Get(ndn.ParseName("/B")))
+		assert.EqualValues(1, sv.Get(ndn.ParseName("/C")))
+	}
+	assert.EqualValues(3, nodeB.Received("/8=A"))
+	assert.EqualValues(1, nodeB.Received("/8=C"))
+	assert.EqualValues(3, nodeC.Received("/8=A"))
+	assert.EqualValues(1, nodeC.Received("/8=B"))
+	assert.EqualValues(0, nodeA.Received("/8=A"))
+}
+
+func TestPipe(t *testing.T) {
+	assert, require := makeAR(t)
+
+	trA, trB, e := sockettransport.Pipe(sockettransport.Config{})
+	require.NoError(e)
+	fwA, fwB := l3.NewForwarder(), l3.NewForwarder()
+	for _, pair := range []struct {
+		fw l3.Forwarder
+		tr l3.Transport
+	}{{fwA, trA}, {fwB, trB}} {
+		face, e := l3.NewFace(pair.tr, l3.FaceConfig{})
+		require.NoError(e)
+		fwFace, e := pair.fw.AddFace(face)
+		require.NoError(e)
+		fwFace.AddRoute(ndn.ParseName("/sync"))
+		t.Cleanup(func() { must.Close(fwFace) })
+	}
+
+	nodeA := newSyncNode(t, fwA, "/A")
+	nodeB := newSyncNode(t, fwB, "/B")
+	nodeA.Publish()
+	nodeB.Publish()
+	nodeB.Publish()
+	time.Sleep(800 * time.Millisecond)
+
+	assert.EqualValues(2, nodeA.Received("/8=B"))
+	assert.EqualValues(1, nodeB.Received("/8=A"))
+}
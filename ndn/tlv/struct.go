@@ -0,0 +1,358 @@
+package tlv
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrStructType indicates a struct cannot be used with Marshal or Unmarshal.
+var ErrStructType = errors.New("unsupported struct for TLV codec")
+
+var (
+	typeBinaryMarshaler   = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+	typeBinaryUnmarshaler = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+	typeElementSlice      = reflect.TypeOf([]Element{})
+)
+
+// structField describes a struct field with `tlv` tag.
+type structField struct {
+	index     []int
+	typ       uint32 // TLV-TYPE, 0 for extensions field
+	omitEmpty bool
+	repeated  bool
+}
+
+// structInfo describes a struct type with `tlv` tags.
+type structInfo struct {
+	err        error
+	fields     []structField
+	byType     map[uint32]int // TLV-TYPE => index in fields
+	extensions []int          // index of extensions field, nil if absent
+}
+
+var (
+	structInfoLock  sync.RWMutex
+	structInfoCache = map[reflect.Type]*structInfo{}
+)
+
+func getStructInfo(t reflect.Type) (*structInfo, error) {
+	structInfoLock.RLock()
+	si := structInfoCache[t]
+	structInfoLock.RUnlock()
+	if si != nil {
+		return si, si.err
+	}
+
+	structInfoLock.Lock()
+	defer structInfoLock.Unlock()
+	return parseStructInfo(t)
+}
+
+// parseStructInfo parses a struct type.
+// Caller must hold structInfoLock.
+func parseStructInfo(t reflect.Type) (*structInfo, error) {
+	if si := structInfoCache[t]; si != nil { // parsed, or being parsed in case of recursive type
+		return si, si.err
+	}
+
+	si := &structInfo{byType: map[uint32]int{}}
+	structInfoCache[t] = si
+	if e := si.parse(t, nil); e != nil {
+		si.err = fmt.Errorf("%w %s: %v", ErrStructType, t, e)
+	}
+	return si, si.err
+}
+
+func (si *structInfo) parse(t reflect.Type, index []int) error {
+	for i, n := 0, t.NumField(); i < n; i++ {
+		sf := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+		tag, hasTag := sf.Tag.Lookup("tlv")
+		if !hasTag && sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			if e := si.parse(sf.Type, fieldIndex); e != nil {
+				return e
+			}
+			continue
+		}
+		if !hasTag || tag == "-" {
+			continue
+		}
+		if !sf.IsExported() {
+			return fmt.Errorf("field %s is unexported", sf.Name)
+		}
+
+		opts := strings.Split(tag, ",")
+		if opts[0] == "*" {
+			if sf.Type != typeElementSlice || si.extensions != nil {
+				return fmt.Errorf("field %s: extensions field must be the only []tlv.Element", sf.Name)
+			}
+			si.extensions = fieldIndex
+			si.fields = append(si.fields, structField{index: fieldIndex})
+			continue
+		}
+
+		typ, e := strconv.ParseUint(opts[0], 0, 32)
+		if e != nil || typ < minType {
+			return fmt.Errorf("field %s: bad TLV-TYPE %s", sf.Name, opts[0])
+		}
+		if _, dup := si.byType[uint32(typ)]; dup {
+			return fmt.Errorf("field %s: duplicate TLV-TYPE 0x%02X", sf.Name, typ)
+		}
+
+		f := structField{
+			index: fieldIndex,
+			typ:   uint32(typ),
+		}
+		for _, opt := range opts[1:] {
+			switch opt {
+			case "omitempty":
+				f.omitEmpty = true
+			default:
+				return fmt.Errorf("field %s: unknown option %s", sf.Name, opt)
+			}
+		}
+
+		elemType := sf.Type
+		if elemType.Kind() == reflect.Slice && !isValueType(elemType) {
+			f.repeated = true
+			elemType = elemType.Elem()
+		}
+		if e := checkValueType(elemType); e != nil {
+			return fmt.Errorf("field %s: %w", sf.Name, e)
+		}
+
+		si.byType[f.typ] = len(si.fields)
+		si.fields = append(si.fields, f)
+	}
+	return nil
+}
+
+// isValueType determines whether a type is encoded as a single TLV-VALUE, rather than repeated elements.
+func isValueType(t reflect.Type) bool {
+	if t.Implements(typeBinaryMarshaler) || reflect.PtrTo(t).Implements(typeBinaryUnmarshaler) {
+		return true
+	}
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+func checkValueType(t reflect.Type) error {
+	if t.Implements(typeBinaryMarshaler) && reflect.PtrTo(t).Implements(typeBinaryUnmarshaler) {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.String:
+		return nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+	case reflect.Struct:
+		_, e := parseStructInfo(t)
+		return e
+	case reflect.Ptr:
+		if t.Elem().Kind() != reflect.Ptr && t.Elem().Kind() != reflect.Slice {
+			return checkValueType(t.Elem())
+		}
+	}
+	return fmt.Errorf("unsupported type %s", t)
+}
+
+// Marshal encodes a struct to TLV-VALUE.
+//
+// Each exported field with a `tlv:"TLV-TYPE"` tag is encoded as a TLV element, in the order of struct fields.
+// TLV-TYPE may be written in decimal or hexadecimal with 0x prefix.
+// Embedded structs without tag are flattened.
+// Supported field types are:
+//   - bool: encoded as zero-length TLV-VALUE if true, omitted if false.
+//   - unsigned integers: encoded as NonNegativeInteger.
+//   - string, []byte, [N]byte: encoded as bytes.
+//   - struct with `tlv` tags: encoded as nested TLV elements.
+//   - type implementing encoding.BinaryMarshaler and encoding.BinaryUnmarshaler, such as ndn.Name.
+//   - pointer to any of the above: omitted if nil.
+//   - slice of any of the above other than byte: encoded as repeated TLV elements.
+//
+// The tag may contain "omitempty" option after a comma, which omits the field if it has zero value.
+//
+// A field of []tlv.Element type may have `tlv:"*"` tag.
+// It collects unrecognized TLV elements during decoding, and they are re-encoded at the field position.
+func Marshal(v interface{}) (value []byte, e error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w %T", ErrStructType, v)
+	}
+	return marshalStruct(rv)
+}
+
+func marshalStruct(rv reflect.Value) (value []byte, e error) {
+	si, e := getStructInfo(rv.Type())
+	if e != nil {
+		return nil, e
+	}
+
+	for _, f := range si.fields {
+		fv := rv.FieldByIndex(f.index)
+		switch {
+		case f.typ == 0:
+			for _, element := range fv.Interface().([]Element) {
+				if value, e = element.Field().Encode(value); e != nil {
+					return nil, e
+				}
+			}
+		case f.repeated:
+			for i, n := 0, fv.Len(); i < n; i++ {
+				if value, e = marshalElement(value, f.typ, fv.Index(i), false); e != nil {
+					return nil, fmt.Errorf("TLV-TYPE 0x%02X: %w", f.typ, e)
+				}
+			}
+		default:
+			if value, e = marshalElement(value, f.typ, fv, f.omitEmpty); e != nil {
+				return nil, fmt.Errorf("TLV-TYPE 0x%02X: %w", f.typ, e)
+			}
+		}
+	}
+	return value, nil
+}
+
+func marshalElement(b []byte, typ uint32, fv reflect.Value, omitEmpty bool) ([]byte, error) {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return b, nil
+		}
+		fv = fv.Elem()
+	} else if omitEmpty && fv.IsZero() {
+		return b, nil
+	}
+	if fv.Kind() == reflect.Bool && !fv.Bool() {
+		return b, nil
+	}
+
+	var value []byte
+	var e error
+	if m, ok := fv.Interface().(encoding.BinaryMarshaler); ok {
+		value, e = m.MarshalBinary()
+	} else {
+		switch fv.Kind() {
+		case reflect.Bool:
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			value = NNI(fv.Uint()).Encode(nil)
+		case reflect.String:
+			value = []byte(fv.String())
+		case reflect.Slice:
+			value = fv.Bytes()
+		case reflect.Array:
+			value = make([]byte, fv.Len())
+			reflect.Copy(reflect.ValueOf(value), fv)
+		case reflect.Struct:
+			value, e = marshalStruct(fv)
+		}
+	}
+	if e != nil {
+		return nil, e
+	}
+	return TLVBytes(typ, value).Encode(b)
+}
+
+// Unmarshal decodes TLV-VALUE into a struct.
+// v must be a pointer to a struct, whose fields are tagged as described in Marshal.
+//
+// Fields absent in the input are left as zero values.
+// If a non-repeated field appears more than once, subsequent occurrences are treated as unrecognized.
+// Unrecognized elements are collected in the `tlv:"*"` field if it exists.
+// Otherwise, following TLV evolvability rules, an unrecognized element of critical TLV-TYPE causes ErrCritical,
+// and an unrecognized element of non-critical TLV-TYPE is ignored.
+func Unmarshal(value []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w %T", ErrStructType, v)
+	}
+	return unmarshalStruct(rv.Elem(), value)
+}
+
+func unmarshalStruct(rv reflect.Value, value []byte) error {
+	si, e := getStructInfo(rv.Type())
+	if e != nil {
+		return e
+	}
+	rv.Set(reflect.Zero(rv.Type()))
+
+	seen := make([]bool, len(si.fields))
+	d := DecodingBuffer(value)
+	for !d.EOF() {
+		de, e := d.Element()
+		if e != nil {
+			return e
+		}
+
+		i, ok := si.byType[de.Type]
+		if ok && (si.fields[i].repeated || !seen[i]) {
+			seen[i] = true
+			f := si.fields[i]
+			fv := rv.FieldByIndex(f.index)
+			if f.repeated {
+				elem := reflect.New(fv.Type().Elem()).Elem()
+				if e := unmarshalElement(elem, de.Value); e != nil {
+					return fmt.Errorf("TLV-TYPE 0x%02X: %w", de.Type, e)
+				}
+				fv.Set(reflect.Append(fv, elem))
+			} else if e := unmarshalElement(fv, de.Value); e != nil {
+				return fmt.Errorf("TLV-TYPE 0x%02X: %w", de.Type, e)
+			}
+			continue
+		}
+
+		switch {
+		case si.extensions != nil:
+			fv := rv.FieldByIndex(si.extensions)
+			fv.Set(reflect.Append(fv, reflect.ValueOf(de.Element)))
+		case de.IsCriticalType():
+			return fmt.Errorf("TLV-TYPE 0x%02X: %w", de.Type, ErrCritical)
+		}
+	}
+	return nil
+}
+
+func unmarshalElement(fv reflect.Value, value []byte) error {
+	if fv.Kind() == reflect.Ptr {
+		ptr := reflect.New(fv.Type().Elem())
+		if e := unmarshalElement(ptr.Elem(), value); e != nil {
+			return e
+		}
+		fv.Set(ptr)
+		return nil
+	}
+
+	if u, ok := fv.Addr().Interface().(encoding.BinaryUnmarshaler); ok {
+		return u.UnmarshalBinary(value)
+	}
+
+	switch fv.Kind() {
+	case reflect.Bool:
+		fv.SetBool(true)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n NNI
+		if e := n.UnmarshalBinary(value); e != nil {
+			return e
+		}
+		if fv.OverflowUint(uint64(n)) {
+			return ErrRange
+		}
+		fv.SetUint(uint64(n))
+	case reflect.String:
+		fv.SetString(string(value))
+	case reflect.Slice:
+		fv.SetBytes(append([]byte{}, value...))
+	case reflect.Array:
+		if len(value) != fv.Len() {
+			return ErrRange
+		}
+		reflect.Copy(fv, reflect.ValueOf(value))
+	case reflect.Struct:
+		return unmarshalStruct(fv, value)
+	}
+	return nil
+}
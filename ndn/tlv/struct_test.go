@@ -0,0 +1,144 @@
+package tlv_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/usnistgov/ndn-dpdk/ndn/tlv"
+)
+
+type testStructHex string
+
+func (h testStructHex) MarshalBinary() ([]byte, error) {
+	return hex.DecodeString(string(h))
+}
+
+func (h *testStructHex) UnmarshalBinary(wire []byte) error {
+	*h = testStructHex(hex.EncodeToString(wire))
+	return nil
+}
+
+type testStructInner struct {
+	A uint8  `tlv:"0xE1"`
+	B string `tlv:"0xE2,omitempty"`
+}
+
+type testStructEmbed struct {
+	E uint64 `tlv:"0xF3"`
+}
+
+type testStruct struct {
+	testStructEmbed
+	Flag     bool              `tlv:"0xC1"`
+	N        uint32            `tlv:"0xC2"`
+	Opt      uint              `tlv:"0xC3,omitempty"`
+	S        string            `tlv:"0xC4"`
+	Bytes    []byte            `tlv:"0xC5,omitempty"`
+	Array    [2]byte           `tlv:"0xC6"`
+	Inner    testStructInner   `tlv:"0xC7"`
+	InnerPtr *testStructInner  `tlv:"0xC8"`
+	List     []testStructInner `tlv:"0xC9"`
+	Nums     []uint16          `tlv:"0xCA"`
+	Hex      testStructHex     `tlv:"0xCB,omitempty"`
+	Ignored  int
+	Skipped  uint `tlv:"-"`
+}
+
+type testStructExt struct {
+	N   uint          `tlv:"0xC2"`
+	Ext []tlv.Element `tlv:"*"`
+	S   string        `tlv:"0xC4"`
+}
+
+func TestStructRoundtrip(t *testing.T) {
+	assert, require := makeAR(t)
+
+	v := testStruct{
+		testStructEmbed: testStructEmbed{E: 0x0102},
+		Flag:            true,
+		N:               0x0A,
+		S:               "s",
+		Array:           [2]byte{0xA0, 0xA1},
+		Inner:           testStructInner{A: 1},
+		InnerPtr:        &testStructInner{A: 2, B: "b"},
+		List:            []testStructInner{{A: 3}, {A: 4}},
+		Nums:            []uint16{5, 6},
+		Hex:             "b0b1",
+		Ignored:         1,
+		Skipped:         2,
+	}
+	wire, e := tlv.Marshal(v)
+	require.NoError(e)
+	assert.Equal(bytesFromHex(
+		"F3020102 C100 C2010A C40173 C602A0A1 C703E10101 C806E10102E20162"+
+			"C903E10103 C903E10104 CA0105 CA0106 CB02B0B1"), wire)
+
+	var decoded testStruct
+	require.NoError(tlv.Unmarshal(wire, &decoded))
+	v.Ignored, v.Skipped = 0, 0
+	assert.Equal(v, decoded)
+
+	wire, e = tlv.Marshal(&testStruct{})
+	require.NoError(e)
+	assert.Equal(bytesFromHex("F30100 C20100 C400 C6020000 C703E10100"), wire)
+}
+
+func TestStructEvolvability(t *testing.T) {
+	assert, require := makeAR(t)
+
+	var v testStructInner
+	require.NoError(tlv.Unmarshal(bytesFromHex("E10101 E00100"), &v))
+	assert.EqualValues(1, v.A)
+	assert.ErrorIs(tlv.Unmarshal(bytesFromHex("E10101 E10102"), &v), tlv.ErrCritical)
+	assert.ErrorIs(tlv.Unmarshal(bytesFromHex("E10101 E30100"), &v), tlv.ErrCritical)
+	assert.ErrorIs(tlv.Unmarshal(bytesFromHex("E1020100"), &v), tlv.ErrRange)
+	assert.ErrorIs(tlv.Unmarshal(bytesFromHex("E102"), &v), tlv.ErrIncomplete)
+
+	var s testStruct
+	require.NoError(tlv.Unmarshal(bytesFromHex("C20101 C20102"), &s))
+	assert.EqualValues(1, s.N)
+	assert.ErrorIs(tlv.Unmarshal(bytesFromHex("C601A0"), &s), tlv.ErrRange)
+	assert.ErrorIs(tlv.Unmarshal(bytesFromHex("C703E30100"), &s), tlv.ErrCritical)
+
+	var x testStructExt
+	require.NoError(tlv.Unmarshal(bytesFromHex("E30100 C20101 C2 0102 C40173 F00100"), &x))
+	assert.EqualValues(1, x.N)
+	assert.Equal("s", x.S)
+	require.Len(x.Ext, 3)
+	assert.EqualValues(0xE3, x.Ext[0].Type)
+	assert.EqualValues(0xC2, x.Ext[1].Type)
+	assert.EqualValues(0xF0, x.Ext[2].Type)
+
+	wire, e := tlv.Marshal(x)
+	require.NoError(e)
+	assert.Equal(bytesFromHex("C20101 E30100 C20102 F00100 C40173"), wire)
+}
+
+func TestStructBadType(t *testing.T) {
+	assert, _ := makeAR(t)
+
+	_, e := tlv.Marshal(1)
+	assert.ErrorIs(e, tlv.ErrStructType)
+	assert.ErrorIs(tlv.Unmarshal(nil, testStructInner{}), tlv.ErrStructType)
+
+	_, e = tlv.Marshal(struct {
+		A int `tlv:"0xC0"`
+	}{})
+	assert.ErrorIs(e, tlv.ErrStructType)
+
+	_, e = tlv.Marshal(struct {
+		A uint `tlv:"0xC0"`
+		B uint `tlv:"0xC0"`
+	}{})
+	assert.ErrorIs(e, tlv.ErrStructType)
+
+	_, e = tlv.Marshal(struct {
+		A uint `tlv:"X"`
+	}{})
+	assert.ErrorIs(e, tlv.ErrStructType)
+
+	_, e = tlv.Marshal(struct {
+		A []string `tlv:"*"`
+	}{})
+	assert.ErrorIs(e, tlv.ErrStructType)
+}
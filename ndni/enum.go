@@ -22,9 +22,18 @@ const (
 		3 + 1 + 1 + // CongestionMark
 		1 + 5 // Payload TL
 
+	// LpReliabilityHeadroom is the additional headroom to insert NDNLPv2 reliability fields.
+	// It is reserved in header mbufs allocated by LpReliability, not in every packet.
+	LpReliabilityHeadroom = 0 +
+		LpMaxAcks*(3+1+8) + // Ack
+		3 + 1 + 8 // TxSequence
+
 	// LpMaxFragments is the maximum number of NDNLPv2 fragments.
 	LpMaxFragments = 31
 
+	// LpMaxAcks is the maximum number of NDNLPv2 Ack fields piggybacked on an outgoing frame.
+	LpMaxAcks = 4
+
 	// L3TypeLengthHeadroom is the required headroom to prepend Interest/Data TLV-TYPE TLV-LENGTH fields.
 	L3TypeLengthHeadroom = 1 + 3
 
@@ -46,7 +46,9 @@ func init() {
 	HeaderMempool = pktmbuf.RegisterTemplate("HEADER", pktmbuf.PoolConfig{
 		Capacity: 65535,
 		PrivSize: int(C.sizeof_PacketPriv),
-		Dataroom: headerDataroom + L3TypeLengthHeadroom, // Interest TL for Interest_ModifyGuiders
+		Dataroom: headerDataroom +
+			L3TypeLengthHeadroom + // Interest TL for Interest_ModifyGuiders
+			LpReliabilityHeadroom, // Ack and TxSequence for LpReliability
 	})
 
 	InterestMempool = pktmbuf.RegisterTemplate("INTEREST", pktmbuf.PoolConfig{